@@ -89,6 +89,9 @@ func setupCommands(app interfaces.Application) *cobra.Command {
 	// Statistics commands
 	statsCmd := setupStatsCommands(app)
 
+	// Silence commands
+	silenceCmd := setupSilenceCommands(app)
+
 	// History command
 	historyCmd := &cobra.Command{
 		Use:   "history [service-name]",
@@ -130,6 +133,7 @@ func setupCommands(app interfaces.Application) *cobra.Command {
 		statusCmd,
 		configCmd,
 		statsCmd,
+		silenceCmd,
 		historyCmd,
 		dbInfoCmd,
 		versionCmd,
@@ -193,4 +197,50 @@ func setupStatsCommands(app interfaces.Application) *cobra.Command {
 	statsCmd.Flags().BoolP("json", "j", false, "Output in JSON format")
 
 	return statsCmd
-}
\ No newline at end of file
+}
+
+// setupSilenceCommands creates commands managing ad-hoc alert silences
+func setupSilenceCommands(app interfaces.Application) *cobra.Command {
+	silenceCmd := &cobra.Command{
+		Use:   "silence",
+		Short: "Silence alerts for checks during planned work",
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Silence the checks with the given names or tags",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checks, _ := cmd.Flags().GetStringSlice("check")
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			duration, _ := cmd.Flags().GetDuration("for")
+			reason, _ := cmd.Flags().GetString("reason")
+			return AddSilence(app, checks, tags, duration, reason)
+		},
+	}
+	addCmd.Flags().StringSliceP("check", "c", nil, "Name of a check to silence (repeatable)")
+	addCmd.Flags().StringSliceP("tag", "t", nil, "Silence the checks with this tag (repeatable)")
+	addCmd.Flags().Duration("for", time.Hour, "How long the silence lasts")
+	addCmd.Flags().StringP("reason", "r", "", "Why the checks are silenced")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the silences that have not ended",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ListSilences(app)
+		},
+	}
+
+	removeCmd := &cobra.Command{
+		Use:   "remove [silence-id]",
+		Short: "End a silence early",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RemoveSilence(app, args[0])
+		},
+	}
+
+	silenceCmd.AddCommand(addCmd, listCmd, removeCmd)
+	return silenceCmd
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/renancavalcantercb/healthcheck-cli/internal/config"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/plugin"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 )
 
 // ShowHistory displays historical data for a service (CLI wrapper)
@@ -60,6 +62,23 @@ func ShowHistory(app interfaces.Application, serviceName string, limit int, sinc
 		fmt.Printf("%-19s %-8s %-12s %-30s\n", timestamp, status, response, errorMsg)
 	}
 
+	// Content versions are only recorded for checks with content_alert_on_change
+	versions, err := app.Stats().GetContentVersions(serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to get content versions: %w", err)
+	}
+	if len(versions) > 0 {
+		fmt.Printf("\n📝 Content versions (%d)\n", len(versions))
+		fmt.Printf("───────────────────────────────────────────────────────────────\n")
+		fmt.Printf("%-19s %-19s %-15s\n", "FIRST SEEN", "LAST SEEN", "HASH")
+		for _, version := range versions {
+			fmt.Printf("%-19s %-19s %-15s\n",
+				version.FirstSeen.Format("01-02 15:04:05"),
+				version.LastSeen.Format("01-02 15:04:05"),
+				truncateString(version.Hash, 15))
+		}
+	}
+
 	return nil
 }
 
@@ -131,8 +150,28 @@ func ValidateConfigWithEnv(configFile, envFile string) error {
 		return err
 	}
 	
+	// Validate the checks handled by plugins against their manifests, without running them
+	var plugins []*plugin.Manifest
+	if cfg.Global.PluginsDir != "" {
+		plugins, err = plugin.ReadManifests(cfg.Global.PluginsDir)
+		if err != nil {
+			fmt.Printf("❌ Plugin manifests are invalid: %v\n", err)
+			return err
+		}
+	}
+	if err := cfg.ValidatePlugins(plugins); err != nil {
+		fmt.Printf("❌ Plugin check validation failed: %v\n", err)
+		return err
+	}
+	
 	fmt.Println("✅ Configuration is valid!")
 	fmt.Printf("   📊 Found %d health checks\n", len(cfg.Checks))
+	if len(cfg.Maintenance) > 0 {
+		fmt.Printf("   🔧 Found %d maintenance windows\n", len(cfg.Maintenance))
+	}
+	for _, p := range plugins {
+		fmt.Printf("   🔌 Plugin %s provides '%s' checks\n", p.Name(), p.Type())
+	}
 	
 	// Show notification status
 	notifications := []string{}
@@ -213,6 +252,9 @@ func showServiceStats(app interfaces.Application, serviceName string, since time
 	fmt.Printf("✅ Successful:       %d\n", stats.SuccessfulChecks)
 	fmt.Printf("❌ Failed:           %d\n", stats.FailedChecks)
 	fmt.Printf("📊 Total Checks:     %d\n", stats.TotalChecks)
+	if stats.MaintenanceChecks > 0 {
+		fmt.Printf("🔧 In Maintenance:   %d (excluded from uptime)\n", stats.MaintenanceChecks)
+	}
 	fmt.Printf("⚡ Avg Response:     %.0fms\n", stats.AvgResponseTimeMs)
 	fmt.Printf("🚀 Min Response:     %dms\n", stats.MinResponseTimeMs)
 	fmt.Printf("🐌 Max Response:     %dms\n", stats.MaxResponseTimeMs)
@@ -225,6 +267,14 @@ func showServiceStats(app interfaces.Application, serviceName string, since time
 		fmt.Printf("❌ Last Failure:     %s\n", stats.LastFailure.Format("2006-01-02 15:04:05"))
 	}
 
+	if len(stats.Families) > 0 {
+		fmt.Printf("\n🌍 Address Families\n")
+		for _, family := range stats.Families {
+			fmt.Printf("   %-6s %.2f%% uptime, %d checks, %.0fms avg\n",
+				family.Family, family.UptimePercent, family.TotalChecks, family.AvgResponseTimeMs)
+		}
+	}
+
 	return nil
 }
 
@@ -271,11 +321,102 @@ func showAllStats(app interfaces.Application, since time.Time, jsonOutput bool)
 
 		fmt.Printf("%-20s %-12s %s%-7s %-10s %-12s %-15s\n", 
 			name, checkType, uptimeColor, uptime, checks, avgRT, lastCheck)
+		for _, family := range stats.Families {
+			fmt.Printf("  └ %-16s %-12s %-8s %-10d %-12s\n",
+				family.Family, "", fmt.Sprintf("%.1f%%", family.UptimePercent),
+				family.TotalChecks, fmt.Sprintf("%.0fms", family.AvgResponseTimeMs))
+		}
+	}
+
+	return nil
+}
+
+// silenceManager is implemented by applications that manage silences
+type silenceManager interface {
+	Silences() interfaces.SilenceService
+}
+
+// silences returns the silence service of app
+func silences(app interfaces.Application) (interfaces.SilenceService, error) {
+	manager, ok := app.(silenceManager)
+	if !ok {
+		return nil, fmt.Errorf("silences are not supported")
+	}
+	return manager.Silences(), nil
+}
+
+// AddSilence silences checks by name or tag (CLI wrapper)
+func AddSilence(app interfaces.Application, checks, tags []string, duration time.Duration, reason string) error {
+	service, err := silences(app)
+	if err != nil {
+		return err
+	}
+
+	silence, err := service.Add(checks, tags, reason, duration)
+	if err != nil {
+		return fmt.Errorf("failed to add silence: %w", err)
+	}
+
+	fmt.Printf("🔕 Silence %s added until %s\n", silence.ID, silence.EndsAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Scope: %s\n", silenceScope(silence))
+	return nil
+}
+
+// ListSilences displays the silences that have not ended (CLI wrapper)
+func ListSilences(app interfaces.Application) error {
+	service, err := silences(app)
+	if err != nil {
+		return err
+	}
+
+	active, err := service.List()
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+
+	if len(active) == 0 {
+		fmt.Println("🔔 No active silences")
+		return nil
 	}
 
+	fmt.Printf("🔕 Active silences (%d)\n", len(active))
+	fmt.Printf("═══════════════════════════════════════════════════════════════\n")
+	fmt.Printf("%-10s %-19s %-24s %-20s\n", "ID", "ENDS", "SCOPE", "REASON")
+	fmt.Printf("───────────────────────────────────────────────────────────────\n")
+	for _, silence := range active {
+		fmt.Printf("%-10s %-19s %-24s %-20s\n",
+			silence.ID,
+			silence.EndsAt.Format("2006-01-02 15:04:05"),
+			truncateString(silenceScope(silence), 24),
+			truncateString(silence.Reason, 20))
+	}
 	return nil
 }
 
+// RemoveSilence ends a silence early (CLI wrapper)
+func RemoveSilence(app interfaces.Application, id string) error {
+	service, err := silences(app)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Remove(id); err != nil {
+		return fmt.Errorf("failed to remove silence: %w", err)
+	}
+
+	fmt.Printf("🔔 Silence %s removed\n", id)
+	return nil
+}
+
+// silenceScope describes the checks a silence covers
+func silenceScope(silence types.Silence) string {
+	scope := append([]string(nil), silence.Checks...)
+	for _, tag := range silence.Tags {
+		scope = append(scope, "tag:"+tag)
+	}
+	return strings.Join(scope, ", ")
+}
+
 // truncateString truncates a string to a specified length
 func truncateString(s string, length int) string {
 	if len(s) <= length {
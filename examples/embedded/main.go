@@ -0,0 +1,54 @@
+// Example embedding the monitoring engine in a Go service with pkg/healthcheck
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/healthcheck"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: embedded <url>")
+		os.Exit(2)
+	}
+
+	monitor, err := healthcheck.New(
+		healthcheck.WithChecks(types.CheckConfig{
+			Name:     "Upstream",
+			URL:      os.Args[1],
+			Interval: 15 * time.Second,
+			Timeout:  5 * time.Second,
+		}),
+		healthcheck.WithStoragePath("./embedded.db"),
+		healthcheck.OnStateChange(func(change healthcheck.StateChangeEvent) {
+			log.Printf("%s changed from %s to %s", change.Check, change.Previous, change.Current)
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer monitor.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		for event := range monitor.Events() {
+			if event, ok := event.(healthcheck.ResultEvent); ok {
+				log.Printf("%s: %s in %v", event.Result.Name, event.Result.Status, event.Result.ResponseTime)
+			}
+		}
+	}()
+
+	if err := monitor.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+// Example running the monitoring engine from pkg/healthcheck with structured
+// logging configured by a logging section in the configuration file
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/renancavalcantercb/healthcheck-cli/internal/config"
+	"github.com/renancavalcantercb/healthcheck-cli/internal/logger"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/healthcheck"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	configFile := "config.yml"
+	if len(os.Args) > 1 {
+		configFile = os.Args[1]
+	}
+
+	if err := run(configFile); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(configFile string) error {
+	// The monitor ignores the logging section, so it is read separately
+	logging, err := loadLogging(configFile)
+	if err != nil {
+		return err
+	}
+	appLogger, err := logger.New(logger.Config{
+		Level:  logging.Level,
+		Format: logging.Format,
+		Output: logging.Output,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	monitor, err := healthcheck.New(
+		healthcheck.WithConfigFile(configFile),
+		healthcheck.OnResult(func(result types.Result) {
+			appLogger.HealthCheckCompleted(result.Name, result.IsHealthy(), result.ResponseTime.String(), map[string]interface{}{
+				"status": result.Status.String(),
+				"error":  result.Error,
+			})
+		}),
+		healthcheck.OnStateChange(func(change healthcheck.StateChangeEvent) {
+			appLogger.Warn("Health check changed state", "check_id", change.Check, "from", change.Previous.String(), "to", change.Current.String())
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start monitor: %w", err)
+	}
+	defer monitor.Close()
+
+	appLogger.ApplicationStarted("example")
+	appLogger.ConfigLoaded(configFile, len(monitor.Checks()))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := monitor.Run(ctx); err != nil {
+		appLogger.Error("Monitor stopped", "error", err.Error())
+		return err
+	}
+
+	appLogger.ApplicationStopped()
+	return nil
+}
+
+// loadLogging reads the logging section of the configuration file
+func loadLogging(configFile string) (config.LoggingConfig, error) {
+	settings := struct {
+		Logging config.LoggingConfig `yaml:"logging"`
+	}{Logging: config.DefaultLoggingConfig()}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return settings.Logging, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return settings.Logging, fmt.Errorf("failed to parse logging settings: %w", err)
+	}
+	return settings.Logging, nil
+}
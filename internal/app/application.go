@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/renancavalcantercb/healthcheck-cli/internal/storage"
 	"github.com/renancavalcantercb/healthcheck-cli/internal/tui"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/plugin"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -23,14 +25,17 @@ import (
 
 // Application implements the main application with service layer architecture
 type Application struct {
-	// Core services
-	healthCheckService interfaces.HealthCheckService
+	// Core services; the health check and config services are kept concrete
+	// for the operations outside their interfaces
+	healthCheckService *services.HealthCheckService
 	statsService       interfaces.StatsService
-	configService      interfaces.ConfigService
+	configService      *services.ConfigService
+	silenceService     interfaces.SilenceService
 	
 	// Infrastructure
 	storage  interfaces.Storage
 	notifier interfaces.NotificationManager
+	plugins  []*plugin.Plugin
 	
 	// Context for cancellation
 	ctx    context.Context
@@ -52,11 +57,13 @@ func NewApplication(deps Dependencies) *Application {
 	healthCheckService := services.NewHealthCheckService(deps.Checkers, deps.Storage, deps.Notifier)
 	statsService := services.NewStatsService(deps.Storage)
 	configService := services.NewConfigService()
+	silenceService := services.NewSilenceService(deps.Storage)
 	
 	return &Application{
 		healthCheckService: healthCheckService,
 		statsService:       statsService,
 		configService:      configService,
+		silenceService:     silenceService,
 		storage:            deps.Storage,
 		notifier:           deps.Notifier,
 		ctx:                ctx,
@@ -75,11 +82,7 @@ func NewApplicationWithDefaults() (*Application, error) {
 	}
 	
 	// Initialize checkers
-	checkers := map[types.CheckType]interfaces.Checker{
-		types.CheckTypeHTTP: checker.NewHTTPChecker(30 * time.Second),
-		types.CheckTypeTCP:  checker.NewTCPChecker(10 * time.Second),
-		types.CheckTypeSSL:  checker.NewSSLChecker(10 * time.Second),
-	}
+	checkers := checker.NewDefaultCheckers()
 	
 	// Initialize notification manager
 	defaultConfig := config.DefaultConfig()
@@ -97,6 +100,7 @@ func NewApplicationWithDefaults() (*Application, error) {
 	// Create other services
 	statsService := services.NewStatsService(storage)
 	configService := services.NewConfigService()
+	silenceService := services.NewSilenceService(storage)
 	
 	ctx, cancel := context.WithCancel(context.Background())
 	
@@ -104,6 +108,7 @@ func NewApplicationWithDefaults() (*Application, error) {
 		healthCheckService: healthCheckService,
 		statsService:       statsService,
 		configService:      configService,
+		silenceService:     silenceService,
 		storage:            storage,
 		notifier:           notifier,
 		ctx:                ctx,
@@ -142,6 +147,12 @@ func (a *Application) Stop() error {
 		a.cancel()
 	}
 	
+	// Shut down checker plugins
+	for _, p := range a.plugins {
+		p.Close()
+	}
+	a.plugins = nil
+	
 	// Close storage if available
 	if a.storage != nil {
 		if err := a.storage.Close(); err != nil {
@@ -169,6 +180,11 @@ func (a *Application) Config() interfaces.ConfigService {
 	return a.configService
 }
 
+// Silences returns the silence service
+func (a *Application) Silences() interfaces.SilenceService {
+	return a.silenceService
+}
+
 // TestEndpoint tests a single endpoint immediately
 func (a *Application) TestEndpoint(url string, timeout time.Duration, verbose bool) error {
 	if timeout == 0 {
@@ -279,9 +295,21 @@ func (a *Application) LoadConfigAndRunWithEnv(configFile, envFile string, daemon
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 	
+	// Start the plugins providing check types that are not built in
+	if err := a.loadPlugins(cfg); err != nil {
+		return err
+	}
+	
+	// Exec checks may only run the configured commands
+	a.healthCheckService.AddChecker(types.CheckTypeExec, checker.NewExecChecker(10*time.Second, cfg.Global.AllowedCommands))
+	
 	// Update notification manager with new config
 	a.notifier = a.notifier.UpdateConfig(cfg)
 	
+	if err := a.healthCheckService.SetMaintenanceWindows(cfg.Maintenance); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	
 	// Convert to CheckConfig format
 	checks := make([]types.CheckConfig, len(cfg.Checks))
 	for i, check := range cfg.Checks {
@@ -313,6 +341,33 @@ func (a *Application) LoadConfigAndRunWithEnv(configFile, envFile string, daemon
 	return nil
 }
 
+// loadPlugins starts the plugins in the configured plugins directory, registers
+// them as checkers and validates the checks they handle
+func (a *Application) loadPlugins(cfg *config.Config) error {
+	if cfg.Global.PluginsDir != "" {
+		a.registerPlugins(cfg.Global.PluginsDir)
+	}
+	
+	if err := cfg.ValidatePlugins(plugin.Manifests(a.plugins)); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	return nil
+}
+
+// registerPlugins starts the plugins in dir and registers them as checkers
+func (a *Application) registerPlugins(dir string) {
+	plugins, err := plugin.Discover(dir)
+	if err != nil {
+		// A broken plugin only fails the checks that need it
+		log.Printf("⚠️  Warning: Some plugins could not be loaded: %v", err)
+	}
+	for _, p := range plugins {
+		a.healthCheckService.AddChecker(p.Type(), p)
+		fmt.Printf("🔌 Loaded plugin %s for '%s' checks\n", p.Name(), p.Type())
+	}
+	a.plugins = append(a.plugins, plugins...)
+}
+
 // ShowStatus shows a status dashboard
 func (a *Application) ShowStatus(watch bool) error {
 	if !watch {
@@ -321,6 +376,14 @@ func (a *Application) ShowStatus(watch bool) error {
 		return nil
 	}
 	
+	if dir := a.configService.GetGlobalConfig().PluginsDir; dir != "" {
+		a.registerPlugins(dir)
+	}
+	
+	if err := a.healthCheckService.SetMaintenanceWindows(a.configService.GetMaintenanceWindows()); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	
 	checks := a.configService.GetChecks()
 	if len(checks) == 0 {
 		// Default example check
@@ -383,21 +446,211 @@ func (a *Application) printResult(result types.Result, verbose bool) {
 	
 	if result.Error != "" {
 		fmt.Printf(" - %s", result.Error)
+	} else if result.Message != "" {
+		message, _, _ := strings.Cut(result.Message, "\n")
+		fmt.Printf(" - %s", message)
 	}
 	
 	fmt.Println()
 	
+	if len(result.Dependents) > 0 {
+		fmt.Printf("  Affected checks: %s\n", strings.Join(result.Dependents, ", "))
+	}
+	
+	if result.Maintenance != "" {
+		fmt.Printf("  🔧 In maintenance (%s), alerts suppressed\n", result.Maintenance)
+	}
+	
+	if result.Unconfirmed != "" {
+		fmt.Printf("  ⏳ Unconfirmed: %s\n", result.Unconfirmed)
+	}
+	
+	if result.Status == types.StatusFlapping {
+		fmt.Printf("  🔁 %d state changes in the flapping window\n", result.StateChanges)
+	}
+	
 	if verbose {
 		fmt.Printf("  URL: %s\n", result.URL)
 		if result.BodySize > 0 {
 			fmt.Printf("  Body Size: %d bytes\n", result.BodySize)
 		}
+		if result.BodyTruncated {
+			fmt.Println("  Body truncated at max_body_size")
+		}
+		if len(result.Families) > 0 {
+			fmt.Println("  Address families:")
+			for _, family := range result.Families {
+				fmt.Printf("    %s %s - %v", family.Status.Emoji(), family.Family, family.ResponseTime)
+				if family.StatusCode > 0 {
+					fmt.Printf(" (HTTP %d)", family.StatusCode)
+				}
+				if family.Error != "" {
+					fmt.Printf(" - %s", family.Error)
+				}
+				fmt.Println()
+			}
+		}
 		if len(result.Headers) > 0 {
 			fmt.Println("  Headers:")
 			for key, value := range result.Headers {
 				fmt.Printf("    %s: %s\n", key, value)
 			}
 		}
+		if t := result.Timings; t != nil {
+			fmt.Println("  Timings:")
+			if t.Reused {
+				fmt.Println("    Connection:  reused")
+			}
+			fmt.Printf("    DNS Lookup:  %v\n", t.DNS)
+			fmt.Printf("    TCP Connect: %v\n", t.Connect)
+			fmt.Printf("    TLS:         %v\n", t.TLS)
+			fmt.Printf("    TTFB:        %v\n", t.TTFB)
+			fmt.Printf("    Transfer:    %v\n", t.Transfer)
+		}
+		if broker := result.Broker; broker != nil {
+			fmt.Println("  Broker:")
+			if broker.Server != "" {
+				fmt.Printf("    Server:     %s\n", broker.Server)
+			}
+			if broker.Queue != "" {
+				fmt.Printf("    Queue:      %s (%d ready, %d consumers)\n", broker.Queue, broker.Messages, broker.Consumers)
+			}
+			if broker.RoundTrip > 0 {
+				fmt.Printf("    Round trip: %v\n", broker.RoundTrip)
+			}
+			if broker.Partitions > 0 {
+				fmt.Printf("    Brokers:    %d\n", broker.Brokers)
+				fmt.Printf("    Partitions: %d in %d topics (%d offline, %d under-replicated)\n",
+					broker.Partitions, broker.Topics, broker.OfflinePartitions, broker.UnderReplicated)
+			}
+		}
+		if mail := result.Mail; mail != nil {
+			fmt.Println("  Mail:")
+			fmt.Printf("    Banner:     %s\n", strings.ReplaceAll(mail.Banner, "\n", " / "))
+			if mail.StartTLS {
+				fmt.Println("    STARTTLS:   yes")
+			}
+			if len(mail.Extensions) > 0 {
+				fmt.Printf("    Extensions: %s\n", strings.Join(mail.Extensions, ", "))
+			}
+			if mail.Mailbox != "" {
+				fmt.Printf("    Mailbox:    %s (%d messages)\n", mail.Mailbox, mail.Messages)
+			}
+		}
+		if domain := result.Domain; domain != nil {
+			fmt.Println("  Domain:")
+			if domain.Registrar != "" {
+				fmt.Printf("    Registrar: %s\n", domain.Registrar)
+			}
+			if !domain.ExpiryDate.IsZero() {
+				fmt.Printf("    Expires:   %s (%d days)\n", domain.ExpiryDate.Format("2006-01-02"), domain.DaysToExpiry)
+			}
+			if len(domain.Statuses) > 0 {
+				fmt.Printf("    Status:    %s\n", strings.Join(domain.Statuses, ", "))
+			}
+			source := fmt.Sprintf("%s from %s", strings.ToUpper(domain.Source), domain.Server)
+			if domain.Cached {
+				source += " (cached)"
+			}
+			fmt.Printf("    Source:    %s\n", source)
+		}
+		if len(result.Containers) > 0 {
+			fmt.Println("  Containers:")
+			for _, container := range result.Containers {
+				state := container.State
+				if container.Health != "" {
+					state += ", " + container.Health
+				}
+				fmt.Printf("    %-20s %s %s (%s, %d restarts)\n", container.Name, container.ID, container.Image, state, container.RestartCount)
+			}
+		}
+		if stream := result.Stream; stream != nil {
+			fmt.Println("  Stream:")
+			fmt.Printf("    Handshake:     %v\n", stream.Handshake)
+			if stream.FirstMessage > 0 {
+				fmt.Printf("    First message: %v (%d received)\n", stream.FirstMessage, stream.Messages)
+			}
+			if stream.Event != "" {
+				fmt.Printf("    Event:         %s\n", stream.Event)
+			}
+			if stream.Message != "" {
+				fmt.Printf("    Message:       %s\n", strings.ReplaceAll(stream.Message, "\n", " / "))
+			}
+		}
+		if graphQL := result.GraphQL; graphQL != nil {
+			fmt.Printf("  GraphQL schema: %d types, sha256 %s\n", graphQL.Types, graphQL.SchemaHash)
+		}
+		if change := result.SchemaChange; change != nil {
+			fmt.Printf("  GraphQL schema changed: %s -> %s\n", change.PreviousHash, change.CurrentHash)
+		}
+		if content := result.Content; content != nil {
+			fmt.Printf("  Content: sha256 %s\n", content.Hash)
+		}
+		if change := result.ContentChange; change != nil {
+			fmt.Printf("  Content changed: +%d -%d lines\n", change.Added, change.Removed)
+			for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+		if strings.Contains(result.Message, "\n") {
+			fmt.Println("  Output:")
+			for _, line := range strings.Split(result.Message, "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+		if len(result.Metrics) > 0 {
+			fmt.Println("  Metrics:")
+			for _, metric := range result.Metrics {
+				fmt.Printf("    %s = %g%s", metric.Label, metric.Value, metric.Unit)
+				if metric.Warn != "" || metric.Crit != "" {
+					fmt.Printf(" (warn %s, crit %s)", metric.Warn, metric.Crit)
+				}
+				fmt.Println()
+			}
+		}
+		if cert := result.CertInfo; cert != nil {
+			fmt.Printf("  Certificate: %s (expires in %d days)\n", cert.CommonName, cert.DaysToExpiry)
+			if cert.Protocol != "" {
+				fmt.Printf("    Protocol: %s, Cipher: %s\n", cert.Protocol, cert.CipherSuite)
+			}
+			if cert.Fingerprint != "" {
+				fmt.Printf("    SHA-256: %s\n", cert.Fingerprint)
+				fmt.Printf("    SPKI:    sha256/%s\n", cert.SPKIHash)
+			}
+			for i, chainCert := range cert.Chain {
+				fmt.Printf("    %d. %s - %s %d bits, %s, expires in %d days\n", i, chainCert.Subject,
+					chainCert.KeyAlgorithm, chainCert.KeyBits, chainCert.SignatureAlgorithm, chainCert.DaysToExpiry)
+			}
+			for _, finding := range cert.Findings {
+				fmt.Printf("    [%s] %s\n", strings.ToUpper(finding.Severity), finding.Message)
+			}
+			if revocation := cert.Revocation; revocation != nil {
+				fmt.Printf("    Revocation: %s", revocation.Status)
+				if revocation.Source != "" {
+					fmt.Printf(" (via %s)", revocation.Source)
+				}
+				if revocation.Error != "" {
+					fmt.Printf(" - %s", revocation.Error)
+				}
+				fmt.Println()
+			}
+		}
+		if change := result.CertChange; change != nil {
+			fmt.Printf("  Certificate key changed: sha256/%s -> sha256/%s\n", change.PreviousSPKI, change.CurrentSPKI)
+		}
+		if len(result.Steps) > 0 {
+			fmt.Println("  Steps:")
+			for i, step := range result.Steps {
+				fmt.Printf("    %d. %s %s - %v", i+1, step.Status.Emoji(), step.Name, step.ResponseTime)
+				if step.StatusCode > 0 {
+					fmt.Printf(" (HTTP %d)", step.StatusCode)
+				}
+				if step.Error != "" {
+					fmt.Printf(" - %s", step.Error)
+				}
+				fmt.Println()
+			}
+		}
 		fmt.Println()
 	}
 }
@@ -406,6 +659,8 @@ func (a *Application) runTUIDashboard(checks []types.CheckConfig) error {
 	// Get memory configuration from config service
 	globalConfig := a.configService.GetGlobalConfig()
 	model := tui.NewWithConfig(globalConfig.MemoryManagement)
+	model.SetDependencies(checks)
+	a.healthCheckService.TrackDependencies(checks)
 	program := tea.NewProgram(model, tea.WithAltScreen())
 	
 	resultsChan := make(chan []types.Result, 10)
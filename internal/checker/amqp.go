@@ -0,0 +1,352 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// AMQP 0-9-1 protocol constants
+const (
+	amqpFrameMethod    = 1
+	amqpFrameHeartbeat = 8
+	amqpFrameEnd       = 0xce
+	amqpMaxFrame       = 1 << 20
+
+	amqpClassConnection = 10
+	amqpClassChannel    = 20
+	amqpClassQueue      = 50
+
+	amqpConnectionStart   = 10
+	amqpConnectionStartOk = 11
+	amqpConnectionTune    = 30
+	amqpConnectionTuneOk  = 31
+	amqpConnectionOpen    = 40
+	amqpConnectionOpenOk  = 41
+	amqpConnectionClose   = 50
+	amqpConnectionCloseOk = 51
+	amqpChannelOpen       = 10
+	amqpChannelOpenOk     = 11
+	amqpChannelClose      = 40
+	amqpQueueDeclare      = 10
+	amqpQueueDeclareOk    = 11
+
+	amqpQueuePassive = 0x01
+)
+
+var amqpProtocol = brokerProtocol{
+	endpointFormat: endpointFormat{
+		name:        "AMQP",
+		schemes:     []string{"amqp", "amqps"},
+		defaultPort: "5672",
+		schemePorts: map[string]string{"amqps": "5671"},
+	},
+	inspect: inspectAMQP,
+}
+
+// amqpError is a Connection.Close or Channel.Close sent by the server
+type amqpError struct {
+	code uint16
+	text string
+}
+
+func (e *amqpError) Error() string {
+	return fmt.Sprintf("%d %s", e.code, e.text)
+}
+
+// amqpConn reads and writes frames
+type amqpConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// inspectAMQP opens a connection and a channel, then passively declares the
+// configured queue to read its depth. amqps:// URLs connect over TLS; the
+// URL path is the virtual host, "/" when empty.
+func inspectAMQP(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, options types.BrokerConfig) (*types.BrokerInfo, error) {
+	if target.scheme == "amqps" {
+		var err error
+		if conn, err = startTLS(ctx, conn, tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+	amqp := &amqpConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if _, err := io.WriteString(conn, "AMQP\x00\x00\x09\x01"); err != nil {
+		return nil, err
+	}
+
+	info := &types.BrokerInfo{}
+	if err := amqp.open(target, info); err != nil {
+		return nil, err
+	}
+
+	if err := amqp.call(1, amqpClassChannel, amqpChannelOpen, []byte{0}, amqpClassChannel, amqpChannelOpenOk); err != nil {
+		return nil, fmt.Errorf("opening channel failed: %w", err)
+	}
+
+	if options.Queue != "" {
+		info.Queue = options.Queue
+		var args amqpBuffer
+		args.uint16(0)
+		args.shortstr(options.Queue)
+		args.WriteByte(amqpQueuePassive)
+		args.uint32(0) // empty arguments table
+		if err := amqp.writeMethod(1, amqpClassQueue, amqpQueueDeclare, args.Bytes()); err != nil {
+			return nil, err
+		}
+		reply, err := amqp.expect(amqpClassQueue, amqpQueueDeclareOk)
+		if err != nil {
+			return nil, fmt.Errorf("queue '%s' unavailable: %w", options.Queue, err)
+		}
+		reply.shortstr()
+		info.Messages = int64(reply.uint32())
+		info.Consumers = int64(reply.uint32())
+		if reply.err != nil {
+			return nil, fmt.Errorf("malformed Queue.DeclareOk: %w", reply.err)
+		}
+	}
+
+	var closeArgs amqpBuffer
+	closeArgs.uint16(200)
+	closeArgs.shortstr("OK")
+	closeArgs.uint16(0)
+	closeArgs.uint16(0)
+	amqp.call(0, amqpClassConnection, amqpConnectionClose, closeArgs.Bytes(), amqpClassConnection, amqpConnectionCloseOk)
+
+	return info, nil
+}
+
+// open performs the connection handshake: Start, Tune and Open
+func (a *amqpConn) open(target *endpoint, info *types.BrokerInfo) error {
+	start, err := a.expect(amqpClassConnection, amqpConnectionStart)
+	if err != nil {
+		return fmt.Errorf("handshake failed: %w", err)
+	}
+	start.uint8()
+	start.uint8()
+	properties := start.table()
+	mechanisms := string(start.longstr())
+	if start.err != nil {
+		return fmt.Errorf("malformed Connection.Start: %w", start.err)
+	}
+	info.Server = strings.TrimSpace(properties["product"] + " " + properties["version"])
+	if !strings.Contains(" "+mechanisms+" ", " PLAIN ") {
+		return fmt.Errorf("server does not offer PLAIN authentication (offers %s)", mechanisms)
+	}
+
+	user, password := target.user, target.password
+	if user == "" {
+		user, password = "guest", "guest"
+	}
+
+	var startOk amqpBuffer
+	var capabilities amqpBuffer
+	capabilities.shortstr("authentication_failure_close")
+	capabilities.WriteString("t\x01")
+	var clientProperties amqpBuffer
+	clientProperties.shortstr("product")
+	clientProperties.WriteByte('S')
+	clientProperties.longstr([]byte("healthcheck-cli"))
+	clientProperties.shortstr("capabilities")
+	clientProperties.WriteByte('F')
+	clientProperties.longstr(capabilities.Bytes())
+	startOk.longstr(clientProperties.Bytes())
+	startOk.shortstr("PLAIN")
+	startOk.longstr([]byte("\x00" + user + "\x00" + password))
+	startOk.shortstr("en_US")
+	if err := a.writeMethod(0, amqpClassConnection, amqpConnectionStartOk, startOk.Bytes()); err != nil {
+		return err
+	}
+
+	tune, err := a.expect(amqpClassConnection, amqpConnectionTune)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("authentication failed: server closed the connection")
+		}
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	channelMax := tune.uint16()
+	frameMax := tune.uint32()
+	if tune.err != nil {
+		return fmt.Errorf("malformed Connection.Tune: %w", tune.err)
+	}
+	if frameMax == 0 || frameMax > amqpMaxFrame {
+		frameMax = amqpMaxFrame
+	}
+	var tuneOk amqpBuffer
+	tuneOk.uint16(channelMax)
+	tuneOk.uint32(frameMax)
+	tuneOk.uint16(0) // no heartbeats for a short-lived connection
+	if err := a.writeMethod(0, amqpClassConnection, amqpConnectionTuneOk, tuneOk.Bytes()); err != nil {
+		return err
+	}
+
+	vhost := target.path
+	if vhost == "" {
+		vhost = "/"
+	}
+	var open amqpBuffer
+	open.shortstr(vhost)
+	open.shortstr("")
+	open.WriteByte(0)
+	if err := a.call(0, amqpClassConnection, amqpConnectionOpen, open.Bytes(), amqpClassConnection, amqpConnectionOpenOk); err != nil {
+		return fmt.Errorf("opening vhost '%s' failed: %w", vhost, err)
+	}
+	return nil
+}
+
+// call sends a method and waits for the expected reply
+func (a *amqpConn) call(channel, class, method uint16, args []byte, replyClass, replyMethod uint16) error {
+	if err := a.writeMethod(channel, class, method, args); err != nil {
+		return err
+	}
+	_, err := a.expect(replyClass, replyMethod)
+	return err
+}
+
+// expect reads the next method, turning Connection.Close and Channel.Close
+// into errors
+func (a *amqpConn) expect(class, method uint16) (*amqpReader, error) {
+	gotClass, gotMethod, args, err := a.readMethod()
+	if err != nil {
+		return nil, err
+	}
+	if (gotClass == amqpClassConnection && gotMethod == amqpConnectionClose) ||
+		(gotClass == amqpClassChannel && gotMethod == amqpChannelClose) {
+		code := args.uint16()
+		text := args.shortstr()
+		return nil, &amqpError{code: code, text: text}
+	}
+	if gotClass != class || gotMethod != method {
+		return nil, fmt.Errorf("unexpected method %d.%d, expected %d.%d", gotClass, gotMethod, class, method)
+	}
+	return args, nil
+}
+
+// readMethod reads frames until a method frame arrives, skipping heartbeats
+func (a *amqpConn) readMethod() (uint16, uint16, *amqpReader, error) {
+	for {
+		header := make([]byte, 7)
+		if _, err := io.ReadFull(a.reader, header); err != nil {
+			return 0, 0, nil, err
+		}
+		size := binary.BigEndian.Uint32(header[3:])
+		if size > amqpMaxFrame {
+			return 0, 0, nil, fmt.Errorf("frame of %d bytes exceeds limit", size)
+		}
+		payload := make([]byte, size+1)
+		if _, err := io.ReadFull(a.reader, payload); err != nil {
+			return 0, 0, nil, err
+		}
+		if payload[size] != amqpFrameEnd {
+			return 0, 0, nil, fmt.Errorf("malformed frame end")
+		}
+
+		switch header[0] {
+		case amqpFrameMethod:
+			args := &amqpReader{wireReader{data: payload[:size]}}
+			class, method := args.uint16(), args.uint16()
+			if args.err != nil {
+				return 0, 0, nil, fmt.Errorf("malformed method frame")
+			}
+			return class, method, args, nil
+		case amqpFrameHeartbeat:
+			continue
+		default:
+			return 0, 0, nil, fmt.Errorf("unexpected frame type %d", header[0])
+		}
+	}
+}
+
+// writeMethod sends one method frame
+func (a *amqpConn) writeMethod(channel, class, method uint16, args []byte) error {
+	frame := make([]byte, 11, 12+len(args))
+	frame[0] = amqpFrameMethod
+	binary.BigEndian.PutUint16(frame[1:], channel)
+	binary.BigEndian.PutUint32(frame[3:], uint32(4+len(args)))
+	binary.BigEndian.PutUint16(frame[7:], class)
+	binary.BigEndian.PutUint16(frame[9:], method)
+	frame = append(frame, args...)
+	frame = append(frame, amqpFrameEnd)
+	_, err := a.conn.Write(frame)
+	return err
+}
+
+// amqpBuffer encodes method arguments
+type amqpBuffer struct {
+	bytes.Buffer
+}
+
+func (b *amqpBuffer) uint16(v uint16) {
+	b.Write(binary.BigEndian.AppendUint16(nil, v))
+}
+
+func (b *amqpBuffer) uint32(v uint32) {
+	b.Write(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (b *amqpBuffer) shortstr(s string) {
+	b.WriteByte(byte(len(s)))
+	b.WriteString(s)
+}
+
+func (b *amqpBuffer) longstr(s []byte) {
+	b.uint32(uint32(len(s)))
+	b.Write(s)
+}
+
+// amqpReader decodes method arguments
+type amqpReader struct {
+	wireReader
+}
+
+func (r *amqpReader) shortstr() string {
+	return string(r.take(int(r.uint8())))
+}
+
+func (r *amqpReader) longstr() []byte {
+	return r.take(int(r.uint32()))
+}
+
+// table decodes a field table, keeping only its string values
+func (r *amqpReader) table() map[string]string {
+	fields := &amqpReader{wireReader{data: r.longstr()}}
+	values := make(map[string]string)
+	for r.err == nil && fields.err == nil && len(fields.data) > 0 {
+		name := fields.shortstr()
+		kind := fields.uint8()
+		switch kind {
+		case 'S':
+			values[name] = string(fields.longstr())
+		case 'x', 'A', 'F':
+			fields.longstr()
+		case 't', 'b', 'B':
+			fields.take(1)
+		case 's', 'u':
+			fields.take(2)
+		case 'I', 'i', 'f':
+			fields.take(4)
+		case 'l', 'L', 'd', 'T':
+			fields.take(8)
+		case 'D':
+			fields.take(5)
+		case 'V':
+		default:
+			fields.err = fmt.Errorf("unknown field type %q", kind)
+		}
+	}
+	if r.err == nil {
+		r.err = fields.err
+	}
+	return values
+}
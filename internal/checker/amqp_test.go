@@ -0,0 +1,200 @@
+package checker
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAMQP is a minimal RabbitMQ that accepts one user on the default vhost
+type fakeAMQP struct {
+	user     string
+	password string
+	queues   map[string][2]uint32 // name -> messages, consumers
+}
+
+func (f *fakeAMQP) serve(conn net.Conn) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(conn, header); err != nil || string(header) != "AMQP\x00\x00\x09\x01" {
+		return
+	}
+	amqp := &amqpConn{conn: conn, reader: bufio.NewReader(conn)}
+	closeWith := func(channel, class, method uint16, code uint16, text string) {
+		var args amqpBuffer
+		args.uint16(code)
+		args.shortstr(text)
+		args.uint16(0)
+		args.uint16(0)
+		amqp.writeMethod(channel, class, method, args.Bytes())
+	}
+
+	var properties amqpBuffer
+	properties.shortstr("capabilities")
+	properties.WriteByte('F')
+	properties.longstr([]byte("\x09publisher\x74\x01"))
+	properties.shortstr("product")
+	properties.WriteByte('S')
+	properties.longstr([]byte("RabbitMQ"))
+	properties.shortstr("version")
+	properties.WriteByte('S')
+	properties.longstr([]byte("3.13.0"))
+	var start amqpBuffer
+	start.Write([]byte{0, 9})
+	start.longstr(properties.Bytes())
+	start.longstr([]byte("AMQPLAIN PLAIN"))
+	start.longstr([]byte("en_US"))
+	amqp.writeMethod(0, amqpClassConnection, amqpConnectionStart, start.Bytes())
+
+	startOk, err := amqp.expect(amqpClassConnection, amqpConnectionStartOk)
+	if err != nil {
+		return
+	}
+	startOk.table()
+	startOk.shortstr()
+	if string(startOk.longstr()) != "\x00"+f.user+"\x00"+f.password {
+		closeWith(0, amqpClassConnection, amqpConnectionClose, 403, "ACCESS_REFUSED - Login was refused using authentication mechanism PLAIN")
+		return
+	}
+
+	var tune amqpBuffer
+	tune.uint16(2047)
+	tune.uint32(131072)
+	tune.uint16(60)
+	amqp.writeMethod(0, amqpClassConnection, amqpConnectionTune, tune.Bytes())
+	if _, err := amqp.expect(amqpClassConnection, amqpConnectionTuneOk); err != nil {
+		return
+	}
+
+	open, err := amqp.expect(amqpClassConnection, amqpConnectionOpen)
+	if err != nil {
+		return
+	}
+	if vhost := open.shortstr(); vhost != "/" {
+		closeWith(0, amqpClassConnection, amqpConnectionClose, 530, "NOT_ALLOWED - vhost "+vhost+" not found")
+		return
+	}
+	amqp.writeMethod(0, amqpClassConnection, amqpConnectionOpenOk, []byte{0})
+
+	for {
+		class, method, args, err := amqp.readMethod()
+		if err != nil {
+			return
+		}
+		switch {
+		case class == amqpClassChannel && method == amqpChannelOpen:
+			amqp.writeMethod(1, amqpClassChannel, amqpChannelOpenOk, []byte{0, 0, 0, 0})
+		case class == amqpClassQueue && method == amqpQueueDeclare:
+			args.uint16()
+			name := args.shortstr()
+			counts, ok := f.queues[name]
+			if !ok {
+				closeWith(1, amqpClassChannel, amqpChannelClose, 404, "NOT_FOUND - no queue '"+name+"' in vhost '/'")
+				continue
+			}
+			var declareOk amqpBuffer
+			declareOk.shortstr(name)
+			declareOk.uint32(counts[0])
+			declareOk.uint32(counts[1])
+			amqp.writeMethod(1, amqpClassQueue, amqpQueueDeclareOk, declareOk.Bytes())
+		case class == amqpClassConnection && method == amqpConnectionClose:
+			amqp.writeMethod(0, amqpClassConnection, amqpConnectionCloseOk, nil)
+			return
+		}
+	}
+}
+
+func TestAMQPChecker(t *testing.T) {
+	server := &fakeAMQP{
+		user:     "monitor",
+		password: "secret",
+		queues:   map[string][2]uint32{"orders": {1500, 2}},
+	}
+	addr := startFakeBroker(t, server.serve)
+
+	tests := []struct {
+		name          string
+		url           string
+		broker        types.BrokerConfig
+		expected      types.Expected
+		wantStatus    types.Status
+		wantError     string
+		wantMessages  int64
+		wantConsumers int64
+	}{
+		{
+			name:       "connection and channel",
+			url:        "amqp://monitor:secret@" + addr,
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:          "queue depth",
+			url:           "amqp://monitor:secret@" + addr + "/%2f",
+			broker:        types.BrokerConfig{Queue: "orders"},
+			expected:      types.Expected{QueueDepthMax: 5000, ConsumersMin: 1},
+			wantStatus:    types.StatusUp,
+			wantMessages:  1500,
+			wantConsumers: 2,
+		},
+		{
+			name:          "queue too deep",
+			url:           "amqp://monitor:secret@" + addr,
+			broker:        types.BrokerConfig{Queue: "orders"},
+			expected:      types.Expected{QueueDepthMax: 1000},
+			wantStatus:    types.StatusWarning,
+			wantError:     "Queue 'orders' has 1500 messages ready, maximum 1000",
+			wantMessages:  1500,
+			wantConsumers: 2,
+		},
+		{
+			name:       "missing queue",
+			url:        "amqp://monitor:secret@" + addr,
+			broker:     types.BrokerConfig{Queue: "missing"},
+			wantStatus: types.StatusDown,
+			wantError:  "queue 'missing' unavailable: 404 NOT_FOUND",
+		},
+		{
+			name:       "wrong password",
+			url:        "amqp://monitor:wrong@" + addr,
+			wantStatus: types.StatusDown,
+			wantError:  "authentication failed: 403 ACCESS_REFUSED",
+		},
+		{
+			name:       "unknown vhost",
+			url:        "amqp://monitor:secret@" + addr + "/staging",
+			wantStatus: types.StatusDown,
+			wantError:  "opening vhost 'staging' failed: 530 NOT_ALLOWED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewAMQPChecker(5 * time.Second).Check(types.CheckConfig{
+				Name:     "rabbit",
+				Type:     types.CheckTypeAMQP,
+				URL:      tt.url,
+				Timeout:  5 * time.Second,
+				Broker:   tt.broker,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			assert.NotContains(t, result.URL, "secret")
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+			if tt.wantStatus == types.StatusDown {
+				return
+			}
+			if assert.NotNil(t, result.Broker) {
+				assert.Equal(t, "RabbitMQ 3.13.0", result.Broker.Server)
+				assert.Equal(t, tt.wantMessages, result.Broker.Messages)
+				assert.Equal(t, tt.wantConsumers, result.Broker.Consumers)
+			}
+		})
+	}
+}
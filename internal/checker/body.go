@@ -0,0 +1,161 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// DefaultMaxBodySize is the response body limit used when a check does not set one
+const DefaultMaxBodySize int64 = 10 << 20 // 10 MiB
+
+// bodyChunkSize is the read size used when streaming response bodies
+const bodyChunkSize = 32 << 10
+
+// responseBody holds what was learned from reading a response body
+type responseBody struct {
+	data        []byte // nil unless the body had to be buffered
+	size        int64
+	truncated   bool
+	skipped     bool
+	contains    *streamMatcher
+	notContains *streamMatcher
+}
+
+// bodyOptions controls how a response body is consumed
+type bodyOptions struct {
+	limit  int64 // maximum bytes to read, 0 means DefaultMaxBodySize
+	buffer bool  // keep the body in memory even if no assertion needs it
+	skip   bool  // do not read the body when no assertion needs it
+}
+
+// readBody consumes a response body in bounded chunks.
+// Substring assertions are matched while streaming, so the body is only kept in
+// memory when an assertion needs all of it (regex, JSON schema) or the caller asks.
+func readBody(r io.Reader, expected types.Expected, opts bodyOptions) (*responseBody, error) {
+	body := &responseBody{}
+
+	if expected.BodyContains != "" {
+		body.contains = newStreamMatcher(expected.BodyContains)
+	}
+	if expected.BodyNotContains != "" {
+		body.notContains = newStreamMatcher(expected.BodyNotContains)
+	}
+
+	if opts.skip && !needsBody(expected) && !opts.buffer {
+		body.skipped = true
+		return body, nil
+	}
+
+	limit := opts.limit
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	var buffer *bytes.Buffer
+	if opts.buffer || needsBufferedBody(expected) {
+		buffer = &bytes.Buffer{}
+	}
+
+	chunk := make([]byte, bodyChunkSize)
+	for {
+		remaining := limit - body.size
+		if remaining <= 0 {
+			// Probe for one more byte to tell "exactly at the limit" from "truncated"
+			var probe [1]byte
+			if n, _ := r.Read(probe[:]); n > 0 {
+				body.truncated = true
+			}
+			break
+		}
+
+		readSize := int64(len(chunk))
+		if remaining < readSize {
+			readSize = remaining
+		}
+
+		n, err := r.Read(chunk[:readSize])
+		if n > 0 {
+			data := chunk[:n]
+			body.size += int64(n)
+			if buffer != nil {
+				buffer.Write(data)
+			}
+			if body.contains != nil {
+				body.contains.feed(data)
+			}
+			if body.notContains != nil {
+				body.notContains.feed(data)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return body, err
+		}
+	}
+
+	if buffer != nil {
+		body.data = buffer.Bytes()
+	}
+
+	return body, nil
+}
+
+// truncationNote explains that an assertion only saw part of the body
+func (b *responseBody) truncationNote() string {
+	if b.truncated {
+		return fmt.Sprintf(" (body truncated at %d bytes)", b.size)
+	}
+	return ""
+}
+
+// needsBody reports whether any assertion inspects the response body
+func needsBody(expected types.Expected) bool {
+	return expected.BodyContains != "" ||
+		expected.BodyNotContains != "" ||
+		expected.MinBodySize > 0 ||
+		needsBufferedBody(expected)
+}
+
+// needsBufferedBody reports whether an assertion needs the whole body in memory
+func needsBufferedBody(expected types.Expected) bool {
+	return expected.BodyRegex != "" || expected.JSONSchema != "" || len(expected.JSON) > 0 || expected.ContentAlertOnChange
+}
+
+// streamMatcher finds a substring in data that arrives in chunks
+type streamMatcher struct {
+	needle []byte
+	tail   []byte
+	found  bool
+}
+
+func newStreamMatcher(needle string) *streamMatcher {
+	return &streamMatcher{needle: []byte(needle)}
+}
+
+// feed scans the next chunk, keeping enough of the previous one to catch
+// matches that straddle a chunk boundary
+func (m *streamMatcher) feed(chunk []byte) {
+	if m.found || len(m.needle) == 0 {
+		m.found = true
+		return
+	}
+
+	window := append(m.tail, chunk...)
+	if bytes.Contains(window, m.needle) {
+		m.found = true
+		m.tail = nil
+		return
+	}
+
+	keep := len(m.needle) - 1
+	if len(window) > keep {
+		window = window[len(window)-keep:]
+	}
+	m.tail = append(m.tail[:0], window...)
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBody_Limit(t *testing.T) {
+	tests := []struct {
+		name          string
+		size          int
+		limit         int64
+		wantSize      int64
+		wantTruncated bool
+	}{
+		{name: "UnderLimit", size: 100, limit: 1000, wantSize: 100},
+		{name: "ExactlyAtLimit", size: 1000, limit: 1000, wantSize: 1000},
+		{name: "OverLimit", size: 5000, limit: 1000, wantSize: 1000, wantTruncated: true},
+		{name: "DefaultLimit", size: 100, limit: 0, wantSize: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bytes.NewReader(bytes.Repeat([]byte("a"), tt.size))
+			body, err := readBody(r, types.Expected{}, bodyOptions{limit: tt.limit, buffer: true})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSize, body.size)
+			assert.Equal(t, tt.wantTruncated, body.truncated)
+			assert.Len(t, body.data, int(tt.wantSize))
+		})
+	}
+}
+
+func TestReadBody_StreamingContains(t *testing.T) {
+	// The needle straddles the boundary between the first and second chunk
+	padding := strings.Repeat("x", bodyChunkSize-3)
+	content := padding + "healthy" + strings.Repeat("y", bodyChunkSize)
+
+	expected := types.Expected{BodyContains: "healthy", BodyNotContains: "error"}
+	body, err := readBody(iotest.HalfReader(strings.NewReader(content)), expected, bodyOptions{})
+	require.NoError(t, err)
+
+	assert.True(t, body.contains.found)
+	assert.False(t, body.notContains.found)
+	assert.Nil(t, body.data, "substring assertions should not buffer the body")
+	assert.Equal(t, int64(len(content)), body.size)
+}
+
+func TestReadBody_BuffersForRegexAndSchema(t *testing.T) {
+	for _, expected := range []types.Expected{{BodyRegex: "ok"}, {JSONSchema: "schema.json"}} {
+		body, err := readBody(strings.NewReader(`{"status":"ok"}`), expected, bodyOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, `{"status":"ok"}`, string(body.data))
+	}
+}
+
+func TestReadBody_Skip(t *testing.T) {
+	body, err := readBody(strings.NewReader("ignored"), types.Expected{}, bodyOptions{skip: true})
+	require.NoError(t, err)
+	assert.True(t, body.skipped)
+	assert.Zero(t, body.size)
+
+	// Body assertions force a read even when skipping is requested
+	body, err = readBody(strings.NewReader("ok"), types.Expected{BodyContains: "ok"}, bodyOptions{skip: true})
+	require.NoError(t, err)
+	assert.False(t, body.skipped)
+	assert.True(t, body.contains.found)
+}
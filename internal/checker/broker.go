@@ -0,0 +1,154 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// brokerProtocol describes how to probe one kind of message broker
+type brokerProtocol struct {
+	endpointFormat
+
+	// inspect authenticates on conn and collects the broker's metrics.
+	// tlsConfig carries the check's TLS options; the protocol decides from the
+	// URL whether to use it.
+	inspect func(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, options types.BrokerConfig) (*types.BrokerInfo, error)
+}
+
+// BrokerChecker connects to a message broker and checks it can serve clients
+type BrokerChecker struct {
+	timeout  time.Duration
+	protocol brokerProtocol
+	tls      *tlsCache
+	dialers  *dialerCache
+}
+
+// NewAMQPChecker creates an AMQP 0-9-1 (RabbitMQ) checker
+func NewAMQPChecker(timeout time.Duration) *BrokerChecker {
+	return newBrokerChecker(timeout, amqpProtocol)
+}
+
+// NewMQTTChecker creates an MQTT 3.1.1 checker
+func NewMQTTChecker(timeout time.Duration) *BrokerChecker {
+	return newBrokerChecker(timeout, mqttProtocol)
+}
+
+// NewKafkaChecker creates a Kafka checker
+func NewKafkaChecker(timeout time.Duration) *BrokerChecker {
+	return newBrokerChecker(timeout, kafkaProtocol)
+}
+
+func newBrokerChecker(timeout time.Duration, protocol brokerProtocol) *BrokerChecker {
+	return &BrokerChecker{
+		timeout:  timeout,
+		protocol: protocol,
+		tls:      newTLSCache(),
+		dialers:  newDialerCache(),
+	}
+}
+
+// Name returns the checker name
+func (b *BrokerChecker) Name() string {
+	return b.protocol.name
+}
+
+// Check connects to the broker, runs the protocol probe and applies the
+// configured thresholds
+func (b *BrokerChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       security.RedactURLCredentials(check.URL),
+		Timestamp: start,
+	}
+
+	target, err := parseEndpoint(check.URL, b.protocol.endpointFormat)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Invalid URL: %v", err)
+		return result
+	}
+
+	dialer, err := b.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		return result
+	}
+
+	tlsConfig, err := b.tls.forHost(check, target.host)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", target.address())
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s connection failed: %v", b.protocol.name, err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	info, err := b.protocol.inspect(ctx, conn, target, tlsConfig, check.Broker)
+	result.ResponseTime = time.Since(start)
+	result.Broker = info
+
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s: %v", b.protocol.name, err)
+		return result
+	}
+
+	result.Status, result.Error = evaluateBroker(info, check.Expected)
+	if result.Status == types.StatusUp && check.Expected.ResponseTimeMax > 0 && result.ResponseTime > check.Expected.ResponseTimeMax {
+		result.Status = types.StatusSlow
+		result.Error = fmt.Sprintf("Response time %v exceeds maximum %v", result.ResponseTime, check.Expected.ResponseTimeMax)
+	}
+	return result
+}
+
+// evaluateBroker applies the broker thresholds, most severe first
+func evaluateBroker(info *types.BrokerInfo, expected types.Expected) (types.Status, string) {
+	if info.OfflinePartitions > 0 {
+		return types.StatusDown, fmt.Sprintf("%d of %d partitions have no leader", info.OfflinePartitions, info.Partitions)
+	}
+
+	if expected.BrokersMin > 0 && info.Brokers < expected.BrokersMin {
+		return types.StatusWarning, fmt.Sprintf("%d brokers available, expected at least %d", info.Brokers, expected.BrokersMin)
+	}
+	if info.UnderReplicated > 0 {
+		return types.StatusWarning, fmt.Sprintf("%d of %d partitions are under-replicated", info.UnderReplicated, info.Partitions)
+	}
+	if info.Queue != "" {
+		if expected.QueueDepthMax > 0 && info.Messages > expected.QueueDepthMax {
+			return types.StatusWarning, fmt.Sprintf("Queue '%s' has %d messages ready, maximum %d", info.Queue, info.Messages, expected.QueueDepthMax)
+		}
+		if expected.ConsumersMin > 0 && info.Consumers < expected.ConsumersMin {
+			return types.StatusWarning, fmt.Sprintf("Queue '%s' has %d consumers, expected at least %d", info.Queue, info.Consumers, expected.ConsumersMin)
+		}
+	}
+
+	if expected.RoundTripMax > 0 && info.RoundTrip > expected.RoundTripMax {
+		return types.StatusSlow, fmt.Sprintf("Round trip %v exceeds maximum %v", info.RoundTrip, expected.RoundTripMax)
+	}
+
+	return types.StatusUp, ""
+}
@@ -0,0 +1,279 @@
+package checker
+
+import (
+	"bytes"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// Minimum key sizes below which a certificate key is reported as weak
+const (
+	minRSAKeyBits   = 2048
+	minECDSAKeyBits = 256
+)
+
+// chainAudit collects findings for a presented certificate chain
+type chainAudit struct {
+	certs      []*x509.Certificate
+	serverName string
+	expected   types.Expected
+	now        time.Time
+	findings   []types.CertFinding
+}
+
+// describeChain summarises every presented certificate, leaf first
+func describeChain(certs []*x509.Certificate, now time.Time) []types.ChainCert {
+	chain := make([]types.ChainCert, 0, len(certs))
+	for _, cert := range certs {
+		algorithm, bits := publicKeyInfo(cert)
+		chain = append(chain, types.ChainCert{
+			Subject:            cert.Subject.String(),
+			Issuer:             cert.Issuer.String(),
+			ExpiryDate:         cert.NotAfter,
+			DaysToExpiry:       int(cert.NotAfter.Sub(now).Hours() / 24),
+			KeyAlgorithm:       algorithm,
+			KeyBits:            bits,
+			SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+			IsCA:               cert.IsCA,
+		})
+	}
+	return chain
+}
+
+// Bounds on the intermediates downloaded to complete a chain
+const (
+	maxIssuerFetches  = 3
+	maxIssuerCertSize = 64 << 10
+)
+
+// issuerFetcher downloads the certificate published at a CA Issuers URL
+type issuerFetcher func(url string) ([]byte, error)
+
+// verifyChain checks the presented chain against the trusted roots and returns
+// the verified chain, leaf first. A chain that only verifies once the
+// intermediates named in its CA Issuers URLs are downloaded is reported as
+// incomplete, which is audited as a finding rather than a hard failure. Any
+// other failure, such as a leaf from an untrusted CA, is returned as err.
+func verifyChain(certs []*x509.Certificate, roots *x509.CertPool, now time.Time, fetchIssuer issuerFetcher) (chain []*x509.Certificate, incomplete bool, err error) {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	options := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	}
+
+	chains, err := certs[0].Verify(options)
+	if err == nil {
+		return chains[0], false, nil
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	if !errors.As(err, &unknownAuthority) || fetchIssuer == nil {
+		return nil, false, err
+	}
+
+	// Follow the CA Issuers URLs from the last presented certificate towards a
+	// trusted root. Only a chain that then verifies is merely incomplete.
+	current := certs[len(certs)-1]
+	for i := 0; i < maxIssuerFetches && !isSelfSigned(current); i++ {
+		url := firstURL("", current.IssuingCertificateURL)
+		if url == "" {
+			break
+		}
+		data, fetchErr := fetchIssuer(url)
+		if fetchErr != nil {
+			break
+		}
+		issuer, parseErr := parseIssuerCert(data)
+		if parseErr != nil || current.CheckSignatureFrom(issuer) != nil {
+			break
+		}
+
+		intermediates.AddCert(issuer)
+		if chains, verifyErr := certs[0].Verify(options); verifyErr == nil {
+			return chains[0], true, nil
+		}
+		current = issuer
+	}
+
+	return nil, false, err
+}
+
+// parseIssuerCert parses a downloaded issuer certificate, DER or PEM encoded
+func parseIssuerCert(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil && block.Type == "CERTIFICATE" {
+		data = block.Bytes
+	}
+	return x509.ParseCertificate(data)
+}
+
+// findIssuer returns the certificate that issued leaf, preferring the verified
+// chain and falling back to the certificates the server presented
+func findIssuer(leaf *x509.Certificate, verified, presented []*x509.Certificate) *x509.Certificate {
+	if len(verified) > 1 {
+		return verified[1]
+	}
+	if isSelfSigned(leaf) {
+		return nil
+	}
+	for _, cert := range presented[1:] {
+		if bytes.Equal(cert.RawSubject, leaf.RawIssuer) && leaf.CheckSignatureFrom(cert) == nil {
+			return cert
+		}
+	}
+	return nil
+}
+
+// auditHostname reports a leaf that does not cover the target name
+func (a *chainAudit) auditHostname() {
+	if a.serverName == "" {
+		return
+	}
+	if err := a.certs[0].VerifyHostname(a.serverName); err != nil {
+		a.add(types.FindingHostnameMismatch, "certificate does not match host '%s' (SANs: %s)",
+			a.serverName, strings.Join(a.certs[0].DNSNames, ", "))
+	}
+}
+
+// auditIncomplete reports a chain that only reached a trusted root through
+// intermediates the server did not send
+func (a *chainAudit) auditIncomplete() {
+	last := a.certs[len(a.certs)-1]
+	a.add(types.FindingChainIncomplete, "chain is incomplete: issuer '%s' was not sent by the server (server must send its intermediates)",
+		last.Issuer.String())
+}
+
+// auditCertificates checks key strength, signatures and intermediate expiry
+func (a *chainAudit) auditCertificates() {
+	for i, cert := range a.certs {
+		label := "leaf"
+		if i > 0 {
+			label = fmt.Sprintf("chain[%d]", i)
+		}
+
+		algorithm, bits := publicKeyInfo(cert)
+		if isWeakKey(cert, bits) {
+			a.add(types.FindingWeakKey, "%s certificate '%s' uses a weak %s key (%d bits)",
+				label, cert.Subject.CommonName, algorithm, bits)
+		}
+
+		// A root's self-signature is never checked, so its algorithm does not matter
+		if isSHA1Signature(cert.SignatureAlgorithm) && !isSelfSigned(cert) {
+			a.add(types.FindingSHA1Signature, "%s certificate '%s' is signed with %s",
+				label, cert.Subject.CommonName, cert.SignatureAlgorithm)
+		}
+
+		if i == 0 {
+			continue
+		}
+		days := int(cert.NotAfter.Sub(a.now).Hours() / 24)
+		switch {
+		case a.now.After(cert.NotAfter):
+			a.add(types.FindingIntermediateExpiry, "%s certificate '%s' expired on %s",
+				label, cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02"))
+		case a.expected.CertExpiryDays > 0 && days <= a.expected.CertExpiryDays:
+			a.add(types.FindingIntermediateExpiry, "%s certificate '%s' expires in %d days (threshold: %d days)",
+				label, cert.Subject.CommonName, days, a.expected.CertExpiryDays)
+		}
+	}
+}
+
+// add records a finding with the severity configured for its type
+func (a *chainAudit) add(findingType, format string, args ...interface{}) {
+	a.findings = append(a.findings, types.CertFinding{
+		Type:     findingType,
+		Severity: findingSeverity(findingType, a.expected),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// findingSeverity returns the configured severity for a finding type, falling
+// back to DOWN for trust problems and WARNING for hygiene problems
+func findingSeverity(findingType string, expected types.Expected) string {
+	configured, fallback := "", types.FindingSeverityWarning
+	switch findingType {
+	case types.FindingChainIncomplete:
+		configured, fallback = expected.CertChainIncomplete, types.FindingSeverityDown
+	case types.FindingHostnameMismatch:
+		configured, fallback = expected.CertHostnameMismatch, types.FindingSeverityDown
+	case types.FindingWeakKey:
+		configured = expected.CertWeakKey
+	case types.FindingSHA1Signature:
+		configured = expected.CertSHA1Signature
+	case types.FindingIntermediateExpiry:
+		configured = expected.CertIntermediateExpiry
+	}
+
+	if configured != "" {
+		return strings.ToLower(configured)
+	}
+	return fallback
+}
+
+// findingsError joins the messages of findings with the given severity
+func findingsError(findings []types.CertFinding, severity string) error {
+	var messages []string
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			messages = append(messages, finding.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+// publicKeyInfo returns the key algorithm and size of a certificate
+func publicKeyInfo(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	case *dsa.PublicKey:
+		return "DSA", key.P.BitLen()
+	default:
+		return cert.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// isWeakKey reports keys below current minimum sizes; DSA is always weak
+func isWeakKey(cert *x509.Certificate, bits int) bool {
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return bits < minRSAKeyBits
+	case *ecdsa.PublicKey:
+		return bits < minECDSAKeyBits
+	case *dsa.PublicKey:
+		return true
+	}
+	return false
+}
+
+func isSHA1Signature(algorithm x509.SignatureAlgorithm) bool {
+	switch algorithm {
+	case x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return true
+	}
+	return false
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject)
+}
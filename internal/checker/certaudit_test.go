@@ -0,0 +1,274 @@
+package checker
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// issueCert creates a certificate signed by parent, or self-signed when parent is nil
+func issueCert(t *testing.T, template *x509.Certificate, key crypto.Signer, parent *testCert) *testCert {
+	t.Helper()
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(365 * 24 * time.Hour)
+	}
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, key.Public(), signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func newECKey(t *testing.T) crypto.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func caTemplate(name string) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+}
+
+func leafTemplate(name string) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		DNSNames:    []string{name},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+}
+
+// startChainServer serves the given chain (leaf first) and returns the server
+// URL and the path of a CA file holding the root
+func startChainServer(t *testing.T, root *testCert, leafKey crypto.Signer, chain ...*x509.Certificate) (string, string) {
+	t.Helper()
+
+	certificate := tls.Certificate{PrivateKey: leafKey}
+	for _, cert := range chain {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caPath := filepath.Join(t.TempDir(), "root.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.cert.Raw}), 0600))
+	return server.URL, caPath
+}
+
+func TestSSLChecker_ChainAudit(t *testing.T) {
+	root := issueCert(t, caTemplate("Test Root"), newECKey(t), nil)
+
+	intermediateTemplate := caTemplate("Test Intermediate")
+	intermediateTemplate.NotAfter = time.Now().Add(10 * 24 * time.Hour)
+	intermediate := issueCert(t, intermediateTemplate, newECKey(t), root)
+
+	// The intermediate is published at the leaf's CA Issuers URL
+	issuers := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(intermediate.cert.Raw)
+	}))
+	t.Cleanup(issuers.Close)
+
+	leafKey := newECKey(t)
+	aiaTemplate := leafTemplate("service.test")
+	aiaTemplate.IssuingCertificateURL = []string{issuers.URL + "/intermediate.der"}
+	leaf := issueCert(t, aiaTemplate, leafKey, intermediate)
+	bareLeaf := issueCert(t, leafTemplate("service.test"), leafKey, intermediate)
+
+	// A leaf from a CA nobody trusts, as an interception proxy would present
+	rogue := issueCert(t, caTemplate("Rogue CA"), newECKey(t), nil)
+	rogueLeaf := issueCert(t, leafTemplate("service.test"), leafKey, rogue)
+
+	fullURL, caPath := startChainServer(t, root, leafKey, leaf.cert, intermediate.cert)
+	leafOnlyURL, _ := startChainServer(t, root, leafKey, leaf.cert)
+	bareLeafURL, _ := startChainServer(t, root, leafKey, bareLeaf.cert)
+	rogueURL, _ := startChainServer(t, root, leafKey, rogueLeaf.cert)
+
+	tests := []struct {
+		name         string
+		url          string
+		serverName   string
+		expected     types.Expected
+		wantStatus   types.Status
+		wantFindings []string
+		errMsg       string
+	}{
+		{
+			name:       "CompleteChain",
+			url:        fullURL,
+			serverName: "service.test",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:         "IncompleteChainDefaultsToDown",
+			url:          leafOnlyURL,
+			serverName:   "service.test",
+			wantStatus:   types.StatusDown,
+			wantFindings: []string{types.FindingChainIncomplete},
+			errMsg:       "chain is incomplete",
+		},
+		{
+			name:         "IncompleteChainAsWarning",
+			url:          leafOnlyURL,
+			serverName:   "service.test",
+			expected:     types.Expected{CertChainIncomplete: "warning"},
+			wantStatus:   types.StatusWarning,
+			wantFindings: []string{types.FindingChainIncomplete},
+		},
+		{
+			name:       "MissingIssuerIsUntrusted",
+			url:        bareLeafURL,
+			serverName: "service.test",
+			expected:   types.Expected{CertChainIncomplete: "warning"},
+			wantStatus: types.StatusDown,
+			errMsg:     "Certificate verification failed",
+		},
+		{
+			name:       "UntrustedCAIsNotIncomplete",
+			url:        rogueURL,
+			serverName: "service.test",
+			expected:   types.Expected{CertChainIncomplete: "ignore"},
+			wantStatus: types.StatusDown,
+			errMsg:     "Certificate verification failed",
+		},
+		{
+			name:         "HostnameMismatch",
+			url:          fullURL,
+			serverName:   "other.test",
+			wantStatus:   types.StatusDown,
+			wantFindings: []string{types.FindingHostnameMismatch},
+			errMsg:       "does not match host 'other.test'",
+		},
+		{
+			name:         "HostnameMismatchIgnored",
+			url:          fullURL,
+			serverName:   "other.test",
+			expected:     types.Expected{CertHostnameMismatch: "ignore"},
+			wantStatus:   types.StatusUp,
+			wantFindings: []string{types.FindingHostnameMismatch},
+		},
+		{
+			name:         "IntermediateOutsideThreshold",
+			url:          fullURL,
+			serverName:   "service.test",
+			expected:     types.Expected{CertExpiryDays: 5, CertIntermediateExpiry: "down"},
+			wantStatus:   types.StatusUp,
+			wantFindings: nil,
+		},
+		{
+			name:         "IntermediateWithinThreshold",
+			url:          fullURL,
+			serverName:   "service.test",
+			expected:     types.Expected{CertExpiryDays: 30, CertIntermediateExpiry: "down"},
+			wantStatus:   types.StatusDown,
+			wantFindings: []string{types.FindingIntermediateExpiry},
+			errMsg:       "Test Intermediate",
+		},
+	}
+
+	checker := NewSSLChecker(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{
+				Name:     tt.name,
+				URL:      tt.url,
+				Timeout:  5 * time.Second,
+				TLS:      types.TLSConfig{CAFile: caPath, ServerName: tt.serverName},
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.errMsg != "" {
+				assert.Contains(t, result.Error, tt.errMsg)
+			}
+
+			require.NotNil(t, result.CertInfo)
+			var findings []string
+			for _, finding := range result.CertInfo.Findings {
+				findings = append(findings, finding.Type)
+			}
+			assert.Equal(t, tt.wantFindings, findings)
+			assert.NotEmpty(t, result.CertInfo.Protocol)
+			assert.NotEmpty(t, result.CertInfo.CipherSuite)
+		})
+	}
+}
+
+func TestSSLChecker_ChainDetails(t *testing.T) {
+	root := issueCert(t, caTemplate("Test Root"), newECKey(t), nil)
+	intermediate := issueCert(t, caTemplate("Test Intermediate"), newECKey(t), root)
+
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	leaf := issueCert(t, leafTemplate("service.test"), weakKey, intermediate)
+
+	url, caPath := startChainServer(t, root, weakKey, leaf.cert, intermediate.cert)
+
+	checker := NewSSLChecker(5 * time.Second)
+	result := checker.Check(types.CheckConfig{
+		Name:    "weak key",
+		URL:     url,
+		Timeout: 5 * time.Second,
+		TLS:     types.TLSConfig{CAFile: caPath, ServerName: "service.test"},
+	})
+
+	assert.Equal(t, types.StatusWarning, result.Status, result.Error)
+	assert.Contains(t, result.Error, "weak RSA key (1024 bits)")
+
+	require.NotNil(t, result.CertInfo)
+	require.Len(t, result.CertInfo.Chain, 2)
+	assert.Equal(t, "RSA", result.CertInfo.Chain[0].KeyAlgorithm)
+	assert.Equal(t, 1024, result.CertInfo.Chain[0].KeyBits)
+	assert.True(t, result.CertInfo.Chain[1].IsCA)
+	assert.Equal(t, "ECDSA", result.CertInfo.Chain[1].KeyAlgorithm)
+}
+
+func TestIsSHA1Signature(t *testing.T) {
+	assert.True(t, isSHA1Signature(x509.SHA1WithRSA))
+	assert.True(t, isSHA1Signature(x509.ECDSAWithSHA1))
+	assert.False(t, isSHA1Signature(x509.SHA256WithRSA))
+	assert.False(t, isSHA1Signature(x509.PureEd25519))
+}
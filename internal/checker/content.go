@@ -0,0 +1,44 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// contentFingerprint normalizes a response body and hashes it. Regions matching
+// the ignore regexes are removed first, then line endings are unified, trailing
+// whitespace is trimmed and blank lines are dropped, so that formatting-only
+// changes do not count as new content.
+func contentFingerprint(data []byte, ignore []string) (*types.ContentInfo, error) {
+	text := string(data)
+	for _, pattern := range ignore {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content_ignore regex '%s': %w", pattern, err)
+		}
+		text = re.ReplaceAllString(text, "")
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	normalized := strings.Join(lines, "\n")
+
+	sum := sha256.Sum256([]byte(normalized))
+	return &types.ContentInfo{
+		Hash: hex.EncodeToString(sum[:]),
+		Text: normalized,
+	}, nil
+}
@@ -0,0 +1,200 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// maxQueryOutput caps how much of a query result is kept for assertions
+const maxQueryOutput = 1 << 20
+
+// databaseProtocol describes how to talk to one kind of database
+type databaseProtocol struct {
+	endpointFormat
+	defaultQuery string
+
+	// query authenticates on conn, runs the query and writes its result to out.
+	// tlsConfig carries the check's TLS options; the protocol decides from the
+	// URL whether to use it.
+	query func(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, query string, out *queryOutput) error
+}
+
+// queryOutput collects a query result as text, one row per line with
+// tab-separated columns
+type queryOutput struct {
+	strings.Builder
+	truncated bool
+}
+
+// line appends one line, dropping it once the output limit is reached
+func (o *queryOutput) line(text string) {
+	if o.truncated || o.Len()+len(text)+1 > maxQueryOutput {
+		o.truncated = true
+		return
+	}
+	if o.Len() > 0 {
+		o.WriteByte('\n')
+	}
+	o.WriteString(text)
+}
+
+// row appends a row of columns
+func (o *queryOutput) row(columns []string) {
+	o.line(strings.Join(columns, "\t"))
+}
+
+// DatabaseChecker connects to a database, runs a query and asserts on the result
+type DatabaseChecker struct {
+	timeout  time.Duration
+	protocol databaseProtocol
+	tls      *tlsCache
+	dialers  *dialerCache
+}
+
+// NewPostgresChecker creates a PostgreSQL checker
+func NewPostgresChecker(timeout time.Duration) *DatabaseChecker {
+	return newDatabaseChecker(timeout, postgresProtocol)
+}
+
+// NewMySQLChecker creates a MySQL checker
+func NewMySQLChecker(timeout time.Duration) *DatabaseChecker {
+	return newDatabaseChecker(timeout, mysqlProtocol)
+}
+
+// NewRedisChecker creates a Redis checker
+func NewRedisChecker(timeout time.Duration) *DatabaseChecker {
+	return newDatabaseChecker(timeout, redisProtocol)
+}
+
+func newDatabaseChecker(timeout time.Duration, protocol databaseProtocol) *DatabaseChecker {
+	return &DatabaseChecker{
+		timeout:  timeout,
+		protocol: protocol,
+		tls:      newTLSCache(),
+		dialers:  newDialerCache(),
+	}
+}
+
+// Name returns the checker name
+func (d *DatabaseChecker) Name() string {
+	return d.protocol.name
+}
+
+// Check connects, authenticates and runs the configured query
+func (d *DatabaseChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       security.RedactURLCredentials(check.URL),
+		Timestamp: start,
+	}
+
+	target, err := parseEndpoint(check.URL, d.protocol.endpointFormat)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Invalid URL: %v", err)
+		return result
+	}
+
+	dialer, err := d.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		return result
+	}
+
+	target.tlsConfigured = !check.TLS.IsZero()
+	tlsConfig, err := d.tls.forHost(check, target.host)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", target.address())
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s connection failed: %v", d.protocol.name, err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	query := check.Query
+	if query == "" {
+		query = d.protocol.defaultQuery
+	}
+
+	var output queryOutput
+	err = d.protocol.query(ctx, conn, target, tlsConfig, query, &output)
+	result.ResponseTime = time.Since(start)
+	result.BodySize = int64(output.Len())
+	result.BodyTruncated = output.truncated
+
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s: %v", d.protocol.name, err)
+		return result
+	}
+
+	if err := validateQueryOutput(&output, check.Expected); err != nil {
+		result.Status = types.StatusDown
+		result.Error = err.Error()
+		return result
+	}
+
+	if check.Expected.ResponseTimeMax > 0 && result.ResponseTime > check.Expected.ResponseTimeMax {
+		result.Status = types.StatusSlow
+		result.Error = fmt.Sprintf("Query time %v exceeds maximum %v", result.ResponseTime, check.Expected.ResponseTimeMax)
+		return result
+	}
+
+	result.Status = types.StatusUp
+	return result
+}
+
+// validateQueryOutput checks a query result against the expected criteria
+func validateQueryOutput(output *queryOutput, expected types.Expected) error {
+	note := ""
+	if output.truncated {
+		note = fmt.Sprintf(" (only the first %d bytes were inspected)", maxQueryOutput)
+	}
+	text := output.String()
+
+	if expected.BodyContains != "" && !strings.Contains(text, expected.BodyContains) {
+		return fmt.Errorf("query result does not contain '%s'%s", expected.BodyContains, note)
+	}
+
+	if expected.BodyNotContains != "" && strings.Contains(text, expected.BodyNotContains) {
+		return fmt.Errorf("query result contains unwanted content '%s'", expected.BodyNotContains)
+	}
+
+	if expected.BodyRegex != "" {
+		re, err := regexp.Compile(expected.BodyRegex)
+		if err != nil {
+			return fmt.Errorf("invalid body regex '%s': %w", expected.BodyRegex, err)
+		}
+		if !re.MatchString(text) {
+			return fmt.Errorf("query result does not match regex '%s'%s", expected.BodyRegex, note)
+		}
+	}
+
+	return nil
+}
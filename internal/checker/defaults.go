@@ -0,0 +1,42 @@
+package checker
+
+import (
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// NewDefaultCheckers returns a checker for every built-in check type. Network
+// checkers are wrapped to probe both address families when asked to. The exec
+// checker allows no commands until replaced by one built with the configured
+// allowed_commands.
+func NewDefaultCheckers() map[types.CheckType]interfaces.Checker {
+	httpChecker := NewHTTPChecker(30 * time.Second)
+	return map[types.CheckType]interfaces.Checker{
+		types.CheckTypeHTTP:      NewDualStackChecker(httpChecker),
+		types.CheckTypeTCP:       NewDualStackChecker(NewTCPChecker(10 * time.Second)),
+		types.CheckTypeSSL:       NewDualStackChecker(NewSSLChecker(10 * time.Second)),
+		types.CheckTypeScenario:  NewScenarioChecker(httpChecker),
+		types.CheckTypePostgres:  NewDualStackChecker(NewPostgresChecker(10 * time.Second)),
+		types.CheckTypeMySQL:     NewDualStackChecker(NewMySQLChecker(10 * time.Second)),
+		types.CheckTypeRedis:     NewDualStackChecker(NewRedisChecker(10 * time.Second)),
+		types.CheckTypeAMQP:      NewDualStackChecker(NewAMQPChecker(10 * time.Second)),
+		types.CheckTypeMQTT:      NewDualStackChecker(NewMQTTChecker(10 * time.Second)),
+		types.CheckTypeKafka:     NewDualStackChecker(NewKafkaChecker(10 * time.Second)),
+		types.CheckTypeSMTP:      NewDualStackChecker(NewSMTPChecker(10 * time.Second)),
+		types.CheckTypeIMAP:      NewDualStackChecker(NewIMAPChecker(10 * time.Second)),
+		types.CheckTypePOP3:      NewDualStackChecker(NewPOP3Checker(10 * time.Second)),
+		types.CheckTypeExec:      NewExecChecker(10*time.Second, nil),
+		types.CheckTypeWebSocket: NewDualStackChecker(NewWebSocketChecker(10 * time.Second)),
+		types.CheckTypeSSE:       NewDualStackChecker(NewSSEChecker(10 * time.Second)),
+		types.CheckTypeGraphQL:   NewDualStackChecker(NewGraphQLChecker(httpChecker)),
+		types.CheckTypeDomain:    NewDomainChecker(10 * time.Second),
+		types.CheckTypeDisk:      NewDiskChecker(),
+		types.CheckTypeMemory:    NewMemoryChecker(),
+		types.CheckTypeLoad:      NewLoadChecker(),
+		types.CheckTypeProcess:   NewProcessChecker(),
+		types.CheckTypeFile:      NewFileChecker(),
+		types.CheckTypeDocker:    NewDockerChecker(10 * time.Second),
+	}
+}
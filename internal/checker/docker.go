@@ -0,0 +1,372 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+const (
+	defaultDockerSocket    = "/var/run/docker.sock"
+	maxDockerResponseSize  = 4 << 20
+	maxDockerHealthMessage = 200
+)
+
+// errContainerNotFound means the Engine API has no container with the configured name or ID
+var errContainerNotFound = errors.New("no such container")
+
+// DockerChecker inspects containers through the Docker Engine API on a unix
+// socket and reports their state, restart count and HEALTHCHECK status
+type DockerChecker struct {
+	timeout time.Duration
+
+	mu       sync.Mutex
+	clients  map[string]*http.Client   // per socket path
+	restarts map[string]map[string]int // last restart count per check, by container ID
+}
+
+// dockerContainer is the part of GET /containers/{id}/json read by the checker
+type dockerContainer struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	Config       struct {
+		Image string `json:"Image"`
+	} `json:"Config"`
+	State struct {
+		Status     string    `json:"Status"`
+		Restarting bool      `json:"Restarting"`
+		ExitCode   int       `json:"ExitCode"`
+		StartedAt  time.Time `json:"StartedAt"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				ExitCode int    `json:"ExitCode"`
+				Output   string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+// NewDockerChecker creates a new Docker container checker
+func NewDockerChecker(timeout time.Duration) *DockerChecker {
+	return &DockerChecker{
+		timeout:  timeout,
+		clients:  make(map[string]*http.Client),
+		restarts: make(map[string]map[string]int),
+	}
+}
+
+// Name returns the checker name
+func (d *DockerChecker) Name() string {
+	return "Docker"
+}
+
+// Check inspects the container named by docker.container, or every container
+// matching docker.labels, and reports every one that is not healthy
+func (d *DockerChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       check.URL,
+		Timestamp: start,
+	}
+
+	config := check.Docker
+	if config.Container == "" && len(config.Labels) == 0 {
+		result.Status = types.StatusError
+		result.Error = "No container or labels configured"
+		return result
+	}
+
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = d.timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client := d.client(dockerSocketPath(config.Socket))
+	containers, err := d.inspect(ctx, client, config)
+	result.ResponseTime = time.Since(start)
+	if err == nil || errors.Is(err, errContainerNotFound) {
+		d.forgetRestarts(check.Name, containers)
+	}
+	if err != nil {
+		result.Status = types.StatusDown
+		if errors.Is(err, errContainerNotFound) {
+			result.Error = fmt.Sprintf("Container %s does not exist", config.Container)
+		} else {
+			result.Error = fmt.Sprintf("Docker API request failed: %v", err)
+		}
+		return result
+	}
+	if len(containers) == 0 {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("No container matches labels %s", formatLabels(config.Labels))
+		return result
+	}
+
+	result.Status = types.StatusUp
+	var problems []string
+	for _, container := range containers {
+		info := containerInfo(container)
+		result.Containers = append(result.Containers, info)
+
+		status, problem := d.evaluate(check, container)
+		if problem == "" {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("Container %s %s", info.Name, problem))
+		if status == types.StatusDown || result.Status == types.StatusUp {
+			result.Status = status
+		}
+	}
+
+	if len(problems) > 0 {
+		result.Error = strings.Join(problems, "; ")
+		return result
+	}
+	if len(containers) == 1 {
+		result.Message = containerSummary(result.Containers[0])
+	} else {
+		result.Message = fmt.Sprintf("%d containers running", len(containers))
+	}
+	return result
+}
+
+// evaluate applies the state, health and restart expectations to a container
+func (d *DockerChecker) evaluate(check types.CheckConfig, container dockerContainer) (types.Status, string) {
+	// The restart count is compared with the previous run, so it is recorded even for failing containers
+	d.mu.Lock()
+	counts, ok := d.restarts[check.Name]
+	if !ok {
+		counts = make(map[string]int)
+		d.restarts[check.Name] = counts
+	}
+	previous, seen := counts[container.ID]
+	counts[container.ID] = container.RestartCount
+	d.mu.Unlock()
+
+	restarted := 0
+	if seen && container.RestartCount > previous {
+		restarted = container.RestartCount - previous
+	}
+
+	state := container.State
+	switch {
+	case state.Restarting:
+		return types.StatusDown, fmt.Sprintf("is restarting (restart count %d, last exit code %d)", container.RestartCount, state.ExitCode)
+	case state.Status != "running":
+		if state.Status == "exited" {
+			return types.StatusDown, fmt.Sprintf("is exited (exit code %d)", state.ExitCode)
+		}
+		return types.StatusDown, fmt.Sprintf("is %s", state.Status)
+	case state.Health != nil && state.Health.Status == "unhealthy":
+		problem := fmt.Sprintf("is unhealthy (%d failed health checks)", state.Health.FailingStreak)
+		if logs := state.Health.Log; len(logs) > 0 {
+			if output := strings.TrimSpace(logs[len(logs)-1].Output); output != "" {
+				problem += ": " + truncateString(output, maxDockerHealthMessage)
+			}
+		}
+		return types.StatusDown, problem
+	case restarted > check.Expected.RestartsMax:
+		// A container caught running between restarts is still in a restart loop
+		if check.Expected.RestartsMax == 0 {
+			return types.StatusDown, fmt.Sprintf("restart count rose by %d since the last check", restarted)
+		}
+		return types.StatusDown, fmt.Sprintf("restart count rose by %d since the last check (maximum %d)", restarted, check.Expected.RestartsMax)
+	case state.Health != nil && state.Health.Status == "starting":
+		return types.StatusWarning, "is still starting, its health check has not passed yet"
+	}
+	return types.StatusUp, ""
+}
+
+// forgetRestarts drops the restart counts a check keeps for containers that
+// are gone, such as containers replaced by a redeploy
+func (d *DockerChecker) forgetRestarts(checkName string, containers []dockerContainer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(containers) == 0 {
+		delete(d.restarts, checkName)
+		return
+	}
+	for id := range d.restarts[checkName] {
+		if !slices.ContainsFunc(containers, func(container dockerContainer) bool { return container.ID == id }) {
+			delete(d.restarts[checkName], id)
+		}
+	}
+}
+
+// inspect returns the configured container, or the containers matching the
+// label selector, including stopped ones
+func (d *DockerChecker) inspect(ctx context.Context, client *http.Client, config types.DockerConfig) ([]dockerContainer, error) {
+	if config.Container != "" {
+		var container dockerContainer
+		if err := dockerGet(ctx, client, "/containers/"+url.PathEscape(config.Container)+"/json", &container); err != nil {
+			return nil, err
+		}
+		return []dockerContainer{container}, nil
+	}
+
+	filters, err := json.Marshal(map[string][]string{"label": labelSelector(config.Labels)})
+	if err != nil {
+		return nil, err
+	}
+
+	var listed []struct {
+		ID string `json:"Id"`
+	}
+	query := url.Values{"all": {"1"}, "filters": {string(filters)}}
+	if err := dockerGet(ctx, client, "/containers/json?"+query.Encode(), &listed); err != nil {
+		return nil, err
+	}
+
+	containers := make([]dockerContainer, 0, len(listed))
+	for _, entry := range listed {
+		var container dockerContainer
+		if err := dockerGet(ctx, client, "/containers/"+entry.ID+"/json", &container); err != nil {
+			// Containers removed between the list and the inspect are skipped
+			if errors.Is(err, errContainerNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// client returns the HTTP client talking to the Engine API on socket
+func (d *DockerChecker) client(socket string) *http.Client {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if client, ok := d.clients[socket]; ok {
+		return client
+	}
+	dialer := &net.Dialer{}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+			MaxIdleConns:    2,
+			IdleConnTimeout: 90 * time.Second,
+		},
+	}
+	d.clients[socket] = client
+	return client
+}
+
+// dockerGet sends a GET request to the Engine API and decodes the JSON reply into out
+func dockerGet(ctx context.Context, client *http.Client, path string, out interface{}) error {
+	// The host is ignored by the unix socket dialer
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDockerResponseSize))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errContainerNotFound
+	case resp.StatusCode != http.StatusOK:
+		// Engine API errors are {"message": "..."}
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiError) == nil && apiError.Message != "" {
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, apiError.Message)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// dockerSocketPath accepts a plain path or a unix:// URL, as in DOCKER_HOST
+func dockerSocketPath(socket string) string {
+	if socket == "" {
+		return defaultDockerSocket
+	}
+	return strings.TrimPrefix(socket, "unix://")
+}
+
+// containerInfo reduces an inspected container to what results report
+func containerInfo(container dockerContainer) types.ContainerInfo {
+	info := types.ContainerInfo{
+		ID:           container.ID,
+		Name:         strings.TrimPrefix(container.Name, "/"),
+		Image:        container.Config.Image,
+		State:        container.State.Status,
+		RestartCount: container.RestartCount,
+		StartedAt:    container.State.StartedAt,
+	}
+	if len(info.ID) > 12 {
+		info.ID = info.ID[:12]
+	}
+	if container.State.Health != nil {
+		info.Health = container.State.Health.Status
+	}
+	return info
+}
+
+// containerSummary describes a healthy container, e.g. "web (nginx:1.25) running for 3h0m0s, healthy"
+func containerSummary(info types.ContainerInfo) string {
+	summary := fmt.Sprintf("%s (%s) %s", info.Name, info.Image, info.State)
+	if !info.StartedAt.IsZero() {
+		summary += fmt.Sprintf(" for %s", time.Since(info.StartedAt).Truncate(time.Second))
+	}
+	if info.Health != "" {
+		summary += ", " + info.Health
+	}
+	return summary
+}
+
+// labelSelector turns labels into Engine API label filters, "key" or "key=value", in a stable order
+func labelSelector(labels map[string]string) []string {
+	var filters []string
+	for key, value := range labels {
+		if value == "" {
+			filters = append(filters, key)
+		} else {
+			filters = append(filters, key+"="+value)
+		}
+	}
+	sort.Strings(filters)
+	return filters
+}
+
+// formatLabels formats a label selector for messages
+func formatLabels(labels map[string]string) string {
+	return strings.Join(labelSelector(labels), ",")
+}
@@ -0,0 +1,279 @@
+package checker
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEngine serves the container list and inspect endpoints of the Docker Engine API
+type fakeEngine struct {
+	mu         sync.Mutex
+	containers map[string]map[string]interface{} // inspect replies by ID
+	labels     map[string][]string               // labels per ID, as key=value
+	filters    string                            // last filters query
+}
+
+// startFakeEngine serves the Engine API on a unix socket in a temporary directory
+func startFakeEngine(t *testing.T) (*fakeEngine, string) {
+	t.Helper()
+
+	engine := &fakeEngine{
+		containers: make(map[string]map[string]interface{}),
+		labels:     make(map[string][]string),
+	}
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: http.HandlerFunc(engine.serve)}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return engine, socket
+}
+
+// add registers a container; state holds the State fields of the inspect reply
+func (f *fakeEngine) add(id, name string, restarts int, state map[string]interface{}, labels ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers[id] = map[string]interface{}{
+		"Id":           id,
+		"Name":         "/" + name,
+		"RestartCount": restarts,
+		"Config":       map[string]interface{}{"Image": "nginx:1.25"},
+		"State":        state,
+	}
+	f.labels[id] = labels
+}
+
+func (f *fakeEngine) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/containers/json" {
+		f.filters = r.URL.Query().Get("filters")
+		var filters map[string][]string
+		json.Unmarshal([]byte(f.filters), &filters)
+
+		listed := []map[string]string{}
+		for id, labels := range f.labels {
+			if matchesAll(labels, filters["label"]) {
+				listed = append(listed, map[string]string{"Id": id})
+			}
+		}
+		json.NewEncoder(w).Encode(listed)
+		return
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/json")
+	for key, container := range f.containers {
+		if key == id || container["Name"] == "/"+id {
+			json.NewEncoder(w).Encode(container)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"message": "No such container: ` + id + `"}`))
+}
+
+func matchesAll(labels, filters []string) bool {
+	for _, filter := range filters {
+		found := false
+		for _, label := range labels {
+			if label == filter || strings.HasPrefix(label, filter+"=") {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func running(health string) map[string]interface{} {
+	state := map[string]interface{}{
+		"Status":    "running",
+		"StartedAt": time.Now().Add(-time.Hour).Format(time.RFC3339Nano),
+	}
+	if health != "" {
+		state["Health"] = map[string]interface{}{
+			"Status":        health,
+			"FailingStreak": 3,
+			"Log":           []map[string]interface{}{{"ExitCode": 1, "Output": "curl: (7) Failed to connect\n"}},
+		}
+	}
+	return state
+}
+
+func dockerCheck(socket string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "web container",
+		Type:    types.CheckTypeDocker,
+		URL:     "web",
+		Timeout: 5 * time.Second,
+		Docker:  types.DockerConfig{Socket: "unix://" + socket, Container: "web"},
+	}
+}
+
+func TestDockerChecker_Container(t *testing.T) {
+	engine, socket := startFakeEngine(t)
+	engine.add("4f3c2b1a0e9d8c7b6a5f", "web", 2, running("healthy"))
+
+	result := NewDockerChecker(5 * time.Second).Check(dockerCheck(socket))
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.Len(t, result.Containers, 1)
+	container := result.Containers[0]
+	assert.Equal(t, "4f3c2b1a0e9d", container.ID)
+	assert.Equal(t, "web", container.Name)
+	assert.Equal(t, "nginx:1.25", container.Image)
+	assert.Equal(t, "running", container.State)
+	assert.Equal(t, "healthy", container.Health)
+	assert.Equal(t, 2, container.RestartCount)
+	assert.True(t, strings.HasPrefix(result.Message, "web (nginx:1.25) running for 1h0m"), result.Message)
+	assert.True(t, strings.HasSuffix(result.Message, ", healthy"), result.Message)
+}
+
+func TestDockerChecker_Status(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      map[string]interface{}
+		wantStatus types.Status
+		wantErr    string
+	}{
+		{
+			name:       "unhealthy",
+			state:      running("unhealthy"),
+			wantStatus: types.StatusDown,
+			wantErr:    "Container web is unhealthy (3 failed health checks): curl: (7) Failed to connect",
+		},
+		{
+			name:       "starting",
+			state:      running("starting"),
+			wantStatus: types.StatusWarning,
+			wantErr:    "Container web is still starting, its health check has not passed yet",
+		},
+		{
+			name:       "exited",
+			state:      map[string]interface{}{"Status": "exited", "ExitCode": 137},
+			wantStatus: types.StatusDown,
+			wantErr:    "Container web is exited (exit code 137)",
+		},
+		{
+			name:       "restart loop",
+			state:      map[string]interface{}{"Status": "restarting", "Restarting": true, "ExitCode": 1},
+			wantStatus: types.StatusDown,
+			wantErr:    "Container web is restarting (restart count 5, last exit code 1)",
+		},
+		{
+			name:       "paused",
+			state:      map[string]interface{}{"Status": "paused"},
+			wantStatus: types.StatusDown,
+			wantErr:    "Container web is paused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, socket := startFakeEngine(t)
+			engine.add("4f3c2b1a0e9d8c7b6a5f", "web", 5, tt.state)
+
+			result := NewDockerChecker(5 * time.Second).Check(dockerCheck(socket))
+
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantErr, result.Error)
+		})
+	}
+}
+
+func TestDockerChecker_Restarts(t *testing.T) {
+	engine, socket := startFakeEngine(t)
+	checker := NewDockerChecker(5 * time.Second)
+	check := dockerCheck(socket)
+
+	engine.add("4f3c2b1a0e9d8c7b6a5f", "web", 4, running(""))
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+
+	// Without restarts_max any restart since the last run is DOWN
+	engine.add("4f3c2b1a0e9d8c7b6a5f", "web", 5, running(""))
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "Container web restart count rose by 1 since the last check", result.Error)
+
+	check.Expected.RestartsMax = 2
+	engine.add("4f3c2b1a0e9d8c7b6a5f", "web", 7, running(""))
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+
+	engine.add("4f3c2b1a0e9d8c7b6a5f", "web", 10, running(""))
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "Container web restart count rose by 3 since the last check (maximum 2)", result.Error)
+
+	// A replaced container starts over, and the old one is forgotten
+	engine.mu.Lock()
+	delete(engine.containers, "4f3c2b1a0e9d8c7b6a5f")
+	engine.mu.Unlock()
+	engine.add("9e8d7c6b5a4f3e2d1c0b", "web", 0, running(""))
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, map[string]int{"9e8d7c6b5a4f3e2d1c0b": 0}, checker.restarts[check.Name])
+
+	engine.mu.Lock()
+	delete(engine.containers, "9e8d7c6b5a4f3e2d1c0b")
+	engine.mu.Unlock()
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Empty(t, checker.restarts)
+}
+
+func TestDockerChecker_Labels(t *testing.T) {
+	engine, socket := startFakeEngine(t)
+	engine.add("aaaaaaaaaaaa0001", "api-1", 0, running("healthy"), "app=api", "tier=backend")
+	engine.add("aaaaaaaaaaaa0002", "api-2", 0, running("unhealthy"), "app=api", "tier=backend")
+	engine.add("bbbbbbbbbbbb0001", "db", 0, running(""), "app=db", "tier=backend")
+
+	check := dockerCheck(socket)
+	check.Docker.Container = ""
+	check.Docker.Labels = map[string]string{"tier": "", "app": "api"}
+
+	result := NewDockerChecker(5 * time.Second).Check(check)
+
+	assert.Equal(t, `{"label":["app=api","tier"]}`, engine.filters)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Len(t, result.Containers, 2)
+	assert.Equal(t, "Container api-2 is unhealthy (3 failed health checks): curl: (7) Failed to connect", result.Error)
+
+	check.Docker.Labels = map[string]string{"app": "worker"}
+	result = NewDockerChecker(5 * time.Second).Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "No container matches labels app=worker", result.Error)
+}
+
+func TestDockerChecker_Errors(t *testing.T) {
+	_, socket := startFakeEngine(t)
+
+	check := dockerCheck(socket)
+	check.Docker.Container = "missing"
+	result := NewDockerChecker(5 * time.Second).Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "Container missing does not exist", result.Error)
+
+	check.Docker.Socket = filepath.Join(t.TempDir(), "absent.sock")
+	result = NewDockerChecker(5 * time.Second).Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "Docker API request failed")
+}
@@ -0,0 +1,400 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// Lookup sources reported in types.DomainInfo
+const (
+	domainSourceRDAP  = "rdap"
+	domainSourceWHOIS = "whois"
+)
+
+const (
+	defaultDomainCacheTTL = 6 * time.Hour
+	rdapBootstrapTTL      = 24 * time.Hour
+	maxRDAPResponseSize   = 1 << 20
+)
+
+// rdapBootstrapURL is the IANA registry of RDAP services per TLD, used when
+// a check has no rdap_server
+var rdapBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+// errDomainNotFound means the registry answered but has no record of the domain
+var errDomainNotFound = errors.New("domain is not registered")
+
+// DomainChecker looks up domain registration data over RDAP, falling back to
+// WHOIS, and warns before the registration expires
+type DomainChecker struct {
+	timeout time.Duration
+	dialers *dialerCache
+
+	mu        sync.Mutex
+	lookups   map[string]cachedDomain
+	bootstrap map[string][]string // TLD to RDAP base URLs
+	fetched   time.Time           // when bootstrap was downloaded
+}
+
+type cachedDomain struct {
+	info    types.DomainInfo
+	expires time.Time
+}
+
+// NewDomainChecker creates a new domain registration checker
+func NewDomainChecker(timeout time.Duration) *DomainChecker {
+	return &DomainChecker{
+		timeout: timeout,
+		dialers: newDialerCache(),
+		lookups: make(map[string]cachedDomain),
+	}
+}
+
+// Name returns the checker name
+func (d *DomainChecker) Name() string {
+	return "Domain"
+}
+
+// Check looks up the registration of the domain named by the check URL
+func (d *DomainChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       check.URL,
+		Timestamp: start,
+	}
+
+	domain, err := parseDomain(check.URL)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Invalid domain: %v", err)
+		return result
+	}
+
+	dialer, err := d.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		return result
+	}
+
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = d.timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	info, err := d.lookup(ctx, dialer, domain, check.Domain)
+	result.ResponseTime = time.Since(start)
+	if err != nil {
+		result.Status = types.StatusDown
+		if errors.Is(err, errDomainNotFound) {
+			result.Error = fmt.Sprintf("Domain %s is not registered", domain)
+		} else {
+			result.Error = fmt.Sprintf("Domain lookup failed: %v", err)
+		}
+		return result
+	}
+
+	if !info.ExpiryDate.IsZero() {
+		info.DaysToExpiry = int(info.ExpiryDate.Sub(start).Hours() / 24)
+	}
+	result.Domain = info
+
+	result.Status, result.Error = evaluateDomain(info, check.Expected)
+	return result
+}
+
+// evaluateDomain applies the expiry and lock expectations to a lookup
+func evaluateDomain(info *types.DomainInfo, expected types.Expected) (types.Status, string) {
+	switch {
+	case !info.ExpiryDate.IsZero() && info.ExpiryDate.Before(time.Now()):
+		return types.StatusDown, fmt.Sprintf("Domain registration expired on %s", info.ExpiryDate.Format("2006-01-02"))
+	case expected.DomainExpiryDays > 0 && info.ExpiryDate.IsZero():
+		return types.StatusWarning, fmt.Sprintf("Registry does not publish an expiry date for %s", info.Domain)
+	case expected.DomainExpiryDays > 0 && info.DaysToExpiry <= expected.DomainExpiryDays:
+		return types.StatusWarning, fmt.Sprintf("Domain registration expires in %d days (threshold: %d days)",
+			info.DaysToExpiry, expected.DomainExpiryDays)
+	case expected.DomainLocked && !info.Locked:
+		return types.StatusWarning, "Domain has no transfer lock"
+	}
+	return types.StatusUp, ""
+}
+
+// lookup returns the registration data of domain, reusing a recent answer.
+// RDAP is tried first; WHOIS is only asked when RDAP gives no answer.
+func (d *DomainChecker) lookup(ctx context.Context, dialer *netdial.Dialer, domain string, config types.DomainConfig) (*types.DomainInfo, error) {
+	key := domain + "|" + config.RDAPServer + "|" + config.WHOISServer
+
+	d.mu.Lock()
+	cached, ok := d.lookups[key]
+	d.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		info := cached.info
+		info.Cached = true
+		return &info, nil
+	}
+
+	info, rdapErr := d.lookupRDAP(ctx, dialer, domain, config.RDAPServer)
+	if errors.Is(rdapErr, errDomainNotFound) {
+		return nil, rdapErr
+	}
+	if rdapErr != nil {
+		var whoisErr error
+		info, whoisErr = lookupWHOIS(ctx, dialer, domain, config.WHOISServer)
+		if errors.Is(whoisErr, errDomainNotFound) {
+			return nil, whoisErr
+		}
+		if whoisErr != nil {
+			return nil, fmt.Errorf("RDAP: %v; WHOIS: %v", rdapErr, whoisErr)
+		}
+	}
+
+	ttl := config.CacheTTL
+	if ttl == 0 {
+		ttl = defaultDomainCacheTTL
+	}
+	d.mu.Lock()
+	d.lookups[key] = cachedDomain{info: *info, expires: time.Now().Add(ttl)}
+	d.mu.Unlock()
+
+	return info, nil
+}
+
+// rdapDomain is the part of an RDAP domain object the check reads (RFC 9083)
+type rdapDomain struct {
+	LDHName  string       `json:"ldhName"`
+	Status   []string     `json:"status"`
+	Events   []rdapEvent  `json:"events"`
+	Entities []rdapEntity `json:"entities"`
+}
+
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+type rdapEntity struct {
+	Roles    []string        `json:"roles"`
+	VCard    json.RawMessage `json:"vcardArray"`
+	Entities []rdapEntity    `json:"entities"`
+}
+
+// lookupRDAP queries the RDAP service for domain, found in the IANA
+// bootstrap registry unless server is set
+func (d *DomainChecker) lookupRDAP(ctx context.Context, dialer *netdial.Dialer, domain, server string) (*types.DomainInfo, error) {
+	transport := &http.Transport{DisableKeepAlives: true}
+	dialer.ConfigureTransport(transport)
+	client := &http.Client{Transport: transport}
+
+	if server == "" {
+		var err error
+		if server, err = d.rdapServer(ctx, client, dialer, domain); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := strings.TrimSuffix(server, "/") + "/domain/" + url.PathEscape(domain)
+	resp, err := rdapGet(ctx, client, dialer, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errDomainNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, server)
+	}
+
+	var object rdapDomain
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRDAPResponseSize)).Decode(&object); err != nil {
+		return nil, fmt.Errorf("invalid RDAP response: %w", err)
+	}
+
+	info := &types.DomainInfo{
+		Domain:    domain,
+		Registrar: rdapRegistrar(object.Entities),
+		Source:    domainSourceRDAP,
+		Server:    server,
+	}
+	for _, event := range object.Events {
+		if event.Action == "expiration" {
+			expiry, err := time.Parse(time.RFC3339, event.Date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expiration date '%s'", event.Date)
+			}
+			info.ExpiryDate = expiry
+		}
+	}
+	for _, status := range object.Status {
+		info.Statuses = append(info.Statuses, eppStatus(status))
+	}
+	info.Locked = transferLocked(info.Statuses)
+
+	return info, nil
+}
+
+// rdapServer finds the RDAP base URL for the TLD of domain in the IANA
+// bootstrap registry, downloading it again once a day
+func (d *DomainChecker) rdapServer(ctx context.Context, client *http.Client, dialer *netdial.Dialer, domain string) (string, error) {
+	d.mu.Lock()
+	services, fetched := d.bootstrap, d.fetched
+	d.mu.Unlock()
+
+	if services == nil || time.Since(fetched) > rdapBootstrapTTL {
+		resp, err := rdapGet(ctx, client, dialer, rdapBootstrapURL)
+		if err != nil {
+			return "", fmt.Errorf("bootstrap: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("bootstrap: unexpected status %d", resp.StatusCode)
+		}
+
+		var registry struct {
+			Services [][][]string `json:"services"`
+		}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxRDAPResponseSize)).Decode(&registry); err != nil {
+			return "", fmt.Errorf("bootstrap: %w", err)
+		}
+
+		services = make(map[string][]string)
+		for _, service := range registry.Services {
+			if len(service) != 2 {
+				continue
+			}
+			for _, tld := range service[0] {
+				services[strings.ToLower(tld)] = service[1]
+			}
+		}
+
+		d.mu.Lock()
+		d.bootstrap, d.fetched = services, time.Now()
+		d.mu.Unlock()
+	}
+
+	// Entries may name more than one label, so the longest matching suffix wins
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels); i++ {
+		servers := services[strings.Join(labels[i:], ".")]
+		if len(servers) == 0 {
+			continue
+		}
+		for _, server := range servers {
+			if strings.HasPrefix(server, "https://") {
+				return server, nil
+			}
+		}
+		return servers[0], nil
+	}
+	return "", fmt.Errorf("no RDAP service for .%s", labels[len(labels)-1])
+}
+
+// rdapGet sends an RDAP request after checking the server is not on a private network
+func rdapGet(ctx context.Context, client *http.Client, dialer *netdial.Dialer, endpoint string) (*http.Response, error) {
+	if err := security.ValidateURLWithLookup(endpoint, dialer.LookupIP); err != nil {
+		return nil, fmt.Errorf("URL validation failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+	req.Header.Set("User-Agent", "HealthCheck-CLI/1.0")
+
+	return client.Do(req)
+}
+
+// rdapRegistrar returns the name of the entity with the registrar role
+func rdapRegistrar(entities []rdapEntity) string {
+	for _, entity := range entities {
+		for _, role := range entity.Roles {
+			if role == "registrar" {
+				if name := vcardName(entity.VCard); name != "" {
+					return name
+				}
+			}
+		}
+		if name := rdapRegistrar(entity.Entities); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// vcardName reads the fn property of a jCard (RFC 7095), e.g.
+// ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar"]]]
+func vcardName(raw json.RawMessage) string {
+	var card []json.RawMessage
+	if err := json.Unmarshal(raw, &card); err != nil || len(card) != 2 {
+		return ""
+	}
+	var properties [][]interface{}
+	if err := json.Unmarshal(card[1], &properties); err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if len(property) == 4 && property[0] == "fn" {
+			if name, ok := property[3].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// eppStatus turns an RDAP status such as "client transfer prohibited" into
+// the EPP form used by WHOIS, clientTransferProhibited
+func eppStatus(status string) string {
+	words := strings.Fields(status)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// transferLocked reports whether a client or server transfer lock is set
+func transferLocked(statuses []string) bool {
+	for _, status := range statuses {
+		if strings.EqualFold(status, "clientTransferProhibited") || strings.EqualFold(status, "serverTransferProhibited") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDomain takes the domain name from a bare name or a URL
+func parseDomain(raw string) (string, error) {
+	domain := strings.TrimSpace(raw)
+	if strings.Contains(domain, "://") {
+		parsed, err := url.Parse(domain)
+		if err != nil {
+			return "", err
+		}
+		domain = parsed.Hostname()
+	}
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if !strings.Contains(domain, ".") || strings.ContainsAny(domain, " /:@") {
+		return "", fmt.Errorf("'%s' is not a domain name", raw)
+	}
+	return domain, nil
+}
@@ -0,0 +1,329 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rdapReply is an RDAP domain object expiring at expiry
+func rdapReply(expiry time.Time, statuses ...string) string {
+	quoted := make([]string, len(statuses))
+	for i, status := range statuses {
+		quoted[i] = fmt.Sprintf("%q", status)
+	}
+	return fmt.Sprintf(`{
+		"objectClassName": "domain",
+		"ldhName": "EXAMPLE.COM",
+		"status": [%s],
+		"events": [
+			{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+			{"eventAction": "expiration", "eventDate": %q}
+		],
+		"entities": [{
+			"objectClassName": "entity",
+			"roles": ["registrar"],
+			"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]
+		}]
+	}`, strings.Join(quoted, ", "), expiry.UTC().Format(time.RFC3339))
+}
+
+// startFakeRDAP serves reply for every domain and counts the lookups
+func startFakeRDAP(t *testing.T, status int, reply string) (string, *int32) {
+	t.Helper()
+
+	var lookups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/domain/") {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&lookups, 1)
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.WriteHeader(status)
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, &lookups
+}
+
+// startFakeWHOIS answers each query with reply(query)
+func startFakeWHOIS(t *testing.T, reply func(query string) string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				query, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				conn.Write([]byte(reply(strings.TrimSpace(query))))
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func domainCheck(rdapServer, whoisServer string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "example.com registration",
+		Type:    types.CheckTypeDomain,
+		URL:     "example.com",
+		Timeout: 5 * time.Second,
+		Domain: types.DomainConfig{
+			RDAPServer:  rdapServer,
+			WHOISServer: whoisServer,
+		},
+		Expected: types.Expected{DomainExpiryDays: 30},
+	}
+}
+
+func TestDomainChecker_RDAP(t *testing.T) {
+	expiry := time.Now().Add(200 * 24 * time.Hour)
+	server, lookups := startFakeRDAP(t, http.StatusOK, rdapReply(expiry, "client transfer prohibited", "active"))
+
+	checker := NewDomainChecker(5 * time.Second)
+	check := domainCheck(server, "")
+	check.Expected.DomainLocked = true
+
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Domain)
+	assert.Equal(t, "example.com", result.Domain.Domain)
+	assert.Equal(t, "Example Registrar, Inc.", result.Domain.Registrar)
+	assert.Equal(t, expiry.UTC().Truncate(time.Second), result.Domain.ExpiryDate)
+	assert.Equal(t, 199, result.Domain.DaysToExpiry)
+	assert.Equal(t, []string{"clientTransferProhibited", "active"}, result.Domain.Statuses)
+	assert.True(t, result.Domain.Locked)
+	assert.Equal(t, "rdap", result.Domain.Source)
+	assert.False(t, result.Domain.Cached)
+
+	// The second run is answered from the cache
+	result = checker.Check(check)
+	require.NotNil(t, result.Domain)
+	assert.True(t, result.Domain.Cached)
+	assert.Equal(t, int32(1), atomic.LoadInt32(lookups))
+}
+
+func TestDomainChecker_Status(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		reply      string
+		locked     bool
+		wantStatus types.Status
+		wantErr    string
+	}{
+		{
+			name:       "expiring soon",
+			status:     http.StatusOK,
+			reply:      rdapReply(time.Now().Add(10*24*time.Hour+time.Hour), "active"),
+			wantStatus: types.StatusWarning,
+			wantErr:    "Domain registration expires in 10 days (threshold: 30 days)",
+		},
+		{
+			name:       "expired",
+			status:     http.StatusOK,
+			reply:      rdapReply(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)),
+			wantStatus: types.StatusDown,
+			wantErr:    "Domain registration expired on 2020-03-01",
+		},
+		{
+			name:       "no transfer lock",
+			status:     http.StatusOK,
+			reply:      rdapReply(time.Now().Add(200*24*time.Hour), "active"),
+			locked:     true,
+			wantStatus: types.StatusWarning,
+			wantErr:    "Domain has no transfer lock",
+		},
+		{
+			name:       "no expiry published",
+			status:     http.StatusOK,
+			reply:      `{"ldhName": "example.com", "status": ["active"]}`,
+			wantStatus: types.StatusWarning,
+			wantErr:    "Registry does not publish an expiry date for example.com",
+		},
+		{
+			name:       "not registered",
+			status:     http.StatusNotFound,
+			reply:      `{"errorCode": 404, "title": "Not Found"}`,
+			wantStatus: types.StatusDown,
+			wantErr:    "Domain example.com is not registered",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := startFakeRDAP(t, tt.status, tt.reply)
+
+			check := domainCheck(server, "")
+			check.Expected.DomainLocked = tt.locked
+
+			result := NewDomainChecker(5 * time.Second).Check(check)
+
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantErr, result.Error)
+		})
+	}
+}
+
+func TestDomainChecker_WHOISFallback(t *testing.T) {
+	rdap, _ := startFakeRDAP(t, http.StatusInternalServerError, "")
+	expiry := time.Now().Add(90 * 24 * time.Hour).UTC().Truncate(time.Second)
+	whois := startFakeWHOIS(t, func(query string) string {
+		return "Domain Name: " + strings.ToUpper(query) + "\r\n" +
+			"Registrar: Example Registrar, Inc.\r\n" +
+			"Registry Expiry Date: " + expiry.Format(time.RFC3339) + "\r\n" +
+			"Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited\r\n" +
+			">>> Last update of whois database: 2026-10-18T08:00:00Z <<<\r\n"
+	})
+
+	result := NewDomainChecker(5 * time.Second).Check(domainCheck(rdap, whois))
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Domain)
+	assert.Equal(t, "whois", result.Domain.Source)
+	assert.Equal(t, whois, result.Domain.Server)
+	assert.Equal(t, expiry, result.Domain.ExpiryDate)
+	assert.True(t, result.Domain.Locked)
+
+	// Both failures are reported when WHOIS has no answer either
+	broken := startFakeWHOIS(t, func(string) string { return "% rate limit exceeded\r\n" })
+	result = NewDomainChecker(5 * time.Second).Check(domainCheck(rdap, broken))
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "Domain lookup failed: RDAP: unexpected status 500 from "+rdap+"; WHOIS: unrecognized WHOIS reply", result.Error)
+}
+
+func TestDomainChecker_Discovery(t *testing.T) {
+	rdap, _ := startFakeRDAP(t, http.StatusOK, rdapReply(time.Now().Add(200*24*time.Hour)))
+	bootstrap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"services": [[["net", "org"], ["http://unused.invalid/"]], [["co.uk", "com"], [%q]]]}`, rdap+"/")
+	}))
+	defer bootstrap.Close()
+
+	registry := startFakeWHOIS(t, func(string) string {
+		return "Registrar: Example Registrar, Inc.\r\nExpiry Date: 2031-05-01\r\n"
+	})
+	root := startFakeWHOIS(t, func(query string) string {
+		if strings.HasSuffix(query, ".com") {
+			return "domain: COM\r\nrefer: " + registry + "\r\n"
+		}
+		return "% no referral\r\n"
+	})
+
+	originalBootstrap, originalRoot := rdapBootstrapURL, whoisRootServer
+	rdapBootstrapURL, whoisRootServer = bootstrap.URL, root
+	defer func() { rdapBootstrapURL, whoisRootServer = originalBootstrap, originalRoot }()
+
+	result := NewDomainChecker(5 * time.Second).Check(domainCheck("", ""))
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, rdap+"/", result.Domain.Server)
+
+	// Without an RDAP answer the WHOIS root refers the query to the registry
+	failing, _ := startFakeRDAP(t, http.StatusServiceUnavailable, "")
+	result = NewDomainChecker(5 * time.Second).Check(domainCheck(failing, ""))
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, registry, result.Domain.Server)
+	assert.Equal(t, time.Date(2031, 5, 1, 0, 0, 0, 0, time.UTC), result.Domain.ExpiryDate)
+
+	// A TLD missing from both registries cannot be looked up
+	check := domainCheck("", "")
+	check.URL = "example.io"
+	result = NewDomainChecker(5 * time.Second).Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "Domain lookup failed: RDAP: no RDAP service for .io; WHOIS: no WHOIS server for .io", result.Error)
+}
+
+func TestParseWHOIS(t *testing.T) {
+	tests := []struct {
+		name       string
+		reply      string
+		wantExpiry time.Time
+		wantLocked bool
+		wantErr    string
+	}{
+		{
+			name:       "registrar format",
+			reply:      "Registrar: Example\nRegistrar Registration Expiration Date: 2027-01-15T05:00:00.000Z\nDomain Status: serverTransferProhibited\n",
+			wantExpiry: time.Date(2027, 1, 15, 5, 0, 0, 0, time.UTC),
+			wantLocked: true,
+		},
+		{
+			name:       "ccTLD format",
+			reply:      "domain: EXAMPLE.RU\nstate: REGISTERED, DELEGATED\nregistrar: RU-CENTER-RU\npaid-till: 2027-03-02T21:00:00Z\n",
+			wantExpiry: time.Date(2027, 3, 2, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "day month year",
+			reply:      "Registrar:\n  Example\nExpiry date:  02-Nov-2028\n",
+			wantExpiry: time.Date(2028, 11, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "not registered",
+			reply:   "No match for \"UNREGISTERED-EXAMPLE.COM\".\n>>> Last update of whois database: 2026-10-18T08:00:00Z <<<\n",
+			wantErr: "domain is not registered",
+		},
+		{
+			name:    "free",
+			reply:   "Domain: unregistered-example.de\nStatus: free\n",
+			wantErr: "domain is not registered",
+		},
+		{
+			name:    "unknown date format",
+			reply:   "Registrar: Example\nExpiration Date: next spring\n",
+			wantErr: "unrecognized expiry date 'next spring'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := parseWHOIS(tt.reply)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpiry, info.ExpiryDate)
+			assert.Equal(t, tt.wantLocked, info.Locked)
+		})
+	}
+}
+
+func TestParseDomain(t *testing.T) {
+	for raw, want := range map[string]string{
+		"example.com":                   "example.com",
+		"Example.COM.":                  "example.com",
+		"https://www.example.co.uk/app": "www.example.co.uk",
+	} {
+		domain, err := parseDomain(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, domain)
+	}
+
+	for _, raw := range []string{"localhost", "example.com:443", "user@example.com"} {
+		_, err := parseDomain(raw)
+		assert.Error(t, err, raw)
+	}
+}
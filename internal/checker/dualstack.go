@@ -0,0 +1,165 @@
+package checker
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// statusSeverity orders statuses from healthy to failed when combining
+// per-family results
+var statusSeverity = map[types.Status]int{
+	types.StatusUp:      0,
+	types.StatusSlow:    1,
+	types.StatusWarning: 2,
+	types.StatusDown:    3,
+	types.StatusError:   4,
+}
+
+// DualStackChecker wraps a checker so ip_version: both probes the target once
+// per resolved address family. Other ip_version values pass straight through
+// to the wrapped checker, whose dialer enforces the family.
+type DualStackChecker struct {
+	inner   interfaces.Checker
+	dialers *dialerCache
+}
+
+// NewDualStackChecker wraps inner with per-family probing
+func NewDualStackChecker(inner interfaces.Checker) *DualStackChecker {
+	return &DualStackChecker{
+		inner:   inner,
+		dialers: newDialerCache(),
+	}
+}
+
+// Name returns the wrapped checker's name
+func (d *DualStackChecker) Name() string {
+	return d.inner.Name()
+}
+
+// Check runs the wrapped checker once per address family the target resolves to
+// and combines the results, keeping the worst status
+func (d *DualStackChecker) Check(check types.CheckConfig) types.Result {
+	if check.IPVersion != types.IPVersionBoth {
+		return d.inner.Check(check)
+	}
+
+	start := time.Now()
+	families, err := d.families(check)
+	if err != nil {
+		return types.Result{
+			Name:         check.Name,
+			URL:          security.RedactURLCredentials(check.URL),
+			Status:       types.StatusDown,
+			Error:        fmt.Sprintf("DNS resolution failed: %v", err),
+			ResponseTime: time.Since(start),
+			Timestamp:    start,
+		}
+	}
+
+	results := make([]types.Result, len(families))
+	var wg sync.WaitGroup
+	for i, family := range families {
+		wg.Add(1)
+		go func(i int, family string) {
+			defer wg.Done()
+			familyCheck := check
+			familyCheck.IPVersion = family
+			results[i] = d.inner.Check(familyCheck)
+		}(i, family)
+	}
+	wg.Wait()
+
+	return combineFamilies(families, results)
+}
+
+// families returns the address families the target resolves to. When a proxy
+// resolves the target there is nothing to inspect, so both families are probed.
+func (d *DualStackChecker) families(check types.CheckConfig) ([]string, error) {
+	dialer, err := d.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		return nil, err
+	}
+
+	if dialer.RemoteResolution() {
+		return []string{types.IPVersion4, types.IPVersion6}, nil
+	}
+	ips, err := dialer.LookupIP(targetHost(check.URL))
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return []string{types.IPVersion4, types.IPVersion6}, nil
+	}
+
+	var has4, has6 bool
+	for _, ip := range ips {
+		if netdial.Family(ip) == types.IPVersion4 {
+			has4 = true
+		} else {
+			has6 = true
+		}
+	}
+
+	var families []string
+	if has4 {
+		families = append(families, types.IPVersion4)
+	}
+	if has6 {
+		families = append(families, types.IPVersion6)
+	}
+	return families, nil
+}
+
+// combineFamilies builds one result from per-family results. Details such as
+// certificate info and timings come from the first family probed.
+func combineFamilies(families []string, results []types.Result) types.Result {
+	combined := results[0]
+	combined.Families = make([]types.FamilyResult, len(results))
+
+	var failures []string
+	for i, result := range results {
+		name := netdial.FamilyName(families[i])
+		combined.Families[i] = types.FamilyResult{
+			Family:       name,
+			Status:       result.Status,
+			Error:        result.Error,
+			ResponseTime: result.ResponseTime,
+			StatusCode:   result.StatusCode,
+		}
+
+		if result.ResponseTime > combined.ResponseTime {
+			combined.ResponseTime = result.ResponseTime
+		}
+		if statusSeverity[result.Status] > statusSeverity[combined.Status] {
+			combined.Status = result.Status
+		}
+		if result.Status != types.StatusUp {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, result.Error))
+		}
+	}
+
+	combined.Error = strings.Join(failures, "; ")
+	return combined
+}
+
+// targetHost extracts the host name from a URL, host:port or bare host target
+func targetHost(target string) string {
+	if strings.Contains(target, "://") {
+		if parsed, err := url.Parse(target); err == nil {
+			return parsed.Hostname()
+		}
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		return host
+	}
+	return target
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// familyChecker records the ip_version of every probe and fails the given families
+type familyChecker struct {
+	mu      sync.Mutex
+	probed  []string
+	failing map[string]bool
+}
+
+func (f *familyChecker) Name() string { return "fake" }
+
+func (f *familyChecker) Check(check types.CheckConfig) types.Result {
+	f.mu.Lock()
+	f.probed = append(f.probed, check.IPVersion)
+	f.mu.Unlock()
+
+	result := types.Result{Name: check.Name, URL: check.URL, Status: types.StatusUp, ResponseTime: 10 * time.Millisecond}
+	if f.failing[check.IPVersion] {
+		result.Status = types.StatusDown
+		result.Error = "connection refused"
+		result.ResponseTime = 30 * time.Millisecond
+	}
+	return result
+}
+
+func TestDualStackChecker(t *testing.T) {
+	t.Run("PassesThroughSingleFamily", func(t *testing.T) {
+		inner := &familyChecker{}
+		result := NewDualStackChecker(inner).Check(types.CheckConfig{Name: "db", URL: "127.0.0.1:5432", IPVersion: "6"})
+
+		assert.Equal(t, types.StatusUp, result.Status)
+		assert.Empty(t, result.Families)
+		assert.Equal(t, []string{"6"}, inner.probed)
+	})
+
+	t.Run("ProbesResolvedFamiliesOnly", func(t *testing.T) {
+		inner := &familyChecker{}
+		result := NewDualStackChecker(inner).Check(types.CheckConfig{
+			Name:      "db",
+			URL:       "db.internal:5432",
+			IPVersion: types.IPVersionBoth,
+			Resolve:   map[string]string{"db.internal": "::1"},
+		})
+
+		assert.Equal(t, types.StatusUp, result.Status)
+		require.Len(t, result.Families, 1)
+		assert.Equal(t, "IPv6", result.Families[0].Family)
+		assert.Equal(t, []string{"6"}, inner.probed)
+	})
+
+	t.Run("CombinesFamilies", func(t *testing.T) {
+		// Behind an HTTP proxy the target cannot be resolved locally, so both families are probed
+		inner := &familyChecker{failing: map[string]bool{"6": true}}
+		result := NewDualStackChecker(inner).Check(types.CheckConfig{
+			Name:      "api",
+			URL:       "https://api.example.com/health",
+			IPVersion: types.IPVersionBoth,
+			Proxy:     "http://proxy.corp:3128",
+		})
+
+		assert.Equal(t, types.StatusDown, result.Status)
+		assert.Equal(t, "IPv6: connection refused", result.Error)
+		assert.Equal(t, 30*time.Millisecond, result.ResponseTime)
+		require.Len(t, result.Families, 2)
+		assert.Equal(t, "IPv4", result.Families[0].Family)
+		assert.Equal(t, types.StatusUp, result.Families[0].Status)
+		assert.Equal(t, "IPv6", result.Families[1].Family)
+		assert.Equal(t, types.StatusDown, result.Families[1].Status)
+		assert.ElementsMatch(t, []string{"4", "6"}, inner.probed)
+	})
+}
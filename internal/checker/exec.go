@@ -0,0 +1,191 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// maxExecOutput caps how much of a command's stdout and stderr is kept.
+// Nagios itself truncates plugin output at a few kilobytes.
+const maxExecOutput = 8 << 10
+
+// execWaitDelay bounds how long to wait for output pipes after the command
+// exits or is killed, in case a child process still holds them open
+const execWaitDelay = time.Second
+
+// Nagios plugin exit codes
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// execEnvKeys are the variables of this process passed on to commands
+var execEnvKeys = []string{"PATH", "HOME", "LANG"}
+
+// ExecChecker runs a local command and interprets it like a Nagios plugin
+type ExecChecker struct {
+	timeout time.Duration
+	allowed []string
+}
+
+// NewExecChecker creates an exec checker that only runs commands matching the
+// allowed patterns (see security.ValidateCommand)
+func NewExecChecker(timeout time.Duration, allowed []string) *ExecChecker {
+	return &ExecChecker{timeout: timeout, allowed: allowed}
+}
+
+// Name returns the checker name
+func (c *ExecChecker) Name() string {
+	return "Exec"
+}
+
+// Check runs the command and maps its exit code to a status: 0 UP, 1 WARNING,
+// 2 DOWN and anything else ERROR. The output before the first '|' becomes the
+// error (or message when UP) and the performance data after it the metrics.
+func (c *ExecChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       security.RedactURLCredentials(check.URL),
+		Timestamp: start,
+	}
+
+	if check.Exec.Command == "" {
+		result.Status = types.StatusError
+		result.Error = "No command configured"
+		return result
+	}
+
+	// Checks are validated when loaded, but may reach the checker another way
+	if err := security.ValidateCommand(check.Exec.Command, c.allowed); err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Command not allowed: %v", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, check.Exec.Command, check.Exec.Args...)
+	cmd.Dir = check.Exec.Dir
+	cmd.Env = execEnv(check.Exec.Env)
+	cmd.WaitDelay = execWaitDelay
+
+	stdout := &boundedBuffer{limit: maxExecOutput}
+	stderr := &boundedBuffer{limit: maxExecOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	result.ResponseTime = time.Since(start)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Command timed out after %v", check.Timeout)
+		return result
+	}
+
+	code := nagiosOK
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			result.Status = types.StatusError
+			result.Error = fmt.Sprintf("Failed to run command: %v", err)
+			return result
+		}
+		code = exitErr.ExitCode()
+	}
+
+	text, metrics := parseNagiosOutput(stdout.String())
+	result.Metrics = metrics
+	if text == "" {
+		text = strings.TrimSpace(stderr.String())
+	}
+	if stdout.truncated {
+		text += " (output truncated)"
+	}
+
+	switch code {
+	case nagiosOK:
+		result.Status = types.StatusUp
+		result.Message = text
+	case nagiosWarning:
+		result.Status = types.StatusWarning
+	case nagiosCritical:
+		result.Status = types.StatusDown
+	case nagiosUnknown:
+		result.Status = types.StatusError
+	default:
+		// Codes outside the plugin API (or -1 when killed by a signal) mean the plugin itself broke
+		result.Status = types.StatusError
+		if text != "" {
+			text = fmt.Sprintf("Command exited with code %d: %s", code, text)
+		}
+	}
+
+	if code != nagiosOK {
+		if text == "" {
+			text = fmt.Sprintf("Command exited with code %d", code)
+		}
+		result.Error = text
+		return result
+	}
+
+	if check.Expected.ResponseTimeMax > 0 && result.ResponseTime > check.Expected.ResponseTimeMax {
+		result.Status = types.StatusSlow
+		result.Error = fmt.Sprintf("Execution time %v exceeds maximum %v", result.ResponseTime, check.Expected.ResponseTimeMax)
+	}
+
+	return result
+}
+
+// execEnv returns the configured variables plus PATH, HOME and LANG from this
+// process. Nothing else is inherited, so secrets in the monitor's environment
+// do not reach the command.
+func execEnv(extra map[string]string) []string {
+	var env []string
+	for _, key := range execEnvKeys {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	for key, value := range extra {
+		env = append(env, key+"="+value)
+	}
+	return env
+}
+
+// boundedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a chatty command cannot grow memory or block on a full pipe
+type boundedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *boundedBuffer) String() string {
+	return b.buf.String()
+}
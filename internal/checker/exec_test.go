@@ -0,0 +1,177 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin writes an executable shell script and returns its path
+func writePlugin(t *testing.T, script string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "check_test.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+	return path
+}
+
+// newExecChecker returns an exec checker that allows only command
+func newExecChecker(command string) *ExecChecker {
+	return NewExecChecker(10*time.Second, []string{command})
+}
+
+func execCheck(command string, args ...string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "plugin",
+		Type:    types.CheckTypeExec,
+		URL:     command,
+		Timeout: 5 * time.Second,
+		Exec:    types.ExecConfig{Command: command, Args: args},
+	}
+}
+
+func TestExecChecker_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		status types.Status
+		text   string
+	}{
+		{"ok", "echo 'DISK OK - free space: / 3326 MB (56%)'; exit 0", types.StatusUp, "DISK OK - free space: / 3326 MB (56%)"},
+		{"warning", "echo 'DISK WARNING - free space: / 512 MB (9%)'; exit 1", types.StatusWarning, "DISK WARNING - free space: / 512 MB (9%)"},
+		{"critical", "echo 'DISK CRITICAL - / is full'; exit 2", types.StatusDown, "DISK CRITICAL - / is full"},
+		{"unknown", "echo 'DISK UNKNOWN - cannot stat /'; exit 3", types.StatusError, "DISK UNKNOWN - cannot stat /"},
+		{"outside plugin API", "echo 'boom'; exit 7", types.StatusError, "Command exited with code 7: boom"},
+		{"no output", "exit 2", types.StatusDown, "Command exited with code 2"},
+		{"stderr fallback", "echo 'permission denied' >&2; exit 3", types.StatusError, "permission denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := writePlugin(t, tt.script)
+			result := newExecChecker(plugin).Check(execCheck(plugin))
+
+			assert.Equal(t, tt.status, result.Status)
+			if tt.status == types.StatusUp {
+				assert.Equal(t, tt.text, result.Message)
+				assert.Empty(t, result.Error)
+			} else {
+				assert.Equal(t, tt.text, result.Error)
+				assert.Empty(t, result.Message)
+			}
+		})
+	}
+}
+
+func TestExecChecker_Perfdata(t *testing.T) {
+	plugin := writePlugin(t, `printf "LOAD OK - load average: 0.42, 0.37, 0.30 | load1=0.420;5.000;10.000;0; load5=0.370;4.000;6.000;0;\n"
+printf "3 CPUs online\n"
+printf "details follow | 'cpu usage'=12%%;80;90;0;100\n"
+printf "procs=118\n"
+`)
+
+	result := newExecChecker(plugin).Check(execCheck(plugin))
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, "LOAD OK - load average: 0.42, 0.37, 0.30\n3 CPUs online\ndetails follow", result.Message)
+	assert.Equal(t, []types.Metric{
+		{Label: "load1", Value: 0.42, Warn: "5.000", Crit: "10.000", Min: "0"},
+		{Label: "load5", Value: 0.37, Warn: "4.000", Crit: "6.000", Min: "0"},
+		{Label: "cpu usage", Value: 12, Unit: "%", Warn: "80", Crit: "90", Min: "0", Max: "100"},
+		{Label: "procs", Value: 118},
+	}, result.Metrics)
+}
+
+func TestExecChecker_ArgsEnvAndDir(t *testing.T) {
+	dir := t.TempDir()
+	plugin := writePlugin(t, `echo "OK - $1 $2 $PLUGIN_MODE $(pwd)"`)
+
+	check := execCheck(plugin, "first arg", "second")
+	check.Exec.Env = map[string]string{"PLUGIN_MODE": "strict"}
+	check.Exec.Dir = dir
+
+	result := newExecChecker(plugin).Check(check)
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	resolved, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.Contains(t, []string{"OK - first arg second strict " + dir, "OK - first arg second strict " + resolved}, result.Message)
+}
+
+func TestExecChecker_Environment(t *testing.T) {
+	t.Setenv("HEALTHCHECK_TEST_SECRET", "hunter2")
+	plugin := writePlugin(t, `echo "OK - ${HEALTHCHECK_TEST_SECRET:-unset} $PLUGIN_MODE"`)
+
+	check := execCheck(plugin)
+	check.Exec.Env = map[string]string{"PLUGIN_MODE": "strict"}
+	result := newExecChecker(plugin).Check(check)
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, "OK - unset strict", result.Message)
+}
+
+func TestExecChecker_AllowedCommands(t *testing.T) {
+	plugin := writePlugin(t, "echo OK")
+
+	result := NewExecChecker(10*time.Second, nil).Check(execCheck(plugin))
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "Command not allowed")
+
+	result = NewExecChecker(10*time.Second, []string{"/usr/lib/nagios/plugins/*"}).Check(execCheck(plugin))
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "is not in allowed_commands")
+
+	result = NewExecChecker(10*time.Second, []string{filepath.Join(filepath.Dir(plugin), "*")}).Check(execCheck(plugin))
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+}
+
+func TestExecChecker_Timeout(t *testing.T) {
+	plugin := writePlugin(t, "sleep 5")
+
+	check := execCheck(plugin)
+	check.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	result := newExecChecker(plugin).Check(check)
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "Command timed out after 100ms")
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestExecChecker_BoundedOutput(t *testing.T) {
+	plugin := writePlugin(t, `echo "OK - noisy"; i=0; while [ $i -lt 2000 ]; do echo "................................"; i=$((i+1)); done`)
+
+	result := newExecChecker(plugin).Check(execCheck(plugin))
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.True(t, strings.HasPrefix(result.Message, "OK - noisy"))
+	assert.True(t, strings.HasSuffix(result.Message, "(output truncated)"))
+	assert.LessOrEqual(t, len(result.Message), maxExecOutput+len(" (output truncated)"))
+}
+
+func TestExecChecker_SlowAndFailures(t *testing.T) {
+	plugin := writePlugin(t, "sleep 0.2; echo OK")
+
+	check := execCheck(plugin)
+	check.Expected.ResponseTimeMax = 50 * time.Millisecond
+	result := newExecChecker(plugin).Check(check)
+	assert.Equal(t, types.StatusSlow, result.Status)
+	assert.Contains(t, result.Error, "Execution time")
+	assert.Equal(t, "OK", result.Message)
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	result = newExecChecker(missing).Check(execCheck(missing))
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "Failed to run command")
+
+	result = newExecChecker(plugin).Check(execCheck(""))
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Equal(t, "No command configured", result.Error)
+}
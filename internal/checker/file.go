@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// defaultFileReadLimit bounds how much of a file is matched when the check has no max_body_size
+const defaultFileReadLimit = 1 << 20
+
+// FileChecker checks that a file exists and is recent, small enough and has the expected content,
+// e.g. a backup marker or a heartbeat file
+type FileChecker struct{}
+
+// NewFileChecker creates a file checker
+func NewFileChecker() *FileChecker {
+	return &FileChecker{}
+}
+
+// Name returns the checker name
+func (f *FileChecker) Name() string {
+	return "File"
+}
+
+// Check stats the file at the check URL path and applies file_max_age,
+// file_max_size, body_contains and body_regex
+func (f *FileChecker) Check(check types.CheckConfig) types.Result {
+	result := localResult(check)
+	expected := check.Expected
+
+	info, err := os.Stat(check.URL)
+	if err != nil {
+		result.ResponseTime = time.Since(result.Timestamp)
+		result.Status = types.StatusDown
+		if errors.Is(err, fs.ErrNotExist) {
+			result.Error = fmt.Sprintf("File %s does not exist", check.URL)
+		} else {
+			result.Error = fmt.Sprintf("Cannot stat %s: %v", check.URL, err)
+		}
+		return result
+	}
+
+	age := result.Timestamp.Sub(info.ModTime())
+	result.BodySize = info.Size()
+	result.Metrics = []types.Metric{
+		{Label: "age", Value: age.Truncate(time.Second).Seconds(), Unit: "s", Crit: durationThreshold(expected.FileMaxAge)},
+		{Label: "size", Value: float64(info.Size()), Unit: "B", Crit: maxThreshold(float64(expected.FileMaxSize)), Min: "0"},
+	}
+
+	var problems []string
+	if expected.FileMaxAge > 0 && age > expected.FileMaxAge {
+		problems = append(problems, fmt.Sprintf("last modified %s ago (maximum %s)", age.Truncate(time.Second), expected.FileMaxAge))
+	}
+	if expected.FileMaxSize > 0 && info.Size() > expected.FileMaxSize {
+		problems = append(problems, fmt.Sprintf("%d bytes (maximum %d)", info.Size(), expected.FileMaxSize))
+	}
+
+	if expected.BodyContains != "" || expected.BodyRegex != "" {
+		if info.IsDir() {
+			problems = append(problems, "is a directory, its content cannot be matched")
+		} else if problem := matchFileContent(check); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	result.ResponseTime = time.Since(result.Timestamp)
+	summary := fmt.Sprintf("%s, modified %s ago", formatBytes(uint64(info.Size())), age.Truncate(time.Second))
+	if len(problems) > 0 {
+		problems[0] = fmt.Sprintf("File %s %s", check.URL, problems[0])
+	}
+	concludeLocal(&result, problems, types.StatusDown, summary)
+	return result
+}
+
+// matchFileContent reads the start of the file and applies body_contains and body_regex.
+// It returns what did not match, or an empty string.
+func matchFileContent(check types.CheckConfig) string {
+	limit := check.MaxBodySize
+	if limit <= 0 {
+		limit = defaultFileReadLimit
+	}
+
+	file, err := os.Open(check.URL)
+	if err != nil {
+		return fmt.Sprintf("cannot be read: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, limit))
+	if err != nil {
+		return fmt.Sprintf("cannot be read: %v", err)
+	}
+	content := string(data)
+
+	if check.Expected.BodyContains != "" && !strings.Contains(content, check.Expected.BodyContains) {
+		return fmt.Sprintf("does not contain '%s'", check.Expected.BodyContains)
+	}
+	if check.Expected.BodyRegex != "" {
+		re, err := regexp.Compile(check.Expected.BodyRegex)
+		if err != nil {
+			return fmt.Sprintf("cannot be matched: invalid regex: %v", err)
+		}
+		if !re.MatchString(content) {
+			return fmt.Sprintf("does not match regex '%s'", check.Expected.BodyRegex)
+		}
+	}
+	return ""
+}
+
+// durationThreshold formats a maximum duration in seconds as a Nagios range
+func durationThreshold(limit time.Duration) string {
+	return maxThreshold(limit.Seconds())
+}
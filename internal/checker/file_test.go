@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileChecker(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "backup.done")
+	require.NoError(t, os.WriteFile(marker, []byte("backup finished status=ok files=1234\n"), 0o644))
+	modified := time.Now().Add(-3 * time.Hour)
+	require.NoError(t, os.Chtimes(marker, modified, modified))
+
+	tests := []struct {
+		name       string
+		path       string
+		expected   types.Expected
+		wantStatus types.Status
+		wantErr    string
+	}{
+		{
+			name:       "fresh enough with content",
+			path:       marker,
+			expected:   types.Expected{FileMaxAge: 25 * time.Hour, FileMaxSize: 1024, BodyContains: "status=ok", BodyRegex: `files=\d+`},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "stale",
+			path:       marker,
+			expected:   types.Expected{FileMaxAge: time.Hour},
+			wantStatus: types.StatusDown,
+			wantErr:    "File " + marker + " last modified 3h0m0s ago (maximum 1h0m0s)",
+		},
+		{
+			name:       "too large",
+			path:       marker,
+			expected:   types.Expected{FileMaxSize: 10},
+			wantStatus: types.StatusDown,
+			wantErr:    "File " + marker + " 37 bytes (maximum 10)",
+		},
+		{
+			name:       "missing content",
+			path:       marker,
+			expected:   types.Expected{BodyContains: "status=failed"},
+			wantStatus: types.StatusDown,
+			wantErr:    "File " + marker + " does not contain 'status=failed'",
+		},
+		{
+			name:       "directory content",
+			path:       dir,
+			expected:   types.Expected{BodyRegex: "ok"},
+			wantStatus: types.StatusDown,
+			wantErr:    "File " + dir + " is a directory, its content cannot be matched",
+		},
+		{
+			name:       "does not exist",
+			path:       filepath.Join(dir, "heartbeat"),
+			wantStatus: types.StatusDown,
+			wantErr:    "File " + filepath.Join(dir, "heartbeat") + " does not exist",
+		},
+	}
+
+	checker := NewFileChecker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{Name: "backup", Type: types.CheckTypeFile, URL: tt.path, Expected: tt.expected})
+
+			require.Equal(t, tt.wantStatus, result.Status, result.Error)
+			assert.Equal(t, tt.wantErr, result.Error)
+		})
+	}
+
+	result := checker.Check(types.CheckConfig{Name: "backup", Type: types.CheckTypeFile, URL: marker})
+	assert.Equal(t, "37 B, modified 3h0m0s ago", result.Message)
+	require.Len(t, result.Metrics, 2)
+	assert.Equal(t, float64(3*60*60), result.Metrics[0].Value)
+}
@@ -0,0 +1,242 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonpath"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// maxGraphQLErrors caps how many error messages are quoted in a result
+const maxGraphQLErrors = 3
+
+// introspectionQuery fetches the full schema, following type references deep
+// enough for lists of non-null lists of non-null types
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) {
+    name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// GraphQLChecker sends a GraphQL operation over HTTP and checks the response for errors
+type GraphQLChecker struct {
+	http *HTTPChecker
+}
+
+// NewGraphQLChecker creates a GraphQL checker that reuses the HTTP checker's
+// transport and response validation
+func NewGraphQLChecker(httpChecker *HTTPChecker) *GraphQLChecker {
+	return &GraphQLChecker{
+		http: httpChecker,
+	}
+}
+
+// Name returns the checker name
+func (g *GraphQLChecker) Name() string {
+	return "GraphQL"
+}
+
+// Check posts the operation, fails on a non-empty errors array even with HTTP 200,
+// asserts on the response data and optionally fingerprints the schema
+func (g *GraphQLChecker) Check(check types.CheckConfig) types.Result {
+	request, err := graphQLRequest(check, check.GraphQL.Query, check.GraphQL.Variables, check.GraphQL.OperationName)
+	if err != nil {
+		return types.Result{
+			Name:      check.Name,
+			URL:       check.URL,
+			Status:    types.StatusError,
+			Error:     fmt.Sprintf("Invalid GraphQL request: %v", err),
+			Timestamp: time.Now(),
+		}
+	}
+	// Data assertions are checked once the errors array is known to be empty
+	request.Expected.JSON = nil
+
+	result, body := g.http.perform(request, true)
+	if body == nil || (result.Status != types.StatusUp && result.Status != types.StatusSlow) {
+		return result
+	}
+
+	document, err := jsonpath.Decode(body.data)
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Response is not JSON%s: %v", body.truncationNote(), err)
+		return result
+	}
+	if err := graphQLErrors(document); err != nil {
+		result.Status = types.StatusDown
+		result.Error = err.Error()
+		return result
+	}
+	if err := validateJSON(document, check.Expected.JSON); err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Response validation failed: %v", err)
+		return result
+	}
+
+	if check.Expected.SchemaAlertOnChange {
+		info, err := g.introspect(check)
+		if err != nil {
+			if result.Status == types.StatusUp {
+				result.Status = types.StatusWarning
+				result.Error = fmt.Sprintf("Schema introspection failed: %v", err)
+			}
+			return result
+		}
+		result.GraphQL = info
+	}
+
+	return result
+}
+
+// introspect fetches the schema and hashes its canonical JSON form
+func (g *GraphQLChecker) introspect(check types.CheckConfig) (*types.GraphQLInfo, error) {
+	request, err := graphQLRequest(check, introspectionQuery, nil, "IntrospectionQuery")
+	if err != nil {
+		return nil, err
+	}
+	// Only the status code matters for the introspection response itself
+	request.Expected = types.Expected{
+		Status:      check.Expected.Status,
+		StatusRange: check.Expected.StatusRange,
+	}
+
+	result, body := g.http.perform(request, true)
+	if body == nil || (result.Status != types.StatusUp && result.Status != types.StatusSlow) {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+
+	document, err := jsonpath.Decode(body.data)
+	if err != nil {
+		return nil, fmt.Errorf("response is not JSON%s: %w", body.truncationNote(), err)
+	}
+	if err := graphQLErrors(document); err != nil {
+		return nil, err
+	}
+	schema, err := jsonpath.Lookup(document, "data.__schema")
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers may list types in any order between runs
+	fields, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("introspection returned no schema")
+	}
+	typeList, _ := fields["types"].([]interface{})
+	sort.SliceStable(typeList, func(i, j int) bool {
+		return typeName(typeList[i]) < typeName(typeList[j])
+	})
+
+	// encoding/json writes object keys sorted, which makes the encoding canonical
+	canonical, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+
+	return &types.GraphQLInfo{
+		SchemaHash: hex.EncodeToString(sum[:]),
+		Types:      len(typeList),
+	}, nil
+}
+
+// graphQLRequest turns a GraphQL check into the HTTP check that carries the operation
+func graphQLRequest(check types.CheckConfig, query string, variables map[string]interface{}, operationName string) (types.CheckConfig, error) {
+	payload := map[string]interface{}{"query": query}
+	if len(variables) > 0 {
+		payload["variables"] = variables
+	}
+	if operationName != "" {
+		payload["operationName"] = operationName
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return check, err
+	}
+
+	request := check
+	request.Method = http.MethodPost
+	request.Body = string(body)
+	request.SkipBody = false
+	request.Headers = make(map[string]string, len(check.Headers)+2)
+	for key, value := range check.Headers {
+		request.Headers[key] = value
+	}
+	setDefaultHeader(request.Headers, "Content-Type", "application/json")
+	setDefaultHeader(request.Headers, "Accept", "application/graphql-response+json, application/json")
+
+	return request, nil
+}
+
+// setDefaultHeader sets a header unless the check already sets it in any letter case
+func setDefaultHeader(headers map[string]string, name, value string) {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return
+		}
+	}
+	headers[name] = value
+}
+
+// graphQLErrors returns an error quoting the messages of a non-empty errors array
+func graphQLErrors(document interface{}) error {
+	response, ok := document.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("response is not a GraphQL result object")
+	}
+
+	list, _ := response["errors"].([]interface{})
+	if len(list) == 0 {
+		if _, ok := response["data"]; !ok {
+			return fmt.Errorf("response has neither data nor errors")
+		}
+		return nil
+	}
+
+	var messages []string
+	for i, entry := range list {
+		if i == maxGraphQLErrors {
+			messages = append(messages, fmt.Sprintf("and %d more", len(list)-i))
+			break
+		}
+		if message, ok := entry.(map[string]interface{})["message"].(string); ok {
+			messages = append(messages, message)
+		} else {
+			messages = append(messages, jsonpath.String(entry))
+		}
+	}
+	return fmt.Errorf("GraphQL errors (%d): %s", len(list), strings.Join(messages, "; "))
+}
+
+// typeName returns the name of an introspected type
+func typeName(entry interface{}) string {
+	name, _ := entry.(map[string]interface{})["name"].(string)
+	return name
+}
@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGraphQL answers operations with reply and introspection with the current schema
+type fakeGraphQL struct {
+	mu      sync.Mutex
+	reply   string
+	schema  string
+	request map[string]interface{}
+}
+
+func (f *fakeGraphQL) setSchema(schema string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schema = schema
+}
+
+func startFakeGraphQL(t *testing.T, reply, schema string) (*fakeGraphQL, string) {
+	t.Helper()
+
+	fake := &fakeGraphQL{reply: reply, schema: schema}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "POST application/json only", http.StatusMethodNotAllowed)
+			return
+		}
+		var request map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if request["operationName"] == "IntrospectionQuery" {
+			w.Write([]byte(fake.schema))
+			return
+		}
+		fake.request = request
+		w.Write([]byte(fake.reply))
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server.URL
+}
+
+func graphQLCheck(url string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "api",
+		Type:    types.CheckTypeGraphQL,
+		URL:     url,
+		Timeout: 5 * time.Second,
+		GraphQL: types.GraphQLConfig{
+			Query:         "query User($id: ID!) { user(id: $id) { name roles } }",
+			Variables:     map[string]interface{}{"id": 42},
+			OperationName: "User",
+		},
+		Expected: types.Expected{Status: 200},
+	}
+}
+
+func TestGraphQLChecker_Data(t *testing.T) {
+	fake, url := startFakeGraphQL(t, `{"data":{"user":{"name":"Ada","roles":["admin"]}}}`, "")
+
+	check := graphQLCheck(url)
+	check.Expected.JSON = []types.JSONMatch{
+		{Path: "data.user.name", Value: "Ada"},
+		{Path: "data.user.roles[0]", Regex: "^admin$"},
+	}
+
+	result := NewGraphQLChecker(NewHTTPChecker(5 * time.Second)).Check(check)
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, 200, result.StatusCode)
+	assert.Nil(t, result.GraphQL)
+	assert.Equal(t, "User", fake.request["operationName"])
+	assert.Equal(t, map[string]interface{}{"id": float64(42)}, fake.request["variables"])
+	assert.Contains(t, fake.request["query"], "user(id: $id)")
+}
+
+func TestGraphQLChecker_Failures(t *testing.T) {
+	tests := []struct {
+		name       string
+		reply      string
+		json       []types.JSONMatch
+		wantStatus types.Status
+		wantErr    string
+	}{
+		{
+			name:       "errors with HTTP 200",
+			reply:      `{"data":null,"errors":[{"message":"Cannot query field 'roles'"},{"message":"Not authorized"}]}`,
+			wantStatus: types.StatusDown,
+			wantErr:    "GraphQL errors (2): Cannot query field 'roles'; Not authorized",
+		},
+		{
+			name:       "many errors are abbreviated",
+			reply:      `{"errors":[{"message":"a"},{"message":"b"},{"message":"c"},{"message":"d"},{"message":"e"}]}`,
+			wantStatus: types.StatusDown,
+			wantErr:    "GraphQL errors (5): a; b; c; and 2 more",
+		},
+		{
+			name:       "errors win over data assertions",
+			reply:      `{"data":{"user":null},"errors":[{"message":"User not found"}]}`,
+			json:       []types.JSONMatch{{Path: "data.user.name"}},
+			wantStatus: types.StatusDown,
+			wantErr:    "GraphQL errors (1): User not found",
+		},
+		{
+			name:       "data assertion mismatch",
+			reply:      `{"data":{"user":{"name":"Grace"}}}`,
+			json:       []types.JSONMatch{{Path: "data.user.name", Value: "Ada"}},
+			wantStatus: types.StatusDown,
+			wantErr:    "JSON path 'data.user.name' has value 'Grace', expected 'Ada'",
+		},
+		{
+			name:       "not a GraphQL result",
+			reply:      `{"status":"ok"}`,
+			wantStatus: types.StatusDown,
+			wantErr:    "response has neither data nor errors",
+		},
+		{
+			name:       "not JSON",
+			reply:      `<html>maintenance</html>`,
+			wantStatus: types.StatusDown,
+			wantErr:    "Response is not JSON",
+		},
+	}
+
+	checker := NewGraphQLChecker(NewHTTPChecker(5 * time.Second))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, url := startFakeGraphQL(t, tt.reply, "")
+
+			check := graphQLCheck(url)
+			check.Expected.JSON = tt.json
+
+			result := checker.Check(check)
+
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Contains(t, result.Error, tt.wantErr)
+		})
+	}
+}
+
+func TestGraphQLChecker_SchemaHash(t *testing.T) {
+	schema := `{"data":{"__schema":{"queryType":{"name":"Query"},"types":[
+		{"kind":"OBJECT","name":"Query","fields":[{"name":"user"}]},
+		{"kind":"OBJECT","name":"User","fields":[{"name":"name"}]}
+	]}}}`
+	reordered := `{"data":{"__schema":{"types":[
+		{"name":"User","fields":[{"name":"name"}],"kind":"OBJECT"},
+		{"name":"Query","kind":"OBJECT","fields":[{"name":"user"}]}
+	],"queryType":{"name":"Query"}}}}`
+	changed := strings.Replace(schema, `{"name":"name"}`, `{"name":"name"},{"name":"email"}`, 1)
+
+	fake, url := startFakeGraphQL(t, `{"data":{"user":{"name":"Ada"}}}`, schema)
+	checker := NewGraphQLChecker(NewHTTPChecker(5 * time.Second))
+
+	check := graphQLCheck(url)
+	check.Expected.SchemaAlertOnChange = true
+
+	first := checker.Check(check)
+	require.Equal(t, types.StatusUp, first.Status, first.Error)
+	require.NotNil(t, first.GraphQL)
+	assert.Len(t, first.GraphQL.SchemaHash, 64)
+	assert.Equal(t, 2, first.GraphQL.Types)
+
+	fake.setSchema(reordered)
+	second := checker.Check(check)
+	require.NotNil(t, second.GraphQL)
+	assert.Equal(t, first.GraphQL.SchemaHash, second.GraphQL.SchemaHash)
+
+	fake.setSchema(changed)
+	third := checker.Check(check)
+	require.NotNil(t, third.GraphQL)
+	assert.NotEqual(t, first.GraphQL.SchemaHash, third.GraphQL.SchemaHash)
+
+	fake.setSchema(`{"errors":[{"message":"introspection is disabled"}]}`)
+	disabled := checker.Check(check)
+	assert.Equal(t, types.StatusWarning, disabled.Status)
+	assert.Equal(t, "Schema introspection failed: GraphQL errors (1): introspection is disabled", disabled.Error)
+}
@@ -4,50 +4,119 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/errors"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonpath"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonschema"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/tlsconfig"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 )
 
 // HTTPChecker implements health checks for HTTP/HTTPS endpoints
 type HTTPChecker struct {
-	client *http.Client
+	client  *http.Client
+	clients map[string]*http.Client // per TLS and network configuration
+	tls     *tlsCache
+	dialers *dialerCache
+	schemas map[string]*jsonschema.Schema
+	mu      sync.Mutex
 }
 
 // NewHTTPChecker creates a new HTTP checker with secure defaults
 func NewHTTPChecker(timeout time.Duration) *HTTPChecker {
 	return &HTTPChecker{
-		client: &http.Client{
-			Timeout: timeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: false, // Validate TLS certificates
-					MinVersion:         tls.VersionTLS12,
-				},
-			},
-			// Limit redirects to prevent infinite loops
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				if len(via) >= 5 {
-					return fmt.Errorf("too many redirects (max 5)")
-				}
-				// Validate redirect URL for security
-				if err := security.ValidateURL(req.URL.String()); err != nil {
-					return fmt.Errorf("redirect URL validation failed: %w", err)
-				}
-				return nil
-			},
+		schemas: make(map[string]*jsonschema.Schema),
+		clients: make(map[string]*http.Client),
+		tls:     newTLSCache(),
+		dialers: newDialerCache(),
+		client: newHTTPClient(timeout, &tls.Config{
+			InsecureSkipVerify: false, // Validate TLS certificates
+			MinVersion:         tls.VersionTLS12,
+		}, nil),
+	}
+}
+
+// newHTTPClient creates an HTTP client with its own transport using the given TLS
+// settings. A nil dialer keeps the system proxy-less defaults.
+func newHTTPClient(timeout time.Duration, tlsConfig *tls.Config, dialer *netdial.Dialer) *http.Client {
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSClientConfig:     tlsConfig,
+	}
+	lookup := security.LookupFunc(net.LookupIP)
+	if dialer != nil {
+		dialer.ConfigureTransport(transport)
+		lookup = dialer.LookupIP
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// Limit redirects to prevent infinite loops
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("too many redirects (max 5)")
+			}
+			// Validate redirect URL for security
+			if err := security.ValidateURLWithLookup(req.URL.String(), lookup); err != nil {
+				return fmt.Errorf("redirect URL validation failed: %w", err)
+			}
+			return nil
 		},
 	}
 }
 
+// dialerFor returns the dialer honoring a check's proxy and resolver settings
+func (h *HTTPChecker) dialerFor(check types.CheckConfig) (*netdial.Dialer, error) {
+	return h.dialers.get(netdial.FromCheck(check))
+}
+
+// clientFor returns the client for a check, creating one transport per distinct
+// TLS and network configuration so connections are pooled between similar checks
+func (h *HTTPChecker) clientFor(check types.CheckConfig, dialer *netdial.Dialer) (*http.Client, error) {
+	network := netdial.FromCheck(check)
+	if check.TLS.IsZero() && network.IsZero() {
+		return h.client, nil
+	}
+
+	tlsConfig := h.client.Transport.(*http.Transport).TLSClientConfig
+	if !check.TLS.IsZero() {
+		var err error
+		tlsConfig, err = h.tls.get(check.Name, check.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A client whose TLS configuration was rebuilt, e.g. for a rotated
+	// certificate, is replaced
+	key := tlsconfig.Key(check.TLS) + "#" + netdial.Key(network)
+	if client, ok := h.clients[key]; ok {
+		if client.Transport.(*http.Transport).TLSClientConfig == tlsConfig {
+			return client, nil
+		}
+		client.CloseIdleConnections()
+	}
+
+	client := newHTTPClient(h.client.Timeout, tlsConfig, dialer)
+	h.clients[key] = client
+	return client, nil
+}
+
 // Name returns the checker name
 func (h *HTTPChecker) Name() string {
 	return "HTTP"
@@ -55,6 +124,14 @@ func (h *HTTPChecker) Name() string {
 
 // Check performs an HTTP health check with security validations
 func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
+	result, _ := h.perform(check, false)
+	return result
+}
+
+// perform runs the request and validations of an HTTP check, also returning the
+// response body, which is buffered when buffer is set. The body is nil when no
+// response was received.
+func (h *HTTPChecker) perform(check types.CheckConfig, buffer bool) (types.Result, *responseBody) {
 	start := time.Now()
 	
 	result := types.Result{
@@ -63,15 +140,24 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 		Timestamp: start,
 	}
 	
+	// Resolve names and connect the way the check's network settings require
+	dialer, err := h.dialerFor(check)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		result.ResponseTime = time.Since(start)
+		return result, nil
+	}
+	
 	// Validate URL for security (prevent SSRF)
-	if err := security.ValidateURL(check.URL); err != nil {
+	if err := security.ValidateURLWithLookup(check.URL, dialer.LookupIP); err != nil {
 		validationErr := errors.NewValidationError("URL validation failed", err.Error()).
 			WithContext("url", check.URL).
 			WithComponent("HTTPChecker")
 		result.Status = types.StatusError
 		result.Error = validationErr.Error()
 		result.ResponseTime = time.Since(start)
-		return result
+		return result, nil
 	}
 	
 	// Validate headers for security (prevent injection)
@@ -82,13 +168,26 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 		result.Status = types.StatusError
 		result.Error = validationErr.Error()
 		result.ResponseTime = time.Since(start)
-		return result
+		return result, nil
+	}
+	
+	// Pick the client matching the check's TLS and network options
+	client, err := h.clientFor(check, dialer)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+		result.ResponseTime = time.Since(start)
+		return result, nil
 	}
 	
 	// Create request context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
 	defer cancel()
 	
+	// Trace connection phases for the timing breakdown
+	recorder := &timingRecorder{}
+	ctx = httptrace.WithClientTrace(ctx, recorder.trace())
+	
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, check.Method, check.URL, strings.NewReader(check.Body))
 	if err != nil {
@@ -99,7 +198,7 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 		result.Status = types.StatusError
 		result.Error = internalErr.Error()
 		result.ResponseTime = time.Since(start)
-		return result
+		return result, nil
 	}
 	
 	// Add headers (already validated above)
@@ -113,7 +212,7 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 	}
 	
 	// Perform request
-	resp, err := h.client.Do(req)
+	resp, err := client.Do(req)
 	duration := time.Since(start)
 	result.ResponseTime = duration
 	
@@ -133,7 +232,7 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 		
 		result.Status = types.StatusDown
 		result.Error = healthErr.Error()
-		return result
+		return result, nil
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -142,17 +241,27 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 		}
 	}()
 	
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body within the configured size limit
+	body, err := readBody(resp.Body, check.Expected, bodyOptions{
+		limit:  check.MaxBodySize,
+		buffer: buffer,
+		skip:   check.SkipBody,
+	})
+	recorder.finish()
+	result.Timings = recorder.timings()
 	if err != nil {
 		result.Status = types.StatusError
 		result.Error = fmt.Sprintf("Failed to read response body: %v", err)
 		result.StatusCode = resp.StatusCode
-		return result
+		return result, body
 	}
 	
 	result.StatusCode = resp.StatusCode
-	result.BodySize = int64(len(body))
+	result.BodySize = body.size
+	result.BodyTruncated = body.truncated
+	if body.skipped && resp.ContentLength > 0 {
+		result.BodySize = resp.ContentLength
+	}
 	result.Headers = make(map[string]string)
 	
 	// Copy important headers
@@ -171,23 +280,41 @@ func (h *HTTPChecker) Check(check types.CheckConfig) types.Result {
 			result.Status = types.StatusDown
 		}
 		result.Error = fmt.Sprintf("Response validation failed: %v", err)
-		return result
+		return result, body
+	}
+	
+	// Fingerprint the body for content change detection
+	if check.Expected.ContentAlertOnChange {
+		content, err := contentFingerprint(body.data, check.Expected.ContentIgnore)
+		if err != nil {
+			result.Status = types.StatusError
+			result.Error = err.Error()
+			return result, body
+		}
+		result.Content = content
 	}
 	
 	// Check response time performance (even if other validations passed)
 	if check.Expected.ResponseTimeMax > 0 && duration > check.Expected.ResponseTimeMax {
 		result.Status = types.StatusSlow
 		result.Error = fmt.Sprintf("Response time %v exceeds maximum %v", duration, check.Expected.ResponseTimeMax)
-		return result
+		return result, body
+	}
+	
+	// Check individual connection phases
+	if err := validateTimings(result.Timings, check.Expected); err != nil {
+		result.Status = types.StatusSlow
+		result.Error = err.Error()
+		return result, body
 	}
 	
 	// All checks passed
 	result.Status = types.StatusUp
-	return result
+	return result, body
 }
 
 // validateResponse validates the HTTP response against expected criteria
-func (h *HTTPChecker) validateResponse(resp *http.Response, body []byte, expected types.Expected) error {
+func (h *HTTPChecker) validateResponse(resp *http.Response, body *responseBody, expected types.Expected) error {
 	// Check status code
 	if expected.Status > 0 && resp.StatusCode != expected.Status {
 		// Check if status range is defined
@@ -201,15 +328,13 @@ func (h *HTTPChecker) validateResponse(resp *http.Response, body []byte, expecte
 		}
 	}
 	
-	bodyStr := string(body)
-	
 	// Check if body contains expected content
-	if expected.BodyContains != "" && !strings.Contains(bodyStr, expected.BodyContains) {
-		return fmt.Errorf("response body does not contain '%s'", expected.BodyContains)
+	if body.contains != nil && !body.contains.found {
+		return fmt.Errorf("response body does not contain '%s'%s", expected.BodyContains, body.truncationNote())
 	}
 	
 	// Check if body does NOT contain unwanted content
-	if expected.BodyNotContains != "" && strings.Contains(bodyStr, expected.BodyNotContains) {
+	if body.notContains != nil && body.notContains.found {
 		return fmt.Errorf("response body contains unwanted content '%s'", expected.BodyNotContains)
 	}
 	
@@ -222,10 +347,120 @@ func (h *HTTPChecker) validateResponse(resp *http.Response, body []byte, expecte
 	}
 	
 	// Check minimum body size
-	if expected.MinBodySize > 0 && int64(len(body)) < expected.MinBodySize {
+	if expected.MinBodySize > 0 && body.size < expected.MinBodySize {
 		return fmt.Errorf("response body size %d bytes is less than minimum %d bytes", 
-			len(body), expected.MinBodySize)
+			body.size, expected.MinBodySize)
+	}
+	
+	// Check body against regular expression
+	if expected.BodyRegex != "" {
+		re, err := regexp.Compile(expected.BodyRegex)
+		if err != nil {
+			return fmt.Errorf("invalid body regex '%s': %w", expected.BodyRegex, err)
+		}
+		if !re.Match(body.data) {
+			return fmt.Errorf("response body does not match regex '%s'%s", expected.BodyRegex, body.truncationNote())
+		}
+	}
+	
+	// Check required headers
+	if err := h.validateHeaders(resp.Header, expected.Headers); err != nil {
+		return err
+	}
+	
+	// Check body against JSON schema
+	if expected.JSONSchema != "" {
+		schema, err := h.loadSchema(expected.JSONSchema)
+		if err != nil {
+			return err
+		}
+		if err := schema.Validate(body.data); err != nil {
+			return fmt.Errorf("JSON schema validation failed%s: %w", body.truncationNote(), err)
+		}
+	}
+	
+	// Check values inside the JSON body
+	if len(expected.JSON) > 0 {
+		document, err := jsonpath.Decode(body.data)
+		if err != nil {
+			return fmt.Errorf("response body is not JSON%s: %w", body.truncationNote(), err)
+		}
+		if err := validateJSON(document, expected.JSON); err != nil {
+			return err
+		}
 	}
 	
 	return nil
 }
+
+// validateJSON checks a decoded JSON document against the expected value assertions
+func validateJSON(document interface{}, expected []types.JSONMatch) error {
+	for _, match := range expected {
+		found, err := jsonpath.Lookup(document, match.Path)
+		if err != nil {
+			return fmt.Errorf("JSON assertion failed: %w", err)
+		}
+		value := jsonpath.String(found)
+
+		if match.Value != "" && value != match.Value {
+			return fmt.Errorf("JSON path '%s' has value '%s', expected '%s'", match.Path, value, match.Value)
+		}
+
+		if match.Regex != "" {
+			re, err := regexp.Compile(match.Regex)
+			if err != nil {
+				return fmt.Errorf("invalid regex for JSON path '%s': %w", match.Path, err)
+			}
+			if !re.MatchString(value) {
+				return fmt.Errorf("JSON path '%s' value '%s' does not match regex '%s'", match.Path, value, match.Regex)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateHeaders checks response headers against the expected header assertions
+func (h *HTTPChecker) validateHeaders(header http.Header, expected []types.HeaderMatch) error {
+	for _, match := range expected {
+		values, exists := header[http.CanonicalHeaderKey(match.Name)]
+		if !exists || len(values) == 0 {
+			return fmt.Errorf("response is missing header '%s'", match.Name)
+		}
+		value := strings.Join(values, ", ")
+		
+		if match.Value != "" && value != match.Value {
+			return fmt.Errorf("header '%s' has value '%s', expected '%s'", match.Name, value, match.Value)
+		}
+		
+		if match.Regex != "" {
+			re, err := regexp.Compile(match.Regex)
+			if err != nil {
+				return fmt.Errorf("invalid regex for header '%s': %w", match.Name, err)
+			}
+			if !re.MatchString(value) {
+				return fmt.Errorf("header '%s' value '%s' does not match regex '%s'", match.Name, value, match.Regex)
+			}
+		}
+	}
+	
+	return nil
+}
+
+// loadSchema returns the compiled JSON schema for a path, loading it on first use
+func (h *HTTPChecker) loadSchema(path string) (*jsonschema.Schema, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	
+	if schema, ok := h.schemas[path]; ok {
+		return schema, nil
+	}
+	
+	schema, err := jsonschema.LoadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JSON schema '%s': %w", path, err)
+	}
+	
+	h.schemas[path] = schema
+	return schema, nil
+}
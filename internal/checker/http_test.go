@@ -0,0 +1,249 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok","version":"v1.4.2"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPChecker_ResponseAssertions(t *testing.T) {
+	server := newTestServer(t)
+
+	schemaPath := filepath.Join(t.TempDir(), "health.schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{
+		"type": "object",
+		"required": ["status"],
+		"properties": {"status": {"const": "ok"}}
+	}`), 0600))
+
+	strictSchemaPath := filepath.Join(t.TempDir(), "strict.schema.json")
+	require.NoError(t, os.WriteFile(strictSchemaPath, []byte(`{
+		"type": "object",
+		"required": ["uptime"]
+	}`), 0600))
+
+	tests := []struct {
+		name       string
+		expected   types.Expected
+		wantStatus types.Status
+		errMsg     string
+	}{
+		{
+			name:       "BodyRegexMatches",
+			expected:   types.Expected{Status: 200, BodyRegex: `"version":"v\d+\.\d+\.\d+"`},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "BodyRegexMismatch",
+			expected:   types.Expected{Status: 200, BodyRegex: `"status":"degraded"`},
+			wantStatus: types.StatusDown,
+			errMsg:     "does not match regex",
+		},
+		{
+			name: "HeadersPresentAndMatching",
+			expected: types.Expected{Status: 200, Headers: []types.HeaderMatch{
+				{Name: "strict-transport-security", Regex: `max-age=\d{8,}`},
+				{Name: "Cache-Control", Value: "no-store"},
+				{Name: "Content-Type"},
+			}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name: "HeaderMissing",
+			expected: types.Expected{Status: 200, Headers: []types.HeaderMatch{
+				{Name: "Content-Security-Policy"},
+			}},
+			wantStatus: types.StatusDown,
+			errMsg:     "missing header 'Content-Security-Policy'",
+		},
+		{
+			name: "HeaderValueMismatch",
+			expected: types.Expected{Status: 200, Headers: []types.HeaderMatch{
+				{Name: "Cache-Control", Value: "public"},
+			}},
+			wantStatus: types.StatusDown,
+			errMsg:     "expected 'public'",
+		},
+		{
+			name:       "JSONSchemaValid",
+			expected:   types.Expected{Status: 200, JSONSchema: schemaPath},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "JSONSchemaInvalid",
+			expected:   types.Expected{Status: 200, JSONSchema: strictSchemaPath},
+			wantStatus: types.StatusDown,
+			errMsg:     "missing required property 'uptime'",
+		},
+		{
+			name: "JSONValuesMatching",
+			expected: types.Expected{Status: 200, JSON: []types.JSONMatch{
+				{Path: "status", Value: "ok"},
+				{Path: "$.version", Regex: `^v1\.`},
+			}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name: "JSONValueMismatch",
+			expected: types.Expected{Status: 200, JSON: []types.JSONMatch{
+				{Path: "status", Value: "degraded"},
+			}},
+			wantStatus: types.StatusDown,
+			errMsg:     "JSON path 'status' has value 'ok', expected 'degraded'",
+		},
+		{
+			name: "JSONPathMissing",
+			expected: types.Expected{Status: 200, JSON: []types.JSONMatch{
+				{Path: "checks.db"},
+			}},
+			wantStatus: types.StatusDown,
+			errMsg:     "key 'checks' not found",
+		},
+	}
+
+	checker := NewHTTPChecker(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{
+				Name:     tt.name,
+				URL:      server.URL,
+				Method:   "GET",
+				Timeout:  5 * time.Second,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.errMsg != "" {
+				assert.Contains(t, result.Error, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_Timings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker(5 * time.Second)
+	check := types.CheckConfig{
+		Name:     "Timed",
+		URL:      server.URL,
+		Method:   "GET",
+		Timeout:  5 * time.Second,
+		Expected: types.Expected{Status: 200},
+	}
+
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Timings)
+	assert.GreaterOrEqual(t, result.Timings.TTFB, 50*time.Millisecond)
+	assert.False(t, result.Timings.Reused)
+
+	// A TTFB limit below the server delay marks the check as slow
+	check.Expected.TTFBMax = 10 * time.Millisecond
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusSlow, result.Status)
+	assert.Contains(t, result.Error, "time to first byte")
+}
+
+func TestHTTPChecker_MaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 64<<10) + "marker"))
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker(5 * time.Second)
+	check := types.CheckConfig{
+		Name:        "Large",
+		URL:         server.URL,
+		Method:      "GET",
+		Timeout:     5 * time.Second,
+		MaxBodySize: 1 << 10,
+		Expected:    types.Expected{Status: 200},
+	}
+
+	result := checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, int64(1<<10), result.BodySize)
+	assert.True(t, result.BodyTruncated)
+
+	// Content past the limit is not seen, and the error says why
+	check.Expected.BodyContains = "marker"
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "body truncated at 1024 bytes")
+
+	// Skipping the body still reports the advertised size
+	check.Expected.BodyContains = ""
+	check.SkipBody = true
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.False(t, result.BodyTruncated)
+}
+
+func TestHTTPChecker_ContentFingerprint(t *testing.T) {
+	body := "<h1>Status</h1>\r\n<p>All systems operational</p>   \r\n\r\n<p>Updated 10:42:01</p>\r\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	checker := NewHTTPChecker(5 * time.Second)
+	check := types.CheckConfig{
+		Name:    "Status page",
+		URL:     server.URL,
+		Method:  "GET",
+		Timeout: 5 * time.Second,
+		Expected: types.Expected{
+			Status:               200,
+			ContentAlertOnChange: true,
+			ContentIgnore:        []string{`Updated \d{2}:\d{2}:\d{2}`},
+		},
+	}
+
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Content)
+	assert.Len(t, result.Content.Hash, 64)
+	assert.Equal(t, "<h1>Status</h1>\n<p>All systems operational</p>\n<p></p>", result.Content.Text)
+
+	// Whitespace, line endings and ignored timestamps do not change the hash
+	body = "<h1>Status</h1>\n<p>All systems operational</p>\n<p>Updated 11:05:37</p>"
+	again := checker.Check(check)
+	require.NotNil(t, again.Content)
+	assert.Equal(t, result.Content.Hash, again.Content.Hash)
+
+	body = "<h1>Status</h1>\n<p>Degraded performance</p>\n<p>Updated 11:06:00</p>"
+	changed := checker.Check(check)
+	require.NotNil(t, changed.Content)
+	assert.NotEqual(t, result.Content.Hash, changed.Content.Hash)
+
+	// Without the option no fingerprint is taken
+	check.Expected.ContentAlertOnChange = false
+	check.Expected.ContentIgnore = nil
+	assert.Nil(t, checker.Check(check).Content)
+}
@@ -0,0 +1,195 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+var imapProtocol = mailProtocol{
+	endpointFormat: endpointFormat{
+		name:        "IMAP",
+		schemes:     []string{"imap", "imaps"},
+		defaultPort: "143",
+		schemePorts: map[string]string{"imaps": "993"},
+	},
+	implicitTLS: "imaps",
+	session:     imapSession,
+}
+
+var (
+	imapLiteral    = regexp.MustCompile(`\{(\d+)\}$`)
+	imapCapability = regexp.MustCompile(`(?i)\[CAPABILITY ([^\]]*)\]`)
+)
+
+// imapClient tags commands on a mail connection
+type imapClient struct {
+	*mailConn
+	tag int
+}
+
+// imapSession reads the greeting, upgrades with STARTTLS, logs in and
+// examines the mailbox named by the URL path (INBOX by default) read-only.
+// A PREAUTH greeting skips STARTTLS and LOGIN since the session is already
+// authenticated.
+func imapSession(ctx context.Context, m *mailConn, target *endpoint) (*types.MailInfo, error) {
+	imap := &imapClient{mailConn: m}
+
+	greeting, err := imap.readResponse()
+	if err != nil {
+		return nil, fmt.Errorf("reading greeting failed: %w", err)
+	}
+	info := &types.MailInfo{Banner: strings.TrimPrefix(greeting, "* ")}
+	status, _, _ := strings.Cut(info.Banner, " ")
+	preauth := strings.EqualFold(status, "PREAUTH")
+	if !strings.EqualFold(status, "OK") && !preauth {
+		return info, fmt.Errorf("server refused the session: %s", info.Banner)
+	}
+
+	if info.Extensions, err = imap.capabilities(greeting); err != nil {
+		return info, err
+	}
+	if !preauth {
+		if err := imap.login(ctx, target, info); err != nil {
+			return info, err
+		}
+	}
+
+	info.Mailbox = target.path
+	if info.Mailbox == "" {
+		info.Mailbox = "INBOX"
+	}
+	untagged, err := imap.command("EXAMINE " + imapQuote(info.Mailbox))
+	if err != nil {
+		return info, fmt.Errorf("examining mailbox '%s' failed: %w", info.Mailbox, err)
+	}
+	for _, line := range untagged {
+		count, kind, _ := strings.Cut(line, " ")
+		if strings.EqualFold(kind, "EXISTS") {
+			info.Messages, _ = strconv.ParseInt(count, 10, 64)
+		}
+	}
+
+	imap.command("LOGOUT")
+	return info, nil
+}
+
+// login upgrades with STARTTLS when wanted, then logs in with LOGIN
+func (c *imapClient) login(ctx context.Context, target *endpoint, info *types.MailInfo) error {
+	if target.user == "" {
+		return fmt.Errorf("URL must include a user name")
+	}
+
+	starttls, err := c.wantStartTLS(target, hasExtension(info.Extensions, "STARTTLS"))
+	if err != nil {
+		return err
+	}
+	if starttls {
+		if _, err := c.command("STARTTLS"); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+		if err := c.startTLS(ctx); err != nil {
+			return err
+		}
+		info.StartTLS = true
+		if info.Extensions, err = c.capabilities(""); err != nil {
+			return err
+		}
+	}
+
+	if hasExtension(info.Extensions, "LOGINDISABLED") {
+		return fmt.Errorf("server does not allow LOGIN on this connection")
+	}
+	if err := c.credentialsAllowed(target.host); err != nil {
+		return err
+	}
+	if _, err := c.command("LOGIN " + imapQuote(target.user) + " " + imapQuote(target.password)); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	return nil
+}
+
+// capabilities returns the capability list from the greeting's response
+// code when present, or asks for it
+func (c *imapClient) capabilities(greeting string) ([]string, error) {
+	if match := imapCapability.FindStringSubmatch(greeting); match != nil {
+		return strings.Fields(match[1]), nil
+	}
+	untagged, err := c.command("CAPABILITY")
+	if err != nil {
+		return nil, fmt.Errorf("CAPABILITY failed: %w", err)
+	}
+	for _, line := range untagged {
+		if fields := strings.Fields(line); len(fields) > 0 && strings.EqualFold(fields[0], "CAPABILITY") {
+			return fields[1:], nil
+		}
+	}
+	return nil, nil
+}
+
+// command sends a tagged command and returns its untagged responses without
+// the leading "* ". A NO or BAD completion is returned as an error holding
+// the server's text; the command itself is left out since it may carry
+// credentials.
+func (c *imapClient) command(command string) ([]string, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if err := c.writeLine(tag + " " + command); err != nil {
+		return nil, err
+	}
+
+	var untagged []string
+	for {
+		line, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if rest, ok := strings.CutPrefix(line, tag+" "); ok {
+			status, _, _ := strings.Cut(rest, " ")
+			if !strings.EqualFold(status, "OK") {
+				return untagged, fmt.Errorf("%s", rest)
+			}
+			return untagged, nil
+		}
+		if rest, ok := strings.CutPrefix(line, "* "); ok {
+			untagged = append(untagged, rest)
+		}
+	}
+}
+
+// readResponse reads one response line, inlining any literals it announces
+func (c *imapClient) readResponse() (string, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	for {
+		match := imapLiteral.FindStringSubmatch(line)
+		if match == nil {
+			return line, nil
+		}
+		size, err := strconv.Atoi(match[1])
+		if err != nil || size > maxMailLine {
+			return "", fmt.Errorf("literal of %s bytes exceeds limit", match[1])
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.reader, literal); err != nil {
+			return "", err
+		}
+		rest, err := c.readLine()
+		if err != nil {
+			return "", err
+		}
+		line = line[:len(line)-len(match[0])] + string(literal) + rest
+	}
+}
+
+// imapQuote renders a quoted string
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,195 @@
+package checker
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIMAP is a minimal IMAP server with one mailbox
+type fakeIMAP struct {
+	tls      *tls.Config // offers STARTTLS when set
+	preauth  bool
+	user     string
+	password string
+	messages int
+}
+
+func (f *fakeIMAP) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	send := func(lines ...string) {
+		for _, line := range lines {
+			fmt.Fprintf(conn, "%s\r\n", line)
+		}
+	}
+
+	if f.preauth {
+		send("* PREAUTH [CAPABILITY IMAP4rev1 IDLE] Logged in as " + f.user)
+	} else {
+		send("* OK Fake IMAP ready.")
+	}
+	encrypted := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, command, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		verb, arg, _ := strings.Cut(command, " ")
+		switch strings.ToUpper(verb) {
+		case "CAPABILITY":
+			capabilities := "IMAP4rev1 IDLE"
+			if f.tls != nil && !encrypted {
+				capabilities += " STARTTLS LOGINDISABLED"
+			}
+			send("* CAPABILITY "+capabilities, tag+" OK Capability completed.")
+		case "STARTTLS":
+			send(tag + " OK Begin TLS negotiation now.")
+			tlsConn := tls.Server(conn, f.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, reader, encrypted = tlsConn, bufio.NewReader(tlsConn), true
+		case "LOGIN":
+			if arg == imapQuote(f.user)+" "+imapQuote(f.password) {
+				send(tag + " OK Logged in")
+			} else {
+				send(tag + " NO [AUTHENTICATIONFAILED] Authentication failed.")
+			}
+		case "EXAMINE":
+			if arg != `"INBOX"` {
+				send(tag + " NO [NONEXISTENT] Mailbox doesn't exist: " + strings.Trim(arg, `"`))
+				continue
+			}
+			send("* FLAGS (\\Answered \\Flagged \\Deleted \\Seen \\Draft)",
+				fmt.Sprintf("* %d EXISTS", f.messages), "* 0 RECENT",
+				tag+" OK [READ-ONLY] Examine completed.")
+		case "LOGOUT":
+			send("* BYE Logging out", tag+" OK Logout completed.")
+			return
+		default:
+			send(tag + " BAD Error in IMAP command")
+		}
+	}
+}
+
+func TestIMAPChecker(t *testing.T) {
+	tests := []struct {
+		name         string
+		server       fakeIMAP
+		url          string
+		expected     types.Expected
+		wantStatus   types.Status
+		wantError    string
+		wantMessages int64
+	}{
+		{
+			name:         "login and count",
+			url:          "imap://monitor:se%%22cret@%s",
+			expected:     types.Expected{Extensions: []string{"IDLE"}},
+			wantStatus:   types.StatusUp,
+			wantMessages: 42,
+		},
+		{
+			name:         "too many messages",
+			url:          "imap://monitor:se%%22cret@%s/INBOX",
+			expected:     types.Expected{MessagesMax: 10},
+			wantStatus:   types.StatusWarning,
+			wantError:    "Mailbox 'INBOX' holds 42 messages, maximum 10",
+			wantMessages: 42,
+		},
+		{
+			name:       "wrong password",
+			url:        "imap://monitor:wrong@%s",
+			wantStatus: types.StatusDown,
+			wantError:  "authentication failed: NO [AUTHENTICATIONFAILED]",
+		},
+		{
+			name:       "unknown mailbox",
+			url:        "imap://monitor:se%%22cret@%s/Archive",
+			wantStatus: types.StatusDown,
+			wantError:  "examining mailbox 'Archive' failed: NO [NONEXISTENT]",
+		},
+		{
+			name:       "missing user",
+			url:        "imap://%s",
+			wantStatus: types.StatusDown,
+			wantError:  "URL must include a user name",
+		},
+		{
+			name:         "preauthenticated",
+			server:       fakeIMAP{preauth: true},
+			url:          "imap://%s",
+			wantStatus:   types.StatusUp,
+			wantMessages: 42,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			server.user, server.password, server.messages = "monitor", `se"cret`, 42
+			addr := startFakeBroker(t, server.serve)
+
+			result := NewIMAPChecker(5 * time.Second).Check(types.CheckConfig{
+				Name:     "mailbox",
+				Type:     types.CheckTypeIMAP,
+				URL:      fmt.Sprintf(tt.url, addr),
+				Timeout:  5 * time.Second,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+			if tt.wantMessages > 0 && assert.NotNil(t, result.Mail) {
+				assert.Equal(t, tt.wantMessages, result.Mail.Messages)
+				assert.Equal(t, "INBOX", result.Mail.Mailbox)
+			}
+		})
+	}
+}
+
+func TestIMAPChecker_STARTTLS(t *testing.T) {
+	serverTLS, caPath := mailTLS(t)
+	addr := startFakeBroker(t, (&fakeIMAP{tls: serverTLS, user: "monitor", password: "secret", messages: 3}).serve)
+
+	result := NewIMAPChecker(5 * time.Second).Check(types.CheckConfig{
+		Name:    "mailbox",
+		Type:    types.CheckTypeIMAP,
+		URL:     "imap://monitor:secret@" + addr,
+		Timeout: 5 * time.Second,
+		TLS:     types.TLSConfig{CAFile: caPath},
+	})
+
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Mail)
+	assert.True(t, result.Mail.StartTLS)
+	assert.Equal(t, int64(3), result.Mail.Messages)
+	require.NotNil(t, result.CertInfo)
+	assert.Equal(t, "mail.test", result.CertInfo.CommonName)
+}
+
+func TestIMAPChecker_LoginDisabledWithoutTLS(t *testing.T) {
+	serverTLS, _ := mailTLS(t)
+	addr := startFakeBroker(t, (&fakeIMAP{tls: serverTLS, user: "monitor", password: "secret"}).serve)
+
+	result := NewIMAPChecker(5 * time.Second).Check(types.CheckConfig{
+		Name:    "mailbox",
+		Type:    types.CheckTypeIMAP,
+		URL:     "imap://monitor:secret@" + addr + "?starttls=false",
+		Timeout: 5 * time.Second,
+	})
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "server does not allow LOGIN on this connection")
+}
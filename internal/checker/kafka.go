@@ -0,0 +1,315 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// Kafka protocol constants
+const (
+	kafkaAPIMetadata         = 3
+	kafkaAPISaslHandshake    = 17
+	kafkaAPISaslAuthenticate = 36
+	kafkaMaxResponse         = 16 << 20
+	kafkaClientID            = "healthcheck-cli"
+)
+
+var kafkaProtocol = brokerProtocol{
+	endpointFormat: endpointFormat{name: "Kafka", schemes: []string{"kafka"}, defaultPort: "9092"},
+	inspect:        inspectKafka,
+}
+
+// kafkaErrorNames names the error codes a metadata probe can run into
+var kafkaErrorNames = map[int16]string{
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	33: "UNSUPPORTED_SASL_MECHANISM",
+	34: "ILLEGAL_SASL_STATE",
+	35: "UNSUPPORTED_VERSION",
+	58: "SASL_AUTHENTICATION_FAILED",
+}
+
+// kafkaError is an error code returned by the broker
+type kafkaError int16
+
+func (e kafkaError) Error() string {
+	if name, ok := kafkaErrorNames[int16(e)]; ok {
+		return name
+	}
+	return fmt.Sprintf("error code %d", int16(e))
+}
+
+// kafkaConn sends requests and reads their responses
+type kafkaConn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	correlation int32
+}
+
+// inspectKafka fetches cluster metadata and counts brokers and partitions
+// that have no leader or are missing in-sync replicas. The URL's tls
+// parameter enables TLS (true or skip-verify); a user name enables SASL with
+// the mechanism in the sasl parameter (PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512).
+func inspectKafka(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, options types.BrokerConfig) (*types.BrokerInfo, error) {
+	switch mode := target.params.Get("tls"); mode {
+	case "", "false":
+	case "true", "skip-verify":
+		if mode == "skip-verify" {
+			tlsConfig = unverifiedTLS(tlsConfig)
+		}
+		var err error
+		if conn, err = startTLS(ctx, conn, tlsConfig); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tls mode '%s'", mode)
+	}
+	kafka := &kafkaConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if target.user != "" {
+		if err := kafka.authenticate(target); err != nil {
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	request := binary.BigEndian.AppendUint32(nil, 0xffffffff) // null: all topics
+	if options.Topic != "" {
+		request = binary.BigEndian.AppendUint32(nil, 1)
+		request = kafkaString(request, options.Topic)
+	}
+	request = append(request, 0) // allow_auto_topic_creation
+	response, err := kafka.call(kafkaAPIMetadata, 4, request)
+	if err != nil {
+		return nil, fmt.Errorf("metadata request failed: %w", err)
+	}
+
+	return parseKafkaMetadata(response, options.Topic)
+}
+
+// parseKafkaMetadata reads a Metadata v4 response
+func parseKafkaMetadata(response *kafkaReader, topic string) (*types.BrokerInfo, error) {
+	info := &types.BrokerInfo{}
+
+	response.int32() // throttle_time_ms
+	for i := response.array(); i > 0; i-- {
+		response.int32()  // node_id
+		response.string() // host
+		response.int32()  // port
+		response.string() // rack
+		info.Brokers++
+	}
+	info.Server = response.string() // cluster_id
+	response.int32()                // controller_id
+
+	for i := response.array(); i > 0 && response.err == nil; i-- {
+		topicErr := response.int16()
+		name := response.string()
+		response.uint8() // is_internal
+		if topicErr == 3 || topicErr == 29 {
+			return nil, fmt.Errorf("topic '%s' unavailable: %w", name, kafkaError(topicErr))
+		}
+		info.Topics++
+
+		partitions := response.array()
+		if topicErr != 0 && partitions == 0 {
+			// e.g. LEADER_NOT_AVAILABLE while the topic is being created
+			info.Partitions++
+			info.OfflinePartitions++
+		}
+		for ; partitions > 0 && response.err == nil; partitions-- {
+			partitionErr := response.int16()
+			response.int32() // partition_index
+			leader := response.int32()
+			replicas := response.array()
+			for j := 0; j < replicas; j++ {
+				response.int32()
+			}
+			isr := response.array()
+			for j := 0; j < isr; j++ {
+				response.int32()
+			}
+
+			info.Partitions++
+			switch {
+			case leader < 0 || partitionErr == 5:
+				info.OfflinePartitions++
+			case isr < replicas:
+				info.UnderReplicated++
+			}
+		}
+	}
+	if response.err != nil {
+		return nil, fmt.Errorf("malformed metadata response: %w", response.err)
+	}
+	if topic != "" && info.Topics == 0 {
+		return nil, fmt.Errorf("topic '%s' unavailable: %w", topic, kafkaError(3))
+	}
+	return info, nil
+}
+
+// authenticate runs SaslHandshake and SaslAuthenticate
+func (k *kafkaConn) authenticate(target *endpoint) error {
+	mechanism := strings.ToUpper(target.params.Get("sasl"))
+	if mechanism == "" {
+		mechanism = "PLAIN"
+	}
+	var newHash func() hash.Hash
+	switch mechanism {
+	case "PLAIN":
+	case "SCRAM-SHA-256":
+		newHash = sha256.New
+	case "SCRAM-SHA-512":
+		newHash = sha512.New
+	default:
+		return fmt.Errorf("unsupported SASL mechanism '%s'", mechanism)
+	}
+
+	response, err := k.call(kafkaAPISaslHandshake, 1, kafkaString(nil, mechanism))
+	if err != nil {
+		return err
+	}
+	if code := response.int16(); code != 0 {
+		return fmt.Errorf("broker does not accept %s: %w", mechanism, kafkaError(code))
+	}
+
+	if newHash == nil {
+		_, err := k.saslAuthenticate([]byte("\x00" + target.user + "\x00" + target.password))
+		return err
+	}
+
+	scram, err := newSCRAMClient(newHash, target.user, target.password)
+	if err != nil {
+		return err
+	}
+	serverFirst, err := k.saslAuthenticate(scram.first())
+	if err != nil {
+		return err
+	}
+	clientFinal, err := scram.final(serverFirst)
+	if err != nil {
+		return err
+	}
+	serverFinal, err := k.saslAuthenticate(clientFinal)
+	if err != nil {
+		return err
+	}
+	return scram.verify(serverFinal)
+}
+
+// saslAuthenticate sends one SASL message and returns the broker's answer
+func (k *kafkaConn) saslAuthenticate(message []byte) ([]byte, error) {
+	request := binary.BigEndian.AppendUint32(nil, uint32(len(message)))
+	response, err := k.call(kafkaAPISaslAuthenticate, 0, append(request, message...))
+	if err != nil {
+		return nil, err
+	}
+	code := response.int16()
+	text := response.string()
+	reply := response.bytes()
+	if code != 0 {
+		if text != "" {
+			return nil, fmt.Errorf("%w: %s", kafkaError(code), text)
+		}
+		return nil, kafkaError(code)
+	}
+	if response.err != nil {
+		return nil, fmt.Errorf("malformed SaslAuthenticate response: %w", response.err)
+	}
+	return reply, nil
+}
+
+// call sends a request with a v1 header and returns the response body
+func (k *kafkaConn) call(apiKey, apiVersion int16, body []byte) (*kafkaReader, error) {
+	k.correlation++
+
+	request := make([]byte, 4, 14+len(kafkaClientID)+len(body))
+	request = binary.BigEndian.AppendUint16(request, uint16(apiKey))
+	request = binary.BigEndian.AppendUint16(request, uint16(apiVersion))
+	request = binary.BigEndian.AppendUint32(request, uint32(k.correlation))
+	request = kafkaString(request, kafkaClientID)
+	request = append(request, body...)
+	binary.BigEndian.PutUint32(request, uint32(len(request)-4))
+	if _, err := k.conn.Write(request); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(k.reader, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size < 4 || size > kafkaMaxResponse {
+		return nil, fmt.Errorf("invalid response size %d", size)
+	}
+	if correlation := int32(binary.BigEndian.Uint32(header[4:])); correlation != k.correlation {
+		return nil, fmt.Errorf("response correlation id %d does not match request %d", correlation, k.correlation)
+	}
+	response := make([]byte, size-4)
+	if _, err := io.ReadFull(k.reader, response); err != nil {
+		return nil, err
+	}
+	return &kafkaReader{wireReader{data: response}}, nil
+}
+
+// kafkaString appends an int16 length-prefixed string
+func kafkaString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
+
+// kafkaReader decodes response fields
+type kafkaReader struct {
+	wireReader
+}
+
+func (r *kafkaReader) int16() int16 {
+	return int16(r.uint16())
+}
+
+func (r *kafkaReader) int32() int32 {
+	return int32(r.uint32())
+}
+
+// string reads a nullable string, returning "" for null
+func (r *kafkaReader) string() string {
+	length := r.int16()
+	if length < 0 {
+		return ""
+	}
+	return string(r.take(int(length)))
+}
+
+// bytes reads a nullable byte array
+func (r *kafkaReader) bytes() []byte {
+	length := r.int32()
+	if length < 0 {
+		return nil
+	}
+	return r.take(int(length))
+}
+
+// array reads an array length, returning 0 for null. Lengths that cannot
+// fit in the remaining data are reported as errors.
+func (r *kafkaReader) array() int {
+	length := r.int32()
+	if length < 0 {
+		return 0
+	}
+	if int(length) > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	return int(length)
+}
@@ -0,0 +1,290 @@
+package checker
+
+import (
+	"bufio"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePartition is one partition: its leader, replica count and in-sync count
+type fakePartition struct {
+	leader   int32
+	replicas int
+	isr      int
+}
+
+// fakeKafka is a minimal Kafka broker that answers metadata requests
+type fakeKafka struct {
+	mechanism string // SASL mechanism required, none when empty
+	user      string
+	password  string
+	brokers   int
+	topics    map[string][]fakePartition
+}
+
+func (f *fakeKafka) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	authenticated := f.mechanism == ""
+	var scram *fakeSCRAMServer
+
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return
+		}
+		request := make([]byte, binary.BigEndian.Uint32(header))
+		if _, err := io.ReadFull(reader, request); err != nil {
+			return
+		}
+		req := &kafkaReader{wireReader{data: request}}
+		apiKey := req.int16()
+		req.int16()
+		correlation := req.uint32()
+		req.string()
+
+		response := binary.BigEndian.AppendUint32(nil, correlation)
+		switch apiKey {
+		case kafkaAPISaslHandshake:
+			code := int16(0)
+			if req.string() != f.mechanism {
+				code = 33
+			}
+			response = binary.BigEndian.AppendUint16(response, uint16(code))
+			response = binary.BigEndian.AppendUint32(response, 1)
+			response = kafkaString(response, f.mechanism)
+		case kafkaAPISaslAuthenticate:
+			message := string(req.bytes())
+			var reply string
+			ok := true
+			switch {
+			case f.mechanism == "PLAIN":
+				ok = message == "\x00"+f.user+"\x00"+f.password
+				authenticated = ok
+			case scram == nil:
+				scram = &fakeSCRAMServer{hash: sha512.New, user: f.user, password: f.password}
+				reply = scram.first(message)
+			default:
+				reply, ok = scram.final(message)
+				authenticated = ok
+			}
+			if ok {
+				response = append(response, 0, 0, 0xff, 0xff)
+			} else {
+				response = append(response, 0, 58)
+				response = kafkaString(response, "Authentication failed: Invalid username or password")
+			}
+			response = binary.BigEndian.AppendUint32(response, uint32(len(reply)))
+			response = append(response, reply...)
+		case kafkaAPIMetadata:
+			if !authenticated {
+				return
+			}
+			response = append(response, f.metadata(req)...)
+		default:
+			return
+		}
+
+		conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(response))), response...))
+	}
+}
+
+// metadata builds a Metadata v4 response body
+func (f *fakeKafka) metadata(req *kafkaReader) []byte {
+	names := make([]string, 0, len(f.topics))
+	if count := req.int32(); count < 0 {
+		for name := range f.topics {
+			names = append(names, name)
+		}
+	} else {
+		for ; count > 0; count-- {
+			names = append(names, req.string())
+		}
+	}
+
+	body := binary.BigEndian.AppendUint32(nil, 0)
+	body = binary.BigEndian.AppendUint32(body, uint32(f.brokers))
+	for i := 0; i < f.brokers; i++ {
+		body = binary.BigEndian.AppendUint32(body, uint32(i+1))
+		body = kafkaString(body, "kafka")
+		body = binary.BigEndian.AppendUint32(body, 9092)
+		body = append(body, 0xff, 0xff)
+	}
+	body = kafkaString(body, "fake-cluster")
+	body = binary.BigEndian.AppendUint32(body, 1)
+
+	body = binary.BigEndian.AppendUint32(body, uint32(len(names)))
+	for _, name := range names {
+		partitions, ok := f.topics[name]
+		if !ok {
+			body = append(body, 0, 3)
+		} else {
+			body = append(body, 0, 0)
+		}
+		body = kafkaString(body, name)
+		body = append(body, 0)
+		body = binary.BigEndian.AppendUint32(body, uint32(len(partitions)))
+		for i, partition := range partitions {
+			body = append(body, 0, 0)
+			body = binary.BigEndian.AppendUint32(body, uint32(i))
+			body = binary.BigEndian.AppendUint32(body, uint32(partition.leader))
+			body = binary.BigEndian.AppendUint32(body, uint32(partition.replicas))
+			for j := 0; j < partition.replicas; j++ {
+				body = binary.BigEndian.AppendUint32(body, uint32(j+1))
+			}
+			body = binary.BigEndian.AppendUint32(body, uint32(partition.isr))
+			for j := 0; j < partition.isr; j++ {
+				body = binary.BigEndian.AppendUint32(body, uint32(j+1))
+			}
+		}
+	}
+	return body
+}
+
+func TestKafkaChecker(t *testing.T) {
+	healthy := map[string][]fakePartition{
+		"orders":   {{leader: 1, replicas: 3, isr: 3}, {leader: 2, replicas: 3, isr: 3}},
+		"payments": {{leader: 3, replicas: 3, isr: 3}},
+	}
+
+	tests := []struct {
+		name                string
+		server              fakeKafka
+		url                 string
+		broker              types.BrokerConfig
+		expected            types.Expected
+		wantStatus          types.Status
+		wantError           string
+		wantTopics          int
+		wantPartitions      int
+		wantUnderReplicated int
+		wantOffline         int
+	}{
+		{
+			name:           "all topics healthy",
+			server:         fakeKafka{brokers: 3, topics: healthy},
+			expected:       types.Expected{BrokersMin: 3},
+			wantStatus:     types.StatusUp,
+			wantTopics:     2,
+			wantPartitions: 3,
+		},
+		{
+			name:           "single topic",
+			server:         fakeKafka{brokers: 3, topics: healthy},
+			broker:         types.BrokerConfig{Topic: "orders"},
+			wantStatus:     types.StatusUp,
+			wantTopics:     1,
+			wantPartitions: 2,
+		},
+		{
+			name:       "unknown topic",
+			server:     fakeKafka{brokers: 3, topics: healthy},
+			broker:     types.BrokerConfig{Topic: "missing"},
+			wantStatus: types.StatusDown,
+			wantError:  "topic 'missing' unavailable: UNKNOWN_TOPIC_OR_PARTITION",
+		},
+		{
+			name:           "too few brokers",
+			server:         fakeKafka{brokers: 2, topics: healthy},
+			expected:       types.Expected{BrokersMin: 3},
+			wantStatus:     types.StatusWarning,
+			wantError:      "2 brokers available, expected at least 3",
+			wantTopics:     2,
+			wantPartitions: 3,
+		},
+		{
+			name: "under-replicated partition",
+			server: fakeKafka{brokers: 3, topics: map[string][]fakePartition{
+				"orders": {{leader: 1, replicas: 3, isr: 2}, {leader: 2, replicas: 3, isr: 3}},
+			}},
+			wantStatus:          types.StatusWarning,
+			wantError:           "1 of 2 partitions are under-replicated",
+			wantTopics:          1,
+			wantPartitions:      2,
+			wantUnderReplicated: 1,
+		},
+		{
+			name: "partition without leader",
+			server: fakeKafka{brokers: 3, topics: map[string][]fakePartition{
+				"orders": {{leader: -1, replicas: 3, isr: 0}, {leader: 2, replicas: 3, isr: 3}},
+			}},
+			wantStatus:     types.StatusDown,
+			wantError:      "1 of 2 partitions have no leader",
+			wantTopics:     1,
+			wantPartitions: 2,
+			wantOffline:    1,
+		},
+		{
+			name:           "sasl plain",
+			server:         fakeKafka{mechanism: "PLAIN", user: "monitor", password: "secret", brokers: 1, topics: healthy},
+			url:            "kafka://monitor:secret@%s",
+			wantStatus:     types.StatusUp,
+			wantTopics:     2,
+			wantPartitions: 3,
+		},
+		{
+			name:       "sasl plain wrong password",
+			server:     fakeKafka{mechanism: "PLAIN", user: "monitor", password: "secret", brokers: 1, topics: healthy},
+			url:        "kafka://monitor:wrong@%s",
+			wantStatus: types.StatusDown,
+			wantError:  "authentication failed: SASL_AUTHENTICATION_FAILED",
+		},
+		{
+			name:           "sasl scram-sha-512",
+			server:         fakeKafka{mechanism: "SCRAM-SHA-512", user: "monitor", password: "secret", brokers: 1, topics: healthy},
+			url:            "kafka://monitor:secret@%s?sasl=scram-sha-512",
+			wantStatus:     types.StatusUp,
+			wantTopics:     2,
+			wantPartitions: 3,
+		},
+		{
+			name:       "mechanism not enabled",
+			server:     fakeKafka{mechanism: "SCRAM-SHA-512", user: "monitor", password: "secret", brokers: 1, topics: healthy},
+			url:        "kafka://monitor:secret@%s",
+			wantStatus: types.StatusDown,
+			wantError:  "broker does not accept PLAIN: UNSUPPORTED_SASL_MECHANISM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			addr := startFakeBroker(t, server.serve)
+
+			url := "kafka://" + addr
+			if tt.url != "" {
+				url = fmt.Sprintf(tt.url, addr)
+			}
+			result := NewKafkaChecker(5 * time.Second).Check(types.CheckConfig{
+				Name:     "kafka",
+				Type:     types.CheckTypeKafka,
+				URL:      url,
+				Timeout:  5 * time.Second,
+				Broker:   tt.broker,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+			if tt.wantPartitions == 0 {
+				return
+			}
+			require.NotNil(t, result.Broker)
+			assert.Equal(t, "fake-cluster", result.Broker.Server)
+			assert.Equal(t, tt.wantTopics, result.Broker.Topics)
+			assert.Equal(t, tt.wantPartitions, result.Broker.Partitions)
+			assert.Equal(t, tt.wantUnderReplicated, result.Broker.UnderReplicated)
+			assert.Equal(t, tt.wantOffline, result.Broker.OfflinePartitions)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// procRoot is where the Linux process information pseudo-filesystem is mounted
+const procRoot = "/proc"
+
+// diskStats describes the filesystem holding a path, in bytes and inodes
+type diskStats struct {
+	total      uint64
+	available  uint64 // free space usable by unprivileged users
+	inodes     uint64
+	inodesFree uint64
+}
+
+// DiskChecker checks free space and inodes on the filesystem holding the check URL path
+type DiskChecker struct{}
+
+// NewDiskChecker creates a disk checker
+func NewDiskChecker() *DiskChecker {
+	return &DiskChecker{}
+}
+
+// Name returns the checker name
+func (d *DiskChecker) Name() string {
+	return "Disk"
+}
+
+// Check reads the filesystem usage and applies disk_free_min and inodes_free_min
+func (d *DiskChecker) Check(check types.CheckConfig) types.Result {
+	result := localResult(check)
+
+	stats, err := diskUsage(check.URL)
+	result.ResponseTime = time.Since(result.Timestamp)
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Cannot read filesystem at %s: %v", check.URL, err)
+		return result
+	}
+
+	expected := check.Expected
+	free := percent(stats.available, stats.total)
+	result.Metrics = []types.Metric{
+		{Label: "disk_free", Value: round2(free), Unit: "%", Warn: minThreshold(expected.DiskFreeMin), Min: "0", Max: "100"},
+		{Label: "disk_free_bytes", Value: float64(stats.available), Unit: "B", Min: "0", Max: strconv.FormatUint(stats.total, 10)},
+	}
+	summary := fmt.Sprintf("%.1f%% free (%s of %s)", free, formatBytes(stats.available), formatBytes(stats.total))
+
+	var problems []string
+	if expected.DiskFreeMin > 0 && free < expected.DiskFreeMin {
+		problems = append(problems, fmt.Sprintf("%.1f%% disk space free (minimum %g%%)", free, expected.DiskFreeMin))
+	}
+
+	// Some filesystems, such as btrfs, do not have a fixed number of inodes
+	if stats.inodes > 0 {
+		inodesFree := percent(stats.inodesFree, stats.inodes)
+		result.Metrics = append(result.Metrics, types.Metric{
+			Label: "inodes_free", Value: round2(inodesFree), Unit: "%", Warn: minThreshold(expected.InodesFreeMin), Min: "0", Max: "100",
+		})
+		summary += fmt.Sprintf(", %.1f%% inodes free", inodesFree)
+		if expected.InodesFreeMin > 0 && inodesFree < expected.InodesFreeMin {
+			problems = append(problems, fmt.Sprintf("%.1f%% inodes free (minimum %g%%)", inodesFree, expected.InodesFreeMin))
+		}
+	}
+
+	concludeLocal(&result, problems, types.StatusWarning, summary)
+	return result
+}
+
+// MemoryChecker checks available memory and swap use from /proc/meminfo
+type MemoryChecker struct {
+	proc string
+}
+
+// NewMemoryChecker creates a memory checker
+func NewMemoryChecker() *MemoryChecker {
+	return &MemoryChecker{proc: procRoot}
+}
+
+// Name returns the checker name
+func (m *MemoryChecker) Name() string {
+	return "Memory"
+}
+
+// Check reads memory usage and applies memory_available_min and swap_used_max
+func (m *MemoryChecker) Check(check types.CheckConfig) types.Result {
+	result := localResult(check)
+
+	info, err := readMeminfo(filepath.Join(m.proc, "meminfo"))
+	result.ResponseTime = time.Since(result.Timestamp)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Cannot read memory usage: %v", err)
+		return result
+	}
+
+	total := info["MemTotal"]
+	available, ok := info["MemAvailable"]
+	if !ok {
+		// Kernels before 3.14 do not estimate available memory
+		available = info["MemFree"] + info["Buffers"] + info["Cached"]
+	}
+	if total == 0 {
+		result.Status = types.StatusError
+		result.Error = "Cannot read memory usage: MemTotal missing from meminfo"
+		return result
+	}
+
+	expected := check.Expected
+	availablePercent := percent(available, total)
+	result.Metrics = []types.Metric{
+		{Label: "memory_available", Value: round2(availablePercent), Unit: "%", Warn: minThreshold(expected.MemoryAvailableMin), Min: "0", Max: "100"},
+		{Label: "memory_available_bytes", Value: float64(available), Unit: "B", Min: "0", Max: strconv.FormatUint(total, 10)},
+	}
+	summary := fmt.Sprintf("%.1f%% available (%s of %s)", availablePercent, formatBytes(available), formatBytes(total))
+
+	var problems []string
+	if expected.MemoryAvailableMin > 0 && availablePercent < expected.MemoryAvailableMin {
+		problems = append(problems, fmt.Sprintf("%.1f%% memory available (minimum %g%%)", availablePercent, expected.MemoryAvailableMin))
+	}
+
+	if swapTotal := info["SwapTotal"]; swapTotal > 0 {
+		swapUsed := percent(swapTotal-info["SwapFree"], swapTotal)
+		result.Metrics = append(result.Metrics, types.Metric{
+			Label: "swap_used", Value: round2(swapUsed), Unit: "%", Warn: maxThreshold(expected.SwapUsedMax), Min: "0", Max: "100",
+		})
+		summary += fmt.Sprintf(", %.1f%% swap used", swapUsed)
+		if expected.SwapUsedMax > 0 && swapUsed > expected.SwapUsedMax {
+			problems = append(problems, fmt.Sprintf("%.1f%% swap used (maximum %g%%)", swapUsed, expected.SwapUsedMax))
+		}
+	}
+
+	concludeLocal(&result, problems, types.StatusWarning, summary)
+	return result
+}
+
+// readMeminfo parses /proc/meminfo into bytes per field
+func readMeminfo(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// e.g. "MemAvailable:    8123456 kB"
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		amount, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			amount *= 1024
+		}
+		info[key] = amount
+	}
+	return info, scanner.Err()
+}
+
+// LoadChecker checks the load average from /proc/loadavg
+type LoadChecker struct {
+	proc string
+	cpus int
+}
+
+// NewLoadChecker creates a load checker
+func NewLoadChecker() *LoadChecker {
+	return &LoadChecker{proc: procRoot, cpus: runtime.NumCPU()}
+}
+
+// Name returns the checker name
+func (l *LoadChecker) Name() string {
+	return "Load"
+}
+
+// Check reads the load averages and applies load_max to the 5-minute average per CPU
+func (l *LoadChecker) Check(check types.CheckConfig) types.Result {
+	result := localResult(check)
+
+	data, err := os.ReadFile(filepath.Join(l.proc, "loadavg"))
+	result.ResponseTime = time.Since(result.Timestamp)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Cannot read load average: %v", err)
+		return result
+	}
+
+	// e.g. "0.52 0.58 0.59 1/389 12345"
+	fields := strings.Fields(string(data))
+	var loads [3]float64
+	for i := range loads {
+		if i >= len(fields) {
+			result.Status = types.StatusError
+			result.Error = fmt.Sprintf("Cannot read load average: unexpected loadavg '%s'", strings.TrimSpace(string(data)))
+			return result
+		}
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			result.Status = types.StatusError
+			result.Error = fmt.Sprintf("Cannot read load average: %v", err)
+			return result
+		}
+	}
+
+	expected := check.Expected
+	perCPU := loads[1] / float64(l.cpus)
+	result.Metrics = []types.Metric{
+		{Label: "load1", Value: loads[0]},
+		{Label: "load5", Value: loads[1]},
+		{Label: "load15", Value: loads[2]},
+		{Label: "load5_per_cpu", Value: round2(perCPU), Warn: maxThreshold(expected.LoadMax), Min: "0"},
+	}
+	summary := fmt.Sprintf("load average %.2f, %.2f, %.2f on %d CPUs", loads[0], loads[1], loads[2], l.cpus)
+
+	var problems []string
+	if expected.LoadMax > 0 && perCPU > expected.LoadMax {
+		problems = append(problems, fmt.Sprintf("5-minute load %.2f per CPU (maximum %g)", perCPU, expected.LoadMax))
+	}
+
+	concludeLocal(&result, problems, types.StatusWarning, summary)
+	return result
+}
+
+// localResult starts the result of a check on the local host
+func localResult(check types.CheckConfig) types.Result {
+	return types.Result{
+		Name:      check.Name,
+		URL:       check.URL,
+		Timestamp: time.Now(),
+	}
+}
+
+// concludeLocal fails the result with status when thresholds were crossed,
+// and otherwise reports the summary as the message of a healthy check
+func concludeLocal(result *types.Result, problems []string, status types.Status, summary string) {
+	if len(problems) > 0 {
+		result.Status = status
+		result.Error = strings.Join(problems, "; ")
+		return
+	}
+	result.Status = types.StatusUp
+	result.Message = summary
+}
+
+// minThreshold formats a lower bound as a Nagios range, alerting below it
+func minThreshold(limit float64) string {
+	if limit <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(limit, 'g', -1, 64) + ":"
+}
+
+// maxThreshold formats an upper bound as a Nagios range, alerting above it
+func maxThreshold(limit float64) string {
+	if limit <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(limit, 'g', -1, 64)
+}
+
+func percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+func round2(value float64) float64 {
+	return float64(int64(value*100+0.5)) / 100
+}
+
+// formatBytes formats a size with binary units, e.g. 21.4 GiB
+func formatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProc creates a fake /proc file under root
+func writeProc(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestDiskChecker(t *testing.T) {
+	checker := NewDiskChecker()
+	check := types.CheckConfig{Name: "root disk", Type: types.CheckTypeDisk, URL: t.TempDir()}
+
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Contains(t, result.Message, "% free (")
+	require.NotEmpty(t, result.Metrics)
+	assert.Equal(t, "disk_free", result.Metrics[0].Label)
+
+	// Nothing can have more than 100% free
+	check.Expected.DiskFreeMin = 100.5
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusWarning, result.Status)
+	assert.Contains(t, result.Error, "disk space free (minimum 100.5%)")
+	assert.Equal(t, "100.5:", result.Metrics[0].Warn)
+
+	check.URL = filepath.Join(check.URL, "missing")
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "Cannot read filesystem at ")
+}
+
+func TestMemoryChecker(t *testing.T) {
+	proc := t.TempDir()
+	writeProc(t, proc, "meminfo", `MemTotal:        8000000 kB
+MemFree:          500000 kB
+MemAvailable:    1000000 kB
+Buffers:          100000 kB
+Cached:          2000000 kB
+SwapTotal:       2000000 kB
+SwapFree:         500000 kB
+HugePages_Total:       0
+`)
+	checker := &MemoryChecker{proc: proc}
+	check := types.CheckConfig{Name: "memory", Type: types.CheckTypeMemory, URL: "localhost"}
+
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, "12.5% available (976.6 MiB of 7.6 GiB), 75.0% swap used", result.Message)
+	require.Len(t, result.Metrics, 3)
+	assert.Equal(t, 12.5, result.Metrics[0].Value)
+	assert.Equal(t, float64(1000000*1024), result.Metrics[1].Value)
+	assert.Equal(t, 75.0, result.Metrics[2].Value)
+
+	check.Expected.MemoryAvailableMin = 20
+	check.Expected.SwapUsedMax = 50
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusWarning, result.Status)
+	assert.Equal(t, "12.5% memory available (minimum 20%); 75.0% swap used (maximum 50%)", result.Error)
+
+	// Old kernels without MemAvailable
+	writeProc(t, proc, "meminfo", "MemTotal: 8000000 kB\nMemFree: 500000 kB\nBuffers: 100000 kB\nCached: 2000000 kB\n")
+	result = checker.Check(types.CheckConfig{Name: "memory"})
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, 32.5, result.Metrics[0].Value)
+
+	result = (&MemoryChecker{proc: t.TempDir()}).Check(check)
+	assert.Equal(t, types.StatusError, result.Status)
+}
+
+func TestLoadChecker(t *testing.T) {
+	proc := t.TempDir()
+	writeProc(t, proc, "loadavg", "6.10 3.20 1.05 3/412 90210\n")
+	checker := &LoadChecker{proc: proc, cpus: 4}
+	check := types.CheckConfig{Name: "load", Type: types.CheckTypeLoad, URL: "localhost"}
+
+	result := checker.Check(check)
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, "load average 6.10, 3.20, 1.05 on 4 CPUs", result.Message)
+	require.Len(t, result.Metrics, 4)
+	assert.Equal(t, 0.8, result.Metrics[3].Value)
+
+	check.Expected.LoadMax = 0.75
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusWarning, result.Status)
+	assert.Equal(t, "5-minute load 0.80 per CPU (maximum 0.75)", result.Error)
+	assert.Equal(t, "0.75", result.Metrics[3].Warn)
+
+	writeProc(t, proc, "loadavg", "garbage\n")
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "Cannot read load average")
+}
@@ -0,0 +1,274 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// maxMailLine caps the length of a single server response line
+const maxMailLine = 64 << 10
+
+// mailProtocol describes how to hold a session with one kind of mail server
+type mailProtocol struct {
+	endpointFormat
+	implicitTLS string // scheme that starts with a TLS handshake
+
+	// session reads the greeting, negotiates STARTTLS, logs in when the URL
+	// has credentials and collects what the server reported
+	session func(ctx context.Context, m *mailConn, target *endpoint) (*types.MailInfo, error)
+}
+
+// mailConn is a line-oriented connection that can be upgraded to TLS
+type mailConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	tlsConfig *tls.Config
+	state     *tls.ConnectionState // set once the connection is encrypted
+}
+
+// MailChecker holds a session with a mail server and asserts on what it advertises
+type MailChecker struct {
+	timeout  time.Duration
+	protocol mailProtocol
+	tls      *tlsCache
+	dialers  *dialerCache
+}
+
+// NewSMTPChecker creates an SMTP checker
+func NewSMTPChecker(timeout time.Duration) *MailChecker {
+	return newMailChecker(timeout, smtpProtocol)
+}
+
+// NewIMAPChecker creates an IMAP checker
+func NewIMAPChecker(timeout time.Duration) *MailChecker {
+	return newMailChecker(timeout, imapProtocol)
+}
+
+// NewPOP3Checker creates a POP3 checker
+func NewPOP3Checker(timeout time.Duration) *MailChecker {
+	return newMailChecker(timeout, pop3Protocol)
+}
+
+func newMailChecker(timeout time.Duration, protocol mailProtocol) *MailChecker {
+	return &MailChecker{
+		timeout:  timeout,
+		protocol: protocol,
+		tls:      newTLSCache(),
+		dialers:  newDialerCache(),
+	}
+}
+
+// Name returns the checker name
+func (c *MailChecker) Name() string {
+	return c.protocol.name
+}
+
+// Check runs a session against the mail server
+func (c *MailChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       security.RedactURLCredentials(check.URL),
+		Timestamp: start,
+	}
+
+	target, err := parseEndpoint(check.URL, c.protocol.endpointFormat)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Invalid URL: %v", err)
+		return result
+	}
+
+	dialer, err := c.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		return result
+	}
+
+	tlsConfig, err := c.tls.forHost(check, target.host)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", target.address())
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s connection failed: %v", c.protocol.name, err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	m := &mailConn{conn: conn, reader: bufio.NewReader(conn), tlsConfig: tlsConfig}
+	var info *types.MailInfo
+	if target.scheme == c.protocol.implicitTLS {
+		err = m.startTLS(ctx)
+	}
+	if err == nil {
+		info, err = c.protocol.session(ctx, m, target)
+	}
+	result.ResponseTime = time.Since(start)
+	result.Mail = info
+	if m.state != nil {
+		result.CertInfo = newCertInfo(*m.state, time.Now())
+	}
+
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s: %v", c.protocol.name, err)
+		return result
+	}
+
+	if err := validateMailInfo(info, check.Expected); err != nil {
+		result.Status = types.StatusDown
+		result.Error = err.Error()
+		return result
+	}
+
+	if result.CertInfo != nil {
+		if err := validateCertificate(result.CertInfo, check.Expected); err != nil {
+			result.Status = types.StatusWarning
+			result.Error = fmt.Sprintf("Certificate validation failed: %v", err)
+			return result
+		}
+	}
+
+	if check.Expected.MessagesMax > 0 && info.Messages > check.Expected.MessagesMax {
+		result.Status = types.StatusWarning
+		result.Error = fmt.Sprintf("Mailbox '%s' holds %d messages, maximum %d", info.Mailbox, info.Messages, check.Expected.MessagesMax)
+		return result
+	}
+
+	if check.Expected.ResponseTimeMax > 0 && result.ResponseTime > check.Expected.ResponseTimeMax {
+		result.Status = types.StatusSlow
+		result.Error = fmt.Sprintf("Session time %v exceeds maximum %v", result.ResponseTime, check.Expected.ResponseTimeMax)
+		return result
+	}
+
+	result.Status = types.StatusUp
+	return result
+}
+
+// validateMailInfo checks the banner and advertised extensions
+func validateMailInfo(info *types.MailInfo, expected types.Expected) error {
+	if expected.BannerContains != "" && !strings.Contains(info.Banner, expected.BannerContains) {
+		return fmt.Errorf("banner does not contain '%s'", expected.BannerContains)
+	}
+
+	var missing []string
+	for _, extension := range expected.Extensions {
+		if !hasExtension(info.Extensions, extension) {
+			missing = append(missing, extension)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("server does not advertise %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// hasExtension reports whether an advertised extension line starts with the
+// wanted keyword, so "AUTH" matches "AUTH PLAIN LOGIN". Matching ignores case.
+func hasExtension(advertised []string, want string) bool {
+	for _, line := range advertised {
+		if strings.EqualFold(line, want) ||
+			(len(line) > len(want) && strings.EqualFold(line[:len(want)], want) && line[len(want)] == ' ') {
+			return true
+		}
+	}
+	return false
+}
+
+// startTLS upgrades the connection and records the negotiated state
+func (m *mailConn) startTLS(ctx context.Context) error {
+	conn, err := startTLS(ctx, m.conn, m.tlsConfig)
+	if err != nil {
+		return err
+	}
+	state := conn.(*tls.Conn).ConnectionState()
+	m.conn = conn
+	m.reader = bufio.NewReader(conn)
+	m.state = &state
+	return nil
+}
+
+// wantStartTLS decides whether to upgrade a plaintext session. The URL's
+// starttls parameter is true (required), false (never) or empty, which
+// upgrades whenever the server offers it.
+func (m *mailConn) wantStartTLS(target *endpoint, offered bool) (bool, error) {
+	if m.state != nil {
+		return false, nil
+	}
+	switch mode := target.params.Get("starttls"); mode {
+	case "":
+		return offered, nil
+	case "true":
+		if !offered {
+			return false, fmt.Errorf("server does not offer STARTTLS")
+		}
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported starttls mode '%s'", mode)
+	}
+}
+
+// credentialsAllowed refuses to send a password in the clear, except to a
+// loopback address, as net/smtp does
+func (m *mailConn) credentialsAllowed(host string) error {
+	if m.state != nil || isLoopbackHost(host) {
+		return nil
+	}
+	return fmt.Errorf("refusing to send credentials without TLS; enable STARTTLS or use an implicit TLS scheme")
+}
+
+// readLine reads one response line without its line ending
+func (m *mailConn) readLine() (string, error) {
+	var line []byte
+	for {
+		chunk, err := m.reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxMailLine {
+			return "", fmt.Errorf("response line exceeds %d bytes", maxMailLine)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(line), "\r\n"), nil
+	}
+}
+
+// writeLine sends one command line. Line breaks are rejected so values from
+// the URL cannot inject extra commands.
+func (m *mailConn) writeLine(line string) error {
+	if strings.ContainsAny(line, "\r\n") {
+		return fmt.Errorf("command contains a line break")
+	}
+	_, err := io.WriteString(m.conn, line+"\r\n")
+	return err
+}
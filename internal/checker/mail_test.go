@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailTLS returns a server TLS config for 127.0.0.1 and the path of a CA
+// file that trusts it
+func mailTLS(t *testing.T) (*tls.Config, string) {
+	t.Helper()
+
+	root := issueCert(t, caTemplate("Mail Root"), newECKey(t), nil)
+	leafKey := newECKey(t)
+	leaf := issueCert(t, leafTemplate("mail.test"), leafKey, root)
+
+	caPath := filepath.Join(t.TempDir(), "root.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.cert.Raw}), 0600))
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{leaf.cert.Raw}, PrivateKey: leafKey}}}, caPath
+}
+
+func TestHasExtension(t *testing.T) {
+	advertised := []string{"SIZE 10240000", "AUTH PLAIN LOGIN", "8BITMIME"}
+
+	assert.True(t, hasExtension(advertised, "auth"))
+	assert.True(t, hasExtension(advertised, "AUTH PLAIN"))
+	assert.True(t, hasExtension(advertised, "8BITMIME"))
+	assert.False(t, hasExtension(advertised, "SIZ"))
+	assert.False(t, hasExtension(advertised, "STARTTLS"))
+}
+
+func TestMailConn_CredentialsAllowed(t *testing.T) {
+	plain := &mailConn{}
+	assert.NoError(t, plain.credentialsAllowed("127.0.0.1"))
+	assert.NoError(t, plain.credentialsAllowed("localhost"))
+	assert.ErrorContains(t, plain.credentialsAllowed("mail.example.com"), "refusing to send credentials without TLS")
+
+	encrypted := &mailConn{state: &tls.ConnectionState{}}
+	assert.NoError(t, encrypted.credentialsAllowed("mail.example.com"))
+}
+
+func TestMailConn_WriteLineRejectsLineBreaks(t *testing.T) {
+	m := &mailConn{}
+	assert.ErrorContains(t, m.writeLine("USER monitor\r\nDELE 1"), "line break")
+}
@@ -0,0 +1,228 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// MQTT 3.1.1 packet types and limits
+const (
+	mqttConnect     = 0x10
+	mqttConnAck     = 0x20
+	mqttPublish     = 0x30
+	mqttPubAck      = 0x40
+	mqttSubscribe   = 0x82
+	mqttSubAck      = 0x90
+	mqttDisconnect  = 0xe0
+	mqttMaxPacket   = 1 << 20
+	mqttKeepAlive   = 30
+	mqttSubscribeID = 1
+	mqttPublishID   = 2
+)
+
+var mqttProtocol = brokerProtocol{
+	endpointFormat: endpointFormat{
+		name:        "MQTT",
+		schemes:     []string{"mqtt", "mqtts"},
+		defaultPort: "1883",
+		schemePorts: map[string]string{"mqtts": "8883"},
+	},
+	inspect: inspectMQTT,
+}
+
+// mqttConnAckErrors maps CONNACK return codes to their meaning
+var mqttConnAckErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// inspectMQTT connects, subscribes to a test topic and publishes a unique
+// message to it, measuring the time until the broker delivers it back.
+// mqtts:// URLs connect over TLS.
+func inspectMQTT(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, options types.BrokerConfig) (*types.BrokerInfo, error) {
+	if target.scheme == "mqtts" {
+		var err error
+		if conn, err = startTLS(ctx, conn, tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+	reader := bufio.NewReader(conn)
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	clientID := options.ClientID
+	if clientID == "" {
+		clientID = "healthcheck-" + hex.EncodeToString(nonce[:5])
+	}
+	topic := options.Topic
+	if topic == "" {
+		topic = "healthcheck/" + clientID
+	}
+	qos := byte(options.QoS)
+
+	var connect []byte
+	connect = mqttString(connect, "MQTT")
+	flags := byte(0x02) // clean session
+	if target.user != "" {
+		flags |= 0x80
+		if target.password != "" {
+			flags |= 0x40
+		}
+	}
+	connect = append(connect, 4, flags)
+	connect = binary.BigEndian.AppendUint16(connect, mqttKeepAlive)
+	connect = mqttString(connect, clientID)
+	if target.user != "" {
+		connect = mqttString(connect, target.user)
+		if target.password != "" {
+			connect = mqttString(connect, target.password)
+		}
+	}
+	if err := mqttWrite(conn, mqttConnect, connect); err != nil {
+		return nil, err
+	}
+
+	kind, body, err := mqttRead(reader)
+	if err != nil {
+		return nil, fmt.Errorf("connect failed: %w", err)
+	}
+	if kind != mqttConnAck || len(body) != 2 {
+		return nil, fmt.Errorf("connect failed: unexpected packet type 0x%02x", kind)
+	}
+	if code := body[1]; code != 0 {
+		if reason, ok := mqttConnAckErrors[code]; ok {
+			return nil, fmt.Errorf("connection refused: %s", reason)
+		}
+		return nil, fmt.Errorf("connection refused with code %d", code)
+	}
+
+	subscribe := binary.BigEndian.AppendUint16(nil, mqttSubscribeID)
+	subscribe = mqttString(subscribe, topic)
+	subscribe = append(subscribe, qos)
+	if err := mqttWrite(conn, mqttSubscribe, subscribe); err != nil {
+		return nil, err
+	}
+	for {
+		kind, body, err := mqttRead(reader)
+		if err != nil {
+			return nil, fmt.Errorf("subscribe failed: %w", err)
+		}
+		if kind != mqttSubAck {
+			continue
+		}
+		if len(body) < 3 || body[2] == 0x80 {
+			return nil, fmt.Errorf("subscription to '%s' was refused", topic)
+		}
+		break
+	}
+
+	payload := hex.EncodeToString(nonce)
+	publish := mqttString(nil, topic)
+	if qos > 0 {
+		publish = binary.BigEndian.AppendUint16(publish, mqttPublishID)
+	}
+	publish = append(publish, payload...)
+	sent := time.Now()
+	if err := mqttWrite(conn, mqttPublish|qos<<1, publish); err != nil {
+		return nil, err
+	}
+
+	info := &types.BrokerInfo{}
+	for info.RoundTrip == 0 {
+		kind, body, err := mqttRead(reader)
+		if err != nil {
+			return nil, fmt.Errorf("waiting for the test message failed: %w", err)
+		}
+		if kind&0xf0 != mqttPublish {
+			continue
+		}
+
+		received := &wireReader{data: body}
+		received.take(int(received.uint16()))
+		if kind&0x06 != 0 {
+			id := received.take(2)
+			if received.err == nil {
+				mqttWrite(conn, mqttPubAck, id)
+			}
+		}
+		if received.err == nil && string(received.data) == payload {
+			info.RoundTrip = time.Since(sent)
+		}
+	}
+
+	mqttWrite(conn, mqttDisconnect, nil)
+	return info, nil
+}
+
+// mqttString appends a length-prefixed UTF-8 string
+func mqttString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
+
+// mqttWrite sends one control packet
+func mqttWrite(w io.Writer, header byte, body []byte) error {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	_, err := w.Write(append(packet, body...))
+	return err
+}
+
+// mqttRead reads one control packet, returning its first header byte and body
+func mqttRead(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, fmt.Errorf("malformed remaining length")
+		}
+		multiplier *= 128
+	}
+	if length > mqttMaxPacket {
+		return 0, nil, fmt.Errorf("packet of %d bytes exceeds limit", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
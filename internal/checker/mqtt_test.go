@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMQTT is a minimal MQTT broker that echoes publishes back to the
+// publishing client's own subscription
+type fakeMQTT struct {
+	user     string
+	password string
+	retained bool // deliver an unrelated retained QoS 1 message on subscribe
+	silent   bool // accept publishes but never deliver them
+}
+
+func (f *fakeMQTT) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+
+	kind, body, err := mqttRead(reader)
+	if err != nil || kind != mqttConnect {
+		return
+	}
+	connect := &wireReader{data: body}
+	connect.take(int(connect.uint16())) // protocol name
+	connect.uint8()                     // level
+	flags := connect.uint8()
+	connect.uint16() // keep alive
+	connect.take(int(connect.uint16()))
+	var user, password string
+	if flags&0x80 != 0 {
+		user = string(connect.take(int(connect.uint16())))
+	}
+	if flags&0x40 != 0 {
+		password = string(connect.take(int(connect.uint16())))
+	}
+	if user != f.user || password != f.password {
+		mqttWrite(conn, mqttConnAck, []byte{0, 4})
+		return
+	}
+	mqttWrite(conn, mqttConnAck, []byte{0, 0})
+
+	var subscribed string
+	var grantedQoS byte
+	for {
+		kind, body, err := mqttRead(reader)
+		if err != nil {
+			return
+		}
+		switch kind & 0xf0 {
+		case mqttSubscribe & 0xf0:
+			subscribe := &wireReader{data: body}
+			id := subscribe.take(2)
+			subscribed = string(subscribe.take(int(subscribe.uint16())))
+			grantedQoS = subscribe.uint8()
+			if strings.HasPrefix(subscribed, "forbidden/") {
+				mqttWrite(conn, mqttSubAck, append(id, 0x80))
+				continue
+			}
+			mqttWrite(conn, mqttSubAck, append(id, grantedQoS))
+			if f.retained {
+				stale := mqttString(nil, subscribed)
+				stale = append(stale, 0, 9)
+				stale = append(stale, "yesterday"...)
+				mqttWrite(conn, mqttPublish|1<<1|1, stale)
+			}
+		case mqttPublish:
+			publish := &wireReader{data: body}
+			topic := string(publish.take(int(publish.uint16())))
+			qos := (kind >> 1) & 0x03
+			if qos > 0 {
+				mqttWrite(conn, mqttPubAck, publish.take(2))
+			}
+			if f.silent || topic != subscribed {
+				continue
+			}
+			delivery := mqttString(nil, topic)
+			if grantedQoS > 0 {
+				delivery = binary.BigEndian.AppendUint16(delivery, 7)
+			}
+			delivery = append(delivery, publish.data...)
+			mqttWrite(conn, mqttPublish|grantedQoS<<1, delivery)
+		case mqttDisconnect:
+			return
+		}
+	}
+}
+
+func TestMQTTChecker(t *testing.T) {
+	tests := []struct {
+		name       string
+		server     fakeMQTT
+		password   string
+		broker     types.BrokerConfig
+		timeout    time.Duration
+		wantStatus types.Status
+		wantError  string
+	}{
+		{
+			name:       "qos 0 round trip",
+			password:   "secret",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "qos 1 round trip past a retained message",
+			server:     fakeMQTT{retained: true},
+			password:   "secret",
+			broker:     types.BrokerConfig{QoS: 1, Topic: "monitoring/probe", ClientID: "probe-1"},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "bad credentials",
+			password:   "wrong",
+			wantStatus: types.StatusDown,
+			wantError:  "connection refused: bad user name or password",
+		},
+		{
+			name:       "subscription refused",
+			password:   "secret",
+			broker:     types.BrokerConfig{Topic: "forbidden/probe"},
+			wantStatus: types.StatusDown,
+			wantError:  "subscription to 'forbidden/probe' was refused",
+		},
+		{
+			name:       "message never delivered",
+			server:     fakeMQTT{silent: true},
+			password:   "secret",
+			timeout:    300 * time.Millisecond,
+			wantStatus: types.StatusDown,
+			wantError:  "waiting for the test message failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			server.user = "monitor"
+			server.password = "secret"
+			addr := startFakeBroker(t, server.serve)
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			result := NewMQTTChecker(timeout).Check(types.CheckConfig{
+				Name:    "mqtt",
+				Type:    types.CheckTypeMQTT,
+				URL:     "mqtt://monitor:" + tt.password + "@" + addr,
+				Timeout: timeout,
+				Broker:  tt.broker,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+			if tt.wantStatus == types.StatusUp && assert.NotNil(t, result.Broker) {
+				assert.Greater(t, result.Broker.RoundTrip, time.Duration(0))
+			}
+		})
+	}
+}
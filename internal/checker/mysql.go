@@ -0,0 +1,488 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+)
+
+// MySQL client/server protocol constants
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientConnectWithDB    = 0x00000008
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientTransactions     = 0x00002000
+	mysqlClientSecureConnection = 0x00008000
+	mysqlClientPluginAuth       = 0x00080000
+
+	mysqlCharsetUTF8MB4 = 45
+	mysqlMaxPacket      = 1<<24 - 1
+
+	mysqlComQuit  = 0x01
+	mysqlComQuery = 0x03
+
+	mysqlNativePassword = "mysql_native_password"
+	mysqlCachingSHA2    = "caching_sha2_password"
+)
+
+var mysqlProtocol = databaseProtocol{
+	endpointFormat: endpointFormat{name: "MySQL", schemes: []string{"mysql"}, defaultPort: "3306"},
+	defaultQuery:   "SELECT 1",
+	query:          queryMySQL,
+}
+
+// mysqlConn reads and writes protocol packets, tracking the sequence number
+type mysqlConn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	sequence byte
+	tls      bool
+	verified bool // TLS with a verified server certificate
+	host     string
+}
+
+// mysqlHandshake holds the fields of the server greeting the client needs
+type mysqlHandshake struct {
+	capabilities uint32
+	nonce        []byte
+	plugin       string
+}
+
+// queryMySQL runs query with COM_QUERY and reads the text result set
+func queryMySQL(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, query string, out *queryOutput) error {
+	if target.user == "" {
+		return fmt.Errorf("URL must include a user name")
+	}
+
+	my := &mysqlConn{conn: conn, reader: bufio.NewReader(conn), host: target.host}
+
+	greeting, err := my.readPacket()
+	if err != nil {
+		return fmt.Errorf("reading server greeting: %w", err)
+	}
+	if greeting[0] == 0xff {
+		return fmt.Errorf("connection refused: %s", mysqlError(greeting))
+	}
+	handshake, err := parseMySQLHandshake(greeting)
+	if err != nil {
+		return err
+	}
+
+	capabilities := uint32(mysqlClientLongPassword | mysqlClientProtocol41 | mysqlClientTransactions |
+		mysqlClientSecureConnection | mysqlClientPluginAuth)
+	if target.path != "" {
+		capabilities |= mysqlClientConnectWithDB
+	}
+
+	if err := my.startTLS(ctx, target, tlsConfig, handshake, &capabilities); err != nil {
+		return err
+	}
+
+	plugin := handshake.plugin
+	if plugin != mysqlNativePassword && plugin != mysqlCachingSHA2 {
+		// The server switches to the account's plugin if it differs
+		plugin = mysqlNativePassword
+	}
+	authData, err := mysqlScramble(plugin, target.password, handshake.nonce)
+	if err != nil {
+		return err
+	}
+
+	response := binary.LittleEndian.AppendUint32(nil, capabilities)
+	response = binary.LittleEndian.AppendUint32(response, mysqlMaxPacket)
+	response = append(response, mysqlCharsetUTF8MB4)
+	response = append(response, make([]byte, 23)...)
+	response = append(append(response, target.user...), 0)
+	response = append(append(response, byte(len(authData))), authData...)
+	if target.path != "" {
+		response = append(append(response, target.path...), 0)
+	}
+	response = append(append(response, plugin...), 0)
+	if err := my.writePacket(response); err != nil {
+		return err
+	}
+
+	if err := my.authenticate(target.password, plugin, handshake.nonce); err != nil {
+		return err
+	}
+
+	my.sequence = 0
+	if err := my.writePacket(append([]byte{mysqlComQuery}, query...)); err != nil {
+		return err
+	}
+	if err := my.readResultSet(out); err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	my.sequence = 0
+	my.writePacket([]byte{mysqlComQuit})
+	return nil
+}
+
+// startTLS upgrades the connection according to the URL's tls parameter:
+// false, true, skip-verify or preferred. Without it TLS is off, unless the
+// check has a tls block, which makes it true.
+func (m *mysqlConn) startTLS(ctx context.Context, target *endpoint, tlsConfig *tls.Config, handshake *mysqlHandshake, capabilities *uint32) error {
+	mode := target.params.Get("tls")
+	if mode == "" && target.tlsConfigured {
+		mode = "true"
+	}
+	switch mode {
+	case "", "false":
+		return nil
+	case "true":
+	case "skip-verify", "preferred":
+		tlsConfig = unverifiedTLS(tlsConfig)
+	default:
+		return fmt.Errorf("unsupported tls mode '%s'", mode)
+	}
+
+	if handshake.capabilities&mysqlClientSSL == 0 {
+		if mode == "preferred" {
+			return nil
+		}
+		return fmt.Errorf("server does not support TLS")
+	}
+
+	*capabilities |= mysqlClientSSL
+	request := binary.LittleEndian.AppendUint32(nil, *capabilities)
+	request = binary.LittleEndian.AppendUint32(request, mysqlMaxPacket)
+	request = append(request, mysqlCharsetUTF8MB4)
+	request = append(request, make([]byte, 23)...)
+	if err := m.writePacket(request); err != nil {
+		return err
+	}
+
+	conn, err := startTLS(ctx, m.conn, tlsConfig)
+	if err != nil {
+		return err
+	}
+	m.conn = conn
+	m.reader = bufio.NewReader(conn)
+	m.tls = true
+	m.verified = !tlsConfig.InsecureSkipVerify
+	return nil
+}
+
+// authenticate handles the server's replies to the handshake response,
+// including auth plugin switches and caching_sha2_password full authentication
+func (m *mysqlConn) authenticate(password, plugin string, nonce []byte) error {
+	for {
+		packet, err := m.readPacket()
+		if err != nil {
+			return fmt.Errorf("reading authentication result: %w", err)
+		}
+
+		switch packet[0] {
+		case 0x00:
+			return nil
+		case 0xff:
+			return fmt.Errorf("authentication failed: %s", mysqlError(packet))
+		case 0xfe:
+			// Auth switch request: plugin name, then new nonce
+			rest := packet[1:]
+			end := bytes.IndexByte(rest, 0)
+			if end < 0 {
+				return fmt.Errorf("malformed auth switch request")
+			}
+			plugin = string(rest[:end])
+			nonce = bytes.TrimRight(rest[end+1:], "\x00")
+			authData, err := mysqlScramble(plugin, password, nonce)
+			if err != nil {
+				return err
+			}
+			if err := m.writePacket(authData); err != nil {
+				return err
+			}
+		case 0x01:
+			if plugin != mysqlCachingSHA2 || len(packet) < 2 {
+				return fmt.Errorf("unexpected authentication data")
+			}
+			switch packet[1] {
+			case 3: // fast authentication succeeded, OK follows
+			case 4: // full authentication required
+				if err := m.fullAuth(password, nonce); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unexpected caching_sha2_password state %d", packet[1])
+			}
+		default:
+			return fmt.Errorf("unexpected packet 0x%02x during authentication", packet[0])
+		}
+	}
+}
+
+// fullAuth sends the password for caching_sha2_password full authentication:
+// in clear text over verified TLS or to a loopback address, otherwise
+// encrypted with the server's RSA key. Over unverified TLS it is refused.
+func (m *mysqlConn) fullAuth(password string, nonce []byte) error {
+	plain := append([]byte(password), 0)
+	if m.verified || (m.tls && isLoopbackHost(m.host)) {
+		return m.writePacket(plain)
+	}
+	if m.tls {
+		return fmt.Errorf("refusing to send the password over TLS without a verified server certificate; use tls=true")
+	}
+
+	if err := m.writePacket([]byte{2}); err != nil {
+		return err
+	}
+	packet, err := m.readPacket()
+	if err != nil {
+		return fmt.Errorf("reading server public key: %w", err)
+	}
+	if packet[0] != 0x01 {
+		return fmt.Errorf("server did not send its public key")
+	}
+	block, _ := pem.Decode(packet[1:])
+	if block == nil {
+		return fmt.Errorf("invalid server public key")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid server public key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("server public key is not RSA")
+	}
+
+	for i := range plain {
+		plain[i] ^= nonce[i%len(nonce)]
+	}
+	encrypted, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, key, plain, nil)
+	if err != nil {
+		return err
+	}
+	return m.writePacket(encrypted)
+}
+
+// readResultSet reads the response to COM_QUERY into out
+func (m *mysqlConn) readResultSet(out *queryOutput) error {
+	packet, err := m.readPacket()
+	if err != nil {
+		return err
+	}
+
+	switch packet[0] {
+	case 0x00:
+		return nil // statement without a result set
+	case 0xff:
+		return fmt.Errorf("%s", mysqlError(packet))
+	case 0xfb:
+		return fmt.Errorf("LOCAL INFILE requests are not supported")
+	}
+
+	count, _, ok := mysqlLengthInt(packet)
+	if !ok {
+		return fmt.Errorf("malformed column count")
+	}
+
+	// Column definitions followed by an EOF packet
+	for i := uint64(0); i <= count; i++ {
+		if _, err := m.readPacket(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		packet, err := m.readPacket()
+		if err != nil {
+			return err
+		}
+		if packet[0] == 0xfe && len(packet) < 9 {
+			return nil
+		}
+		if packet[0] == 0xff {
+			return fmt.Errorf("%s", mysqlError(packet))
+		}
+
+		columns := make([]string, 0, count)
+		for i := uint64(0); i < count; i++ {
+			if len(packet) > 0 && packet[0] == 0xfb {
+				columns = append(columns, "NULL")
+				packet = packet[1:]
+				continue
+			}
+			length, n, ok := mysqlLengthInt(packet)
+			if !ok || uint64(len(packet)-n) < length {
+				return fmt.Errorf("malformed row")
+			}
+			columns = append(columns, string(packet[n:n+int(length)]))
+			packet = packet[n+int(length):]
+		}
+		out.row(columns)
+	}
+}
+
+// readPacket reads one packet, joining payloads split at the maximum packet size
+func (m *mysqlConn) readPacket() ([]byte, error) {
+	var payload []byte
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(m.reader, header); err != nil {
+			return nil, err
+		}
+		length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+		if header[3] != m.sequence {
+			return nil, fmt.Errorf("packet out of order (got %d, want %d)", header[3], m.sequence)
+		}
+		m.sequence++
+
+		chunk := make([]byte, length)
+		if _, err := io.ReadFull(m.reader, chunk); err != nil {
+			return nil, err
+		}
+		payload = append(payload, chunk...)
+		if length < mysqlMaxPacket {
+			break
+		}
+	}
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("empty packet")
+	}
+	return payload, nil
+}
+
+// writePacket writes one packet with the next sequence number
+func (m *mysqlConn) writePacket(payload []byte) error {
+	if len(payload) >= mysqlMaxPacket {
+		return fmt.Errorf("packet too large")
+	}
+	packet := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), m.sequence}
+	m.sequence++
+	_, err := m.conn.Write(append(packet, payload...))
+	return err
+}
+
+// parseMySQLHandshake decodes a protocol version 10 greeting
+func parseMySQLHandshake(packet []byte) (*mysqlHandshake, error) {
+	if packet[0] != 10 {
+		return nil, fmt.Errorf("unsupported protocol version %d", packet[0])
+	}
+
+	pos := bytes.IndexByte(packet[1:], 0) + 2 // skip server version
+	if pos < 2 || len(packet) < pos+4+8+1+2 {
+		return nil, fmt.Errorf("malformed server greeting")
+	}
+	pos += 4 // connection id
+	nonce := append([]byte(nil), packet[pos:pos+8]...)
+	pos += 8 + 1
+	handshake := &mysqlHandshake{capabilities: uint32(binary.LittleEndian.Uint16(packet[pos:]))}
+	pos += 2
+
+	if len(packet) >= pos+1+2+2+1+10 {
+		pos += 1 + 2 // character set, status flags
+		handshake.capabilities |= uint32(binary.LittleEndian.Uint16(packet[pos:])) << 16
+		pos += 2
+		authLength := int(packet[pos])
+		pos += 1 + 10
+
+		if handshake.capabilities&mysqlClientSecureConnection != 0 {
+			n := max(13, authLength-8)
+			if len(packet) < pos+n {
+				return nil, fmt.Errorf("malformed server greeting")
+			}
+			nonce = append(nonce, bytes.TrimRight(packet[pos:pos+n], "\x00")...)
+			pos += n
+		}
+		if handshake.capabilities&mysqlClientPluginAuth != 0 && pos < len(packet) {
+			handshake.plugin = string(bytes.TrimRight(packet[pos:], "\x00"))
+		}
+	}
+
+	if handshake.capabilities&mysqlClientProtocol41 == 0 {
+		return nil, fmt.Errorf("server does not support protocol 4.1")
+	}
+	handshake.nonce = nonce
+	return handshake, nil
+}
+
+// mysqlScramble computes the auth response of a password plugin
+func mysqlScramble(plugin, password string, nonce []byte) ([]byte, error) {
+	if password == "" {
+		return nil, nil
+	}
+
+	switch plugin {
+	case mysqlNativePassword:
+		// SHA1(password) XOR SHA1(nonce + SHA1(SHA1(password)))
+		hash := sha1.Sum([]byte(password))
+		double := sha1.Sum(hash[:])
+		mix := sha1.New()
+		mix.Write(nonce)
+		mix.Write(double[:])
+		return xorBytes(hash[:], mix.Sum(nil)), nil
+	case mysqlCachingSHA2:
+		// SHA256(password) XOR SHA256(SHA256(SHA256(password)) + nonce)
+		hash := sha256.Sum256([]byte(password))
+		double := sha256.Sum256(hash[:])
+		mix := sha256.New()
+		mix.Write(double[:])
+		mix.Write(nonce)
+		return xorBytes(hash[:], mix.Sum(nil)), nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication plugin '%s'", plugin)
+	}
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// mysqlLengthInt decodes a length-encoded integer, returning its size in bytes
+func mysqlLengthInt(data []byte) (uint64, int, bool) {
+	if len(data) == 0 {
+		return 0, 0, false
+	}
+	size := 1
+	switch data[0] {
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	}
+	if len(data) < size {
+		return 0, 0, false
+	}
+	if size == 1 {
+		return uint64(data[0]), 1, true
+	}
+	var value uint64
+	for i := size - 1; i >= 1; i-- {
+		value = value<<8 | uint64(data[i])
+	}
+	return value, size, true
+}
+
+// mysqlError formats an ERR packet as "Error code (state): message"
+func mysqlError(packet []byte) string {
+	if len(packet) < 3 {
+		return "unknown error"
+	}
+	code := binary.LittleEndian.Uint16(packet[1:])
+	message := packet[3:]
+	if len(message) >= 6 && message[0] == '#' {
+		return fmt.Sprintf("Error %d (%s): %s", code, message[1:6], message[6:])
+	}
+	return fmt.Sprintf("Error %d: %s", code, message)
+}
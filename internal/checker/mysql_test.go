@@ -0,0 +1,273 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMySQL is a minimal MySQL server that accepts one user and answers
+// every query with a single row
+type fakeMySQL struct {
+	greetingPlugin string // plugin announced in the greeting
+	accountPlugin  string // plugin the account uses; a difference triggers an auth switch
+	fullAuth       bool   // caching_sha2_password cache miss
+	user           string
+	password       string
+	key            *rsa.PrivateKey
+}
+
+func startFakeMySQL(t *testing.T, server *fakeMySQL) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func (f *fakeMySQL) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	var sequence byte
+
+	write := func(payload []byte) {
+		conn.Write(append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequence}, payload...))
+		sequence++
+	}
+	read := func() []byte {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return nil
+		}
+		sequence = header[3] + 1
+		payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+		io.ReadFull(reader, payload)
+		return payload
+	}
+	deny := func() {
+		write(append([]byte{0xff, 0x15, 0x04}, "#28000Access denied for user '"+f.user+"'"...))
+	}
+	ok := []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+
+	nonce := []byte("abcdefgh0123456789AB")
+	greeting := []byte{10}
+	greeting = append(greeting, "8.0.36\x00"...)
+	greeting = append(greeting, 1, 0, 0, 0)
+	greeting = append(greeting, nonce[:8]...)
+	greeting = append(greeting, 0)
+	capabilities := uint32(mysqlClientProtocol41 | mysqlClientSecureConnection | mysqlClientPluginAuth | mysqlClientConnectWithDB)
+	greeting = append(greeting, byte(capabilities), byte(capabilities>>8))
+	greeting = append(greeting, mysqlCharsetUTF8MB4, 2, 0)
+	greeting = append(greeting, byte(capabilities>>16), byte(capabilities>>24))
+	greeting = append(greeting, 21)
+	greeting = append(greeting, make([]byte, 10)...)
+	greeting = append(greeting, nonce[8:]...)
+	greeting = append(greeting, 0)
+	greeting = append(greeting, f.greetingPlugin...)
+	greeting = append(greeting, 0)
+	write(greeting)
+
+	response := read()
+	if len(response) < 33 {
+		return
+	}
+	rest := response[32:]
+	userEnd := bytes.IndexByte(rest, 0)
+	user := string(rest[:userEnd])
+	rest = rest[userEnd+1:]
+	authData := rest[1 : 1+int(rest[0])]
+	rest = rest[1+int(rest[0]):]
+	plugin := string(rest[bytes.LastIndexByte(rest[:len(rest)-1], 0)+1 : len(rest)-1])
+
+	if plugin != f.accountPlugin {
+		nonce = []byte("ZYXWVUTSRQponmlkjihg")
+		write(append(append([]byte{0xfe}, f.accountPlugin+"\x00"...), append(nonce, 0)...))
+		authData = read()
+	}
+
+	expected, _ := mysqlScramble(f.accountPlugin, f.password, nonce)
+	if user != f.user || !bytes.Equal(authData, expected) {
+		deny()
+		return
+	}
+
+	if f.accountPlugin == mysqlCachingSHA2 {
+		if !f.fullAuth {
+			write([]byte{0x01, 3})
+		} else {
+			write([]byte{0x01, 4})
+			if request := read(); len(request) != 1 || request[0] != 2 {
+				deny()
+				return
+			}
+			der, _ := x509.MarshalPKIXPublicKey(&f.key.PublicKey)
+			write(append([]byte{0x01}, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...))
+			plain, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, f.key, read(), nil)
+			if err != nil {
+				deny()
+				return
+			}
+			for i := range plain {
+				plain[i] ^= nonce[i%len(nonce)]
+			}
+			if string(plain) != f.password+"\x00" {
+				deny()
+				return
+			}
+		}
+	}
+	write(ok)
+
+	for {
+		command := read()
+		if len(command) == 0 || command[0] == mysqlComQuit {
+			return
+		}
+		query := string(command[1:])
+		switch {
+		case strings.Contains(query, "missing_table"):
+			write(append([]byte{0xff, 0x7a, 0x04}, "#42S02Table 'app.missing_table' doesn't exist"...))
+		case strings.HasPrefix(query, "SET"):
+			write(ok)
+		default:
+			write([]byte{2})
+			write([]byte("column definition"))
+			write([]byte("column definition"))
+			write([]byte{0xfe, 0, 0, 2, 0})
+			write([]byte{2, 'O', 'N', 0xfb})
+			write([]byte{0xfe, 0, 0, 2, 0})
+		}
+	}
+}
+
+func TestMySQLChecker(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		server     fakeMySQL
+		password   string
+		query      string
+		expected   types.Expected
+		wantStatus types.Status
+		wantError  string
+	}{
+		{
+			name:       "native password",
+			server:     fakeMySQL{greetingPlugin: mysqlNativePassword, accountPlugin: mysqlNativePassword},
+			password:   "secret",
+			expected:   types.Expected{BodyContains: "ON\tNULL"},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "caching sha2 fast path",
+			server:     fakeMySQL{greetingPlugin: mysqlCachingSHA2, accountPlugin: mysqlCachingSHA2},
+			password:   "secret",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "caching sha2 full authentication",
+			server:     fakeMySQL{greetingPlugin: mysqlCachingSHA2, accountPlugin: mysqlCachingSHA2, fullAuth: true},
+			password:   "secret",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "auth switch",
+			server:     fakeMySQL{greetingPlugin: mysqlCachingSHA2, accountPlugin: mysqlNativePassword},
+			password:   "secret",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "wrong password",
+			server:     fakeMySQL{greetingPlugin: mysqlNativePassword, accountPlugin: mysqlNativePassword},
+			password:   "wrong",
+			wantStatus: types.StatusDown,
+			wantError:  "authentication failed: Error 1045 (28000): Access denied",
+		},
+		{
+			name:       "statement without result set",
+			server:     fakeMySQL{greetingPlugin: mysqlNativePassword, accountPlugin: mysqlNativePassword},
+			password:   "secret",
+			query:      "SET @probe = 1",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "query error",
+			server:     fakeMySQL{greetingPlugin: mysqlNativePassword, accountPlugin: mysqlNativePassword},
+			password:   "secret",
+			query:      "SELECT * FROM missing_table",
+			wantStatus: types.StatusDown,
+			wantError:  "query failed: Error 1146 (42S02)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			server.user = "monitor"
+			server.password = "secret"
+			server.key = key
+			addr := startFakeMySQL(t, &server)
+
+			result := NewMySQLChecker(5 * time.Second).Check(types.CheckConfig{
+				Name:     "db",
+				Type:     types.CheckTypeMySQL,
+				URL:      "mysql://monitor:" + tt.password + "@" + addr + "/app",
+				Timeout:  5 * time.Second,
+				Query:    tt.query,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestMySQLChecker_TLSRequired(t *testing.T) {
+	addr := startFakeMySQL(t, &fakeMySQL{
+		greetingPlugin: mysqlNativePassword,
+		accountPlugin:  mysqlNativePassword,
+		user:           "monitor",
+		password:       "secret",
+	})
+
+	for _, check := range []types.CheckConfig{
+		{Name: "db", URL: "mysql://monitor:secret@" + addr + "/app?tls=true", Timeout: 5 * time.Second},
+		// A tls block turns TLS on without a tls parameter
+		{Name: "db", URL: "mysql://monitor:secret@" + addr + "/app", Timeout: 5 * time.Second, TLS: types.TLSConfig{MinVersion: "1.2"}},
+	} {
+		result := NewMySQLChecker(5 * time.Second).Check(check)
+
+		assert.Equal(t, types.StatusDown, result.Status)
+		assert.Contains(t, result.Error, "server does not support TLS")
+	}
+}
@@ -0,0 +1,38 @@
+package checker
+
+import (
+	"sync"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+)
+
+// dialerCache builds dialers once per distinct proxy and resolver settings
+type dialerCache struct {
+	mu      sync.Mutex
+	dialers map[string]*netdial.Dialer
+}
+
+func newDialerCache() *dialerCache {
+	return &dialerCache{
+		dialers: make(map[string]*netdial.Dialer),
+	}
+}
+
+// get returns the dialer for the given network options
+func (c *dialerCache) get(opts netdial.Options) (*netdial.Dialer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := netdial.Key(opts)
+	if dialer, ok := c.dialers[key]; ok {
+		return dialer, nil
+	}
+
+	dialer, err := netdial.New(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.dialers[key] = dialer
+	return dialer, nil
+}
@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPChecker_Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer target.Close()
+
+	// A forward proxy that relays plain HTTP requests to the target
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		resp, err := http.Get(r.URL.String())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		buf := make([]byte, 64)
+		n, _ := resp.Body.Read(buf)
+		w.Write(buf[:n])
+	}))
+	defer proxy.Close()
+
+	checker := NewHTTPChecker(5 * time.Second)
+	check := types.CheckConfig{
+		Name:     "proxied",
+		URL:      target.URL,
+		Method:   "GET",
+		Timeout:  5 * time.Second,
+		Proxy:    proxy.URL,
+		Expected: types.Expected{Status: 200, BodyContains: "OK"},
+	}
+
+	result := checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&proxied))
+
+	// proxy: none bypasses it
+	check.Proxy = "none"
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&proxied))
+
+	check.Proxy = "ftp://proxy"
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "Network configuration error")
+}
+
+func TestTCPChecker_StaticResolve(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	checker := NewTCPChecker(5 * time.Second)
+	result := checker.Check(types.CheckConfig{
+		Name:    "db",
+		URL:     net.JoinHostPort("db.internal.invalid", port),
+		Timeout: 5 * time.Second,
+		Resolve: map[string]string{"db.internal.invalid": "127.0.0.1"},
+	})
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+}
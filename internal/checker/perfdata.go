@@ -0,0 +1,135 @@
+package checker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// parseNagiosOutput splits plugin output into its status text and performance
+// data. The first line is "TEXT | PERFDATA"; later lines are long text until
+// one contains '|', after which everything is more performance data.
+func parseNagiosOutput(output string) (string, []types.Metric) {
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	var text, perfdata []string
+	inPerfdata := false
+	for i, line := range lines {
+		if inPerfdata {
+			perfdata = append(perfdata, line)
+			continue
+		}
+		before, after, found := strings.Cut(line, "|")
+		text = append(text, strings.TrimSpace(before))
+		if found {
+			perfdata = append(perfdata, after)
+			// Only the first line's perfdata stays on its own line; a pipe in
+			// the long text starts the multi-line perfdata block
+			inPerfdata = i > 0
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(text, "\n")), parsePerfdata(strings.Join(perfdata, " "))
+}
+
+// parsePerfdata parses space separated 'label'=value[UOM];[warn];[crit];[min];[max]
+// items. Malformed items and undetermined (U) values are skipped.
+func parsePerfdata(perfdata string) []types.Metric {
+	var metrics []types.Metric
+
+	rest := perfdata
+	for {
+		rest = strings.TrimLeft(rest, " \t\n")
+		if rest == "" {
+			return metrics
+		}
+
+		label, remainder, ok := perfdataLabel(rest)
+		if !ok {
+			// Skip to the next item
+			if end := strings.IndexAny(rest, " \t\n"); end >= 0 {
+				rest = rest[end:]
+				continue
+			}
+			return metrics
+		}
+
+		item := remainder
+		if end := strings.IndexAny(remainder, " \t\n"); end >= 0 {
+			item, rest = remainder[:end], remainder[end:]
+		} else {
+			rest = ""
+		}
+
+		if metric, ok := perfdataMetric(label, item); ok {
+			metrics = append(metrics, metric)
+		}
+	}
+}
+
+// perfdataLabel reads a label and its '=' from the start of s. Quoted labels may
+// contain spaces and escape a quote by doubling it.
+func perfdataLabel(s string) (label, rest string, ok bool) {
+	if !strings.HasPrefix(s, "'") {
+		end := strings.IndexAny(s, "= \t\n")
+		if end <= 0 || s[end] != '=' {
+			return "", "", false
+		}
+		return s[:end], s[end+1:], true
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			b.WriteByte('\'')
+			i++
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '=' && b.Len() > 0 {
+			return b.String(), s[i+2:], true
+		}
+		return "", "", false
+	}
+	return "", "", false
+}
+
+// perfdataMetric parses value[UOM];[warn];[crit];[min];[max]
+func perfdataMetric(label, item string) (types.Metric, bool) {
+	fields := strings.Split(item, ";")
+
+	value := fields[0]
+	end := 0
+	for end < len(value) && strings.IndexByte("0123456789+-.,eE", value[end]) >= 0 {
+		end++
+	}
+	// Back off over a trailing e or E that starts a unit rather than an exponent
+	for end > 0 {
+		if _, err := strconv.ParseFloat(strings.Replace(value[:end], ",", ".", 1), 64); err == nil {
+			break
+		}
+		end--
+	}
+	if end == 0 {
+		return types.Metric{}, false
+	}
+	number, _ := strconv.ParseFloat(strings.Replace(value[:end], ",", ".", 1), 64)
+
+	metric := types.Metric{
+		Label: label,
+		Value: number,
+		Unit:  value[end:],
+	}
+	thresholds := []*string{&metric.Warn, &metric.Crit, &metric.Min, &metric.Max}
+	for i, field := range fields[1:] {
+		if i < len(thresholds) {
+			*thresholds[i] = field
+		}
+	}
+	return metric, true
+}
@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePerfdata(t *testing.T) {
+	tests := []struct {
+		name     string
+		perfdata string
+		want     []types.Metric
+	}{
+		{
+			name:     "full item",
+			perfdata: "time=0.012s;1.000;2.000;0.000;10.000",
+			want:     []types.Metric{{Label: "time", Value: 0.012, Unit: "s", Warn: "1.000", Crit: "2.000", Min: "0.000", Max: "10.000"}},
+		},
+		{
+			name:     "value only",
+			perfdata: "users=3",
+			want:     []types.Metric{{Label: "users", Value: 3}},
+		},
+		{
+			name:     "ranges kept verbatim",
+			perfdata: "temp=41C;@10:20;~:30",
+			want:     []types.Metric{{Label: "temp", Value: 41, Unit: "C", Warn: "@10:20", Crit: "~:30"}},
+		},
+		{
+			name:     "quoted labels",
+			perfdata: "'/ usage'=87%;90;95 'it''s'=1c",
+			want: []types.Metric{
+				{Label: "/ usage", Value: 87, Unit: "%", Warn: "90", Crit: "95"},
+				{Label: "it's", Value: 1, Unit: "c"},
+			},
+		},
+		{
+			name:     "units and exponents",
+			perfdata: "size=1.5e3KB rx=-4B used=2,5GB",
+			want: []types.Metric{
+				{Label: "size", Value: 1500, Unit: "KB"},
+				{Label: "rx", Value: -4, Unit: "B"},
+				{Label: "used", Value: 2.5, Unit: "GB"},
+			},
+		},
+		{
+			name:     "malformed and undetermined items are skipped",
+			perfdata: "garbage rtt=U;100;200 =5 'open=1 ok=1",
+			want:     []types.Metric{{Label: "ok", Value: 1}},
+		},
+		{
+			name:     "empty",
+			perfdata: "   ",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parsePerfdata(tt.perfdata))
+		})
+	}
+}
+
+func TestParseNagiosOutput(t *testing.T) {
+	text, metrics := parseNagiosOutput("PING OK - Packet loss = 0%\r\n")
+	assert.Equal(t, "PING OK - Packet loss = 0%", text)
+	assert.Nil(t, metrics)
+
+	text, metrics = parseNagiosOutput("HTTP OK|time=0.1s\nbody matched\n")
+	assert.Equal(t, "HTTP OK\nbody matched", text)
+	assert.Equal(t, []types.Metric{{Label: "time", Value: 0.1, Unit: "s"}}, metrics)
+
+	text, metrics = parseNagiosOutput("")
+	assert.Empty(t, text)
+	assert.Nil(t, metrics)
+}
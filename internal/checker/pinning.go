@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/tlsconfig"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// certFingerprint returns the SHA-256 fingerprint of a certificate as lowercase hex
+func certFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// spkiHash returns the base64 SHA-256 hash of a certificate's SubjectPublicKeyInfo,
+// the same format used by HPKP and `openssl ... | base64` pin recipes
+func spkiHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// validatePins enforces certificate fingerprints against the leaf and SPKI pins
+// against every presented certificate, so an intermediate or backup key can be pinned
+func validatePins(certs []*x509.Certificate, expected types.Expected) error {
+	if len(expected.CertFingerprints) > 0 {
+		leaf := certFingerprint(certs[0])
+		matched := false
+		for _, fingerprint := range expected.CertFingerprints {
+			if tlsconfig.NormalizeFingerprint(fingerprint) == leaf {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("leaf certificate fingerprint %s does not match any pinned fingerprint", leaf)
+		}
+	}
+
+	if len(expected.SPKIPins) > 0 {
+		pins := make(map[string]bool, len(expected.SPKIPins))
+		for _, pin := range expected.SPKIPins {
+			pins[tlsconfig.NormalizeSPKIPin(pin)] = true
+		}
+		matched := false
+		for _, cert := range certs {
+			if pins[spkiHash(cert)] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("no certificate in the chain matches a pinned SPKI hash (leaf is sha256/%s)", spkiHash(certs[0]))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSLChecker_Pinning(t *testing.T) {
+	root := issueCert(t, caTemplate("Test Root"), newECKey(t), nil)
+	intermediate := issueCert(t, caTemplate("Test Intermediate"), newECKey(t), root)
+	leafKey := newECKey(t)
+	leaf := issueCert(t, leafTemplate("service.test"), leafKey, intermediate)
+	other := issueCert(t, leafTemplate("other.test"), newECKey(t), intermediate)
+
+	targetURL, caPath := startChainServer(t, root, leafKey, leaf.cert, intermediate.cert)
+
+	// Colon-separated uppercase form as printed by openssl
+	fingerprint := certFingerprint(leaf.cert)
+	var pairs []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		pairs = append(pairs, strings.ToUpper(fingerprint[i:i+2]))
+	}
+	opensslFingerprint := "sha256:" + strings.Join(pairs, ":")
+
+	tests := []struct {
+		name       string
+		expected   types.Expected
+		wantStatus types.Status
+		errMsg     string
+	}{
+		{
+			name:       "FingerprintMatches",
+			expected:   types.Expected{CertFingerprints: []string{opensslFingerprint}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "FingerprintMismatch",
+			expected:   types.Expected{CertFingerprints: []string{certFingerprint(other.cert)}},
+			wantStatus: types.StatusDown,
+			errMsg:     "Certificate pinning failed",
+		},
+		{
+			name:       "LeafSPKIPin",
+			expected:   types.Expected{SPKIPins: []string{"sha256/" + spkiHash(leaf.cert)}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "IntermediateSPKIPin",
+			expected:   types.Expected{SPKIPins: []string{spkiHash(other.cert), spkiHash(intermediate.cert)}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "SPKIPinMismatch",
+			expected:   types.Expected{SPKIPins: []string{spkiHash(other.cert)}},
+			wantStatus: types.StatusDown,
+			errMsg:     "no certificate in the chain matches",
+		},
+	}
+
+	checker := NewSSLChecker(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{
+				Name:     tt.name,
+				URL:      targetURL,
+				Timeout:  5 * time.Second,
+				TLS:      types.TLSConfig{CAFile: caPath, ServerName: "service.test"},
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.errMsg != "" {
+				assert.Contains(t, result.Error, tt.errMsg)
+			}
+			require.NotNil(t, result.CertInfo)
+			assert.Equal(t, fingerprint, result.CertInfo.Fingerprint)
+			assert.Equal(t, spkiHash(leaf.cert), result.CertInfo.SPKIHash)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+var pop3Protocol = mailProtocol{
+	endpointFormat: endpointFormat{
+		name:        "POP3",
+		schemes:     []string{"pop3", "pop3s"},
+		defaultPort: "110",
+		schemePorts: map[string]string{"pop3s": "995"},
+	},
+	implicitTLS: "pop3s",
+	session:     pop3Session,
+}
+
+// pop3Session reads the greeting, upgrades with STLS, logs in with USER and
+// PASS and counts the messages in the maildrop with STAT
+func pop3Session(ctx context.Context, m *mailConn, target *endpoint) (*types.MailInfo, error) {
+	if target.user == "" {
+		return nil, fmt.Errorf("URL must include a user name")
+	}
+
+	greeting, err := m.readLine()
+	if err != nil {
+		return nil, fmt.Errorf("reading greeting failed: %w", err)
+	}
+	banner, ok := strings.CutPrefix(greeting, "+OK")
+	info := &types.MailInfo{Banner: strings.TrimSpace(banner)}
+	if !ok {
+		return info, fmt.Errorf("server refused the session: %s", greeting)
+	}
+
+	info.Extensions = m.pop3Capabilities()
+
+	starttls, err := m.wantStartTLS(target, hasExtension(info.Extensions, "STLS"))
+	if err != nil {
+		return info, err
+	}
+	if starttls {
+		if _, err := m.pop3Command("STLS"); err != nil {
+			return info, fmt.Errorf("STLS failed: %w", err)
+		}
+		if err := m.startTLS(ctx); err != nil {
+			return info, err
+		}
+		info.StartTLS = true
+		info.Extensions = m.pop3Capabilities()
+	}
+
+	if err := m.credentialsAllowed(target.host); err != nil {
+		return info, err
+	}
+	if _, err := m.pop3Command("USER " + target.user); err != nil {
+		return info, fmt.Errorf("authentication failed: %w", err)
+	}
+	if _, err := m.pop3Command("PASS " + target.password); err != nil {
+		return info, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	stat, err := m.pop3Command("STAT")
+	if err != nil {
+		return info, fmt.Errorf("STAT failed: %w", err)
+	}
+	count, _, _ := strings.Cut(stat, " ")
+	info.Mailbox = "INBOX"
+	if info.Messages, err = strconv.ParseInt(count, 10, 64); err != nil {
+		return info, fmt.Errorf("malformed STAT reply '%s'", stat)
+	}
+
+	m.pop3Command("QUIT")
+	return info, nil
+}
+
+// pop3Capabilities asks for the CAPA list. Servers without CAPA answer -ERR,
+// which leaves the list empty.
+func (m *mailConn) pop3Capabilities() []string {
+	if _, err := m.pop3Command("CAPA"); err != nil {
+		return nil
+	}
+	var capabilities []string
+	for {
+		line, err := m.readLine()
+		if err != nil || line == "." {
+			return capabilities
+		}
+		capabilities = append(capabilities, strings.TrimPrefix(line, "."))
+	}
+}
+
+// pop3Command sends a command and returns the text after +OK. An -ERR reply
+// is returned as an error holding the server's text; the command is left
+// out since it may carry credentials.
+func (m *mailConn) pop3Command(command string) (string, error) {
+	if err := m.writeLine(command); err != nil {
+		return "", err
+	}
+	line, err := m.readLine()
+	if err != nil {
+		return "", err
+	}
+	if reply, ok := strings.CutPrefix(line, "+OK"); ok {
+		return strings.TrimSpace(reply), nil
+	}
+	return "", fmt.Errorf("%s", line)
+}
@@ -0,0 +1,146 @@
+package checker
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePOP3 is a minimal POP3 server with one maildrop
+type fakePOP3 struct {
+	tls      *tls.Config // offers STLS when set
+	user     string
+	password string
+	messages int
+}
+
+func (f *fakePOP3) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	send := func(lines ...string) {
+		for _, line := range lines {
+			fmt.Fprintf(conn, "%s\r\n", line)
+		}
+	}
+
+	send("+OK Fake POP3 ready <1896.697170952@mail.test>")
+	encrypted := false
+	var user string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch strings.ToUpper(verb) {
+		case "CAPA":
+			send("+OK Capability list follows", "USER", "UIDL")
+			if f.tls != nil && !encrypted {
+				send("STLS")
+			}
+			send(".")
+		case "STLS":
+			send("+OK Begin TLS negotiation")
+			tlsConn := tls.Server(conn, f.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, reader, encrypted = tlsConn, bufio.NewReader(tlsConn), true
+		case "USER":
+			user = arg
+			send("+OK")
+		case "PASS":
+			if user == f.user && arg == f.password {
+				send("+OK Logged in.")
+			} else {
+				send("-ERR [AUTH] Authentication failed.")
+			}
+		case "STAT":
+			send(fmt.Sprintf("+OK %d %d", f.messages, f.messages*1024))
+		case "QUIT":
+			send("+OK Logging out.")
+			return
+		default:
+			send("-ERR Unknown command")
+		}
+	}
+}
+
+func TestPOP3Checker(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		expected   types.Expected
+		wantStatus types.Status
+		wantError  string
+	}{
+		{
+			name:       "login and count",
+			password:   "secret",
+			expected:   types.Expected{BannerContains: "Fake POP3", Extensions: []string{"UIDL"}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "too many messages",
+			password:   "secret",
+			expected:   types.Expected{MessagesMax: 5},
+			wantStatus: types.StatusWarning,
+			wantError:  "Mailbox 'INBOX' holds 7 messages, maximum 5",
+		},
+		{
+			name:       "wrong password",
+			password:   "wrong",
+			wantStatus: types.StatusDown,
+			wantError:  "authentication failed: -ERR [AUTH] Authentication failed.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startFakeBroker(t, (&fakePOP3{user: "monitor", password: "secret", messages: 7}).serve)
+
+			result := NewPOP3Checker(5 * time.Second).Check(types.CheckConfig{
+				Name:     "maildrop",
+				Type:     types.CheckTypePOP3,
+				URL:      "pop3://monitor:" + tt.password + "@" + addr,
+				Timeout:  5 * time.Second,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+			if tt.wantStatus != types.StatusDown && assert.NotNil(t, result.Mail) {
+				assert.Equal(t, int64(7), result.Mail.Messages)
+			}
+		})
+	}
+}
+
+func TestPOP3Checker_STLS(t *testing.T) {
+	serverTLS, caPath := mailTLS(t)
+	addr := startFakeBroker(t, (&fakePOP3{tls: serverTLS, user: "monitor", password: "secret", messages: 2}).serve)
+
+	result := NewPOP3Checker(5 * time.Second).Check(types.CheckConfig{
+		Name:    "maildrop",
+		Type:    types.CheckTypePOP3,
+		URL:     "pop3://monitor:secret@" + addr + "?starttls=true",
+		Timeout: 5 * time.Second,
+		TLS:     types.TLSConfig{CAFile: caPath},
+	})
+
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Mail)
+	assert.True(t, result.Mail.StartTLS)
+	assert.Equal(t, int64(2), result.Mail.Messages)
+	require.NotNil(t, result.CertInfo)
+	assert.Equal(t, "mail.test", result.CertInfo.CommonName)
+}
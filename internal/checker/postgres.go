@@ -0,0 +1,343 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+)
+
+// PostgreSQL frontend/backend protocol constants
+const (
+	postgresProtocolVersion = 196608   // 3.0
+	postgresSSLRequest      = 80877103 // magic version number of SSLRequest
+	postgresMaxMessage      = 1 << 24
+
+	postgresAuthOK           = 0
+	postgresAuthCleartext    = 3
+	postgresAuthMD5          = 5
+	postgresAuthSASL         = 10
+	postgresAuthSASLContinue = 11
+	postgresAuthSASLFinal    = 12
+)
+
+var postgresProtocol = databaseProtocol{
+	endpointFormat: endpointFormat{name: "PostgreSQL", schemes: []string{"postgres", "postgresql"}, defaultPort: "5432"},
+	defaultQuery:   "SELECT 1",
+	query:          queryPostgres,
+}
+
+// postgresConn reads and writes protocol messages
+type postgresConn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	verified bool // TLS with a verified server certificate
+}
+
+// queryPostgres runs query with the simple query protocol
+func queryPostgres(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, query string, out *queryOutput) error {
+	if target.user == "" {
+		return fmt.Errorf("URL must include a user name")
+	}
+
+	conn, verified, err := postgresStartTLS(ctx, conn, target, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	pg := &postgresConn{conn: conn, reader: bufio.NewReader(conn), verified: verified}
+
+	database := target.path
+	if database == "" {
+		database = target.user
+	}
+	startup := binary.BigEndian.AppendUint32(nil, postgresProtocolVersion)
+	for _, param := range [][2]string{{"user", target.user}, {"database", database}, {"application_name", "healthcheck-cli"}} {
+		startup = append(startup, param[0]...)
+		startup = append(startup, 0)
+		startup = append(startup, param[1]...)
+		startup = append(startup, 0)
+	}
+	startup = append(startup, 0)
+	if err := pg.send(0, startup); err != nil {
+		return err
+	}
+
+	if err := pg.authenticate(target); err != nil {
+		return err
+	}
+	if err := pg.waitReady(nil); err != nil {
+		return err
+	}
+
+	if err := pg.send('Q', append([]byte(query), 0)); err != nil {
+		return err
+	}
+	if err := pg.waitReady(out); err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	pg.send('X', nil)
+	return nil
+}
+
+// postgresStartTLS negotiates TLS according to the URL's sslmode, which
+// follows libpq: disable, prefer, require, verify-ca or verify-full. Without
+// an sslmode it is verify-full when the check has a tls block and prefer
+// otherwise. It reports whether the server certificate was verified.
+func postgresStartTLS(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config) (net.Conn, bool, error) {
+	mode := target.params.Get("sslmode")
+	if mode == "" && target.tlsConfigured {
+		mode = "verify-full"
+	}
+	verified := (mode == "verify-ca" || mode == "verify-full") && !tlsConfig.InsecureSkipVerify
+	switch mode {
+	case "disable":
+		return conn, false, nil
+	case "", "prefer", "require":
+		tlsConfig = unverifiedTLS(tlsConfig)
+	case "verify-ca":
+		tlsConfig = chainOnlyTLS(tlsConfig)
+	case "verify-full":
+	default:
+		return nil, false, fmt.Errorf("unsupported sslmode '%s'", mode)
+	}
+
+	request := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, 8), postgresSSLRequest)
+	if _, err := conn.Write(request); err != nil {
+		return nil, false, err
+	}
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return nil, false, fmt.Errorf("reading SSL response: %w", err)
+	}
+
+	switch reply[0] {
+	case 'S':
+		conn, err := startTLS(ctx, conn, tlsConfig)
+		return conn, err == nil && verified, err
+	case 'N':
+		if mode == "" || mode == "prefer" {
+			return conn, false, nil
+		}
+		return nil, false, fmt.Errorf("server does not support SSL but sslmode is %s", mode)
+	default:
+		return nil, false, fmt.Errorf("unexpected SSL response %q", reply[0])
+	}
+}
+
+// send writes a message; kind 0 writes an untyped startup message
+func (p *postgresConn) send(kind byte, payload []byte) error {
+	var message []byte
+	if kind != 0 {
+		message = append(message, kind)
+	}
+	message = binary.BigEndian.AppendUint32(message, uint32(len(payload)+4))
+	message = append(message, payload...)
+	_, err := p.conn.Write(message)
+	return err
+}
+
+// receive reads one backend message
+func (p *postgresConn) receive() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(p.reader, header); err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length < 4 || length > postgresMaxMessage {
+		return 0, nil, fmt.Errorf("invalid message length %d", length)
+	}
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(p.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+// authenticate answers the server's authentication requests until it accepts the login
+func (p *postgresConn) authenticate(target *endpoint) error {
+	var scram *scramClient
+
+	for {
+		kind, msg, err := p.receive()
+		if err != nil {
+			return fmt.Errorf("reading authentication request: %w", err)
+		}
+
+		switch kind {
+		case 'E':
+			return fmt.Errorf("authentication failed: %s", postgresError(msg))
+		case 'R':
+		default:
+			return fmt.Errorf("unexpected message %q during authentication", kind)
+		}
+
+		if len(msg) < 4 {
+			return fmt.Errorf("short authentication request")
+		}
+		data := msg[4:]
+
+		switch code := binary.BigEndian.Uint32(msg); code {
+		case postgresAuthOK:
+			return nil
+		case postgresAuthCleartext:
+			if err := p.passwordAllowed(target.host); err != nil {
+				return err
+			}
+			err = p.send('p', append([]byte(target.password), 0))
+		case postgresAuthMD5:
+			if err := p.passwordAllowed(target.host); err != nil {
+				return err
+			}
+			if len(data) < 4 {
+				return fmt.Errorf("short MD5 salt")
+			}
+			err = p.send('p', append([]byte(postgresMD5(target.user, target.password, data[:4])), 0))
+		case postgresAuthSASL:
+			if !bytes.Contains(data, []byte("SCRAM-SHA-256\x00")) {
+				return fmt.Errorf("server offers no supported SASL mechanism")
+			}
+			scram, err = newSCRAMClient(sha256.New, "", target.password)
+			if err != nil {
+				return err
+			}
+			first := scram.first()
+			payload := append([]byte("SCRAM-SHA-256\x00"), binary.BigEndian.AppendUint32(nil, uint32(len(first)))...)
+			err = p.send('p', append(payload, first...))
+		case postgresAuthSASLContinue:
+			if scram == nil {
+				return fmt.Errorf("unexpected SASL continuation")
+			}
+			var final []byte
+			if final, err = scram.final(data); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+			err = p.send('p', final)
+		case postgresAuthSASLFinal:
+			if scram == nil {
+				return fmt.Errorf("unexpected SASL completion")
+			}
+			if err := scram.verify(data); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
+		default:
+			return fmt.Errorf("unsupported authentication method %d", code)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// passwordAllowed refuses to send a cleartext or MD5 password, which can be
+// read or replayed, unless the server certificate was verified or the server
+// is on a loopback address. SCRAM does not reveal the password and is always allowed.
+func (p *postgresConn) passwordAllowed(host string) error {
+	if p.verified || isLoopbackHost(host) {
+		return nil
+	}
+	return fmt.Errorf("refusing to send a cleartext or MD5 password without verified TLS; use sslmode=verify-full or SCRAM authentication")
+}
+
+// waitReady reads messages until ReadyForQuery, collecting data rows into
+// out when it is not nil. An ErrorResponse is returned once the server is ready.
+func (p *postgresConn) waitReady(out *queryOutput) error {
+	var serverErr error
+
+	for {
+		kind, msg, err := p.receive()
+		if err != nil {
+			// Fatal errors such as an unknown database close the connection
+			if serverErr != nil {
+				return serverErr
+			}
+			return err
+		}
+
+		switch kind {
+		case 'Z':
+			return serverErr
+		case 'E':
+			if serverErr == nil {
+				serverErr = fmt.Errorf("%s", postgresError(msg))
+			}
+		case 'D':
+			if out != nil {
+				columns, err := postgresDataRow(msg)
+				if err != nil {
+					return err
+				}
+				out.row(columns)
+			}
+		}
+	}
+}
+
+// postgresDataRow decodes the text columns of a DataRow message
+func postgresDataRow(msg []byte) ([]string, error) {
+	if len(msg) < 2 {
+		return nil, fmt.Errorf("short data row")
+	}
+	count := int(binary.BigEndian.Uint16(msg))
+	msg = msg[2:]
+
+	columns := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if len(msg) < 4 {
+			return nil, fmt.Errorf("short data row")
+		}
+		length := int32(binary.BigEndian.Uint32(msg))
+		msg = msg[4:]
+		if length < 0 {
+			columns = append(columns, "NULL")
+			continue
+		}
+		if int(length) > len(msg) {
+			return nil, fmt.Errorf("short data row")
+		}
+		columns = append(columns, string(msg[:length]))
+		msg = msg[length:]
+	}
+	return columns, nil
+}
+
+// postgresError formats an ErrorResponse as "message (SQLSTATE code)"
+func postgresError(msg []byte) string {
+	var message, code string
+	for len(msg) > 1 {
+		field := msg[0]
+		end := bytes.IndexByte(msg[1:], 0)
+		if end < 0 {
+			break
+		}
+		value := string(msg[1 : end+1])
+		msg = msg[end+2:]
+
+		switch field {
+		case 'M':
+			message = value
+		case 'C':
+			code = value
+		}
+	}
+	if code == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (SQLSTATE %s)", message, code)
+}
+
+// postgresMD5 computes the md5 password response: md5(md5(password+user)+salt)
+func postgresMD5(user, password string, salt []byte) string {
+	inner := md5.Sum([]byte(password + user))
+	outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), salt...))
+	return "md5" + hex.EncodeToString(outer[:])
+}
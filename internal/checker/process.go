@@ -0,0 +1,156 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// ProcessChecker counts the running processes matching a name or command line
+type ProcessChecker struct {
+	proc string
+}
+
+// NewProcessChecker creates a process checker
+func NewProcessChecker() *ProcessChecker {
+	return &ProcessChecker{proc: procRoot}
+}
+
+// Name returns the checker name
+func (p *ProcessChecker) Name() string {
+	return "Process"
+}
+
+// Check counts matching processes and applies processes_min and processes_max
+func (p *ProcessChecker) Check(check types.CheckConfig) types.Result {
+	result := localResult(check)
+
+	config := check.Process
+	if config.IsZero() {
+		result.Status = types.StatusError
+		result.Error = "No process name or cmdline configured"
+		return result
+	}
+
+	var cmdline *regexp.Regexp
+	if config.Cmdline != "" {
+		var err error
+		if cmdline, err = regexp.Compile(config.Cmdline); err != nil {
+			result.Status = types.StatusError
+			result.Error = fmt.Sprintf("Invalid cmdline regex: %v", err)
+			return result
+		}
+	}
+
+	pids, err := p.matching(config.Name, cmdline)
+	result.ResponseTime = time.Since(result.Timestamp)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Cannot list processes: %v", err)
+		return result
+	}
+
+	minimum, maximum := check.Expected.ProcessesMin, check.Expected.ProcessesMax
+	if minimum == 0 {
+		minimum = 1
+	}
+	threshold := strconv.Itoa(minimum) + ":"
+	if maximum > 0 {
+		threshold += strconv.Itoa(maximum)
+	}
+	result.Metrics = []types.Metric{
+		{Label: "processes", Value: float64(len(pids)), Crit: threshold, Min: "0"},
+	}
+
+	var problems []string
+	switch {
+	case len(pids) == 0:
+		problems = append(problems, "no matching process is running")
+	case len(pids) < minimum:
+		problems = append(problems, fmt.Sprintf("%d matching processes running (minimum %d)", len(pids), minimum))
+	case maximum > 0 && len(pids) > maximum:
+		problems = append(problems, fmt.Sprintf("%d matching processes running (maximum %d)", len(pids), maximum))
+	}
+
+	summary := fmt.Sprintf("%d matching processes (PID %s)", len(pids), strings.Join(firstPIDs(pids, 5), ", "))
+	concludeLocal(&result, problems, types.StatusDown, summary)
+	return result
+}
+
+// matching returns the IDs of live processes whose executable name equals
+// name and whose command line matches cmdline, when those are set
+func (p *ProcessChecker) matching(name string, cmdline *regexp.Regexp) ([]int, error) {
+	entries, err := os.ReadDir(p.proc)
+	if err != nil {
+		return nil, err
+	}
+
+	self := os.Getpid()
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() || pid == self {
+			continue
+		}
+		dir := filepath.Join(p.proc, entry.Name())
+
+		// Processes may exit while they are listed, so read errors only skip them
+		stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		comm, state, ok := parseProcStat(string(stat))
+		if !ok || state == "Z" {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+		if err != nil {
+			continue
+		}
+		args := strings.Split(strings.TrimRight(string(raw), "\x00"), "\x00")
+
+		// comm is cut to 15 characters, so the name is also compared with argv[0]
+		if name != "" && comm != name && filepath.Base(args[0]) != name {
+			continue
+		}
+		if cmdline != nil && !cmdline.MatchString(strings.Join(args, " ")) {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// parseProcStat reads the command name and state from /proc/<pid>/stat, e.g.
+// "1234 (nginx: worker) S 1 ...". The name may itself contain spaces and parentheses.
+func parseProcStat(stat string) (comm, state string, ok bool) {
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return "", "", false
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) == 0 {
+		return "", "", false
+	}
+	return stat[open+1 : end], fields[0], true
+}
+
+// firstPIDs formats up to limit process IDs, noting how many were left out
+func firstPIDs(pids []int, limit int) []string {
+	var out []string
+	for i, pid := range pids {
+		if i == limit {
+			out = append(out, fmt.Sprintf("and %d more", len(pids)-limit))
+			break
+		}
+		out = append(out, strconv.Itoa(pid))
+	}
+	return out
+}
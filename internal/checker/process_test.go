@@ -0,0 +1,124 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcesses lays out a /proc with a few processes
+func fakeProcesses(t *testing.T) string {
+	t.Helper()
+	proc := t.TempDir()
+
+	processes := []struct {
+		pid, stat, cmdline string
+	}{
+		{"1", "1 (systemd) S 0 1 1", "/sbin/init\x00splash\x00"},
+		{"101", "101 (nginx) S 1 101 101", "nginx: master process /usr/sbin/nginx\x00"},
+		{"102", "102 (nginx) S 101 101 101", "nginx: worker process\x00"},
+		{"103", "103 (nginx) Z 101 101 101", ""},
+		{"200", "200 (java) S 1 200 200", "/usr/bin/java\x00-jar\x00/opt/app/billing-service.jar\x00"},
+		{"300", "300 (postgres-expor) S 1 300 300", "/usr/local/bin/postgres-exporter\x00--web.listen-address=:9187\x00"},
+	}
+	for _, p := range processes {
+		writeProc(t, proc, p.pid+"/stat", p.stat)
+		writeProc(t, proc, p.pid+"/cmdline", p.cmdline)
+	}
+	writeProc(t, proc, "loadavg", "0.00 0.00 0.00 1/1 1\n")
+
+	return proc
+}
+
+func TestProcessChecker(t *testing.T) {
+	checker := &ProcessChecker{proc: fakeProcesses(t)}
+
+	tests := []struct {
+		name        string
+		process     types.ProcessConfig
+		min, max    int
+		wantStatus  types.Status
+		wantMessage string
+		wantErr     string
+	}{
+		{
+			name:        "by name, zombies skipped",
+			process:     types.ProcessConfig{Name: "nginx"},
+			wantStatus:  types.StatusUp,
+			wantMessage: "2 matching processes (PID 101, 102)",
+		},
+		{
+			name:        "by cmdline",
+			process:     types.ProcessConfig{Cmdline: `billing-service\.jar`},
+			wantStatus:  types.StatusUp,
+			wantMessage: "1 matching processes (PID 200)",
+		},
+		{
+			name:        "name longer than comm",
+			process:     types.ProcessConfig{Name: "postgres-exporter", Cmdline: ":9187"},
+			wantStatus:  types.StatusUp,
+			wantMessage: "1 matching processes (PID 300)",
+		},
+		{
+			name:       "not running",
+			process:    types.ProcessConfig{Name: "redis-server"},
+			wantStatus: types.StatusDown,
+			wantErr:    "no matching process is running",
+		},
+		{
+			name:       "too few",
+			process:    types.ProcessConfig{Name: "nginx"},
+			min:        3,
+			wantStatus: types.StatusDown,
+			wantErr:    "2 matching processes running (minimum 3)",
+		},
+		{
+			name:        "anchored cmdline",
+			process:     types.ProcessConfig{Name: "nginx", Cmdline: "^nginx: worker"},
+			wantStatus:  types.StatusUp,
+			wantMessage: "1 matching processes (PID 102)",
+		},
+		{
+			name:       "above maximum",
+			process:    types.ProcessConfig{Name: "nginx"},
+			max:        1,
+			wantStatus: types.StatusDown,
+			wantErr:    "2 matching processes running (maximum 1)",
+		},
+		{
+			name:       "invalid regex",
+			process:    types.ProcessConfig{Cmdline: "("},
+			wantStatus: types.StatusError,
+			wantErr:    "Invalid cmdline regex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{
+				Name:     "process",
+				Type:     types.CheckTypeProcess,
+				Process:  tt.process,
+				Expected: types.Expected{ProcessesMin: tt.min, ProcessesMax: tt.max},
+			})
+
+			require.Equal(t, tt.wantStatus, result.Status, result.Error)
+			assert.Contains(t, result.Error, tt.wantErr)
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, result.Message)
+			}
+		})
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	comm, state, ok := parseProcStat("4242 (tmux: server) (1)) R 1 4242 4242 0")
+	require.True(t, ok)
+	assert.Equal(t, "tmux: server) (1)", comm)
+	assert.Equal(t, "R", state)
+
+	_, _, ok = parseProcStat("4242 tmux")
+	assert.False(t, ok)
+}
@@ -0,0 +1,213 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxRedisReply caps the size of a single bulk string or array in a reply
+const maxRedisReply = 16 << 20
+
+var redisProtocol = databaseProtocol{
+	endpointFormat: endpointFormat{name: "Redis", schemes: []string{"redis", "rediss"}, defaultPort: "6379"},
+	defaultQuery:   "PING",
+	query:          queryRedis,
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// queryRedis authenticates, selects the database and runs one command.
+// rediss:// URLs connect over TLS.
+func queryRedis(ctx context.Context, conn net.Conn, target *endpoint, tlsConfig *tls.Config, query string, out *queryOutput) error {
+	if target.scheme == "rediss" {
+		var err error
+		if conn, err = startTLS(ctx, conn, tlsConfig); err != nil {
+			return err
+		}
+	}
+	reader := bufio.NewReader(conn)
+
+	if target.password != "" {
+		args := []string{"AUTH", target.password}
+		if target.user != "" {
+			args = []string{"AUTH", target.user, target.password}
+		}
+		if _, err := redisCall(conn, reader, args); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if target.path != "" && target.path != "0" {
+		if _, err := strconv.Atoi(target.path); err != nil {
+			return fmt.Errorf("database must be a number, got '%s'", target.path)
+		}
+		if _, err := redisCall(conn, reader, []string{"SELECT", target.path}); err != nil {
+			return fmt.Errorf("selecting database %s failed: %w", target.path, err)
+		}
+	}
+
+	args, err := splitCommand(query)
+	if err != nil {
+		return err
+	}
+	reply, err := redisCall(conn, reader, args)
+	if err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
+
+	renderRedisReply(reply, out)
+	return nil
+}
+
+// redisCall sends a command and reads its reply, returning error replies as errors
+func redisCall(w io.Writer, r *bufio.Reader, args []string) (interface{}, error) {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(w, command.String()); err != nil {
+		return nil, err
+	}
+
+	reply, err := readRedisReply(r)
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := reply.(redisError); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
+
+// readRedisReply decodes one RESP2 reply into a string, int64, redisError,
+// []interface{} or nil
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, value := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return value, nil
+	case '-':
+		return redisError(value), nil
+	case ':':
+		return strconv.ParseInt(value, 10, 64)
+	case '$':
+		length, err := strconv.Atoi(value)
+		if err != nil || length > maxRedisReply {
+			return nil, fmt.Errorf("invalid bulk length %q", value)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:length]), nil
+	case '*':
+		count, err := strconv.Atoi(value)
+		if err != nil || count > maxRedisReply {
+			return nil, fmt.Errorf("invalid array length %q", value)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := readRedisReply(r)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
+
+// renderRedisReply writes a reply as text. Bulk strings such as INFO output
+// keep their own lines; arrays write one element per line.
+func renderRedisReply(reply interface{}, out *queryOutput) {
+	switch value := reply.(type) {
+	case nil:
+		out.line("(nil)")
+	case string:
+		out.line(strings.ReplaceAll(value, "\r\n", "\n"))
+	case int64:
+		out.line(strconv.FormatInt(value, 10))
+	case redisError:
+		out.line("(error) " + string(value))
+	case []interface{}:
+		for _, item := range value {
+			renderRedisReply(item, out)
+		}
+	}
+}
+
+// splitCommand splits a command line into arguments like redis-cli does,
+// honoring single and double quotes
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unbalanced quotes in command")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
@@ -0,0 +1,167 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeRedis serves a few commands of the Redis protocol for one password
+func startFakeRedis(t *testing.T, password string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn, password)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveFakeRedis(conn net.Conn, password string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := password == ""
+
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		command := strings.ToUpper(args[0])
+		switch {
+		case command == "AUTH":
+			if args[len(args)-1] != password {
+				fmt.Fprint(conn, "-WRONGPASS invalid username-password pair or user is disabled.\r\n")
+				continue
+			}
+			authenticated = true
+			fmt.Fprint(conn, "+OK\r\n")
+		case !authenticated:
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+		case command == "SELECT":
+			fmt.Fprint(conn, "+OK\r\n")
+		case command == "PING":
+			fmt.Fprint(conn, "+PONG\r\n")
+		case command == "INFO":
+			info := "# Replication\r\nrole:master\r\nconnected_slaves:1\r\n"
+			fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(info), info)
+		case command == "GET":
+			fmt.Fprint(conn, "$-1\r\n")
+		case command == "DBSIZE":
+			fmt.Fprint(conn, ":42\r\n")
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+func TestRedisChecker(t *testing.T) {
+	addr := startFakeRedis(t, "secret")
+
+	tests := []struct {
+		name       string
+		url        string
+		query      string
+		expected   types.Expected
+		wantStatus types.Status
+		wantError  string
+	}{
+		{name: "ping", url: "redis://:secret@" + addr + "/2", expected: types.Expected{BodyContains: "PONG"}, wantStatus: types.StatusUp},
+		{name: "acl user", url: "redis://monitor:secret@" + addr, wantStatus: types.StatusUp},
+		{
+			name:       "replication role",
+			url:        "redis://:secret@" + addr,
+			query:      "INFO replication",
+			expected:   types.Expected{BodyRegex: `(?m)^role:master$`},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "replica expected",
+			url:        "redis://:secret@" + addr,
+			query:      "INFO replication",
+			expected:   types.Expected{BodyContains: "role:slave"},
+			wantStatus: types.StatusDown,
+			wantError:  "query result does not contain 'role:slave'",
+		},
+		{name: "integer reply", url: "redis://:secret@" + addr, query: "DBSIZE", expected: types.Expected{BodyContains: "42"}, wantStatus: types.StatusUp},
+		{name: "nil reply", url: "redis://:secret@" + addr, query: `GET "missing key"`, expected: types.Expected{BodyContains: "(nil)"}, wantStatus: types.StatusUp},
+		{name: "wrong password", url: "redis://:wrong@" + addr, wantStatus: types.StatusDown, wantError: "authentication failed: WRONGPASS"},
+		{name: "no password", url: "redis://" + addr, wantStatus: types.StatusDown, wantError: "command failed: NOAUTH"},
+		{name: "unknown command", url: "redis://:secret@" + addr, query: "FLUSHALL", wantStatus: types.StatusDown, wantError: "unknown command"},
+		{name: "bad database", url: "redis://:secret@" + addr + "/cache", wantStatus: types.StatusDown, wantError: "database must be a number"},
+	}
+
+	checker := NewRedisChecker(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{
+				Name:     "cache",
+				Type:     types.CheckTypeRedis,
+				URL:      tt.url,
+				Timeout:  5 * time.Second,
+				Query:    tt.query,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{command: "PING", want: []string{"PING"}},
+		{command: "  INFO   replication ", want: []string{"INFO", "replication"}},
+		{command: `GET "user:1 name"`, want: []string{"GET", "user:1 name"}},
+		{command: `SET k 'it''s'`, want: []string{"SET", "k", "its"}},
+		{command: `ECHO "say \"hi\""`, want: []string{"ECHO", `say "hi"`}},
+		{command: `ECHO ""`, want: []string{"ECHO", ""}},
+		{command: `GET "open`, wantErr: true},
+		{command: "   ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := splitCommand(tt.command)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package checker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/ocsp"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// Revocation sources reported in types.Revocation
+const (
+	revocationSourceStapled = "ocsp-stapled"
+	revocationSourceOCSP    = "ocsp"
+	revocationSourceCRL     = "crl"
+)
+
+// Size limits for responder downloads
+const (
+	maxOCSPResponseSize = 64 << 10
+	maxCRLSize          = 20 << 20
+)
+
+// crlReasons names the RFC 5280 CRLReason codes
+var crlReasons = map[int]string{
+	0:  "unspecified",
+	1:  "keyCompromise",
+	2:  "cACompromise",
+	3:  "affiliationChanged",
+	4:  "superseded",
+	5:  "cessationOfOperation",
+	6:  "certificateHold",
+	8:  "removeFromCRL",
+	9:  "privilegeWithdrawn",
+	10: "aACompromise",
+}
+
+// revocationChecker looks up certificate revocation status over OCSP and CRL,
+// caching answers until the responder's nextUpdate. Answers past their
+// nextUpdate are never used.
+type revocationChecker struct {
+	mu   sync.Mutex
+	ocsp map[string]cachedRevocation
+	crls map[string]*x509.RevocationList
+}
+
+type cachedRevocation struct {
+	revocation types.Revocation
+	expires    time.Time
+}
+
+func newRevocationChecker() *revocationChecker {
+	return &revocationChecker{
+		ocsp: make(map[string]cachedRevocation),
+		crls: make(map[string]*x509.RevocationList),
+	}
+}
+
+// check determines the revocation status of leaf. The stapled OCSP response is
+// preferred, then the OCSP responder, then the CRL distribution point.
+func (r *revocationChecker) check(leaf, issuer *x509.Certificate, stapled []byte, config types.RevocationConfig, dialer *netdial.Dialer, timeout time.Duration) *types.Revocation {
+	if issuer == nil {
+		return &types.Revocation{Status: ocsp.Unknown, Error: "issuer certificate not available"}
+	}
+
+	var failures []string
+
+	if len(stapled) > 0 {
+		response, err := ocsp.ParseResponse(stapled, leaf, issuer, time.Now())
+		if err == nil {
+			return revocationFromOCSP(response, revocationSourceStapled)
+		}
+		failures = append(failures, fmt.Sprintf("stapled OCSP: %v", err))
+	}
+
+	// Responders are reached through the same proxy and resolver as the target
+	transport := &http.Transport{DisableKeepAlives: true}
+	dialer.ConfigureTransport(transport)
+	client := &http.Client{Timeout: timeout, Transport: transport}
+	lookup := dialer.LookupIP
+
+	if responder := firstURL(config.OCSPURL, leaf.OCSPServer); responder != "" {
+		revocation, err := r.queryOCSP(client, lookup, responder, leaf, issuer)
+		if err == nil {
+			return revocation
+		}
+		failures = append(failures, fmt.Sprintf("OCSP %s: %v", responder, err))
+	}
+
+	if distributionPoint := firstURL(config.CRLURL, leaf.CRLDistributionPoints); distributionPoint != "" {
+		revocation, err := r.queryCRL(client, lookup, distributionPoint, leaf, issuer)
+		if err == nil {
+			return revocation
+		}
+		failures = append(failures, fmt.Sprintf("CRL %s: %v", distributionPoint, err))
+	}
+
+	if len(failures) == 0 {
+		failures = append(failures, "certificate has no OCSP responder or CRL distribution point")
+	}
+	return &types.Revocation{Status: ocsp.Unknown, Error: strings.Join(failures, "; ")}
+}
+
+// queryOCSP asks an OCSP responder about leaf, using a cached answer while it is fresh
+func (r *revocationChecker) queryOCSP(client *http.Client, lookup security.LookupFunc, responder string, leaf, issuer *x509.Certificate) (*types.Revocation, error) {
+	fingerprint := sha256.Sum256(issuer.Raw)
+	key := responder + "|" + hex.EncodeToString(fingerprint[:]) + "|" + leaf.SerialNumber.String()
+
+	now := time.Now()
+	r.mu.Lock()
+	cached, ok := r.ocsp[key]
+	r.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		revocation := cached.revocation
+		return &revocation, nil
+	}
+
+	request, err := ocsp.CreateRequest(leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := fetch(client, lookup, http.MethodPost, responder, request, maxOCSPResponseSize)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := ocsp.ParseResponse(body, leaf, issuer, now)
+	if err != nil {
+		return nil, err
+	}
+
+	revocation := revocationFromOCSP(response, revocationSourceOCSP)
+	r.mu.Lock()
+	for cachedKey, entry := range r.ocsp {
+		if !now.Before(entry.expires) {
+			delete(r.ocsp, cachedKey)
+		}
+	}
+	if !response.NextUpdate.IsZero() {
+		r.ocsp[key] = cachedRevocation{revocation: *revocation, expires: response.NextUpdate}
+	}
+	r.mu.Unlock()
+	return revocation, nil
+}
+
+// queryCRL looks leaf up in the issuer's CRL, downloading it again after
+// nextUpdate. A CRL that is still stale after the download is rejected.
+func (r *revocationChecker) queryCRL(client *http.Client, lookup security.LookupFunc, distributionPoint string, leaf, issuer *x509.Certificate) (*types.Revocation, error) {
+	now := time.Now()
+	r.mu.Lock()
+	list, ok := r.crls[distributionPoint]
+	r.mu.Unlock()
+
+	if !ok || !bytes.Equal(list.RawIssuer, issuer.RawSubject) || ocsp.CheckFreshness(list.ThisUpdate, list.NextUpdate, now) != nil {
+		body, err := fetch(client, lookup, http.MethodGet, distributionPoint, nil, maxCRLSize)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err = x509.ParseRevocationList(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed CRL: %w", err)
+		}
+		if !bytes.Equal(list.RawIssuer, issuer.RawSubject) {
+			return nil, fmt.Errorf("CRL issued by '%s', not the certificate issuer", list.Issuer)
+		}
+		if err := list.CheckSignatureFrom(issuer); err != nil {
+			return nil, fmt.Errorf("CRL signature invalid: %w", err)
+		}
+		if err := ocsp.CheckFreshness(list.ThisUpdate, list.NextUpdate, now); err != nil {
+			r.mu.Lock()
+			delete(r.crls, distributionPoint)
+			r.mu.Unlock()
+			return nil, fmt.Errorf("CRL %w", err)
+		}
+
+		r.mu.Lock()
+		r.crls[distributionPoint] = list
+		r.mu.Unlock()
+	}
+
+	revocation := &types.Revocation{
+		Status:     ocsp.Good,
+		Source:     revocationSourceCRL,
+		NextUpdate: list.NextUpdate,
+	}
+	for _, entry := range list.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+			revocation.Status = ocsp.Revoked
+			revocation.RevokedAt = entry.RevocationTime
+			revocation.Reason = entry.ReasonCode
+			break
+		}
+	}
+	return revocation, nil
+}
+
+// revocationDescription explains a revoked status for result errors
+func revocationDescription(revocation *types.Revocation) string {
+	reason, ok := crlReasons[revocation.Reason]
+	if !ok {
+		reason = fmt.Sprintf("reason %d", revocation.Reason)
+	}
+	return fmt.Sprintf("certificate revoked on %s (%s, via %s)",
+		revocation.RevokedAt.Format("2006-01-02"), reason, revocation.Source)
+}
+
+func revocationFromOCSP(response *ocsp.Response, source string) *types.Revocation {
+	return &types.Revocation{
+		Status:     response.Status,
+		Source:     source,
+		RevokedAt:  response.RevokedAt,
+		Reason:     response.RevocationReason,
+		NextUpdate: response.NextUpdate,
+	}
+}
+
+// firstURL returns the override if set, otherwise the first HTTP URL in candidates
+func firstURL(override string, candidates []string) string {
+	if override != "" {
+		return override
+	}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, "http://") || strings.HasPrefix(candidate, "https://") {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// fetch performs a bounded responder request
+func fetch(client *http.Client, lookup security.LookupFunc, method, url string, body []byte, limit int64) ([]byte, error) {
+	if err := security.ValidateURLWithLookup(url, lookup); err != nil {
+		return nil, fmt.Errorf("URL validation failed: %w", err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/ocsp-request")
+		req.Header.Set("Accept", "application/ocsp-response")
+	}
+	req.Header.Set("User-Agent", "HealthCheck-CLI/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("response exceeds %d bytes", limit)
+	}
+	return data, nil
+}
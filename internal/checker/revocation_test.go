@@ -0,0 +1,226 @@
+package checker
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/ocsp"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ocspStandIn serves a fixed OCSP answer for the leaf and counts requests
+func ocspStandIn(t *testing.T, issuer *testCert, leaf *x509.Certificate, status string) (*httptest.Server, *int32) {
+	t.Helper()
+
+	now := time.Now().Truncate(time.Second)
+	response, err := ocsp.CreateResponse(issuer.cert, issuer.key, ocsp.ResponseTemplate{
+		Status:           status,
+		SerialNumber:     leaf.SerialNumber,
+		ThisUpdate:       now,
+		NextUpdate:       now.Add(time.Hour),
+		RevokedAt:        now.Add(-24 * time.Hour),
+		RevocationReason: 1,
+	})
+	require.NoError(t, err)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestSSLChecker_Revocation(t *testing.T) {
+	root := issueCert(t, caTemplate("Test Root"), newECKey(t), nil)
+	leafKey := newECKey(t)
+	leaf := issueCert(t, leafTemplate("service.test"), leafKey, root)
+	targetURL, caPath := startChainServer(t, root, leafKey, leaf.cert)
+
+	goodResponder, goodRequests := ocspStandIn(t, root, leaf.cert, ocsp.Good)
+	revokedResponder, _ := ocspStandIn(t, root, leaf.cert, ocsp.Revoked)
+	brokenResponder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer brokenResponder.Close()
+
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: leaf.cert.SerialNumber, RevocationTime: time.Now().Add(-time.Hour), ReasonCode: 4},
+		},
+	}, root.cert, root.key)
+	require.NoError(t, err)
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(crlDER)
+	}))
+	defer crlServer.Close()
+
+	// A replayed good answer and an outdated CRL, both past their nextUpdate
+	staleResponse, err := ocsp.CreateResponse(root.cert, root.key, ocsp.ResponseTemplate{
+		Status:       ocsp.Good,
+		SerialNumber: leaf.cert.SerialNumber,
+		ThisUpdate:   time.Now().Add(-48 * time.Hour),
+		NextUpdate:   time.Now().Add(-24 * time.Hour),
+	})
+	require.NoError(t, err)
+	staleResponder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(staleResponse)
+	}))
+	defer staleResponder.Close()
+
+	staleCRL, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-48 * time.Hour),
+		NextUpdate: time.Now().Add(-24 * time.Hour),
+	}, root.cert, root.key)
+	require.NoError(t, err)
+	staleCRLServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(staleCRL)
+	}))
+	defer staleCRLServer.Close()
+
+	tests := []struct {
+		name       string
+		revocation types.RevocationConfig
+		wantStatus types.Status
+		wantSource string
+		wantResult string
+		errMsg     string
+	}{
+		{
+			name:       "GoodViaResponder",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: goodResponder.URL},
+			wantStatus: types.StatusUp,
+			wantSource: "ocsp",
+			wantResult: ocsp.Good,
+		},
+		{
+			name:       "RevokedViaResponder",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: revokedResponder.URL},
+			wantStatus: types.StatusDown,
+			wantSource: "ocsp",
+			wantResult: ocsp.Revoked,
+			errMsg:     "keyCompromise",
+		},
+		{
+			name:       "FallsBackToCRL",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: brokenResponder.URL, CRLURL: crlServer.URL},
+			wantStatus: types.StatusDown,
+			wantSource: "crl",
+			wantResult: ocsp.Revoked,
+			errMsg:     "superseded",
+		},
+		{
+			name:       "UnknownSoftFail",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: brokenResponder.URL},
+			wantStatus: types.StatusWarning,
+			wantResult: ocsp.Unknown,
+			errMsg:     "unexpected status 500",
+		},
+		{
+			name:       "StaleResponseRejected",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: staleResponder.URL, HardFail: true},
+			wantStatus: types.StatusDown,
+			wantResult: ocsp.Unknown,
+			errMsg:     "OCSP response is stale",
+		},
+		{
+			name:       "StaleCRLRejected",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: brokenResponder.URL, CRLURL: staleCRLServer.URL},
+			wantStatus: types.StatusWarning,
+			wantResult: ocsp.Unknown,
+			errMsg:     "CRL is stale",
+		},
+		{
+			name:       "UnknownHardFail",
+			revocation: types.RevocationConfig{Enabled: true, OCSPURL: brokenResponder.URL, HardFail: true},
+			wantStatus: types.StatusDown,
+			wantResult: ocsp.Unknown,
+		},
+	}
+
+	checker := NewSSLChecker(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(types.CheckConfig{
+				Name:       tt.name,
+				URL:        targetURL,
+				Timeout:    5 * time.Second,
+				TLS:        types.TLSConfig{CAFile: caPath, ServerName: "service.test"},
+				Revocation: tt.revocation,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			if tt.errMsg != "" {
+				assert.Contains(t, result.Error, tt.errMsg)
+			}
+			require.NotNil(t, result.CertInfo)
+			require.NotNil(t, result.CertInfo.Revocation)
+			assert.Equal(t, tt.wantResult, result.CertInfo.Revocation.Status)
+			assert.Equal(t, tt.wantSource, result.CertInfo.Revocation.Source)
+		})
+	}
+
+	// The good answer is cached until nextUpdate
+	result := checker.Check(types.CheckConfig{
+		Name:       "cached",
+		URL:        targetURL,
+		Timeout:    5 * time.Second,
+		TLS:        types.TLSConfig{CAFile: caPath, ServerName: "service.test"},
+		Revocation: types.RevocationConfig{Enabled: true, OCSPURL: goodResponder.URL},
+	})
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, int32(1), atomic.LoadInt32(goodRequests))
+}
+
+func TestSSLChecker_StapledOCSP(t *testing.T) {
+	root := issueCert(t, caTemplate("Test Root"), newECKey(t), nil)
+	leafKey := newECKey(t)
+	leaf := issueCert(t, leafTemplate("service.test"), leafKey, root)
+
+	staple, err := ocsp.CreateResponse(root.cert, root.key, ocsp.ResponseTemplate{
+		Status:       ocsp.Good,
+		SerialNumber: leaf.cert.SerialNumber,
+		ThisUpdate:   time.Now(),
+		NextUpdate:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leaf.cert.Raw},
+		PrivateKey:  leafKey,
+		OCSPStaple:  staple,
+	}}}
+	server.StartTLS()
+	defer server.Close()
+
+	_, caPath := startChainServer(t, root, leafKey, leaf.cert)
+
+	checker := NewSSLChecker(5 * time.Second)
+	result := checker.Check(types.CheckConfig{
+		Name:       "stapled",
+		URL:        server.URL,
+		Timeout:    5 * time.Second,
+		TLS:        types.TLSConfig{CAFile: caPath, ServerName: "service.test"},
+		Revocation: types.RevocationConfig{Enabled: true, HardFail: true},
+	})
+
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.CertInfo.Revocation)
+	assert.Equal(t, "ocsp-stapled", result.CertInfo.Revocation.Source)
+}
@@ -0,0 +1,273 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonpath"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// variablePattern matches {{name}} placeholders in scenario steps
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// ScenarioChecker implements multi-step HTTP scenario checks
+type ScenarioChecker struct {
+	http *HTTPChecker
+}
+
+// NewScenarioChecker creates a new scenario checker that reuses the HTTP checker's
+// transport and response validation
+func NewScenarioChecker(httpChecker *HTTPChecker) *ScenarioChecker {
+	return &ScenarioChecker{
+		http: httpChecker,
+	}
+}
+
+// Name returns the checker name
+func (s *ScenarioChecker) Name() string {
+	return "Scenario"
+}
+
+// Check runs every step of the scenario in order, stopping at the first failure
+func (s *ScenarioChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       check.URL,
+		Timestamp: start,
+	}
+
+	if len(check.Steps) == 0 {
+		result.Status = types.StatusError
+		result.Error = "Scenario has no steps"
+		return result
+	}
+
+	base, err := url.Parse(check.URL)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Invalid scenario base URL: %v", err)
+		return result
+	}
+
+	dialer, err := s.http.dialerFor(check)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		return result
+	}
+
+	httpClient, err := s.http.clientFor(check, dialer)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+		return result
+	}
+
+	// The timeout covers the whole scenario, not each step
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	// Each run gets its own cookie jar so session cookies flow between steps
+	jar, _ := cookiejar.New(nil)
+	client := *httpClient
+	client.Jar = jar
+
+	variables := make(map[string]string)
+	slowStep := ""
+
+	for i, step := range check.Steps {
+		stepName := step.Name
+		if stepName == "" {
+			stepName = fmt.Sprintf("step %d", i+1)
+		}
+
+		stepResult := s.runStep(ctx, &client, dialer, base, step, check.MaxBodySize, variables)
+		stepResult.Name = stepName
+		result.Steps = append(result.Steps, stepResult)
+		result.StatusCode = stepResult.StatusCode
+
+		// A slow step still lets the scenario run its remaining steps, such as cleanup
+		if stepResult.Status == types.StatusSlow {
+			if slowStep == "" {
+				slowStep = fmt.Sprintf("Step '%s' was slow: %s", stepName, stepResult.Error)
+			}
+			continue
+		}
+		if stepResult.Status != types.StatusUp {
+			result.ResponseTime = time.Since(start)
+			result.Status = stepResult.Status
+			result.FailedStep = stepName
+			result.Error = fmt.Sprintf("Step '%s' failed: %s", stepName, stepResult.Error)
+			return result
+		}
+	}
+
+	duration := time.Since(start)
+	result.ResponseTime = duration
+
+	if check.Expected.ResponseTimeMax > 0 && duration > check.Expected.ResponseTimeMax {
+		result.Status = types.StatusSlow
+		result.Error = fmt.Sprintf("Scenario time %v exceeds maximum %v", duration, check.Expected.ResponseTimeMax)
+		return result
+	}
+	if slowStep != "" {
+		result.Status = types.StatusSlow
+		result.Error = slowStep
+		return result
+	}
+
+	result.Status = types.StatusUp
+	return result
+}
+
+// runStep executes a single scenario step and records extracted variables
+func (s *ScenarioChecker) runStep(ctx context.Context, client *http.Client, dialer *netdial.Dialer, base *url.URL, step types.ScenarioStep, maxBodySize int64, variables map[string]string) types.StepResult {
+	start := time.Now()
+	stepResult := types.StepResult{}
+
+	fail := func(status types.Status, format string, args ...interface{}) types.StepResult {
+		stepResult.Status = status
+		stepResult.Error = fmt.Sprintf(format, args...)
+		stepResult.ResponseTime = time.Since(start)
+		return stepResult
+	}
+
+	target, err := base.Parse(substituteVariables(step.URL, variables))
+	if err != nil {
+		return fail(types.StatusError, "invalid step URL: %v", err)
+	}
+
+	if err := security.ValidateURLWithLookup(target.String(), dialer.LookupIP); err != nil {
+		return fail(types.StatusError, "URL validation failed: %v", err)
+	}
+
+	headers := make(map[string]string, len(step.Headers))
+	for key, value := range step.Headers {
+		headers[key] = substituteVariables(value, variables)
+	}
+	if err := security.ValidateHTTPHeaders(headers); err != nil {
+		return fail(types.StatusError, "header validation failed: %v", err)
+	}
+
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	body := substituteVariables(step.Body, variables)
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), strings.NewReader(body))
+	if err != nil {
+		return fail(types.StatusError, "failed to create request: %v", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "HealthCheck-CLI/1.0")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fail(types.StatusDown, "scenario timed out")
+		}
+		return fail(types.StatusDown, "request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Steps always buffer the body so variables can be extracted from it
+	respBody, err := readBody(resp.Body, step.Expected, bodyOptions{
+		limit:  maxBodySize,
+		buffer: true,
+	})
+	stepResult.StatusCode = resp.StatusCode
+	if err != nil {
+		return fail(types.StatusError, "failed to read response body: %v", err)
+	}
+
+	// Without an explicit expectation any 2xx response is a success
+	if step.Expected.Status == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return fail(types.StatusDown, "unexpected status %d", resp.StatusCode)
+	}
+
+	if err := s.http.validateResponse(resp, respBody, step.Expected); err != nil {
+		return fail(types.StatusDown, "response validation failed: %v", err)
+	}
+
+	if err := extractVariables(step.Extract, resp.Header, respBody.data, variables); err != nil {
+		return fail(types.StatusDown, "%v", err)
+	}
+
+	stepResult.ResponseTime = time.Since(start)
+	if step.Expected.ResponseTimeMax > 0 && stepResult.ResponseTime > step.Expected.ResponseTimeMax {
+		stepResult.Status = types.StatusSlow
+		stepResult.Error = fmt.Sprintf("step time %v exceeds maximum %v", stepResult.ResponseTime, step.Expected.ResponseTimeMax)
+		return stepResult
+	}
+
+	stepResult.Status = types.StatusUp
+	return stepResult
+}
+
+// extractVariables stores values taken from the response into the variables map
+func extractVariables(extractions []types.Extraction, header http.Header, body []byte, variables map[string]string) error {
+	var document interface{}
+	decoded := false
+
+	for _, extraction := range extractions {
+		switch {
+		case extraction.Header != "":
+			value := header.Get(extraction.Header)
+			if value == "" {
+				return fmt.Errorf("cannot extract '%s': header '%s' not present", extraction.Name, extraction.Header)
+			}
+			variables[extraction.Name] = value
+
+		case extraction.JSON != "":
+			if !decoded {
+				var err error
+				document, err = jsonpath.Decode(body)
+				if err != nil {
+					return fmt.Errorf("cannot extract '%s': %v", extraction.Name, err)
+				}
+				decoded = true
+			}
+			value, err := jsonpath.Lookup(document, extraction.JSON)
+			if err != nil {
+				return fmt.Errorf("cannot extract '%s': %v", extraction.Name, err)
+			}
+			variables[extraction.Name] = jsonpath.String(value)
+
+		default:
+			return fmt.Errorf("extraction '%s' has no json or header source", extraction.Name)
+		}
+	}
+
+	return nil
+}
+
+// substituteVariables replaces {{name}} placeholders with extracted values.
+// Unknown placeholders are left untouched so the failure is visible in the request.
+func substituteVariables(input string, variables map[string]string) string {
+	if !strings.Contains(input, "{{") {
+		return input
+	}
+	return variablePattern.ReplaceAllStringFunc(input, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return match
+	})
+}
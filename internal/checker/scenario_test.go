@@ -0,0 +1,163 @@
+package checker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScenarioServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("X-Request-Id", "req-42")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"token": "secret-token", "items": []int{7, 8}},
+		})
+	})
+	mux.HandleFunc("/objects/8", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" || r.Header.Get("X-Trace") != "req-42" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"id":8}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScenarioChecker_Success(t *testing.T) {
+	server := newScenarioServer(t)
+	checker := NewScenarioChecker(NewHTTPChecker(5 * time.Second))
+
+	result := checker.Check(types.CheckConfig{
+		Name:    "Object lifecycle",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Steps: []types.ScenarioStep{
+			{
+				Name:   "login",
+				Method: "POST",
+				URL:    "/login",
+				Body:   `{"user":"monitor"}`,
+				Extract: []types.Extraction{
+					{Name: "token", JSON: "data.token"},
+					{Name: "object_id", JSON: "data.items[1]"},
+					{Name: "request_id", Header: "X-Request-Id"},
+				},
+			},
+			{
+				Name:     "fetch object",
+				URL:      "/objects/{{object_id}}",
+				Headers:  map[string]string{"Authorization": "Bearer {{token}}", "X-Trace": "{{request_id}}"},
+				Expected: types.Expected{Status: 200, BodyContains: `"id":8`},
+			},
+			{
+				Name:     "delete object",
+				Method:   "DELETE",
+				URL:      server.URL + "/objects/{{ object_id }}",
+				Headers:  map[string]string{"Authorization": "Bearer {{token}}", "X-Trace": "{{request_id}}"},
+				Expected: types.Expected{Status: 204},
+			},
+		},
+	})
+
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Empty(t, result.FailedStep)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, "delete object", result.Steps[2].Name)
+	assert.Equal(t, 204, result.Steps[2].StatusCode)
+	for _, step := range result.Steps {
+		assert.Equal(t, types.StatusUp, step.Status)
+		assert.Greater(t, step.ResponseTime, time.Duration(0))
+	}
+}
+
+func TestScenarioChecker_StopsAtFailedStep(t *testing.T) {
+	server := newScenarioServer(t)
+	checker := NewScenarioChecker(NewHTTPChecker(5 * time.Second))
+
+	result := checker.Check(types.CheckConfig{
+		Name:    "Broken flow",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Steps: []types.ScenarioStep{
+			{Name: "login", Method: "POST", URL: "/login"},
+			{Name: "unauthenticated fetch", URL: "/objects/8"},
+			{Name: "never runs", URL: "/objects/8"},
+		},
+	})
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "unauthenticated fetch", result.FailedStep)
+	assert.Contains(t, result.Error, "unexpected status 401")
+	require.Len(t, result.Steps, 2)
+	assert.Equal(t, types.StatusDown, result.Steps[1].Status)
+}
+
+func TestScenarioChecker_SlowStepContinues(t *testing.T) {
+	server := newScenarioServer(t)
+	checker := NewScenarioChecker(NewHTTPChecker(5 * time.Second))
+
+	result := checker.Check(types.CheckConfig{
+		Name:    "Slow login",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Steps: []types.ScenarioStep{
+			{
+				Name:     "login",
+				Method:   "POST",
+				URL:      "/login",
+				Extract:  []types.Extraction{{Name: "token", JSON: "data.token"}, {Name: "request_id", Header: "X-Request-Id"}},
+				Expected: types.Expected{ResponseTimeMax: time.Nanosecond},
+			},
+			{
+				Name:     "delete object",
+				Method:   "DELETE",
+				URL:      "/objects/8",
+				Headers:  map[string]string{"Authorization": "Bearer {{token}}", "X-Trace": "{{request_id}}"},
+				Expected: types.Expected{Status: 204},
+			},
+		},
+	})
+
+	assert.Equal(t, types.StatusSlow, result.Status)
+	assert.Empty(t, result.FailedStep)
+	assert.Contains(t, result.Error, "Step 'login' was slow: step time")
+	require.Len(t, result.Steps, 2)
+	assert.Equal(t, types.StatusSlow, result.Steps[0].Status)
+	assert.Equal(t, types.StatusUp, result.Steps[1].Status, result.Steps[1].Error)
+}
+
+func TestScenarioChecker_MissingExtraction(t *testing.T) {
+	server := newScenarioServer(t)
+	checker := NewScenarioChecker(NewHTTPChecker(5 * time.Second))
+
+	result := checker.Check(types.CheckConfig{
+		Name:    "Bad extraction",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Steps: []types.ScenarioStep{
+			{Name: "login", Method: "POST", URL: "/login", Extract: []types.Extraction{{Name: "token", JSON: "data.missing"}}},
+		},
+	})
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "login", result.FailedStep)
+	assert.Contains(t, result.Error, "key 'missing' not found")
+}
@@ -0,0 +1,119 @@
+package checker
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// scramClient implements the client side of SCRAM (RFC 5802) for a given
+// hash, e.g. SCRAM-SHA-256 (RFC 7677) or SCRAM-SHA-512
+type scramClient struct {
+	hash            func() hash.Hash
+	password        string
+	nonce           string
+	clientFirstBare string
+	authMessage     string
+	saltedPassword  []byte
+}
+
+// newSCRAMClient starts an exchange. PostgreSQL takes the user name from the
+// startup message, so it passes an empty user.
+func newSCRAMClient(newHash func() hash.Hash, user, password string) (*scramClient, error) {
+	raw := make([]byte, 18)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	nonce := base64.StdEncoding.EncodeToString(raw)
+	user = strings.NewReplacer("=", "=3D", ",", "=2C").Replace(user)
+	return &scramClient{
+		hash:            newHash,
+		password:        password,
+		nonce:           nonce,
+		clientFirstBare: "n=" + user + ",r=" + nonce,
+	}, nil
+}
+
+// first returns the client-first-message
+func (s *scramClient) first() []byte {
+	return []byte("n,," + s.clientFirstBare)
+}
+
+// final answers the server-first-message with the client proof
+func (s *scramClient) final(serverFirst []byte) ([]byte, error) {
+	attrs := scramAttributes(string(serverFirst))
+	if message, ok := attrs["e"]; ok {
+		return nil, fmt.Errorf("SCRAM error: %s", message)
+	}
+	nonce, salt64, iterations := attrs["r"], attrs["s"], attrs["i"]
+
+	if !strings.HasPrefix(nonce, s.nonce) || len(nonce) == len(s.nonce) {
+		return nil, fmt.Errorf("server nonce does not extend the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCRAM salt: %w", err)
+	}
+	var iter int
+	if _, err := fmt.Sscanf(iterations, "%d", &iter); err != nil || iter < 1 {
+		return nil, fmt.Errorf("invalid SCRAM iteration count '%s'", iterations)
+	}
+
+	s.saltedPassword, err = pbkdf2.Key(s.hash, s.password, salt, iter, s.hash().Size())
+	if err != nil {
+		return nil, err
+	}
+
+	withoutProof := "c=biws,r=" + nonce
+	s.authMessage = s.clientFirstBare + "," + string(serverFirst) + "," + withoutProof
+
+	clientKey := scramHMAC(s.hash, s.saltedPassword, "Client Key")
+	storedKey := s.hash()
+	storedKey.Write(clientKey)
+	signature := scramHMAC(s.hash, storedKey.Sum(nil), s.authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ signature[i]
+	}
+
+	return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof)), nil
+}
+
+// verify checks the server signature in the server-final-message
+func (s *scramClient) verify(serverFinal []byte) error {
+	attrs := scramAttributes(string(serverFinal))
+	if message, ok := attrs["e"]; ok {
+		return fmt.Errorf("SCRAM error: %s", message)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil {
+		return fmt.Errorf("invalid SCRAM server signature: %w", err)
+	}
+	serverKey := scramHMAC(s.hash, s.saltedPassword, "Server Key")
+	if !hmac.Equal(signature, scramHMAC(s.hash, serverKey, s.authMessage)) {
+		return fmt.Errorf("server signature mismatch")
+	}
+	return nil
+}
+
+func scramHMAC(newHash func() hash.Hash, key []byte, message string) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// scramAttributes splits "a=1,b=2" into its attributes
+func scramAttributes(message string) map[string]string {
+	attrs := make(map[string]string)
+	for _, part := range strings.Split(message, ",") {
+		if len(part) >= 2 && part[1] == '=' {
+			attrs[part[:1]] = part[2:]
+		}
+	}
+	return attrs
+}
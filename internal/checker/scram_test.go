@@ -0,0 +1,109 @@
+package checker
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSCRAMServer runs the server side of one SCRAM exchange
+type fakeSCRAMServer struct {
+	hash     func() hash.Hash
+	user     string
+	password string
+
+	clientFirstBare string
+	serverFirst     string
+	salted          []byte
+}
+
+// first answers the client-first-message
+func (f *fakeSCRAMServer) first(clientFirst string) string {
+	f.clientFirstBare = strings.TrimPrefix(clientFirst, "n,,")
+	nonce := scramAttributes(f.clientFirstBare)["r"] + "server"
+
+	salt := []byte("fake-salt")
+	f.salted, _ = pbkdf2.Key(f.hash, f.password, salt, 4096, f.hash().Size())
+	f.serverFirst = "r=" + nonce + ",s=" + base64.StdEncoding.EncodeToString(salt) + ",i=4096"
+	return f.serverFirst
+}
+
+// final checks the client proof and returns the server-final-message, or
+// false when the user or password is wrong
+func (f *fakeSCRAMServer) final(clientFinal string) (string, bool) {
+	if f.user != "" && scramAttributes(f.clientFirstBare)["n"] != f.user {
+		return "", false
+	}
+	withoutProof := clientFinal[:strings.LastIndex(clientFinal, ",p=")]
+	proof, _ := base64.StdEncoding.DecodeString(scramAttributes(clientFinal)["p"])
+
+	storedKey := f.hash()
+	storedKey.Write(scramHMAC(f.hash, f.salted, "Client Key"))
+	authMessage := f.clientFirstBare + "," + f.serverFirst + "," + withoutProof
+	signature := scramHMAC(f.hash, storedKey.Sum(nil), authMessage)
+	if len(proof) != len(signature) {
+		return "", false
+	}
+	recovered := f.hash()
+	recovered.Write(xorBytes(proof, signature))
+	if !hmac.Equal(recovered.Sum(nil), storedKey.Sum(nil)) {
+		return "", false
+	}
+
+	serverSignature := scramHMAC(f.hash, scramHMAC(f.hash, f.salted, "Server Key"), authMessage)
+	return "v=" + base64.StdEncoding.EncodeToString(serverSignature), true
+}
+
+func TestSCRAMClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     func() hash.Hash
+		user     string
+		password string
+		wantOK   bool
+	}{
+		{name: "sha-256", hash: sha256.New, user: "monitor", password: "secret", wantOK: true},
+		{name: "sha-512 with escaped user", hash: sha512.New, user: "a=b,c", password: "secret", wantOK: true},
+		{name: "wrong password", hash: sha256.New, user: "monitor", password: "wrong", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeSCRAMServer{hash: tt.hash, password: "secret"}
+			if tt.user == "a=b,c" {
+				server.user = "a=3Db=2Cc"
+			}
+
+			client, err := newSCRAMClient(tt.hash, tt.user, tt.password)
+			require.NoError(t, err)
+
+			serverFirst := server.first(string(client.first()))
+			clientFinal, err := client.final([]byte(serverFirst))
+			require.NoError(t, err)
+
+			serverFinal, ok := server.final(string(clientFinal))
+			assert.Equal(t, tt.wantOK, ok)
+			if ok {
+				assert.NoError(t, client.verify([]byte(serverFinal)))
+			}
+		})
+	}
+
+	t.Run("forged server signature", func(t *testing.T) {
+		client, err := newSCRAMClient(sha256.New, "", "secret")
+		require.NoError(t, err)
+		server := &fakeSCRAMServer{hash: sha256.New, password: "secret"}
+		_, err = client.final([]byte(server.first(string(client.first()))))
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, client.verify([]byte("v="+base64.StdEncoding.EncodeToString(make([]byte, 32)))), "signature mismatch")
+	})
+}
@@ -0,0 +1,166 @@
+package checker
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// smtpHelloName is the name sent in EHLO and HELO
+const smtpHelloName = "localhost"
+
+var smtpProtocol = mailProtocol{
+	endpointFormat: endpointFormat{
+		name:        "SMTP",
+		schemes:     []string{"smtp", "smtps"},
+		defaultPort: "25",
+		schemePorts: map[string]string{"smtps": "465"},
+	},
+	implicitTLS: "smtps",
+	session:     smtpSession,
+}
+
+// smtpSession reads the banner, sends EHLO, upgrades with STARTTLS and
+// authenticates with AUTH PLAIN or LOGIN when the URL has credentials
+func smtpSession(ctx context.Context, m *mailConn, target *endpoint) (*types.MailInfo, error) {
+	code, lines, err := m.smtpReply()
+	if err != nil {
+		return nil, fmt.Errorf("reading greeting failed: %w", err)
+	}
+	info := &types.MailInfo{Banner: strings.Join(lines, "\n")}
+	if code != 220 {
+		return info, fmt.Errorf("server refused the session: %d %s", code, info.Banner)
+	}
+
+	if info.Extensions, err = m.smtpHello(); err != nil {
+		return info, err
+	}
+
+	starttls, err := m.wantStartTLS(target, hasExtension(info.Extensions, "STARTTLS"))
+	if err != nil {
+		return info, err
+	}
+	if starttls {
+		if err := m.smtpExpect("STARTTLS", 220); err != nil {
+			return info, err
+		}
+		if err := m.startTLS(ctx); err != nil {
+			return info, err
+		}
+		info.StartTLS = true
+		if info.Extensions, err = m.smtpHello(); err != nil {
+			return info, err
+		}
+	}
+
+	if target.user != "" {
+		if err := m.smtpAuth(target, info.Extensions); err != nil {
+			return info, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	m.smtpCommand("QUIT")
+	return info, nil
+}
+
+// smtpHello sends EHLO, falling back to HELO for servers without ESMTP, and
+// returns the advertised extensions
+func (m *mailConn) smtpHello() ([]string, error) {
+	code, lines, err := m.smtpCommand("EHLO " + smtpHelloName)
+	if err != nil {
+		return nil, fmt.Errorf("EHLO failed: %w", err)
+	}
+	if code == 250 {
+		return lines[1:], nil
+	}
+	if err := m.smtpExpect("HELO "+smtpHelloName, 250); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// smtpAuth logs in with the first supported mechanism the server offers
+func (m *mailConn) smtpAuth(target *endpoint, extensions []string) error {
+	var mechanisms []string
+	for _, extension := range extensions {
+		if fields := strings.Fields(extension); len(fields) > 0 && strings.EqualFold(fields[0], "AUTH") {
+			mechanisms = fields[1:]
+		}
+	}
+	if len(mechanisms) == 0 {
+		return fmt.Errorf("server does not offer AUTH")
+	}
+	if err := m.credentialsAllowed(target.host); err != nil {
+		return err
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	switch {
+	case hasExtension(mechanisms, "PLAIN"):
+		return m.smtpExpect("AUTH PLAIN "+encode([]byte("\x00"+target.user+"\x00"+target.password)), 235)
+	case hasExtension(mechanisms, "LOGIN"):
+		if err := m.smtpExpect("AUTH LOGIN", 334); err != nil {
+			return err
+		}
+		if err := m.smtpExpect(encode([]byte(target.user)), 334); err != nil {
+			return err
+		}
+		return m.smtpExpect(encode([]byte(target.password)), 235)
+	default:
+		return fmt.Errorf("server offers no supported AUTH mechanism (offers %s)", strings.Join(mechanisms, " "))
+	}
+}
+
+// smtpExpect sends a command and fails unless the reply has the wanted code.
+// The command is left out of errors since it may carry credentials.
+func (m *mailConn) smtpExpect(command string, want int) error {
+	code, lines, err := m.smtpCommand(command)
+	if err != nil {
+		return err
+	}
+	if code != want {
+		return fmt.Errorf("%d %s", code, strings.Join(lines, " "))
+	}
+	return nil
+}
+
+// smtpCommand sends a command and reads its reply
+func (m *mailConn) smtpCommand(command string) (int, []string, error) {
+	if err := m.writeLine(command); err != nil {
+		return 0, nil, err
+	}
+	return m.smtpReply()
+}
+
+// smtpReply reads a possibly multi-line reply, returning its code and the
+// text of each line
+func (m *mailConn) smtpReply() (int, []string, error) {
+	var code int
+	var lines []string
+	for {
+		line, err := m.readLine()
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(line) < 3 {
+			return 0, nil, fmt.Errorf("malformed reply %q", line)
+		}
+		lineCode, err := strconv.Atoi(line[:3])
+		if err != nil || (code != 0 && lineCode != code) {
+			return 0, nil, fmt.Errorf("malformed reply %q", line)
+		}
+		code = lineCode
+		if len(line) == 3 {
+			lines = append(lines, "")
+			return code, lines, nil
+		}
+		lines = append(lines, line[4:])
+		if line[3] != '-' {
+			return code, lines, nil
+		}
+	}
+}
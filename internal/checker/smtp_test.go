@@ -0,0 +1,233 @@
+package checker
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a minimal mail relay
+type fakeSMTP struct {
+	tls      *tls.Config // offers STARTTLS when set
+	auth     string      // advertised AUTH mechanisms
+	noESMTP  bool        // rejects EHLO
+	user     string
+	password string
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	send := func(lines ...string) {
+		for _, line := range lines {
+			fmt.Fprintf(conn, "%s\r\n", line)
+		}
+	}
+	readLine := func() string {
+		line, _ := reader.ReadString('\n')
+		return strings.TrimRight(line, "\r\n")
+	}
+	decode := func(s string) string {
+		data, _ := base64.StdEncoding.DecodeString(s)
+		return string(data)
+	}
+
+	send("220-mail.test ESMTP FakeMail", "220 No UCE")
+	encrypted := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			if f.noESMTP {
+				send("502 5.5.2 Command not recognized")
+				continue
+			}
+			extensions := []string{"mail.test greets " + arg, "SIZE 10240000", "8BITMIME"}
+			if f.tls != nil && !encrypted {
+				extensions = append(extensions, "STARTTLS")
+			}
+			if f.auth != "" {
+				extensions = append(extensions, "AUTH "+f.auth)
+			}
+			for i, extension := range extensions {
+				separator := "-"
+				if i == len(extensions)-1 {
+					separator = " "
+				}
+				send("250" + separator + extension)
+			}
+		case "HELO":
+			send("250 mail.test")
+		case "STARTTLS":
+			send("220 2.0.0 Ready to start TLS")
+			tlsConn := tls.Server(conn, f.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, reader, encrypted = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			var credentials string
+			switch mechanism {
+			case "PLAIN":
+				credentials = decode(initial)
+			case "LOGIN":
+				send("334 VXNlcm5hbWU6")
+				user := decode(readLine())
+				send("334 UGFzc3dvcmQ6")
+				credentials = "\x00" + user + "\x00" + decode(readLine())
+			}
+			if credentials == "\x00"+f.user+"\x00"+f.password {
+				send("235 2.7.0 Authentication successful")
+			} else {
+				send("535 5.7.8 Authentication credentials invalid")
+			}
+		case "QUIT":
+			send("221 2.0.0 Bye")
+			return
+		default:
+			send("500 5.5.1 Unrecognized command")
+		}
+	}
+}
+
+func TestSMTPChecker(t *testing.T) {
+	tests := []struct {
+		name       string
+		server     fakeSMTP
+		url        string
+		expected   types.Expected
+		wantStatus types.Status
+		wantError  string
+	}{
+		{
+			name:       "banner and extensions",
+			url:        "smtp://%s",
+			expected:   types.Expected{BannerContains: "FakeMail", Extensions: []string{"SIZE", "8bitmime"}},
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "banner mismatch",
+			url:        "smtp://%s",
+			expected:   types.Expected{BannerContains: "Postfix"},
+			wantStatus: types.StatusDown,
+			wantError:  "banner does not contain 'Postfix'",
+		},
+		{
+			name:       "missing extensions",
+			url:        "smtp://%s",
+			expected:   types.Expected{Extensions: []string{"PIPELINING", "SIZE", "DSN"}},
+			wantStatus: types.StatusDown,
+			wantError:  "server does not advertise PIPELINING, DSN",
+		},
+		{
+			name:       "HELO fallback",
+			server:     fakeSMTP{noESMTP: true},
+			url:        "smtp://%s",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "STARTTLS required but not offered",
+			url:        "smtp://%s?starttls=true",
+			wantStatus: types.StatusDown,
+			wantError:  "server does not offer STARTTLS",
+		},
+		{
+			name:       "AUTH PLAIN",
+			server:     fakeSMTP{auth: "PLAIN LOGIN"},
+			url:        "smtp://relay:secret@%s",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "AUTH LOGIN",
+			server:     fakeSMTP{auth: "LOGIN"},
+			url:        "smtp://relay:secret@%s",
+			wantStatus: types.StatusUp,
+		},
+		{
+			name:       "wrong password",
+			server:     fakeSMTP{auth: "PLAIN"},
+			url:        "smtp://relay:wrong@%s",
+			wantStatus: types.StatusDown,
+			wantError:  "authentication failed: 535 5.7.8",
+		},
+		{
+			name:       "AUTH not offered",
+			url:        "smtp://relay:secret@%s",
+			wantStatus: types.StatusDown,
+			wantError:  "server does not offer AUTH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server
+			server.user, server.password = "relay", "secret"
+			addr := startFakeBroker(t, server.serve)
+
+			result := NewSMTPChecker(5 * time.Second).Check(types.CheckConfig{
+				Name:     "relay",
+				Type:     types.CheckTypeSMTP,
+				URL:      fmt.Sprintf(tt.url, addr),
+				Timeout:  5 * time.Second,
+				Expected: tt.expected,
+			})
+
+			assert.Equal(t, tt.wantStatus, result.Status, result.Error)
+			assert.NotContains(t, result.URL, "secret")
+			if tt.wantError != "" {
+				assert.Contains(t, result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestSMTPChecker_STARTTLS(t *testing.T) {
+	serverTLS, caPath := mailTLS(t)
+	addr := startFakeBroker(t, (&fakeSMTP{tls: serverTLS, auth: "PLAIN", user: "relay", password: "secret"}).serve)
+
+	result := NewSMTPChecker(5 * time.Second).Check(types.CheckConfig{
+		Name:     "relay",
+		Type:     types.CheckTypeSMTP,
+		URL:      "smtp://relay:secret@" + addr + "?starttls=true",
+		Timeout:  5 * time.Second,
+		TLS:      types.TLSConfig{CAFile: caPath},
+		Expected: types.Expected{Extensions: []string{"AUTH PLAIN"}, CertExpiryDays: 30},
+	})
+
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Mail)
+	assert.True(t, result.Mail.StartTLS)
+	assert.False(t, hasExtension(result.Mail.Extensions, "STARTTLS"), "extensions are re-read after STARTTLS")
+	require.NotNil(t, result.CertInfo)
+	assert.Equal(t, "mail.test", result.CertInfo.CommonName)
+	assert.NotEmpty(t, result.CertInfo.SPKIHash)
+	assert.Len(t, result.CertInfo.Chain, 1)
+}
+
+func TestSMTPChecker_UntrustedCertificate(t *testing.T) {
+	serverTLS, _ := mailTLS(t)
+	addr := startFakeBroker(t, (&fakeSMTP{tls: serverTLS}).serve)
+
+	result := NewSMTPChecker(5 * time.Second).Check(types.CheckConfig{
+		Name:    "relay",
+		Type:    types.CheckTypeSMTP,
+		URL:     "smtp://" + addr,
+		Timeout: 5 * time.Second,
+	})
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "TLS handshake failed")
+}
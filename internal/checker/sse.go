@@ -0,0 +1,132 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+var sseProtocol = streamProtocol{
+	endpointFormat: endpointFormat{
+		name:        "SSE",
+		schemes:     []string{"http", "https"},
+		defaultPort: "80",
+		schemePorts: map[string]string{"https": "443"},
+	},
+	tlsScheme:  "https",
+	httpURL:    func(raw string) string { return raw },
+	unit:       "event",
+	waitAlways: true,
+	handshake:  sseHandshake,
+	open:       openSSE,
+}
+
+// sseHandshake asks for an event stream
+func sseHandshake(req *http.Request) (func(*http.Response) error, error) {
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	return func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if mediaType != "text/event-stream" {
+			return fmt.Errorf("expected content type text/event-stream, got '%s'", resp.Header.Get("Content-Type"))
+		}
+		return nil
+	}, nil
+}
+
+// sseStream parses events from the response body
+type sseStream struct {
+	reader     *bufio.Reader
+	maxMessage int64
+	skipLF     bool // the last line ended with CR, so a following LF is part of it
+}
+
+func openSSE(conn net.Conn, reader *bufio.Reader, resp *http.Response, check types.CheckConfig) (streamSource, error) {
+	return &sseStream{
+		reader:     bufio.NewReader(resp.Body),
+		maxMessage: maxStreamMessage(check),
+	}, nil
+}
+
+// next returns the next dispatched event, following the parsing rules of the
+// HTML event stream format: events end at a blank line, data lines are joined
+// with newlines and events without data are dropped
+func (s *sseStream) next() (streamMessage, error) {
+	var event string
+	var data []string
+	size := int64(0)
+	for {
+		line, err := s.readLine(s.maxMessage - size)
+		if err != nil {
+			return streamMessage{}, err
+		}
+
+		if line == "" {
+			if data != nil {
+				if event == "" {
+					event = "message"
+				}
+				return streamMessage{event: event, data: strings.Join(data, "\n")}, nil
+			}
+			event = ""
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment, often used as a keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+			size += int64(len(value)) + 1
+		}
+	}
+}
+
+// readLine reads one line of at most limit bytes, accepting CRLF, LF or CR endings
+func (s *sseStream) readLine(limit int64) (string, error) {
+	var line []byte
+	for {
+		b, err := s.reader.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				return "", fmt.Errorf("server closed the stream")
+			}
+			return "", err
+		}
+		skipLF := s.skipLF
+		s.skipLF = false
+		switch b {
+		case '\n':
+			if skipLF {
+				continue
+			}
+			return string(line), nil
+		case '\r':
+			s.skipLF = true
+			return string(line), nil
+		}
+		if int64(len(line)) >= limit {
+			return "", fmt.Errorf("event exceeds %d bytes", s.maxMessage)
+		}
+		line = append(line, b)
+	}
+}
+
+// close leaves the body alone: closing it would drain the endless stream,
+// and the checker closes the connection underneath it anyway
+func (s *sseStream) close() {}
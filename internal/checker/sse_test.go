@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeSSE streams the given chunks as an event stream, then holds the connection open
+func startFakeSSE(t *testing.T, contentType string, chunks ...string) string {
+	t.Helper()
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "event stream only", http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		for _, chunk := range chunks {
+			fmt.Fprint(w, chunk)
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(done)
+		server.Close()
+	})
+
+	return server.URL
+}
+
+func sseCheck(url string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "events",
+		Type:    types.CheckTypeSSE,
+		URL:     url,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestSSEChecker_EventArrives(t *testing.T) {
+	url := startFakeSSE(t, "text/event-stream; charset=utf-8",
+		": keep-alive\n\n",
+		"event: update\ndata: {\"price\": 1}\n\n",
+		"event: heartbeat\ndata: line one\ndata: line two\nid: 7\n\n",
+	)
+
+	check := sseCheck(url + "/events")
+	check.Stream.Event = "heartbeat"
+
+	result := NewSSEChecker(5 * time.Second).Check(check)
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Stream)
+	assert.Equal(t, "heartbeat", result.Stream.Event)
+	assert.Equal(t, "line one\nline two", result.Stream.Message)
+	assert.Equal(t, 2, result.Stream.Messages)
+	assert.Positive(t, result.Stream.Handshake)
+}
+
+func TestSSEChecker_AnyEventIsEnough(t *testing.T) {
+	url := startFakeSSE(t, "text/event-stream", "data: hello\n\n")
+
+	result := NewSSEChecker(5 * time.Second).Check(sseCheck(url))
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, "message", result.Stream.Event)
+	assert.Equal(t, "hello", result.Stream.Message)
+}
+
+func TestSSEChecker_NoEventInTime(t *testing.T) {
+	url := startFakeSSE(t, "text/event-stream", ": keep-alive\n\n")
+
+	check := sseCheck(url)
+	check.Expected.MessageWithin = 200 * time.Millisecond
+
+	result := NewSSEChecker(5 * time.Second).Check(check)
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "No matching event within 200ms (0 received)", result.Error)
+}
+
+func TestSSEChecker_WrongContentType(t *testing.T) {
+	url := startFakeSSE(t, "application/json", `{"events": []}`)
+
+	result := NewSSEChecker(5 * time.Second).Check(sseCheck(url))
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "SSE handshake failed: expected content type text/event-stream, got 'application/json'", result.Error)
+}
+
+func TestSSEChecker_StreamEnds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: ping\n\n")
+	}))
+	defer server.Close()
+
+	result := NewSSEChecker(5 * time.Second).Check(sseCheck(server.URL))
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "SSE stream failed after 0 events: server closed the stream", result.Error)
+}
+
+func TestSSEStream_LineEndings(t *testing.T) {
+	stream := &sseStream{
+		reader:     bufio.NewReader(strings.NewReader("data: a\r\rdata: b\r\ndata:c\r\n\r\nevent: x\ndata\n\n")),
+		maxMessage: 1024,
+	}
+
+	first, err := stream.next()
+	require.NoError(t, err)
+	assert.Equal(t, streamMessage{event: "message", data: "a"}, first)
+
+	second, err := stream.next()
+	require.NoError(t, err)
+	assert.Equal(t, streamMessage{event: "message", data: "b\nc"}, second)
+
+	third, err := stream.next()
+	require.NoError(t, err)
+	assert.Equal(t, streamMessage{event: "x", data: ""}, third)
+
+	stream = &sseStream{reader: bufio.NewReader(strings.NewReader("data: " + strings.Repeat("x", 64) + "\n\n")), maxMessage: 32}
+	_, err = stream.next()
+	assert.ErrorContains(t, err, "event exceeds 32 bytes")
+}
@@ -1,26 +1,37 @@
 package checker
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/ocsp"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 )
 
 // SSLChecker implements SSL certificate checks
 type SSLChecker struct {
-	timeout time.Duration
+	timeout    time.Duration
+	tls        *tlsCache
+	dialers    *dialerCache
+	revocation *revocationChecker
 }
 
 // NewSSLChecker creates a new SSL checker
 func NewSSLChecker(timeout time.Duration) *SSLChecker {
 	return &SSLChecker{
-		timeout: timeout,
+		timeout:    timeout,
+		tls:        newTLSCache(),
+		dialers:    newDialerCache(),
+		revocation: newRevocationChecker(),
 	}
 }
 
@@ -39,8 +50,17 @@ func (s *SSLChecker) Check(check types.CheckConfig) types.Result {
 		Timestamp: start,
 	}
 	
+	// Resolve names and connect the way the check's network settings require
+	dialer, err := s.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
+	
 	// Validate target for security (supports both URL and host:port formats)
-	if err := security.ValidateSSLTarget(check.URL); err != nil {
+	if err := security.ValidateSSLTargetWithLookup(check.URL, dialer.LookupIP); err != nil {
 		result.Status = types.StatusError
 		result.Error = fmt.Sprintf("Target validation failed: %v", err)
 		result.ResponseTime = time.Since(start)
@@ -56,8 +76,17 @@ func (s *SSLChecker) Check(check types.CheckConfig) types.Result {
 		return result
 	}
 	
+	// Build TLS settings for this check
+	tlsConfig, err := s.tls.forHost(check, host)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
+	
 	// Connect to the server and get certificate info
-	certInfo, err := s.getCertificateInfo(host, port)
+	certInfo, pinErr, verifyErr, err := s.getCertificateInfo(dialer, host, port, tlsConfig, check)
 	duration := time.Since(start)
 	result.ResponseTime = duration
 	
@@ -69,13 +98,62 @@ func (s *SSLChecker) Check(check types.CheckConfig) types.Result {
 	
 	result.CertInfo = certInfo
 	
+	// An untrusted or expired chain is a hard failure
+	if verifyErr != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Certificate verification failed: %v", verifyErr)
+		return result
+	}
+	
+	// A certificate that does not match the pins may be an interception proxy
+	if pinErr != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Certificate pinning failed: %v", pinErr)
+		return result
+	}
+	
+	// Revoked certificates are a hard failure, unknown status only with hard_fail
+	if revocation := certInfo.Revocation; revocation != nil {
+		if revocation.Status == ocsp.Revoked {
+			result.Status = types.StatusDown
+			result.Error = fmt.Sprintf("Certificate revocation check failed: %s", revocationDescription(revocation))
+			return result
+		}
+		if revocation.Status == ocsp.Unknown && check.Revocation.HardFail {
+			result.Status = types.StatusDown
+			result.Error = fmt.Sprintf("Certificate revocation status unknown: %s", revocation.Error)
+			return result
+		}
+	}
+	
+	// Audit findings configured as DOWN
+	if err := findingsError(certInfo.Findings, types.FindingSeverityDown); err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("Certificate audit failed: %v", err)
+		return result
+	}
+	
 	// Validate certificate
-	if err := s.validateCertificate(certInfo, check.Expected); err != nil {
+	if err := validateCertificate(certInfo, check.Expected); err != nil {
 		result.Status = types.StatusWarning
 		result.Error = fmt.Sprintf("Certificate validation failed: %v", err)
 		return result
 	}
 	
+	// Audit findings configured as WARNING
+	if err := findingsError(certInfo.Findings, types.FindingSeverityWarning); err != nil {
+		result.Status = types.StatusWarning
+		result.Error = fmt.Sprintf("Certificate audit warning: %v", err)
+		return result
+	}
+	
+	// Soft-fail revocation lookups are reported as warnings
+	if revocation := certInfo.Revocation; revocation != nil && revocation.Status == ocsp.Unknown {
+		result.Status = types.StatusWarning
+		result.Error = fmt.Sprintf("Certificate revocation status unknown: %s", revocation.Error)
+		return result
+	}
+	
 	// Check response time performance
 	if check.Expected.ResponseTimeMax > 0 && duration > check.Expected.ResponseTimeMax {
 		result.Status = types.StatusSlow
@@ -124,53 +202,113 @@ func (s *SSLChecker) parseHostPort(rawURL string) (string, string, error) {
 	return host, port, nil
 }
 
-// getCertificateInfo connects to the server and retrieves certificate information
-func (s *SSLChecker) getCertificateInfo(host, port string) (*types.CertInfo, error) {
+// getCertificateInfo connects to the server and audits the presented certificate chain.
+// Chain verification is done after the handshake so an incomplete chain can still be
+// inspected; verifyErr is set when the chain is untrusted for any other reason and
+// pinErr when the chain does not match the configured pins.
+func (s *SSLChecker) getCertificateInfo(dialer *netdial.Dialer, host, port string, tlsConfig *tls.Config, check types.CheckConfig) (certInfo *types.CertInfo, pinErr, verifyErr, err error) {
 	// Create connection with timeout
-	dialer := &net.Dialer{
-		Timeout: s.timeout,
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
 	
-	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, port), &tls.Config{
-		ServerName:         host,
-		InsecureSkipVerify: false, // Always verify certificates
-	})
+	verify := !tlsConfig.InsecureSkipVerify
+	dialConfig := tlsConfig.Clone()
+	dialConfig.InsecureSkipVerify = true // verified below, no data is exchanged over this connection
+	
+	rawConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
 	if err != nil {
-		return nil, fmt.Errorf("TLS connection failed: %w", err)
+		return nil, nil, nil, fmt.Errorf("TLS connection failed: %w", err)
 	}
+	conn := tls.Client(rawConn, dialConfig)
 	defer conn.Close()
 	
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, nil, nil, fmt.Errorf("TLS connection failed: %w", err)
+	}
+	
 	// Get certificate chain
-	certs := conn.ConnectionState().PeerCertificates
+	state := conn.ConnectionState()
+	certs := state.PeerCertificates
 	if len(certs) == 0 {
-		return nil, fmt.Errorf("no certificates found")
+		return nil, nil, nil, fmt.Errorf("no certificates found")
 	}
 	
 	// Use the first certificate (leaf certificate)
 	cert := certs[0]
-	
-	// Calculate days to expiry
 	now := time.Now()
-	daysToExpiry := int(cert.NotAfter.Sub(now).Hours() / 24)
+	certInfo = newCertInfo(state, now)
 	
-	// Check if certificate is currently valid
-	isValid := now.After(cert.NotBefore) && now.Before(cert.NotAfter)
+	audit := &chainAudit{
+		certs:      certs,
+		serverName: tlsConfig.ServerName,
+		expected:   check.Expected,
+		now:        now,
+	}
 	
-	certInfo := &types.CertInfo{
+	// Trust and hostname findings only apply when verification is enabled
+	var verified []*x509.Certificate
+	if verify {
+		var incomplete bool
+		verified, incomplete, verifyErr = verifyChain(certs, tlsConfig.RootCAs, now, s.issuerFetcher(dialer))
+		if incomplete {
+			audit.auditIncomplete()
+		}
+		audit.auditHostname()
+	}
+	audit.auditCertificates()
+	certInfo.Findings = audit.findings
+	
+	// Look up revocation status of the leaf
+	if check.Revocation.Enabled {
+		timeout := check.Revocation.Timeout
+		if timeout <= 0 {
+			timeout = check.Timeout
+		}
+		if timeout <= 0 {
+			timeout = s.timeout
+		}
+		issuer := findIssuer(cert, verified, certs)
+		certInfo.Revocation = s.revocation.check(cert, issuer, state.OCSPResponse, check.Revocation, dialer, timeout)
+	}
+	
+	return certInfo, validatePins(certs, check.Expected), verifyErr, nil
+}
+
+// issuerFetcher downloads missing intermediates through the check's network settings
+func (s *SSLChecker) issuerFetcher(dialer *netdial.Dialer) issuerFetcher {
+	transport := &http.Transport{DisableKeepAlives: true}
+	dialer.ConfigureTransport(transport)
+	client := &http.Client{Timeout: s.timeout, Transport: transport}
+	
+	return func(url string) ([]byte, error) {
+		return fetch(client, dialer.LookupIP, http.MethodGet, url, nil, maxIssuerCertSize)
+	}
+}
+
+// newCertInfo describes the leaf and chain presented on an established TLS
+// connection. It is shared by every checker that speaks TLS.
+func newCertInfo(state tls.ConnectionState, now time.Time) *types.CertInfo {
+	certs := state.PeerCertificates
+	cert := certs[0]
+	
+	return &types.CertInfo{
 		Subject:      cert.Subject.String(),
 		Issuer:       cert.Issuer.String(),
 		ExpiryDate:   cert.NotAfter,
-		DaysToExpiry: daysToExpiry,
-		IsValid:      isValid,
+		DaysToExpiry: int(cert.NotAfter.Sub(now).Hours() / 24),
+		IsValid:      now.After(cert.NotBefore) && now.Before(cert.NotAfter),
 		CommonName:   cert.Subject.CommonName,
 		DNSNames:     cert.DNSNames,
+		Protocol:     tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		Chain:        describeChain(certs, now),
+		Fingerprint:  certFingerprint(cert),
+		SPKIHash:     spkiHash(cert),
 	}
-	
-	return certInfo, nil
 }
 
 // validateCertificate validates the certificate against expected criteria
-func (s *SSLChecker) validateCertificate(certInfo *types.CertInfo, expected types.Expected) error {
+func validateCertificate(certInfo *types.CertInfo, expected types.Expected) error {
 	// Check certificate validity
 	if !certInfo.IsValid {
 		return fmt.Errorf("certificate is not valid (expired or not yet valid)")
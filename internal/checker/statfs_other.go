@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package checker
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// diskUsage is not implemented on this platform
+func diskUsage(path string) (diskStats, error) {
+	return diskStats{}, fmt.Errorf("disk checks are not supported on %s", runtime.GOOS)
+}
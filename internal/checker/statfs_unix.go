@@ -0,0 +1,21 @@
+//go:build linux || darwin
+
+package checker
+
+import "syscall"
+
+// diskUsage reports the space and inodes of the filesystem holding path
+func diskUsage(path string) (diskStats, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return diskStats{}, err
+	}
+
+	blockSize := uint64(fs.Bsize)
+	return diskStats{
+		total:      fs.Blocks * blockSize,
+		available:  fs.Bavail * blockSize,
+		inodes:     fs.Files,
+		inodesFree: fs.Ffree,
+	}, nil
+}
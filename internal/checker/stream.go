@@ -0,0 +1,359 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// defaultMaxStreamMessage caps a single message when the check sets no max_body_size
+const defaultMaxStreamMessage = 1 << 20
+
+// maxStreamPreview caps how much of the matching message is kept in the result
+const maxStreamPreview = 200
+
+// streamMessage is one WebSocket message or SSE event
+type streamMessage struct {
+	event string // SSE event type
+	data  string
+}
+
+// streamSource yields the messages received after the handshake
+type streamSource interface {
+	next() (streamMessage, error)
+	close()
+}
+
+// streamProtocol describes the handshake and framing of one kind of stream
+type streamProtocol struct {
+	endpointFormat
+	tlsScheme string // scheme that connects over TLS
+	httpURL   func(raw string) string
+	unit      string // what a message is called in errors: message or event
+
+	// waitAlways makes the check wait for a message even without a message
+	// to send or an assertion on its content
+	waitAlways bool
+
+	// handshake adds the protocol's headers to the request and returns a
+	// function that accepts or rejects the server's response
+	handshake func(req *http.Request) (accept func(resp *http.Response) error, err error)
+
+	// open starts reading messages once the handshake is accepted, sending
+	// check.Stream.Send first where the protocol supports it
+	open func(conn net.Conn, reader *bufio.Reader, resp *http.Response, check types.CheckConfig) (streamSource, error)
+}
+
+// StreamChecker opens a WebSocket or Server-Sent Events stream and waits for a message
+type StreamChecker struct {
+	timeout  time.Duration
+	protocol streamProtocol
+	tls      *tlsCache
+	dialers  *dialerCache
+}
+
+// NewWebSocketChecker creates a WebSocket checker
+func NewWebSocketChecker(timeout time.Duration) *StreamChecker {
+	return newStreamChecker(timeout, webSocketProtocol)
+}
+
+// NewSSEChecker creates a Server-Sent Events checker
+func NewSSEChecker(timeout time.Duration) *StreamChecker {
+	return newStreamChecker(timeout, sseProtocol)
+}
+
+func newStreamChecker(timeout time.Duration, protocol streamProtocol) *StreamChecker {
+	return &StreamChecker{
+		timeout:  timeout,
+		protocol: protocol,
+		tls:      newTLSCache(),
+		dialers:  newDialerCache(),
+	}
+}
+
+// Name returns the checker name
+func (c *StreamChecker) Name() string {
+	return c.protocol.name
+}
+
+// Check performs the handshake and, when configured, waits for a matching message
+func (c *StreamChecker) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       security.RedactURLCredentials(check.URL),
+		Timestamp: start,
+	}
+
+	target, err := parseEndpoint(check.URL, c.protocol.endpointFormat)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Invalid URL: %v", err)
+		return result
+	}
+
+	dialer, err := c.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		return result
+	}
+
+	// The same SSRF and header injection rules as HTTP checks apply
+	requestURL := c.protocol.httpURL(check.URL)
+	if err := security.ValidateURLWithLookup(requestURL, dialer.LookupIP); err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("URL validation failed: %v", err)
+		return result
+	}
+	if err := security.ValidateHTTPHeaders(check.Headers); err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Header validation failed: %v", err)
+		return result
+	}
+
+	match, err := newMessageMatcher(check)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = err.Error()
+		return result
+	}
+
+	var tlsConfig *tls.Config
+	if target.scheme == c.protocol.tlsScheme {
+		tlsConfig, err = c.tls.forHost(check, target.host)
+		if err != nil {
+			result.Status = types.StatusError
+			result.Error = fmt.Sprintf("TLS configuration error: %v", err)
+			return result
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", target.address())
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s connection failed: %v", c.protocol.name, err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
+	defer func() { conn.Close() }()
+
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	if tlsConfig != nil {
+		conn, err = startTLS(ctx, conn, tlsConfig)
+		if err != nil {
+			result.Status = types.StatusDown
+			result.Error = fmt.Sprintf("%s connection failed: %v", c.protocol.name, err)
+			result.ResponseTime = time.Since(start)
+			return result
+		}
+		result.CertInfo = newCertInfo(conn.(*tls.Conn).ConnectionState(), time.Now())
+	}
+
+	source, err := c.handshake(ctx, conn, requestURL, target, check)
+	handshake := time.Since(start)
+	result.ResponseTime = handshake
+	result.Stream = &types.StreamInfo{Handshake: handshake}
+	if err != nil {
+		result.Status = types.StatusDown
+		result.Error = fmt.Sprintf("%s handshake failed: %v", c.protocol.name, err)
+		return result
+	}
+	defer source.close()
+
+	if c.protocol.waitAlways || match.configured() {
+		within := deadline.Sub(time.Now())
+		if check.Expected.MessageWithin > 0 && check.Expected.MessageWithin < within {
+			within = check.Expected.MessageWithin
+			conn.SetReadDeadline(time.Now().Add(within))
+		}
+
+		if err := c.await(source, match, result.Stream, within); err != nil {
+			result.ResponseTime = time.Since(start)
+			result.Status = types.StatusDown
+			result.Error = err.Error()
+			return result
+		}
+		result.ResponseTime = time.Since(start)
+	}
+
+	if result.CertInfo != nil {
+		if err := validateCertificate(result.CertInfo, check.Expected); err != nil {
+			result.Status = types.StatusWarning
+			result.Error = fmt.Sprintf("Certificate validation failed: %v", err)
+			return result
+		}
+	}
+
+	if check.Expected.ResponseTimeMax > 0 && result.ResponseTime > check.Expected.ResponseTimeMax {
+		result.Status = types.StatusSlow
+		result.Error = fmt.Sprintf("Response time %v exceeds maximum %v", result.ResponseTime, check.Expected.ResponseTimeMax)
+		return result
+	}
+
+	result.Status = types.StatusUp
+	return result
+}
+
+// handshake sends the opening request and hands the connection to the protocol
+func (c *StreamChecker) handshake(ctx context.Context, conn net.Conn, requestURL string, target *endpoint, check types.CheckConfig) (streamSource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Credentials from the URL become basic auth and are not sent in the request line
+	req.URL.User = nil
+	if target.user != "" {
+		req.SetBasicAuth(target.user, target.password)
+	}
+	for key, value := range check.Headers {
+		req.Header.Set(key, value)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "HealthCheck-CLI/1.0")
+	}
+
+	accept, err := c.protocol.handshake(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := accept(resp); err != nil {
+		return nil, err
+	}
+
+	return c.protocol.open(conn, reader, resp, check)
+}
+
+// await reads messages until one matches, recording what was received
+func (c *StreamChecker) await(source streamSource, match *messageMatcher, info *types.StreamInfo, within time.Duration) error {
+	waitStart := time.Now()
+	for {
+		message, err := source.next()
+		if err != nil {
+			if isTimeout(err) {
+				return fmt.Errorf("No matching %s within %v (%d received)", c.protocol.unit, within.Round(time.Millisecond), info.Messages)
+			}
+			return fmt.Errorf("%s stream failed after %d %ss: %v", c.protocol.name, info.Messages, c.protocol.unit, err)
+		}
+
+		info.Messages++
+		if !match.matches(message) {
+			continue
+		}
+
+		info.FirstMessage = time.Since(waitStart)
+		info.Event = message.event
+		info.Message = truncateString(message.data, maxStreamPreview)
+		return nil
+	}
+}
+
+// isTimeout reports whether err comes from an expired deadline
+func isTimeout(err error) bool {
+	if os.IsTimeout(err) {
+		return true
+	}
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// messageMatcher decides which message satisfies the check
+type messageMatcher struct {
+	event    string
+	contains string
+	regex    *regexp.Regexp
+	wait     bool // a message was sent or a deadline for one was set
+}
+
+func newMessageMatcher(check types.CheckConfig) (*messageMatcher, error) {
+	match := &messageMatcher{
+		event:    check.Stream.Event,
+		contains: check.Expected.BodyContains,
+		wait:     check.Stream.Send != "" || check.Expected.MessageWithin > 0,
+	}
+	if check.Expected.BodyRegex != "" {
+		re, err := regexp.Compile(check.Expected.BodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body regex '%s': %w", check.Expected.BodyRegex, err)
+		}
+		match.regex = re
+	}
+	return match, nil
+}
+
+// configured reports whether the check asks to wait for a message at all
+func (m *messageMatcher) configured() bool {
+	return m.wait || m.event != "" || m.contains != "" || m.regex != nil
+}
+
+func (m *messageMatcher) matches(message streamMessage) bool {
+	if m.event != "" && message.event != m.event {
+		return false
+	}
+	if m.contains != "" && !strings.Contains(message.data, m.contains) {
+		return false
+	}
+	return m.regex == nil || m.regex.MatchString(message.data)
+}
+
+// truncateString shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+// streamURL maps a ws:// or wss:// URL onto the http:// or https:// URL of its handshake
+func streamURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	switch parsed.Scheme {
+	case "ws":
+		parsed.Scheme = "http"
+	case "wss":
+		parsed.Scheme = "https"
+	}
+	return parsed.String()
+}
+
+// maxStreamMessage returns the largest message a check accepts
+func maxStreamMessage(check types.CheckConfig) int64 {
+	if check.MaxBodySize > 0 {
+		return check.MaxBodySize
+	}
+	return defaultMaxStreamMessage
+}
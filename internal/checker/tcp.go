@@ -3,21 +3,23 @@ package checker
 import (
 	"context"
 	"fmt"
-	"net"
 	"time"
 
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 )
 
 // TCPChecker implements health checks for TCP endpoints
 type TCPChecker struct {
 	timeout time.Duration
+	dialers *dialerCache
 }
 
 // NewTCPChecker creates a new TCP checker
 func NewTCPChecker(timeout time.Duration) *TCPChecker {
 	return &TCPChecker{
 		timeout: timeout,
+		dialers: newDialerCache(),
 	}
 }
 
@@ -40,8 +42,14 @@ func (t *TCPChecker) Check(check types.CheckConfig) types.Result {
 	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
 	defer cancel()
 	
-	// Create dialer
-	var d net.Dialer
+	// Create dialer honoring the check's proxy and resolver settings
+	d, err := t.dialers.get(netdial.FromCheck(check))
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Network configuration error: %v", err)
+		result.ResponseTime = time.Since(start)
+		return result
+	}
 	
 	// Attempt connection
 	conn, err := d.DialContext(ctx, "tcp", check.URL)
@@ -0,0 +1,127 @@
+package checker
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// timingRecorder collects httptrace events for a single request
+type timingRecorder struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	bodyDone     time.Time
+	reused       bool
+}
+
+// trace returns a ClientTrace that records connection phases.
+// On redirects the phases of the last hop win.
+func (t *timingRecorder) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mark(&t.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mark(&t.dnsDone)
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Keep the first attempt when dialing several addresses
+			if t.connectStart.IsZero() || !t.connectDone.IsZero() {
+				t.connectStart = time.Now()
+				t.connectDone = time.Time{}
+			}
+		},
+		ConnectDone: func(string, string, error) {
+			t.mark(&t.connectDone)
+		},
+		TLSHandshakeStart: func() {
+			t.mark(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mark(&t.tlsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mark(&t.wroteRequest)
+		},
+		GotFirstResponseByte: func() {
+			t.mark(&t.firstByte)
+		},
+	}
+}
+
+func (t *timingRecorder) mark(target *time.Time) {
+	t.mu.Lock()
+	*target = time.Now()
+	t.mu.Unlock()
+}
+
+// finish records the moment the response body was fully read
+func (t *timingRecorder) finish() {
+	t.mark(&t.bodyDone)
+}
+
+// timings converts the recorded events into a Timings breakdown
+func (t *timingRecorder) timings() *types.Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &types.Timings{
+		DNS:      between(t.dnsStart, t.dnsDone),
+		Connect:  between(t.connectStart, t.connectDone),
+		TLS:      between(t.tlsStart, t.tlsDone),
+		TTFB:     between(t.wroteRequest, t.firstByte),
+		Transfer: between(t.firstByte, t.bodyDone),
+		Reused:   t.reused,
+	}
+}
+
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// validateTimings checks the timing breakdown against the expected phase maximums
+func validateTimings(timings *types.Timings, expected types.Expected) error {
+	if timings == nil {
+		return nil
+	}
+
+	limits := []struct {
+		phase string
+		value time.Duration
+		max   time.Duration
+	}{
+		{"DNS lookup", timings.DNS, expected.DNSTimeMax},
+		{"TCP connect", timings.Connect, expected.ConnectTimeMax},
+		{"TLS handshake", timings.TLS, expected.TLSTimeMax},
+		{"time to first byte", timings.TTFB, expected.TTFBMax},
+	}
+
+	for _, limit := range limits {
+		if limit.max > 0 && limit.value > limit.max {
+			return fmt.Errorf("%s %v exceeds maximum %v", limit.phase, limit.value, limit.max)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/tlsconfig"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// tlsCache builds per-check TLS configurations and shares them between checks
+// with identical options. A configuration is rebuilt when its certificate,
+// key or CA file changes, so rotated certificates are picked up.
+type tlsCache struct {
+	mu      sync.Mutex
+	configs map[string]tlsEntry
+	warned  map[string]bool
+}
+
+// tlsEntry is a built configuration and the files it was built from
+type tlsEntry struct {
+	config *tls.Config
+	files  []fileStamp
+}
+
+// fileStamp identifies a version of a file by its modification time and size
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newTLSCache() *tlsCache {
+	return &tlsCache{
+		configs: make(map[string]tlsEntry),
+		warned:  make(map[string]bool),
+	}
+}
+
+// stampFiles returns the stamps of the certificate, key and CA files. Unset or
+// unreadable files get a zero stamp; building the configuration reports them.
+func stampFiles(opts types.TLSConfig) []fileStamp {
+	paths := []string{opts.CertFile, opts.KeyFile, opts.CAFile}
+	stamps := make([]fileStamp, len(paths))
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+// get returns the TLS configuration for a check, warning once per check
+// when certificate verification is disabled
+func (c *tlsCache) get(checkName string, opts types.TLSConfig) (*tls.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if opts.InsecureSkipVerify && !c.warned[checkName] {
+		c.warned[checkName] = true
+		fmt.Fprintf(os.Stderr, "⚠️  WARNING: TLS certificate verification is DISABLED for check '%s' (insecure_skip_verify: true). "+
+			"Connections can be intercepted; use ca_file for private CAs instead.\n", checkName)
+	}
+
+	key := tlsconfig.Key(opts)
+	files := stampFiles(opts)
+	if entry, ok := c.configs[key]; ok && slices.EqualFunc(entry.files, files, func(a, b fileStamp) bool {
+		return a.modTime.Equal(b.modTime) && a.size == b.size
+	}) {
+		return entry.config, nil
+	}
+
+	config, err := tlsconfig.Build(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.configs[key] = tlsEntry{config: config, files: files}
+	return config, nil
+}
+
+// forHost returns the TLS settings for a check, defaulting SNI to host
+func (c *tlsCache) forHost(check types.CheckConfig, host string) (*tls.Config, error) {
+	if check.TLS.IsZero() {
+		return &tls.Config{
+			ServerName: host,
+			MinVersion: tlsconfig.DefaultMinVersion,
+		}, nil
+	}
+
+	shared, err := c.get(check.Name, check.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	config := shared.Clone()
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	return config, nil
+}
@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCert creates a self-signed client certificate and returns the
+// certificate, its PEM path and the key PEM path
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "healthcheck-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return cert, certPath, keyPath
+}
+
+func TestHTTPChecker_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certPath, keyPath := writeClientCert(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caPath := filepath.Join(dir, "server-ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	checker := NewHTTPChecker(5 * time.Second)
+	check := types.CheckConfig{
+		Name:     "mTLS",
+		URL:      server.URL,
+		Method:   "GET",
+		Timeout:  5 * time.Second,
+		Expected: types.Expected{Status: 200},
+	}
+
+	// Without a client certificate or the private CA the handshake fails
+	result := checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status)
+
+	check.TLS = types.TLSConfig{CertFile: certPath, KeyFile: keyPath, CAFile: caPath}
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+
+	// Checks with identical TLS options share one client
+	check.Name = "mTLS copy"
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Len(t, checker.clients, 1)
+
+	// Broken TLS options are reported as configuration errors
+	check.TLS = types.TLSConfig{CertFile: certPath}
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "TLS configuration error")
+}
+
+func TestSSLChecker_CustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	checker := NewSSLChecker(5 * time.Second)
+	check := types.CheckConfig{
+		Name:    "private CA",
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+	}
+
+	result := checker.Check(check)
+	assert.Equal(t, types.StatusDown, result.Status, "system roots should not trust the test server")
+
+	// The httptest certificate is issued for example.com
+	check.TLS = types.TLSConfig{CAFile: caPath, ServerName: "example.com"}
+	result = checker.Check(check)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.CertInfo)
+}
+
+func TestTLSCache_ReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, certPath, keyPath := writeClientCert(t, dir)
+	opts := types.TLSConfig{CertFile: certPath, KeyFile: keyPath}
+
+	cache := newTLSCache()
+	first, err := cache.get("mTLS", opts)
+	require.NoError(t, err)
+	same, err := cache.get("mTLS", opts)
+	require.NoError(t, err)
+	assert.Same(t, first, same)
+
+	// A rotated certificate is loaded on the next use
+	writeClientCert(t, dir)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certPath, later, later))
+	rotated, err := cache.get("mTLS", opts)
+	require.NoError(t, err)
+	assert.NotSame(t, first, rotated)
+	assert.NotEqual(t, first.Certificates[0].Certificate[0], rotated.Certificates[0].Certificate[0])
+}
@@ -0,0 +1,238 @@
+package checker
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// webSocketGUID is appended to the client key to derive Sec-WebSocket-Accept (RFC 6455 section 1.3)
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+var webSocketProtocol = streamProtocol{
+	endpointFormat: endpointFormat{
+		name:        "WebSocket",
+		schemes:     []string{"ws", "wss"},
+		defaultPort: "80",
+		schemePorts: map[string]string{"wss": "443"},
+	},
+	tlsScheme: "wss",
+	httpURL:   streamURL,
+	unit:      "message",
+	handshake: webSocketHandshake,
+	open:      openWebSocket,
+}
+
+// webSocketHandshake turns the request into an upgrade request
+func webSocketHandshake(req *http.Request) (func(*http.Response) error, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	return func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			return fmt.Errorf("expected status 101, got %d", resp.StatusCode)
+		}
+		if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+			return fmt.Errorf("server did not upgrade to websocket")
+		}
+		if !headerHasToken(resp.Header, "Connection", "upgrade") {
+			return fmt.Errorf("server response lacks Connection: Upgrade")
+		}
+		if resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+			return fmt.Errorf("invalid Sec-WebSocket-Accept")
+		}
+		return nil
+	}, nil
+}
+
+// webSocketAccept derives the Sec-WebSocket-Accept value expected for key
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHasToken reports whether a comma separated header contains token
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// webSocketConn reads and writes frames on an upgraded connection
+type webSocketConn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	maxMessage int64
+}
+
+func openWebSocket(conn net.Conn, reader *bufio.Reader, resp *http.Response, check types.CheckConfig) (streamSource, error) {
+	ws := &webSocketConn{conn: conn, reader: reader, maxMessage: maxStreamMessage(check)}
+	if check.Stream.Send != "" {
+		if err := ws.writeFrame(wsText, []byte(check.Stream.Send)); err != nil {
+			return nil, fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+	return ws, nil
+}
+
+// next returns the next text or binary message, answering pings on the way
+func (w *webSocketConn) next() (streamMessage, error) {
+	var message []byte
+	fragmented := false
+	for {
+		fin, opcode, payload, err := w.readFrame(int64(len(message)))
+		if err != nil {
+			return streamMessage{}, err
+		}
+
+		switch opcode {
+		case wsPing:
+			if err := w.writeFrame(wsPong, payload); err != nil {
+				return streamMessage{}, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			return streamMessage{}, closeError(payload)
+		case wsText, wsBinary:
+			if fragmented {
+				return streamMessage{}, fmt.Errorf("new message started inside a fragmented one")
+			}
+		case wsContinuation:
+			if !fragmented {
+				return streamMessage{}, fmt.Errorf("continuation frame without a message")
+			}
+		default:
+			return streamMessage{}, fmt.Errorf("unknown opcode %#x", opcode)
+		}
+
+		message = append(message, payload...)
+		if fin {
+			return streamMessage{data: string(message)}, nil
+		}
+		fragmented = true
+	}
+}
+
+// readFrame reads one frame; buffered is the size of the message assembled so far
+func (w *webSocketConn) readFrame(buffered int64) (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(w.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, fmt.Errorf("unexpected reserved bits in frame")
+	}
+	if header[1]&0x80 != 0 {
+		return false, 0, nil, fmt.Errorf("server sent a masked frame")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(w.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(w.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	control := opcode&0x8 != 0
+	if control && (length > 125 || !fin) {
+		return false, 0, nil, fmt.Errorf("invalid control frame")
+	}
+	if !control && length > uint64(w.maxMessage-buffered) {
+		return false, 0, nil, fmt.Errorf("message exceeds %d bytes", w.maxMessage)
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(w.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single masked frame, as clients must
+func (w *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := w.conn.Write(frame)
+	return err
+}
+
+// close says goodbye with a normal closure; errors are irrelevant at this point
+func (w *webSocketConn) close() {
+	w.writeFrame(wsClose, binary.BigEndian.AppendUint16(nil, 1000))
+}
+
+// closeError describes a close frame received while waiting for a message
+func closeError(payload []byte) error {
+	if len(payload) < 2 {
+		return fmt.Errorf("server closed the connection")
+	}
+	code := binary.BigEndian.Uint16(payload)
+	if reason := string(payload[2:]); reason != "" {
+		return fmt.Errorf("server closed the connection with code %d: %s", code, reason)
+	}
+	return fmt.Errorf("server closed the connection with code %d", code)
+}
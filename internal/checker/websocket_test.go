@@ -0,0 +1,234 @@
+package checker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeWebSocket serves upgrades with serve, which speaks frames on the hijacked connection
+func startFakeWebSocket(t *testing.T, tls bool, serve func(conn net.Conn, rw *bufio.ReadWriter)) string {
+	t.Helper()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			http.Error(w, "not a websocket upgrade", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		rw.Flush()
+		serve(conn, rw)
+	})
+
+	var server *httptest.Server
+	if tls {
+		server = httptest.NewTLSServer(handler)
+	} else {
+		server = httptest.NewServer(handler)
+	}
+	t.Cleanup(server.Close)
+
+	return strings.Replace(server.URL, "http", "ws", 1)
+}
+
+// readClientFrame reads and unmasks one client frame
+func readClientFrame(r io.Reader) (opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		io.ReadFull(r, extended[:])
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		io.ReadFull(r, extended[:])
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return header[0] & 0x0F, payload, nil
+}
+
+// writeServerFrame writes one unmasked frame
+func writeServerFrame(w *bufio.ReadWriter, fin bool, opcode byte, payload string) {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	w.Write([]byte{first, byte(len(payload))})
+	w.WriteString(payload)
+	w.Flush()
+}
+
+func webSocketCheck(url string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "realtime",
+		Type:    types.CheckTypeWebSocket,
+		URL:     url,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestWebSocketChecker_SendAndReply(t *testing.T) {
+	pongs := make(chan string, 1)
+	url := startFakeWebSocket(t, false, func(conn net.Conn, rw *bufio.ReadWriter) {
+		opcode, payload, err := readClientFrame(rw)
+		if err != nil || opcode != wsText || string(payload) != `{"type":"ping"}` {
+			return
+		}
+		writeServerFrame(rw, true, wsText, `{"type":"welcome"}`)
+		writeServerFrame(rw, true, wsPing, "are you there")
+		if opcode, payload, err := readClientFrame(rw); err == nil && opcode == wsPong {
+			pongs <- string(payload)
+		}
+		// The reply arrives in two fragments
+		writeServerFrame(rw, false, wsText, `{"type":`)
+		writeServerFrame(rw, true, wsContinuation, `"pong"}`)
+		readClientFrame(rw) // close
+	})
+
+	check := webSocketCheck(url + "/socket")
+	check.Stream.Send = `{"type":"ping"}`
+	check.Expected.BodyContains = `"pong"`
+
+	result := NewWebSocketChecker(5 * time.Second).Check(check)
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	require.NotNil(t, result.Stream)
+	assert.Equal(t, 2, result.Stream.Messages)
+	assert.Equal(t, `{"type":"pong"}`, result.Stream.Message)
+	assert.Positive(t, result.Stream.Handshake)
+	assert.Positive(t, result.Stream.FirstMessage)
+	assert.Equal(t, "are you there", <-pongs)
+}
+
+func TestWebSocketChecker_HandshakeOnly(t *testing.T) {
+	url := startFakeWebSocket(t, false, func(conn net.Conn, rw *bufio.ReadWriter) {
+		readClientFrame(rw)
+	})
+
+	result := NewWebSocketChecker(5 * time.Second).Check(webSocketCheck(url))
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Zero(t, result.Stream.Messages)
+	assert.Zero(t, result.Stream.FirstMessage)
+}
+
+func TestWebSocketChecker_NoReplyInTime(t *testing.T) {
+	url := startFakeWebSocket(t, false, func(conn net.Conn, rw *bufio.ReadWriter) {
+		readClientFrame(rw)
+		writeServerFrame(rw, true, wsText, "something else")
+		time.Sleep(time.Second)
+	})
+
+	check := webSocketCheck(url)
+	check.Stream.Send = "ping"
+	check.Expected.BodyRegex = "^pong$"
+	check.Expected.MessageWithin = 200 * time.Millisecond
+
+	result := NewWebSocketChecker(5 * time.Second).Check(check)
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "No matching message within 200ms (1 received)", result.Error)
+	assert.Less(t, result.ResponseTime, time.Second)
+}
+
+func TestWebSocketChecker_ServerCloses(t *testing.T) {
+	url := startFakeWebSocket(t, false, func(conn net.Conn, rw *bufio.ReadWriter) {
+		readClientFrame(rw)
+		writeServerFrame(rw, true, wsClose, "\x03\xf0policy")
+	})
+
+	check := webSocketCheck(url)
+	check.Stream.Send = "subscribe"
+
+	result := NewWebSocketChecker(5 * time.Second).Check(check)
+
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Contains(t, result.Error, "server closed the connection with code 1008: policy")
+}
+
+func TestWebSocketChecker_HandshakeFailures(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer plain.Close()
+
+	badAccept := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Upgrade", "websocket")
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Sec-WebSocket-Accept", "bm90IHRoZSByaWdodCBrZXk=")
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	}))
+	defer badAccept.Close()
+
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{strings.Replace(plain.URL, "http", "ws", 1), "WebSocket handshake failed: expected status 101, got 200"},
+		{strings.Replace(badAccept.URL, "http", "ws", 1), "WebSocket handshake failed: invalid Sec-WebSocket-Accept"},
+	}
+
+	for _, tt := range tests {
+		result := NewWebSocketChecker(5 * time.Second).Check(webSocketCheck(tt.url))
+		assert.Equal(t, types.StatusDown, result.Status)
+		assert.Equal(t, tt.wantErr, result.Error)
+	}
+}
+
+func TestWebSocketChecker_TLSAndCredentials(t *testing.T) {
+	url := startFakeWebSocket(t, true, func(conn net.Conn, rw *bufio.ReadWriter) {
+		readClientFrame(rw)
+		writeServerFrame(rw, true, wsText, "ready")
+		readClientFrame(rw)
+	})
+
+	check := webSocketCheck(strings.Replace(url, "wss://", "wss://monitor:s3cret@", 1))
+	check.TLS.InsecureSkipVerify = true
+	check.Stream.Send = "hello"
+
+	result := NewWebSocketChecker(5 * time.Second).Check(check)
+
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.NotNil(t, result.CertInfo)
+	assert.Equal(t, "ready", result.Stream.Message)
+	assert.NotContains(t, result.URL, "s3cret")
+}
+
+func TestWebSocketChecker_InvalidURL(t *testing.T) {
+	result := NewWebSocketChecker(5 * time.Second).Check(webSocketCheck("http://example.com/socket"))
+
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "WebSocket checks require a ws:// or wss:// URL")
+}
@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+const maxWHOISResponseSize = 256 << 10
+
+// whoisRootServer refers queries to the WHOIS server of each TLD, used when
+// a check has no whois_server
+var whoisRootServer = "whois.iana.org"
+
+// whoisExpiryKeys name the expiry date across common registry and registrar formats
+var whoisExpiryKeys = map[string]bool{
+	"registry expiry date":                   true,
+	"registrar registration expiration date": true,
+	"expiration date":                        true,
+	"expiry date":                            true,
+	"expire date":                            true,
+	"expires":                                true,
+	"expires on":                             true,
+	"paid-till":                              true,
+}
+
+// whoisDateLayouts are tried in order to parse expiry dates
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006.01.02",
+	"02-Jan-2006",
+	"02.01.2006",
+}
+
+// whoisNotFound are the answers registries give for unregistered domains
+var whoisNotFound = []string{"no match for", "not found", "no data found", "no entries found", "status: free"}
+
+// lookupWHOIS queries server, or the server whois.iana.org refers to for the
+// TLD of domain, and reads the registration data from the reply
+func lookupWHOIS(ctx context.Context, dialer *netdial.Dialer, domain, server string) (*types.DomainInfo, error) {
+	if server == "" {
+		referral, err := whoisQuery(ctx, dialer, whoisRootServer, domain)
+		if err != nil {
+			return nil, err
+		}
+		if server = whoisValue(referral, "refer"); server == "" {
+			return nil, fmt.Errorf("no WHOIS server for .%s", domain[strings.LastIndex(domain, ".")+1:])
+		}
+	}
+
+	reply, err := whoisQuery(ctx, dialer, server, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := parseWHOIS(reply)
+	if err != nil {
+		return nil, err
+	}
+	info.Domain = domain
+	info.Source = domainSourceWHOIS
+	info.Server = server
+	return info, nil
+}
+
+// whoisQuery sends domain to a WHOIS server (RFC 3912) and reads the whole reply
+func whoisQuery(ctx context.Context, dialer *netdial.Dialer, server, domain string) (string, error) {
+	address := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		address = net.JoinHostPort(server, "43")
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte(domain + "\r\n")); err != nil {
+		return "", fmt.Errorf("query %s: %w", server, err)
+	}
+
+	reply, err := io.ReadAll(io.LimitReader(conn, maxWHOISResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("read from %s: %w", server, err)
+	}
+	return string(reply), nil
+}
+
+// parseWHOIS reads the registrar, expiry date and status codes from a
+// "key: value" WHOIS reply
+func parseWHOIS(reply string) (*types.DomainInfo, error) {
+	info := &types.DomainInfo{}
+
+	for _, line := range strings.Split(reply, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case whoisExpiryKeys[key] && info.ExpiryDate.IsZero():
+			expiry, err := parseWHOISDate(value)
+			if err != nil {
+				return nil, err
+			}
+			info.ExpiryDate = expiry
+		case key == "registrar" && info.Registrar == "":
+			info.Registrar = value
+		case key == "domain status" || key == "status":
+			// Statuses are followed by an ICANN link, e.g. clientTransferProhibited https://icann.org/epp#...
+			info.Statuses = append(info.Statuses, strings.Fields(value)[0])
+		}
+	}
+
+	if info.ExpiryDate.IsZero() && info.Registrar == "" {
+		lower := strings.ToLower(reply)
+		for _, marker := range whoisNotFound {
+			if strings.Contains(lower, marker) {
+				return nil, errDomainNotFound
+			}
+		}
+		if len(info.Statuses) == 0 {
+			return nil, fmt.Errorf("unrecognized WHOIS reply")
+		}
+	}
+
+	info.Locked = transferLocked(info.Statuses)
+	return info, nil
+}
+
+// parseWHOISDate parses an expiry date in one of the common WHOIS layouts
+func parseWHOISDate(value string) (time.Time, error) {
+	for _, layout := range whoisDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized expiry date '%s'", value)
+}
+
+// whoisValue returns the value of the first line starting with key
+func whoisValue(reply, key string) string {
+	for _, line := range strings.Split(reply, "\n") {
+		k, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if found && strings.EqualFold(strings.TrimSpace(k), key) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -2,22 +2,35 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/dependency"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/env"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/flap"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonpath"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonschema"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/maintenance"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/plugin"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/tlsconfig"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the main configuration structure
 type Config struct {
-	Global        GlobalConfig   `yaml:"global"`
-	Checks        []CheckConfig  `yaml:"checks"`
-	Notifications Notifications `yaml:"notifications"`
+	Global        GlobalConfig              `yaml:"global"`
+	Checks        []CheckConfig             `yaml:"checks"`
+	Notifications Notifications             `yaml:"notifications"`
+	Maintenance   []types.MaintenanceWindow `yaml:"maintenance"` // windows during which alerts are suppressed
 }
 
 // GlobalConfig contains global settings
@@ -34,6 +47,12 @@ type GlobalConfig struct {
 	RateLimit         types.RateLimitConfig         `yaml:"rate_limit"`
 	CircuitBreaker    types.CircuitBreakerConfig    `yaml:"circuit_breaker"`
 	MemoryManagement  types.MemoryManagementConfig  `yaml:"memory_management"`
+	MaxBodySize       int64                         `yaml:"max_body_size"`
+	Proxy             string                        `yaml:"proxy"`
+	Resolver          string                        `yaml:"resolver"`
+	Resolve           map[string]string             `yaml:"resolve"`
+	AllowedCommands   []string                      `yaml:"allowed_commands"` // glob patterns of commands exec checks may run
+	PluginsDir        string                        `yaml:"plugins_dir"`      // directory of checker plugin executables
 }
 
 // CheckConfig wraps the types.CheckConfig with YAML tags
@@ -142,6 +161,7 @@ func DefaultConfig() *Config {
 				CleanupInterval:        5 * time.Minute,    // Run cleanup every 5 minutes
 				MaxTotalMemoryMB:       100,                // Limit total memory usage to 100MB
 			},
+			MaxBodySize: 10 << 20, // Read at most 10 MiB of each response body
 		},
 		Notifications: Notifications{
 			GlobalRules: NotificationRules{
@@ -325,6 +345,24 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("default_interval must be greater than 0")
 	}
 	
+	if c.Global.MaxBodySize < 0 {
+		return fmt.Errorf("max_body_size cannot be negative")
+	}
+	
+	if err := netdial.Validate(netdial.Options{
+		Proxy:    c.Global.Proxy,
+		Resolver: c.Global.Resolver,
+		Resolve:  c.Global.Resolve,
+	}); err != nil {
+		return fmt.Errorf("global network settings: %w", err)
+	}
+	
+	for _, pattern := range c.Global.AllowedCommands {
+		if _, err := filepath.Match(pattern, ""); err != nil || !filepath.IsAbs(pattern) {
+			return fmt.Errorf("allowed_commands pattern '%s' must be a valid absolute path glob", pattern)
+		}
+	}
+	
 	// Validate checks
 	if len(c.Checks) == 0 {
 		return fmt.Errorf("at least one check must be defined")
@@ -336,6 +374,14 @@ func (c *Config) Validate() error {
 		}
 	}
 	
+	if err := c.validateDependencies(); err != nil {
+		return err
+	}
+	
+	if err := c.validateMaintenance(); err != nil {
+		return err
+	}
+	
 	// Validate notifications
 	if err := c.validateNotifications(); err != nil {
 		return err
@@ -349,7 +395,8 @@ func (c *Config) validateCheck(check CheckConfig, index int) error {
 		return fmt.Errorf("check[%d]: name is required", index)
 	}
 	
-	if check.URL == "" {
+	// Exec, memory, load, process, docker and plugin checks need no URL; it defaults to a label
+	if check.URL == "" && !labelledByDefault(check.Type) {
 		return fmt.Errorf("check[%d]: URL is required", index)
 	}
 	
@@ -366,6 +413,121 @@ func (c *Config) validateCheck(check CheckConfig, index int) error {
 	case types.CheckTypeSSL:
 		// SSL checks can accept both URL format and host:port format
 		// No strict validation needed as the SSL checker handles both
+	case types.CheckTypePostgres, types.CheckTypeMySQL, types.CheckTypeRedis,
+		types.CheckTypeAMQP, types.CheckTypeMQTT, types.CheckTypeKafka,
+		types.CheckTypeSMTP, types.CheckTypeIMAP, types.CheckTypePOP3,
+		types.CheckTypeWebSocket:
+		if err := validateConnectionURL(check.Type, check.URL); err != nil {
+			return fmt.Errorf("check[%d]: %w", index, err)
+		}
+		if err := validateBroker(check); err != nil {
+			return fmt.Errorf("check[%d]: %w", index, err)
+		}
+		if err := validateMail(check); err != nil {
+			return fmt.Errorf("check[%d]: %w", index, err)
+		}
+	case types.CheckTypeSSE:
+		if !strings.HasPrefix(check.URL, "http://") && !strings.HasPrefix(check.URL, "https://") {
+			return fmt.Errorf("check[%d]: SSE checks require http:// or https:// URL", index)
+		}
+	case types.CheckTypeGraphQL:
+		if !strings.HasPrefix(check.URL, "http://") && !strings.HasPrefix(check.URL, "https://") {
+			return fmt.Errorf("check[%d]: GraphQL checks require http:// or https:// URL", index)
+		}
+		if strings.TrimSpace(check.GraphQL.Query) == "" {
+			return fmt.Errorf("check[%d]: graphql.query is required", index)
+		}
+	case types.CheckTypeDomain:
+		if !domainNamePattern.MatchString(check.URL) {
+			return fmt.Errorf("check[%d]: domain checks take a domain name such as example.com as URL", index)
+		}
+	case types.CheckTypeDisk, types.CheckTypeFile:
+		if !filepath.IsAbs(check.URL) || filepath.Clean(check.URL) != check.URL {
+			return fmt.Errorf("check[%d]: %s checks require a clean absolute path as URL", index, check.Type)
+		}
+	case types.CheckTypeProcess:
+		if check.Process.IsZero() {
+			return fmt.Errorf("check[%d]: process checks require process.name or process.cmdline", index)
+		}
+	case types.CheckTypeDocker:
+		if (check.Docker.Container == "") == (len(check.Docker.Labels) == 0) {
+			return fmt.Errorf("check[%d]: docker checks require exactly one of docker.container or docker.labels", index)
+		}
+	case types.CheckTypeExec:
+		if err := security.ValidateCommand(check.Exec.Command, c.Global.AllowedCommands); err != nil {
+			return fmt.Errorf("check[%d]: exec: %w", index, err)
+		}
+		for key := range check.Exec.Env {
+			if key == "" || strings.ContainsAny(key, "=\x00") {
+				return fmt.Errorf("check[%d]: exec env name '%s' is invalid", index, key)
+			}
+		}
+		if check.Exec.Dir != "" && !filepath.IsAbs(check.Exec.Dir) {
+			return fmt.Errorf("check[%d]: exec dir must be an absolute path", index)
+		}
+	case types.CheckTypeScenario:
+		if !strings.HasPrefix(check.URL, "http://") && !strings.HasPrefix(check.URL, "https://") {
+			return fmt.Errorf("check[%d]: scenario checks require an http:// or https:// base URL", index)
+		}
+		if len(check.Steps) == 0 {
+			return fmt.Errorf("check[%d]: scenario checks require at least one step", index)
+		}
+		for j, step := range check.Steps {
+			for _, extraction := range step.Extract {
+				if extraction.Name == "" {
+					return fmt.Errorf("check[%d].steps[%d]: extraction name is required", index, j)
+				}
+				if (extraction.JSON == "") == (extraction.Header == "") {
+					return fmt.Errorf("check[%d].steps[%d]: extraction '%s' must set exactly one of json or header", index, j, extraction.Name)
+				}
+			}
+			if err := validateJSONMatches(step.Expected.JSON); err != nil {
+				return fmt.Errorf("check[%d].steps[%d]: %w", index, j, err)
+			}
+			if err := validateAssertions(step.Expected); err != nil {
+				return fmt.Errorf("check[%d].steps[%d]: %w", index, j, err)
+			}
+		}
+	}
+	
+	if check.Type != types.CheckTypeExec && !check.Exec.IsZero() {
+		return fmt.Errorf("check[%d]: exec settings are only supported by exec checks", index)
+	}
+	
+	// Other types are provided by plugins, which are only known once they are started
+	if check.Type != "" && !check.Type.IsBuiltin() && !plugin.ValidType(check.Type) {
+		return fmt.Errorf("check[%d]: check type '%s' is invalid", index, check.Type)
+	}
+	if check.Type.IsBuiltin() && len(check.Plugin) > 0 {
+		return fmt.Errorf("check[%d]: plugin options are only supported by check types provided by plugins", index)
+	}
+	
+	if err := validateStream(check); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if err := validateGraphQL(check); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if err := validateContentChange(check); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if err := validateAssertions(check.Expected); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if err := validateDomain(check); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if err := validateLocal(check); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if err := validateDocker(check); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
 	}
 	
 	if check.Interval <= 0 {
@@ -380,6 +542,66 @@ func (c *Config) validateCheck(check CheckConfig, index int) error {
 		return fmt.Errorf("check[%d]: timeout must be less than interval", index)
 	}
 	
+	if check.MaxBodySize < 0 {
+		return fmt.Errorf("check[%d]: max_body_size cannot be negative", index)
+	}
+	
+	if err := tlsconfig.Validate(check.TLS); err != nil {
+		return fmt.Errorf("check[%d]: tls: %w", index, err)
+	}
+	
+	if err := netdial.Validate(netdial.FromCheck(check.CheckConfig)); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
+	if !types.IsValidIPVersion(check.IPVersion) {
+		return fmt.Errorf("check[%d]: ip_version must be 4, 6 or both, got '%s'", index, check.IPVersion)
+	}
+	if check.IPVersion == types.IPVersionBoth && check.Type == types.CheckTypeScenario {
+		return fmt.Errorf("check[%d]: ip_version both is not supported for scenario checks", index)
+	}
+	
+	revocationURLs := []struct{ field, value string }{
+		{"ocsp_url", check.Revocation.OCSPURL},
+		{"crl_url", check.Revocation.CRLURL},
+	}
+	for _, u := range revocationURLs {
+		if u.value != "" && !strings.HasPrefix(u.value, "http://") && !strings.HasPrefix(u.value, "https://") {
+			return fmt.Errorf("check[%d]: revocation %s must be an http:// or https:// URL", index, u.field)
+		}
+	}
+	if check.Revocation.Timeout < 0 {
+		return fmt.Errorf("check[%d]: revocation timeout cannot be negative", index)
+	}
+	
+	for _, fingerprint := range check.Expected.CertFingerprints {
+		if err := tlsconfig.ValidateFingerprint(fingerprint); err != nil {
+			return fmt.Errorf("check[%d]: %w", index, err)
+		}
+	}
+	for _, pin := range check.Expected.SPKIPins {
+		if err := tlsconfig.ValidateSPKIPin(pin); err != nil {
+			return fmt.Errorf("check[%d]: %w", index, err)
+		}
+	}
+	
+	findingSeverities := []struct{ field, value string }{
+		{"cert_chain_incomplete", check.Expected.CertChainIncomplete},
+		{"cert_hostname_mismatch", check.Expected.CertHostnameMismatch},
+		{"cert_weak_key", check.Expected.CertWeakKey},
+		{"cert_sha1_signature", check.Expected.CertSHA1Signature},
+		{"cert_intermediate_expiry", check.Expected.CertIntermediateExpiry},
+	}
+	for _, severity := range findingSeverities {
+		if !types.IsValidFindingSeverity(severity.value) {
+			return fmt.Errorf("check[%d]: %s must be one of warning, down or ignore", index, severity.field)
+		}
+	}
+	
+	if err := flap.Validate(check.CheckConfig); err != nil {
+		return fmt.Errorf("check[%d]: %w", index, err)
+	}
+	
 	return nil
 }
 
@@ -414,6 +636,451 @@ func (c *Config) validateNotifications() error {
 	return nil
 }
 
+// connectionSchemes lists the URL schemes accepted by each database, message
+// broker, mail server and WebSocket check type
+var connectionSchemes = map[types.CheckType][]string{
+	types.CheckTypePostgres:  {"postgres", "postgresql"},
+	types.CheckTypeMySQL:     {"mysql"},
+	types.CheckTypeRedis:     {"redis", "rediss"},
+	types.CheckTypeAMQP:      {"amqp", "amqps"},
+	types.CheckTypeMQTT:      {"mqtt", "mqtts"},
+	types.CheckTypeKafka:     {"kafka"},
+	types.CheckTypeSMTP:      {"smtp", "smtps"},
+	types.CheckTypeIMAP:      {"imap", "imaps"},
+	types.CheckTypePOP3:      {"pop3", "pop3s"},
+	types.CheckTypeWebSocket: {"ws", "wss"},
+}
+
+// checkTypeForScheme infers a database, broker, mail or WebSocket check type from the URL scheme
+func checkTypeForScheme(rawURL string) (types.CheckType, bool) {
+	scheme, _, found := strings.Cut(rawURL, "://")
+	if !found {
+		return "", false
+	}
+	for checkType, schemes := range connectionSchemes {
+		for _, s := range schemes {
+			if s == scheme {
+				return checkType, true
+			}
+		}
+	}
+	return "", false
+}
+
+// validateConnectionURL checks the connection URL of a database, broker, mail
+// or WebSocket check. Errors never repeat the URL since it usually holds a password.
+func validateConnectionURL(checkType types.CheckType, rawURL string) error {
+	schemes := connectionSchemes[checkType]
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%s URL is malformed", checkType)
+	}
+
+	valid := false
+	for _, scheme := range schemes {
+		valid = valid || parsed.Scheme == scheme
+	}
+	if !valid {
+		return fmt.Errorf("%s checks require a %s:// URL", checkType, strings.Join(schemes, ":// or "))
+	}
+	if parsed.Hostname() == "" {
+		return fmt.Errorf("%s URL must include a host", checkType)
+	}
+
+	database := strings.TrimPrefix(parsed.Path, "/")
+	switch checkType {
+	case types.CheckTypePostgres:
+		if parsed.User.Username() == "" {
+			return fmt.Errorf("postgres URL must include a user name")
+		}
+		switch mode := parsed.Query().Get("sslmode"); mode {
+		case "", "disable", "prefer", "require", "verify-ca", "verify-full":
+		default:
+			return fmt.Errorf("postgres sslmode must be disable, prefer, require, verify-ca or verify-full, got '%s'", mode)
+		}
+	case types.CheckTypeMySQL:
+		if parsed.User.Username() == "" {
+			return fmt.Errorf("mysql URL must include a user name")
+		}
+		switch mode := parsed.Query().Get("tls"); mode {
+		case "", "false", "true", "skip-verify", "preferred":
+		default:
+			return fmt.Errorf("mysql tls must be false, true, skip-verify or preferred, got '%s'", mode)
+		}
+	case types.CheckTypeRedis:
+		if database != "" {
+			if _, err := strconv.Atoi(database); err != nil {
+				return fmt.Errorf("redis database must be a number, got '%s'", database)
+			}
+		}
+	case types.CheckTypeKafka:
+		switch mode := parsed.Query().Get("tls"); mode {
+		case "", "false", "true", "skip-verify":
+		default:
+			return fmt.Errorf("kafka tls must be false, true or skip-verify, got '%s'", mode)
+		}
+		if mechanism := parsed.Query().Get("sasl"); mechanism != "" {
+			switch strings.ToUpper(mechanism) {
+			case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+			default:
+				return fmt.Errorf("kafka sasl must be PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512, got '%s'", mechanism)
+			}
+			if parsed.User.Username() == "" {
+				return fmt.Errorf("kafka sasl requires a user name in the URL")
+			}
+		}
+	case types.CheckTypeSMTP, types.CheckTypeIMAP, types.CheckTypePOP3:
+		switch mode := parsed.Query().Get("starttls"); mode {
+		case "", "true", "false":
+		default:
+			return fmt.Errorf("%s starttls must be true or false, got '%s'", checkType, mode)
+		}
+		if checkType == types.CheckTypePOP3 && parsed.User.Username() == "" {
+			return fmt.Errorf("pop3 URL must include a user name")
+		}
+	}
+
+	return nil
+}
+
+// validateMail checks that mail server expectations are only set on mail checks
+func validateMail(check CheckConfig) error {
+	expected := check.Expected
+	mail := check.Type == types.CheckTypeSMTP || check.Type == types.CheckTypeIMAP || check.Type == types.CheckTypePOP3
+
+	if (expected.BannerContains != "" || len(expected.Extensions) > 0) && !mail {
+		return fmt.Errorf("banner_contains and extensions are only supported by smtp, imap and pop3 checks")
+	}
+	if expected.MessagesMax > 0 && check.Type != types.CheckTypeIMAP && check.Type != types.CheckTypePOP3 {
+		return fmt.Errorf("messages_max is only supported by imap and pop3 checks")
+	}
+	return nil
+}
+
+// validateStream checks the WebSocket and SSE settings against the check type
+func validateStream(check CheckConfig) error {
+	stream := check.Type == types.CheckTypeWebSocket || check.Type == types.CheckTypeSSE
+
+	if check.Stream.Send != "" && check.Type != types.CheckTypeWebSocket {
+		return fmt.Errorf("stream.send is only supported by websocket checks")
+	}
+	if check.Stream.Event != "" && check.Type != types.CheckTypeSSE {
+		return fmt.Errorf("stream.event is only supported by sse checks")
+	}
+	if check.Expected.MessageWithin < 0 {
+		return fmt.Errorf("message_within cannot be negative")
+	}
+	if check.Expected.MessageWithin > 0 && !stream {
+		return fmt.Errorf("message_within is only supported by websocket and sse checks")
+	}
+	if check.Expected.MessageWithin > 0 && check.Timeout > 0 && check.Expected.MessageWithin >= check.Timeout {
+		return fmt.Errorf("message_within must be less than timeout")
+	}
+	return nil
+}
+
+// validateGraphQL checks the GraphQL settings and JSON assertions against the check type
+func validateGraphQL(check CheckConfig) error {
+	if check.Type != types.CheckTypeGraphQL && !check.GraphQL.IsZero() {
+		return fmt.Errorf("graphql settings are only supported by graphql checks")
+	}
+	if check.Expected.SchemaAlertOnChange && check.Type != types.CheckTypeGraphQL {
+		return fmt.Errorf("schema_alert_on_change is only supported by graphql checks")
+	}
+	if len(check.Expected.JSON) > 0 && check.Type != types.CheckTypeHTTP && check.Type != types.CheckTypeGraphQL {
+		return fmt.Errorf("json assertions are only supported by http and graphql checks")
+	}
+	return validateJSONMatches(check.Expected.JSON)
+}
+
+// validateContentChange checks the content change settings against the check type
+func validateContentChange(check CheckConfig) error {
+	expected := check.Expected
+
+	if (expected.ContentAlertOnChange || len(expected.ContentIgnore) > 0) && check.Type != types.CheckTypeHTTP {
+		return fmt.Errorf("content_alert_on_change and content_ignore are only supported by http checks")
+	}
+	if len(expected.ContentIgnore) > 0 && !expected.ContentAlertOnChange {
+		return fmt.Errorf("content_ignore requires content_alert_on_change")
+	}
+	if expected.ContentAlertOnChange && check.SkipBody {
+		return fmt.Errorf("content_alert_on_change cannot be combined with skip_body")
+	}
+	for _, pattern := range expected.ContentIgnore {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid content_ignore regex '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// dockerLabelSelector labels a docker check by its label selector, e.g. "app=api,tier"
+func dockerLabelSelector(labels map[string]string) string {
+	selector := make([]string, 0, len(labels))
+	for key, value := range labels {
+		if value != "" {
+			key += "=" + value
+		}
+		selector = append(selector, key)
+	}
+	sort.Strings(selector)
+	return strings.Join(selector, ",")
+}
+
+// ValidatePlugins checks that a plugin manifest provides the type of every
+// check without a built-in type, and that the check's options match the
+// manifest's schema
+func (c *Config) ValidatePlugins(plugins []*plugin.Manifest) error {
+	for i, check := range c.Checks {
+		if check.Type.IsBuiltin() {
+			continue
+		}
+
+		var provider *plugin.Manifest
+		for _, p := range plugins {
+			if p.Type() == check.Type {
+				provider = p
+				break
+			}
+		}
+		if provider == nil {
+			if c.Global.PluginsDir == "" {
+				return fmt.Errorf("check[%d]: unknown check type '%s' (no plugins_dir is configured)", i, check.Type)
+			}
+			return fmt.Errorf("check[%d]: unknown check type '%s', no plugin in %s provides it", i, check.Type, c.Global.PluginsDir)
+		}
+		if err := provider.ValidateOptions(check.Plugin); err != nil {
+			return fmt.Errorf("check[%d]: plugin options for %s: %w", i, provider.Name(), err)
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks the depends_on names of all checks together
+func (c *Config) validateDependencies() error {
+	checks := make([]types.CheckConfig, len(c.Checks))
+	for i, check := range c.Checks {
+		checks[i] = check.CheckConfig
+	}
+	return dependency.Validate(checks)
+}
+
+// validateMaintenance checks the maintenance windows and that the checks
+// they name exist
+func (c *Config) validateMaintenance() error {
+	if _, err := maintenance.NewWindows(c.Maintenance); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(c.Checks))
+	for _, check := range c.Checks {
+		names[check.Name] = true
+	}
+	for i, window := range c.Maintenance {
+		for _, name := range window.Checks {
+			if !names[name] {
+				return fmt.Errorf("maintenance[%d]: unknown check '%s'", i, name)
+			}
+		}
+	}
+	return nil
+}
+
+// labelledByDefault reports whether checks of this type may omit the URL
+func labelledByDefault(checkType types.CheckType) bool {
+	switch checkType {
+	case types.CheckTypeExec, types.CheckTypeMemory, types.CheckTypeLoad, types.CheckTypeProcess, types.CheckTypeDocker:
+		return true
+	}
+	return checkType != "" && !checkType.IsBuiltin()
+}
+
+// validateLocal checks the local resource settings and thresholds against the check type
+func validateLocal(check CheckConfig) error {
+	expected := check.Expected
+
+	if check.Type != types.CheckTypeProcess && !check.Process.IsZero() {
+		return fmt.Errorf("process settings are only supported by process checks")
+	}
+	if check.Process.Cmdline != "" {
+		if _, err := regexp.Compile(check.Process.Cmdline); err != nil {
+			return fmt.Errorf("process.cmdline regex is invalid: %w", err)
+		}
+	}
+
+	thresholds := []struct {
+		set       bool
+		checkType types.CheckType
+		names     string
+	}{
+		{expected.DiskFreeMin != 0 || expected.InodesFreeMin != 0, types.CheckTypeDisk, "disk_free_min and inodes_free_min"},
+		{expected.MemoryAvailableMin != 0 || expected.SwapUsedMax != 0, types.CheckTypeMemory, "memory_available_min and swap_used_max"},
+		{expected.LoadMax != 0, types.CheckTypeLoad, "load_max"},
+		{expected.ProcessesMin != 0 || expected.ProcessesMax != 0, types.CheckTypeProcess, "processes_min and processes_max"},
+		{expected.FileMaxAge != 0 || expected.FileMaxSize != 0, types.CheckTypeFile, "file_max_age and file_max_size"},
+	}
+	for _, threshold := range thresholds {
+		if threshold.set && check.Type != threshold.checkType {
+			return fmt.Errorf("%s can only be used with %s checks", threshold.names, threshold.checkType)
+		}
+	}
+
+	percentages := []struct {
+		name  string
+		value float64
+	}{
+		{"disk_free_min", expected.DiskFreeMin},
+		{"inodes_free_min", expected.InodesFreeMin},
+		{"memory_available_min", expected.MemoryAvailableMin},
+		{"swap_used_max", expected.SwapUsedMax},
+	}
+	for _, percentage := range percentages {
+		if percentage.value < 0 || percentage.value > 100 {
+			return fmt.Errorf("%s must be a percentage between 0 and 100", percentage.name)
+		}
+	}
+	if expected.LoadMax < 0 || expected.ProcessesMin < 0 || expected.ProcessesMax < 0 || expected.FileMaxAge < 0 || expected.FileMaxSize < 0 {
+		return fmt.Errorf("load_max, processes_min, processes_max, file_max_age and file_max_size cannot be negative")
+	}
+	if expected.ProcessesMax > 0 && expected.ProcessesMin > expected.ProcessesMax {
+		return fmt.Errorf("processes_min cannot be greater than processes_max")
+	}
+	return nil
+}
+
+// validateDocker checks the Docker settings and expectations against the check type
+func validateDocker(check CheckConfig) error {
+	docker := check.Docker
+
+	if check.Type != types.CheckTypeDocker {
+		if !docker.IsZero() {
+			return fmt.Errorf("docker settings are only supported by docker checks")
+		}
+		if check.Expected.RestartsMax != 0 {
+			return fmt.Errorf("restarts_max can only be used with docker checks")
+		}
+		return nil
+	}
+
+	if check.Expected.RestartsMax < 0 {
+		return fmt.Errorf("restarts_max cannot be negative")
+	}
+	if docker.Socket != "" && !filepath.IsAbs(strings.TrimPrefix(docker.Socket, "unix://")) {
+		return fmt.Errorf("docker.socket must be an absolute path or a unix:// URL")
+	}
+	for key := range docker.Labels {
+		if key == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("docker label name '%s' is invalid", key)
+		}
+	}
+	return nil
+}
+
+// domainNamePattern matches a fully qualified domain name with an optional trailing dot
+var domainNamePattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+\.?$`)
+
+// validateDomain checks the domain settings and expectations against the check type
+func validateDomain(check CheckConfig) error {
+	domain, expected := check.Domain, check.Expected
+
+	if check.Type != types.CheckTypeDomain {
+		if !domain.IsZero() {
+			return fmt.Errorf("domain settings are only supported by domain checks")
+		}
+		if expected.DomainExpiryDays != 0 || expected.DomainLocked {
+			return fmt.Errorf("domain_expiry_days and domain_locked are only supported by domain checks")
+		}
+		return nil
+	}
+
+	if expected.DomainExpiryDays < 0 {
+		return fmt.Errorf("domain_expiry_days cannot be negative")
+	}
+	if domain.CacheTTL < 0 {
+		return fmt.Errorf("domain.cache_ttl cannot be negative")
+	}
+	if domain.RDAPServer != "" && !strings.HasPrefix(domain.RDAPServer, "http://") && !strings.HasPrefix(domain.RDAPServer, "https://") {
+		return fmt.Errorf("domain.rdap_server must be an http:// or https:// URL")
+	}
+	if strings.Contains(domain.WHOISServer, "://") {
+		return fmt.Errorf("domain.whois_server should use host or host:port format")
+	}
+	return nil
+}
+
+// validateJSONMatches checks that JSON assertions have well-formed paths and regexes
+func validateJSONMatches(matches []types.JSONMatch) error {
+	for _, match := range matches {
+		if match.Path == "" {
+			return fmt.Errorf("json assertion path is required")
+		}
+		if err := jsonpath.Validate(match.Path); err != nil {
+			return fmt.Errorf("json assertion: %w", err)
+		}
+		if match.Regex != "" {
+			if _, err := regexp.Compile(match.Regex); err != nil {
+				return fmt.Errorf("json assertion regex for '%s' is invalid: %w", match.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAssertions checks the body regex, the header assertions and the
+// JSON schema, so that mistakes show up when the configuration is loaded
+// rather than as DOWN results
+func validateAssertions(expected types.Expected) error {
+	if expected.BodyRegex != "" {
+		if _, err := regexp.Compile(expected.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body_regex '%s': %w", expected.BodyRegex, err)
+		}
+	}
+	for i, header := range expected.Headers {
+		if strings.TrimSpace(header.Name) == "" {
+			return fmt.Errorf("expected headers[%d]: name is required", i)
+		}
+		if header.Regex != "" {
+			if _, err := regexp.Compile(header.Regex); err != nil {
+				return fmt.Errorf("invalid regex '%s' for header '%s': %w", header.Regex, header.Name, err)
+			}
+		}
+	}
+	if expected.JSONSchema != "" {
+		if _, err := jsonschema.LoadFile(expected.JSONSchema); err != nil {
+			return fmt.Errorf("json_schema %s: %w", expected.JSONSchema, err)
+		}
+	}
+	return nil
+}
+
+// validateBroker checks the broker settings and thresholds against the check type
+func validateBroker(check CheckConfig) error {
+	broker, expected := check.Broker, check.Expected
+
+	if broker.Queue != "" && check.Type != types.CheckTypeAMQP {
+		return fmt.Errorf("broker.queue is only supported by amqp checks")
+	}
+	if (expected.QueueDepthMax > 0 || expected.ConsumersMin > 0) && broker.Queue == "" {
+		return fmt.Errorf("queue_depth_max and consumers_min require broker.queue")
+	}
+	if broker.Topic != "" && check.Type != types.CheckTypeMQTT && check.Type != types.CheckTypeKafka {
+		return fmt.Errorf("broker.topic is only supported by mqtt and kafka checks")
+	}
+	if check.Type == types.CheckTypeMQTT && strings.ContainsAny(broker.Topic, "+#") {
+		return fmt.Errorf("broker.topic must not contain MQTT wildcards")
+	}
+	if broker.QoS < 0 || broker.QoS > 1 {
+		return fmt.Errorf("broker.qos must be 0 or 1, got %d", broker.QoS)
+	}
+	if (broker.ClientID != "" || broker.QoS != 0 || expected.RoundTripMax > 0) && check.Type != types.CheckTypeMQTT {
+		return fmt.Errorf("broker.client_id, broker.qos and round_trip_max are only supported by mqtt checks")
+	}
+	if expected.BrokersMin > 0 && check.Type != types.CheckTypeKafka {
+		return fmt.Errorf("brokers_min is only supported by kafka checks")
+	}
+	return nil
+}
+
 // ApplyDefaults applies default values to checks that don't have them specified
 func (c *Config) ApplyDefaults() {
 	for i := range c.Checks {
@@ -423,16 +1090,54 @@ func (c *Config) ApplyDefaults() {
 		if check.Type == "" {
 			if strings.HasPrefix(check.URL, "http") {
 				check.Type = types.CheckTypeHTTP
+			} else if checkType, ok := checkTypeForScheme(check.URL); ok {
+				check.Type = checkType
 			} else {
 				check.Type = types.CheckTypeTCP
 			}
 		}
 		
+		// Checks without a target are labelled unless a URL is given
+		if check.URL == "" {
+			switch check.Type {
+			case types.CheckTypeExec:
+				check.URL = check.Exec.Command
+			case types.CheckTypeMemory, types.CheckTypeLoad:
+				check.URL = "localhost"
+			case types.CheckTypeProcess:
+				check.URL = check.Process.Name
+				if check.URL == "" {
+					check.URL = check.Process.Cmdline
+				}
+			case types.CheckTypeDocker:
+				check.URL = check.Docker.Container
+				if check.URL == "" {
+					check.URL = dockerLabelSelector(check.Docker.Labels)
+				}
+			default:
+				if !check.Type.IsBuiltin() {
+					check.URL = string(check.Type)
+				}
+			}
+		}
+		
+		// Domain checks warn a month before the registration expires
+		if check.Type == types.CheckTypeDomain && check.Expected.DomainExpiryDays == 0 {
+			check.Expected.DomainExpiryDays = 30
+		}
+		
 		// Apply default method for HTTP checks
 		if check.Type == types.CheckTypeHTTP && check.Method == "" {
 			check.Method = "GET"
 		}
 		
+		// Apply default method for scenario steps
+		for j := range check.Steps {
+			if check.Steps[j].Method == "" {
+				check.Steps[j].Method = "GET"
+			}
+		}
+		
 		// Apply default timeout
 		if check.Timeout == 0 {
 			check.Timeout = c.Global.DefaultTimeout
@@ -443,6 +1148,29 @@ func (c *Config) ApplyDefaults() {
 			check.Interval = c.Global.DefaultInterval
 		}
 		
+		// Apply default response body limit
+		if check.MaxBodySize == 0 {
+			check.MaxBodySize = c.Global.MaxBodySize
+		}
+		
+		// Apply global network settings; per-check values win
+		if check.Proxy == "" {
+			check.Proxy = c.Global.Proxy
+		}
+		if check.Resolver == "" {
+			check.Resolver = c.Global.Resolver
+		}
+		if len(c.Global.Resolve) > 0 {
+			resolve := make(map[string]string, len(c.Global.Resolve)+len(check.Resolve))
+			for host, ip := range c.Global.Resolve {
+				resolve[host] = ip
+			}
+			for host, ip := range check.Resolve {
+				resolve[host] = ip
+			}
+			check.Resolve = resolve
+		}
+		
 		// Apply default expected status
 		if check.Expected.Status == 0 {
 			check.Expected.Status = 200
@@ -509,6 +1237,14 @@ func SaveExample(filePath string) error {
 						Delay:    5 * time.Second,
 						Backoff:  "exponential",
 					},
+					Confirm: types.ConfirmConfig{
+						Failures: 3,
+						Within:   5,
+					},
+					Flapping: types.FlappingConfig{
+						Threshold: 6,
+						Window:    time.Hour,
+					},
 					Tags: []string{"api", "critical"},
 				},
 			},
@@ -612,6 +1348,14 @@ func SaveExample(filePath string) error {
 				EscalationDelay: 15 * time.Minute,
 			},
 		},
+		Maintenance: []types.MaintenanceWindow{
+			{
+				Name:     "Weekly database maintenance",
+				Schedule: "0 3 * * sun", // Sundays at 03:00
+				Duration: time.Hour,
+				Tags:     []string{"database"},
+			},
+		},
 	}
 	
 	data, err := yaml.Marshal(config)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/renancavalcantercb/healthcheck-cli/internal/config"
@@ -77,6 +78,8 @@ func (n *DiscordNotifier) prepareMessage(result types.Result) ([]byte, error) {
 		color = 0xFF0000 // Red
 	case types.StatusSlow:
 		color = 0xFFA500 // Orange
+	case types.StatusFlapping:
+		color = 0xFF00FF // Magenta
 	default:
 		color = 0x808080 // Gray
 	}
@@ -153,6 +156,45 @@ func (n *DiscordNotifier) prepareMessage(result types.Result) ([]byte, error) {
 		})
 	}
 
+	// List the checks that are unreachable because of this failure
+	if len(result.Dependents) > 0 {
+		message.Embeds[0].Fields = append(message.Embeds[0].Fields, struct {
+			Name   string `json:"name"`
+			Value  string `json:"value"`
+			Inline bool   `json:"inline"`
+		}{
+			Name:   fmt.Sprintf("Affected Checks (%d)", len(result.Dependents)),
+			Value:  strings.Join(result.Dependents, ", "),
+			Inline: false,
+		})
+	}
+
+	// Say how often a flapping check changed state
+	if result.Status == types.StatusFlapping {
+		message.Embeds[0].Fields = append(message.Embeds[0].Fields, struct {
+			Name   string `json:"name"`
+			Value  string `json:"value"`
+			Inline bool   `json:"inline"`
+		}{
+			Name:   "State Changes",
+			Value:  fmt.Sprintf("%d in the flapping window; no further alerts until it settles", result.StateChanges),
+			Inline: false,
+		})
+	}
+
+	// Add content diff if the page changed
+	if result.ContentChange != nil && result.ContentChange.Diff != "" {
+		message.Embeds[0].Fields = append(message.Embeds[0].Fields, struct {
+			Name   string `json:"name"`
+			Value  string `json:"value"`
+			Inline bool   `json:"inline"`
+		}{
+			Name:   "Content Diff",
+			Value:  "```diff\n" + truncateDiff(result.ContentChange.Diff, maxDiscordDiff) + "```",
+			Inline: false,
+		})
+	}
+
 	// Convert to JSON
 	content, err := json.Marshal(message)
 	if err != nil {
@@ -162,6 +204,21 @@ func (n *DiscordNotifier) prepareMessage(result types.Result) ([]byte, error) {
 	return content, nil
 }
 
+// maxDiscordDiff keeps the diff field under Discord's 1024 character field limit
+const maxDiscordDiff = 1000
+
+// truncateDiff cuts a diff at the last full line that fits in limit characters
+func truncateDiff(diff string, limit int) string {
+	if len(diff) <= limit {
+		return diff
+	}
+	cut := strings.LastIndex(diff[:limit-4], "\n")
+	if cut < 0 {
+		cut = limit - 5
+	}
+	return diff[:cut+1] + "...\n"
+}
+
 // maskWebhookURL masks the webhook URL for logging
 func maskWebhookURL(url string) string {
 	if len(url) < 20 {
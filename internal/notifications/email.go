@@ -3,6 +3,7 @@ package notifications
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"log"
 	"net"
 	"net/smtp"
@@ -212,6 +213,31 @@ func (n *EmailNotifier) generateAdditionalDetails(result types.Result) string {
 		`, result.Error))
 	}
 
+	if len(result.Dependents) > 0 {
+		details.WriteString(fmt.Sprintf(`
+			<div class="metric">
+				<strong>Affected Checks:</strong> %s
+			</div>
+		`, html.EscapeString(strings.Join(result.Dependents, ", "))))
+	}
+
+	if result.Status == types.StatusFlapping {
+		details.WriteString(fmt.Sprintf(`
+			<div class="metric">
+				<strong>State Changes:</strong> %d in the flapping window; no further alerts until it settles
+			</div>
+		`, result.StateChanges))
+	}
+
+	if result.ContentChange != nil && result.ContentChange.Diff != "" {
+		details.WriteString(fmt.Sprintf(`
+			<div class="metric">
+				<strong>Content Diff:</strong>
+				<pre>%s</pre>
+			</div>
+		`, html.EscapeString(result.ContentChange.Diff)))
+	}
+
 	if result.BodySize > 0 {
 		details.WriteString(fmt.Sprintf(`
 			<div class="metric">
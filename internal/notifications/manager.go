@@ -17,6 +17,7 @@ type Manager struct {
 	emailNotifier    *EmailNotifier
 	discordNotifier  *DiscordNotifier
 	lastNotification map[string]time.Time
+	flapping         map[string]bool // checks already alerted on as FLAPPING
 	mu               sync.RWMutex
 }
 
@@ -27,6 +28,7 @@ func NewManager(config *config.Config) *Manager {
 	manager := &Manager{
 		config:           config,
 		lastNotification: make(map[string]time.Time),
+		flapping:         make(map[string]bool),
 	}
 
 	// Initialize email notifier if enabled
@@ -52,17 +54,44 @@ func NewManager(config *config.Config) *Manager {
 func (m *Manager) Notify(result types.Result) error {
 	log.Printf("📢 Processing notification for %s (Status: %s)", result.Name, result.Status)
 	
-	// Check if we should notify based on rules
-	if !m.shouldNotify(result) {
-		log.Printf("📢 Notification ignored (notification rules)")
+	// Failures behind a failing dependency are reported in the dependency's alert
+	if result.Status == types.StatusUnreachable {
+		log.Printf("📢 Notification suppressed (%s depends on %s)", result.Name, result.UnreachableVia)
 		return nil
 	}
-
-	// Check cooldown
-	if !m.checkCooldown(result.Name) {
-		log.Printf("📢 Notification ignored (cooldown)")
+	
+	// Planned work and silences mute every alert, the result is only stored
+	if result.Maintenance != "" {
+		log.Printf("📢 Notification suppressed (%s is in maintenance: %s)", result.Name, result.Maintenance)
+		return nil
+	}
+	
+	// A flapping check gets a single alert instead of one per state change
+	m.mu.Lock()
+	alerted := m.flapping[result.Name]
+	if result.Status != types.StatusFlapping {
+		delete(m.flapping, result.Name)
+	}
+	m.mu.Unlock()
+	if result.Status == types.StatusFlapping && alerted {
+		log.Printf("📢 Notification suppressed (%s is still flapping)", result.Name)
 		return nil
 	}
+	
+	// Certificate, schema and content changes are opted into per check and always delivered
+	if result.CertChange == nil && result.SchemaChange == nil && result.ContentChange == nil {
+		// Check if we should notify based on rules
+		if !m.shouldNotify(result) {
+			log.Printf("📢 Notification ignored (notification rules)")
+			return nil
+		}
+
+		// Check cooldown
+		if !m.checkCooldown(result.Name) {
+			log.Printf("📢 Notification ignored (cooldown)")
+			return nil
+		}
+	}
 
 	// Send email notification if enabled
 	if m.emailNotifier != nil {
@@ -85,6 +114,9 @@ func (m *Manager) Notify(result types.Result) error {
 	// Update last notification time
 	m.mu.Lock()
 	m.lastNotification[result.Name] = time.Now()
+	if result.Status == types.StatusFlapping {
+		m.flapping[result.Name] = true
+	}
 	m.mu.Unlock()
 
 	return nil
@@ -97,7 +129,7 @@ func (m *Manager) shouldNotify(result types.Result) bool {
 	switch result.Status {
 	case types.StatusUp:
 		return rules.OnSuccess
-	case types.StatusDown:
+	case types.StatusDown, types.StatusFlapping:
 		return rules.OnFailure
 	case types.StatusSlow:
 		return rules.OnSlowResponse
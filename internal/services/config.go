@@ -74,6 +74,7 @@ func (s *ConfigService) GetGlobalConfig() types.GlobalConfig {
 		UserAgent:       s.config.Global.UserAgent,
 		MaxRetries:      s.config.Global.MaxRetries,
 		RetryDelay:      s.config.Global.RetryDelay,
+		PluginsDir:      s.config.Global.PluginsDir,
 	}
 }
 
@@ -86,6 +87,15 @@ func (s *ConfigService) GetNotificationConfig() interface{} {
 	return s.config.Notifications
 }
 
+// GetMaintenanceWindows returns the configured maintenance windows
+func (s *ConfigService) GetMaintenanceWindows() []types.MaintenanceWindow {
+	if s.config == nil {
+		return nil
+	}
+	
+	return s.config.Maintenance
+}
+
 // GetConfig returns the raw configuration (for backward compatibility)
 func (s *ConfigService) GetConfig() *config.Config {
 	return s.config
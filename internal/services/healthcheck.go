@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/circuitbreaker"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/dependency"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/flap"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/maintenance"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/ratelimit"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/security"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/textdiff"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 	"golang.org/x/time/rate"
 )
@@ -22,8 +28,41 @@ type HealthCheckService struct {
 	rateLimiter     ratelimit.Limiter
 	circuitBreakers *circuitbreaker.Manager
 	mu              sync.RWMutex
+
+	// Dependencies between checks, the configuration of each check so a parent
+	// can be checked again for its dependents, and the last status of each check
+	dependencies *dependency.Graph
+	configs      map[string]types.CheckConfig
+	states       map[string]checkState
+	dependencyMu sync.Mutex
+
+	// Confirmed status of each check and its recent state changes
+	flaps  *flap.Tracker
+	flapMu sync.Mutex
+
+	// Maintenance windows from the configuration, and the silences last read
+	// from storage
+	windows        []*maintenance.Window
+	silences       []types.Silence
+	silencesLoaded time.Time
+	windowsMu      sync.RWMutex
+}
+
+// checkState is the last status recorded for a check, and when it was recorded
+type checkState struct {
+	status types.Status
+	at     time.Time
 }
 
+// parentFresh is how long before a dependent's run a parent's healthy status
+// is trusted without checking the parent again. Single runs check parents
+// right before their dependents.
+const parentFresh = 5 * time.Second
+
+// silenceRefresh is how long silences read from storage are reused, so a new
+// silence can take this long to apply
+const silenceRefresh = 15 * time.Second
+
 // NewHealthCheckService creates a new health check service
 func NewHealthCheckService(
 	checkers map[types.CheckType]interfaces.Checker,
@@ -38,10 +77,14 @@ func NewHealthCheckService(
 	}
 	
 	return &HealthCheckService{
-		checkers:    checkers,
-		storage:     storage,
-		notifier:    notifier,
-		rateLimiter: ratelimit.NewPerEndpointLimiter(rateLimitConfig),
+		checkers:     checkers,
+		storage:      storage,
+		notifier:     notifier,
+		rateLimiter:  ratelimit.NewPerEndpointLimiter(rateLimitConfig),
+		dependencies: dependency.New(nil),
+		configs:      make(map[string]types.CheckConfig),
+		states:       make(map[string]checkState),
+		flaps:        flap.New(),
 	}
 }
 
@@ -81,6 +124,10 @@ func NewHealthCheckServiceWithRateLimit(
 		notifier:        notifier,
 		rateLimiter:     limiter,
 		circuitBreakers: cbManager,
+		dependencies:    dependency.New(nil),
+		configs:         make(map[string]types.CheckConfig),
+		states:          make(map[string]checkState),
+		flaps:           flap.New(),
 	}
 }
 
@@ -125,11 +172,16 @@ func NewHealthCheckServiceWithConfig(
 		notifier:        notifier,
 		rateLimiter:     limiter,
 		circuitBreakers: cbManager,
+		dependencies:    dependency.New(nil),
+		configs:         make(map[string]types.CheckConfig),
+		states:          make(map[string]checkState),
+		flaps:           flap.New(),
 	}
 }
 
 // ExecuteCheck performs a single health check with retry logic
 func (s *HealthCheckService) ExecuteCheck(ctx context.Context, check types.CheckConfig) (types.Result, error) {
+	started := time.Now()
 	s.mu.RLock()
 	checker, exists := s.checkers[check.Type]
 	s.mu.RUnlock()
@@ -176,7 +228,7 @@ func (s *HealthCheckService) ExecuteCheck(ctx context.Context, check types.Check
 				if circuitbreaker.IsCircuitBreakerOpen(err) {
 					result = types.Result{
 						Name:         check.Name,
-						URL:          check.URL,
+						URL:          security.RedactURLCredentials(check.URL),
 						Status:       types.StatusDown,
 						Error:        err.Error(),
 						Timestamp:    time.Now(),
@@ -206,9 +258,37 @@ func (s *HealthCheckService) ExecuteCheck(ctx context.Context, check types.Check
 		}
 	}
 
+	// A change of state is only reported once enough results confirm it, but
+	// history keeps what the check observed
+	observed := result
+	s.applyConfirmation(check, &result)
+	confirmed := result.Status
+	
+	// A failure behind a failing dependency is reported on the dependency instead
+	s.applyDependencies(check, &result, started)
+	s.applyMaintenance(check, &result)
+
+	// Compare the certificate key with the previous run before it is overwritten
+	if check.Expected.CertAlertOnChange {
+		s.detectCertChange(&result)
+	}
+	if check.Expected.SchemaAlertOnChange {
+		s.detectSchemaChange(&result)
+	}
+	if check.Expected.ContentAlertOnChange {
+		s.detectContentChange(&result)
+	}
+
 	// Store result if storage is available
 	if s.storage != nil {
-		if err := s.storage.SaveResult(result); err != nil {
+		stored := result
+		if confirmed != observed.Status {
+			stored.Status = observed.Status
+			stored.Error = observed.Error
+			stored.UnreachableVia = ""
+			stored.Dependents = nil
+		}
+		if err := s.storage.SaveResult(stored); err != nil {
 			log.Printf("Warning: failed to save result to storage: %v", err)
 		}
 	}
@@ -225,25 +305,36 @@ func (s *HealthCheckService) ExecuteChecks(ctx context.Context, checks []types.C
 	resultsChan := make(chan types.Result, len(checks))
 	errorsChan := make(chan error, len(checks))
 	
-	var wg sync.WaitGroup
-	
+	s.TrackDependencies(checks)
+	byName := make(map[string][]types.CheckConfig)
 	for _, check := range checks {
-		wg.Add(1)
-		go func(c types.CheckConfig) {
-			defer wg.Done()
-			
-			result, err := s.ExecuteCheck(ctx, c)
-			if err != nil {
-				errorsChan <- fmt.Errorf("check %s failed: %w", c.Name, err)
-				return
+		byName[check.Name] = append(byName[check.Name], check)
+	}
+	
+	// Checks run after the checks they depend on, so their failures can be attributed
+	for _, level := range dependency.New(checks).Levels() {
+		var wg sync.WaitGroup
+		
+		for _, name := range level {
+			for _, check := range byName[name] {
+				wg.Add(1)
+				go func(c types.CheckConfig) {
+					defer wg.Done()
+					
+					result, err := s.ExecuteCheck(ctx, c)
+					if err != nil {
+						errorsChan <- fmt.Errorf("check %s failed: %w", c.Name, err)
+						return
+					}
+					
+					resultsChan <- result
+				}(check)
 			}
-			
-			resultsChan <- result
-		}(check)
+		}
+		
+		wg.Wait()
 	}
 	
-	// Wait for all checks to complete
-	wg.Wait()
 	close(resultsChan)
 	close(errorsChan)
 	
@@ -337,6 +428,8 @@ func (s *HealthCheckService) StartMonitoring(ctx context.Context, checks []types
 		return fmt.Errorf("no checks provided")
 	}
 
+	s.TrackDependencies(checks)
+	
 	var wg sync.WaitGroup
 	
 	for _, check := range checks {
@@ -361,6 +454,112 @@ func (s *HealthCheckService) StartMonitoring(ctx context.Context, checks []types
 	return nil
 }
 
+// detectCertChange raises a WARNING when the leaf certificate key differs from the
+// last one stored for the check
+func (s *HealthCheckService) detectCertChange(result *types.Result) {
+	if s.storage == nil || result.CertInfo == nil {
+		return
+	}
+
+	current := result.CertInfo.SPKIHash
+	s.detectChange(result, "certificate", current, s.storage.GetLastCertSPKI, func(previous string) string {
+		result.CertChange = &types.CertChange{PreviousSPKI: previous, CurrentSPKI: current}
+		return fmt.Sprintf("Certificate changed: public key sha256/%s replaced sha256/%s", current, previous)
+	})
+}
+
+// detectSchemaChange raises a WARNING when the GraphQL schema hash differs from the
+// last one stored for the check
+func (s *HealthCheckService) detectSchemaChange(result *types.Result) {
+	if s.storage == nil || result.GraphQL == nil {
+		return
+	}
+
+	current := result.GraphQL.SchemaHash
+	s.detectChange(result, "schema hash", current, s.storage.GetLastSchemaHash, func(previous string) string {
+		result.SchemaChange = &types.SchemaChange{PreviousHash: previous, CurrentHash: current}
+		return fmt.Sprintf("GraphQL schema changed: %s replaced %s", shortHash(current), shortHash(previous))
+	})
+}
+
+// detectContentChange raises a WARNING with a diff excerpt when the normalized body
+// differs from the version seen last for the check
+func (s *HealthCheckService) detectContentChange(result *types.Result) {
+	if s.storage == nil || result.Content == nil {
+		return
+	}
+
+	var previous *types.ContentVersion
+	load := func(name string) (string, error) {
+		var err error
+		if previous, err = s.storage.GetLastContentVersion(name); err != nil || previous == nil {
+			return "", err
+		}
+		return previous.Hash, nil
+	}
+
+	current := result.Content
+	s.detectChange(result, "content", current.Hash, load, func(string) string {
+		edits := textdiff.Lines(previous.Text, current.Text)
+		added, removed := textdiff.Count(edits)
+		result.ContentChange = &types.ContentChange{
+			PreviousHash: previous.Hash,
+			CurrentHash:  current.Hash,
+			Added:        added,
+			Removed:      removed,
+			Diff: diffExcerpt(textdiff.Unified(edits,
+				"previous (first seen "+previous.FirstSeen.Format(time.RFC3339)+")", "current", 2)),
+		}
+		return fmt.Sprintf("Content changed: %d lines added, %d removed (%s replaced %s)",
+			added, removed, shortHash(current.Hash), shortHash(previous.Hash))
+	})
+}
+
+// detectChange compares the fingerprint current with the one load returns for
+// the check, the last one stored. When they differ, describe records the change
+// on the result and returns the message of the WARNING raised for it. The first
+// run only records a baseline, and a worse status from the check itself is kept.
+func (s *HealthCheckService) detectChange(result *types.Result, what, current string, load func(check string) (string, error), describe func(previous string) string) {
+	if current == "" {
+		return
+	}
+
+	previous, err := load(result.Name)
+	if err != nil {
+		log.Printf("Warning: failed to load previous %s for %s: %v", what, result.Name, err)
+		return
+	}
+	if previous == "" || previous == current {
+		return
+	}
+
+	message := describe(previous)
+	if result.Status == types.StatusUp || result.Status == types.StatusSlow {
+		result.Status = types.StatusWarning
+		result.Error = message
+	}
+}
+
+// maxDiffLines bounds the diff excerpt carried by a content change
+const maxDiffLines = 40
+
+// diffExcerpt keeps the start of a diff short enough for notifications
+func diffExcerpt(diff string) string {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	if len(lines) <= maxDiffLines {
+		return diff
+	}
+	return strings.Join(lines[:maxDiffLines], "\n") + fmt.Sprintf("\n... %d more lines\n", len(lines)-maxDiffLines)
+}
+
+// shortHash abbreviates a hex hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
 // calculateRetryDelay calculates the delay before retry based on backoff strategy
 func (s *HealthCheckService) calculateRetryDelay(retry types.RetryConfig, attempt int) time.Duration {
 	baseDelay := retry.Delay
@@ -386,6 +585,159 @@ func (s *HealthCheckService) calculateRetryDelay(retry types.RetryConfig, attemp
 	}
 }
 
+// TrackDependencies records the dependencies of checks up front, so a parent
+// can be checked again for its dependents before its own first run
+func (s *HealthCheckService) TrackDependencies(checks []types.CheckConfig) {
+	s.dependencyMu.Lock()
+	defer s.dependencyMu.Unlock()
+	
+	for _, check := range checks {
+		s.dependencies.Add(check)
+		s.configs[check.Name] = check
+	}
+}
+
+// applyDependencies marks a failed check UNREACHABLE while a check it depends
+// on is failing, and lists the dependents failing along with a failed check
+func (s *HealthCheckService) applyDependencies(check types.CheckConfig, result *types.Result, started time.Time) {
+	if result.IsCritical() {
+		for _, parent := range check.DependsOn {
+			if status, down := s.parentStatus(parent, started); down {
+				result.Status = types.StatusUnreachable
+				result.UnreachableVia = parent
+				result.Error = fmt.Sprintf("Unreachable because %s is %s: %s", parent, status, result.Error)
+				break
+			}
+		}
+	}
+	
+	s.dependencyMu.Lock()
+	defer s.dependencyMu.Unlock()
+	
+	s.dependencies.Add(check)
+	s.configs[check.Name] = check
+	s.states[check.Name] = checkState{status: result.Status, at: time.Now()}
+	if result.IsCritical() {
+		result.Dependents = nil
+		for _, dependent := range s.dependencies.Dependents(check.Name) {
+			if state, ok := s.states[dependent]; ok && failing(state.status) {
+				result.Dependents = append(result.Dependents, dependent)
+			}
+		}
+	}
+}
+
+// parentStatus returns the status of the parent check and whether it is
+// failing. Monitored checks run on their own tickers, so a dependent often
+// fails before its parent's next run records the outage: a parent that did not
+// fail is checked again unless its status was recorded within parentFresh of
+// the dependent's run starting.
+func (s *HealthCheckService) parentStatus(name string, since time.Time) (types.Status, bool) {
+	s.dependencyMu.Lock()
+	state, checked := s.states[name]
+	parent, known := s.configs[name]
+	s.dependencyMu.Unlock()
+	
+	if checked && (failing(state.status) || state.at.After(since.Add(-parentFresh))) {
+		return state.status, failing(state.status)
+	}
+	if !known {
+		return state.status, false
+	}
+	
+	s.mu.RLock()
+	checker, exists := s.checkers[parent.Type]
+	s.mu.RUnlock()
+	if !exists {
+		return state.status, false
+	}
+	
+	probed := time.Now()
+	result := checker.Check(parent)
+	if !failing(result.Status) {
+		return result.Status, false
+	}
+	
+	// Other dependents of the parent can rely on this until its next run
+	s.dependencyMu.Lock()
+	if current := s.states[name]; current.at.Before(probed) {
+		s.states[name] = checkState{status: result.Status, at: probed}
+	}
+	s.dependencyMu.Unlock()
+	return result.Status, true
+}
+
+// failing reports whether a status makes the checks depending on it unreachable
+func failing(status types.Status) bool {
+	return status == types.StatusDown || status == types.StatusError || status == types.StatusUnreachable
+}
+
+// applyConfirmation reports the confirmed status of a check that asks for
+// confirmation over several results, or FLAPPING while it changes state too
+// often. The confirmed status drives notifications and dependents; the
+// observed one is what gets stored.
+func (s *HealthCheckService) applyConfirmation(check types.CheckConfig, result *types.Result) {
+	if !flap.Enabled(check) {
+		return
+	}
+	
+	s.flapMu.Lock()
+	outcome := s.flaps.Observe(check, result.Status, time.Now())
+	s.flapMu.Unlock()
+	
+	result.Status = outcome.Status
+	result.Unconfirmed = outcome.Unconfirmed
+	result.StateChanges = outcome.Changes
+}
+
+// SetMaintenanceWindows replaces the maintenance windows during which results
+// are flagged and not alerted on
+func (s *HealthCheckService) SetMaintenanceWindows(windows []types.MaintenanceWindow) error {
+	parsed, err := maintenance.NewWindows(windows)
+	if err != nil {
+		return err
+	}
+	
+	s.windowsMu.Lock()
+	defer s.windowsMu.Unlock()
+	s.windows = parsed
+	return nil
+}
+
+// applyMaintenance flags a result produced while a maintenance window or a
+// silence covers its check
+func (s *HealthCheckService) applyMaintenance(check types.CheckConfig, result *types.Result) {
+	now := time.Now()
+	windows, silences := s.maintenanceState(now)
+	result.Maintenance = maintenance.Find(windows, silences, check, now)
+}
+
+// maintenanceState returns the maintenance windows and the silences, reading
+// the silences from storage again once silenceRefresh has passed
+func (s *HealthCheckService) maintenanceState(now time.Time) ([]*maintenance.Window, []types.Silence) {
+	store, ok := s.storage.(interfaces.SilenceStore)
+	
+	s.windowsMu.RLock()
+	windows, silences := s.windows, s.silences
+	fresh := now.Sub(s.silencesLoaded) < silenceRefresh
+	s.windowsMu.RUnlock()
+	if !ok || fresh {
+		return windows, silences
+	}
+	
+	silences, err := store.GetActiveSilences(now)
+	if err != nil {
+		log.Printf("Warning: failed to load silences: %v", err)
+	}
+	
+	// A failed read is retried after silenceRefresh as well
+	s.windowsMu.Lock()
+	s.silences = silences
+	s.silencesLoaded = now
+	s.windowsMu.Unlock()
+	return windows, silences
+}
+
 // AddChecker adds a new checker to the service
 func (s *HealthCheckService) AddChecker(checkType types.CheckType, checker interfaces.Checker) {
 	s.mu.Lock()
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/maintenance"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// SilenceService implements ad-hoc silences kept in storage
+type SilenceService struct {
+	storage interfaces.Storage
+}
+
+// NewSilenceService creates a new silence service
+func NewSilenceService(storage interfaces.Storage) *SilenceService {
+	return &SilenceService{
+		storage: storage,
+	}
+}
+
+// Add silences the named checks and the checks carrying tags for duration, starting now
+func (s *SilenceService) Add(checks, tags []string, reason string, duration time.Duration) (types.Silence, error) {
+	store, err := silenceStore(s.storage)
+	if err != nil {
+		return types.Silence{}, err
+	}
+
+	silence, err := maintenance.NewSilence(checks, tags, reason, time.Now(), duration)
+	if err != nil {
+		return types.Silence{}, err
+	}
+
+	if err := store.SaveSilence(silence); err != nil {
+		return types.Silence{}, err
+	}
+	return silence, nil
+}
+
+// List returns the silences that have not ended yet
+func (s *SilenceService) List() ([]types.Silence, error) {
+	store, err := silenceStore(s.storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.GetActiveSilences(time.Now())
+}
+
+// Remove ends a silence early
+func (s *SilenceService) Remove(id string) error {
+	store, err := silenceStore(s.storage)
+	if err != nil {
+		return err
+	}
+
+	return store.DeleteSilence(id)
+}
+
+// silenceStore returns the silence support of storage
+func silenceStore(storage interfaces.Storage) (interfaces.SilenceStore, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("storage not available - silences require data persistence")
+	}
+
+	store, ok := storage.(interfaces.SilenceStore)
+	if !ok {
+		return nil, fmt.Errorf("storage does not support silences")
+	}
+	return store, nil
+}
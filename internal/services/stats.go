@@ -47,6 +47,15 @@ func (s *StatsService) GetHistory(serviceName string, since time.Time, limit int
 	return s.storage.GetServiceHistory(serviceName, since, limit)
 }
 
+// GetContentVersions retrieves the content versions recorded for a service
+func (s *StatsService) GetContentVersions(serviceName string) ([]types.ContentVersion, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("storage not available - content versions require data persistence")
+	}
+	
+	return s.storage.GetContentVersions(serviceName)
+}
+
 // GetDatabaseInfo retrieves information about the database
 func (s *StatsService) GetDatabaseInfo() (map[string]interface{}, error) {
 	if s.storage == nil {
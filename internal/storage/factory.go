@@ -141,6 +141,16 @@ func findInString(s, substr string) bool {
 	return false
 }
 
+// uptimePercent is the share of successful checks among those that count
+// towards uptime, which leaves out checks run during maintenance. A service
+// checked only during maintenance has had no downtime.
+func uptimePercent(successful, counted int64) float64 {
+	if counted <= 0 {
+		return 100
+	}
+	return (float64(successful) / float64(counted)) * 100
+}
+
 // MigrateFromSQLiteToMemory migrates data from SQLite to Memory storage
 func MigrateFromSQLiteToMemory(sqlitePath, memoryPath string) error {
 	// Try to open SQLite database
@@ -16,6 +16,8 @@ type MemoryStorage struct {
 	mu           sync.RWMutex
 	results      []types.CheckResult
 	services     map[string]*ServiceInfo
+	contents     map[string][]ContentRecord
+	silences     []types.Silence
 	path         string
 	maxResults   int
 	autoSave     bool
@@ -32,12 +34,22 @@ type ServiceInfo struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ContentRecord is a content version as persisted, including its normalized text
+type ContentRecord struct {
+	Hash      string    `json:"hash"`
+	Text      string    `json:"text"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
 // MemoryStorageData represents the persistent data structure
 type MemoryStorageData struct {
-	Results  []types.CheckResult    `json:"results"`
-	Services map[string]*ServiceInfo `json:"services"`
-	Version  string                 `json:"version"`
-	SavedAt  time.Time              `json:"saved_at"`
+	Results  []types.CheckResult        `json:"results"`
+	Services map[string]*ServiceInfo    `json:"services"`
+	Contents map[string][]ContentRecord `json:"contents,omitempty"`
+	Silences []types.Silence            `json:"silences,omitempty"`
+	Version  string                     `json:"version"`
+	SavedAt  time.Time                  `json:"saved_at"`
 }
 
 // NewMemoryStorage creates a new in-memory storage with optional file persistence
@@ -45,6 +57,7 @@ func NewMemoryStorage(filePath string) (*MemoryStorage, error) {
 	storage := &MemoryStorage{
 		results:      make([]types.CheckResult, 0),
 		services:     make(map[string]*ServiceInfo),
+		contents:     make(map[string][]ContentRecord),
 		path:         filePath,
 		maxResults:   10000, // Keep last 10k results
 		autoSave:     filePath != "",
@@ -112,6 +125,11 @@ func (m *MemoryStorage) loadFromFile() error {
 	if m.services == nil {
 		m.services = make(map[string]*ServiceInfo)
 	}
+	m.contents = storageData.Contents
+	if m.contents == nil {
+		m.contents = make(map[string][]ContentRecord)
+	}
+	m.silences = storageData.Silences
 
 	fmt.Printf("📁 Loaded %d results and %d services from %s\n", 
 		len(m.results), len(m.services), m.path)
@@ -129,6 +147,8 @@ func (m *MemoryStorage) saveToFile() error {
 	storageData := MemoryStorageData{
 		Results:  m.results,
 		Services: m.services,
+		Contents: m.contents,
+		Silences: m.silences,
 		Version:  "1.0",
 		SavedAt:  time.Now(),
 	}
@@ -186,6 +206,22 @@ func (m *MemoryStorage) SaveResult(result types.Result) error {
 		BodySize:       result.BodySize,
 		Timestamp:      result.Timestamp,
 		CreatedAt:      time.Now(),
+		FailedStep:     result.FailedStep,
+		Steps:          result.Steps,
+		Timings:        result.Timings,
+		Families:       result.Families,
+		Metrics:        result.Metrics,
+		Maintenance:    result.Maintenance,
+	}
+	if result.CertInfo != nil {
+		checkResult.CertSPKI = result.CertInfo.SPKIHash
+	}
+	if result.GraphQL != nil {
+		checkResult.SchemaHash = result.GraphQL.SchemaHash
+	}
+	if result.Content != nil {
+		checkResult.ContentHash = result.Content.Hash
+		m.saveContentVersion(result.Name, result.Content, result.Timestamp)
 	}
 
 	// Add to results
@@ -258,8 +294,11 @@ func (m *MemoryStorage) GetServiceStats(name string, since time.Time) (*types.Se
 	}
 
 	var totalChecks, successfulChecks, failedChecks int64
+	var maintenanceChecks, countedSuccessful int64
 	var responseTimes []int64
 	var lastCheck, lastSuccess, lastFailure time.Time
+	families := make(map[string]*familyTotals)
+	var familyOrder []string
 
 	for _, result := range m.results {
 		if result.Name != name || result.Timestamp.Before(since) {
@@ -269,6 +308,29 @@ func (m *MemoryStorage) GetServiceStats(name string, since time.Time) (*types.Se
 		totalChecks++
 		responseTimes = append(responseTimes, result.ResponseTimeMs)
 
+		// Checks run during maintenance don't count towards uptime
+		resultFamilies := result.Families
+		if result.Maintenance != "" {
+			maintenanceChecks++
+			resultFamilies = nil
+		} else if result.Status == int(types.StatusUp) {
+			countedSuccessful++
+		}
+
+		for _, family := range resultFamilies {
+			totals, ok := families[family.Family]
+			if !ok {
+				totals = &familyTotals{}
+				families[family.Family] = totals
+				familyOrder = append(familyOrder, family.Family)
+			}
+			totals.total++
+			totals.responseTimeMs += family.ResponseTime.Milliseconds()
+			if family.Status == types.StatusUp {
+				totals.successful++
+			}
+		}
+
 		if result.Timestamp.After(lastCheck) {
 			lastCheck = result.Timestamp
 		}
@@ -296,11 +358,8 @@ func (m *MemoryStorage) GetServiceStats(name string, since time.Time) (*types.Se
 	stats.LastCheck = lastCheck
 	stats.LastSuccess = lastSuccess
 	stats.LastFailure = lastFailure
-
-	// Calculate uptime percentage
-	if totalChecks > 0 {
-		stats.UptimePercent = (float64(successfulChecks) / float64(totalChecks)) * 100
-	}
+	stats.MaintenanceChecks = maintenanceChecks
+	stats.UptimePercent = uptimePercent(countedSuccessful, totalChecks-maintenanceChecks)
 
 	// Calculate response time statistics
 	if len(responseTimes) > 0 {
@@ -316,9 +375,29 @@ func (m *MemoryStorage) GetServiceStats(name string, since time.Time) (*types.Se
 		stats.AvgResponseTimeMs = float64(sum) / float64(len(responseTimes))
 	}
 
+	// Split by address family for ip_version: both checks
+	sort.Strings(familyOrder)
+	for _, name := range familyOrder {
+		totals := families[name]
+		stats.Families = append(stats.Families, types.FamilyStats{
+			Family:            name,
+			TotalChecks:       totals.total,
+			SuccessfulChecks:  totals.successful,
+			UptimePercent:     (float64(totals.successful) / float64(totals.total)) * 100,
+			AvgResponseTimeMs: float64(totals.responseTimeMs) / float64(totals.total),
+		})
+	}
+
 	return stats, nil
 }
 
+// familyTotals accumulates per-family counters while computing stats
+type familyTotals struct {
+	total          int64
+	successful     int64
+	responseTimeMs int64
+}
+
 // GetAllServiceStats returns stats for all services
 func (m *MemoryStorage) GetAllServiceStats(since time.Time) ([]types.ServiceStats, error) {
 	m.mu.RLock()
@@ -370,6 +449,142 @@ func (m *MemoryStorage) GetServiceHistory(name string, since time.Time, limit in
 	return results, nil
 }
 
+// GetLastCertSPKI returns the most recently stored certificate key hash for a service
+func (m *MemoryStorage) GetLastCertSPKI(name string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest types.CheckResult
+	for _, result := range m.results {
+		if result.Name == name && result.CertSPKI != "" && !result.Timestamp.Before(latest.Timestamp) {
+			latest = result
+		}
+	}
+
+	return latest.CertSPKI, nil
+}
+
+// GetLastSchemaHash returns the most recently stored GraphQL schema hash for a service
+func (m *MemoryStorage) GetLastSchemaHash(name string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest types.CheckResult
+	for _, result := range m.results {
+		if result.Name == name && result.SchemaHash != "" && !result.Timestamp.Before(latest.Timestamp) {
+			latest = result
+		}
+	}
+
+	return latest.SchemaHash, nil
+}
+
+// saveContentVersion records a normalized body the first time it is seen and
+// moves its last-seen time forward on later runs; the caller holds the lock
+func (m *MemoryStorage) saveContentVersion(name string, content *types.ContentInfo, seen time.Time) {
+	records := m.contents[name]
+	for i := range records {
+		if records[i].Hash == content.Hash {
+			records[i].LastSeen = seen
+			return
+		}
+	}
+	m.contents[name] = append(records, ContentRecord{
+		Hash:      content.Hash,
+		Text:      content.Text,
+		FirstSeen: seen,
+		LastSeen:  seen,
+	})
+}
+
+// GetLastContentVersion returns the content version seen most recently for a service
+func (m *MemoryStorage) GetLastContentVersion(name string) (*types.ContentVersion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *types.ContentVersion
+	for _, record := range m.contents[name] {
+		if latest == nil || !record.LastSeen.Before(latest.LastSeen) {
+			latest = &types.ContentVersion{
+				Hash:      record.Hash,
+				FirstSeen: record.FirstSeen,
+				LastSeen:  record.LastSeen,
+				Text:      record.Text,
+			}
+		}
+	}
+
+	return latest, nil
+}
+
+// GetContentVersions returns every content version recorded for a service, oldest first
+func (m *MemoryStorage) GetContentVersions(name string) ([]types.ContentVersion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var versions []types.ContentVersion
+	for _, record := range m.contents[name] {
+		versions = append(versions, types.ContentVersion{
+			Hash:      record.Hash,
+			FirstSeen: record.FirstSeen,
+			LastSeen:  record.LastSeen,
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].FirstSeen.Before(versions[j].FirstSeen)
+	})
+
+	return versions, nil
+}
+
+// SaveSilence stores a silence, replacing one with the same ID
+func (m *MemoryStorage) SaveSilence(silence types.Silence) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.silences {
+		if m.silences[i].ID == silence.ID {
+			m.silences[i] = silence
+			return nil
+		}
+	}
+	m.silences = append(m.silences, silence)
+	return nil
+}
+
+// GetActiveSilences returns the silences that have not ended by now,
+// including those that start later, ending soonest first
+func (m *MemoryStorage) GetActiveSilences(now time.Time) ([]types.Silence, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var silences []types.Silence
+	for _, silence := range m.silences {
+		if silence.EndsAt.After(now) {
+			silences = append(silences, silence)
+		}
+	}
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].EndsAt.Before(silences[j].EndsAt)
+	})
+
+	return silences, nil
+}
+
+// DeleteSilence removes a silence before it ends
+func (m *MemoryStorage) DeleteSilence(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, silence := range m.silences {
+		if silence.ID == id {
+			m.silences = append(m.silences[:i], m.silences[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("silence %s not found", id)
+}
+
 // CleanupOldData removes data older than the specified duration
 func (m *MemoryStorage) CleanupOldData(olderThan time.Duration) error {
 	m.mu.Lock()
@@ -392,6 +607,28 @@ func (m *MemoryStorage) CleanupOldData(olderThan time.Duration) error {
 		fmt.Printf("🧹 Cleaned up %d old check results (older than %v)\n", removeCount, olderThan)
 	}
 
+	for name, records := range m.contents {
+		kept := records[:0]
+		for _, record := range records {
+			if !record.LastSeen.Before(cutoff) {
+				kept = append(kept, record)
+			}
+		}
+		if len(kept) == 0 {
+			delete(m.contents, name)
+		} else {
+			m.contents[name] = kept
+		}
+	}
+
+	silences := m.silences[:0]
+	for _, silence := range m.silences {
+		if !silence.EndsAt.Before(cutoff) {
+			silences = append(silences, silence)
+		}
+	}
+	m.silences = silences
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -281,4 +282,229 @@ func TestMemoryStorage_DatabaseInfo(t *testing.T) {
 	assert.Contains(t, info, "memory_usage_bytes")
 	assert.Contains(t, info, "oldest_record")
 	assert.Contains(t, info, "newest_record")
-}
\ No newline at end of file
+}
+
+func TestMemoryStorage_ScenarioSteps(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	result := types.Result{
+		Name:         "Login Flow",
+		URL:          "https://api.example.com",
+		Status:       types.StatusDown,
+		Error:        "Step 'fetch profile' failed: unexpected status 401",
+		ResponseTime: 300 * time.Millisecond,
+		Timestamp:    time.Now(),
+		FailedStep:   "fetch profile",
+		Steps: []types.StepResult{
+			{Name: "login", Status: types.StatusUp, StatusCode: 200, ResponseTime: 120 * time.Millisecond},
+			{Name: "fetch profile", Status: types.StatusDown, StatusCode: 401, ResponseTime: 180 * time.Millisecond, Error: "unexpected status 401"},
+		},
+	}
+	require.NoError(t, storage.SaveResult(result))
+
+	history, err := storage.GetServiceHistory("Login Flow", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "fetch profile", history[0].FailedStep)
+	require.Len(t, history[0].Steps, 2)
+	assert.Equal(t, "login", history[0].Steps[0].Name)
+	assert.Equal(t, 401, history[0].Steps[1].StatusCode)
+}
+
+func TestMemoryStorage_Metrics(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	metrics := []types.Metric{{Label: "load1", Value: 0.42, Warn: "5", Crit: "10", Min: "0"}}
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "Load",
+		URL:       "/usr/lib/nagios/plugins/check_load",
+		Status:    types.StatusUp,
+		Timestamp: time.Now(),
+		Metrics:   metrics,
+	}))
+
+	history, err := storage.GetServiceHistory("Load", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, metrics, history[0].Metrics)
+}
+
+func TestMemoryStorage_LastCertSPKI(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "Site TLS",
+		Status:    types.StatusUp,
+		Timestamp: now,
+		CertInfo:  &types.CertInfo{SPKIHash: "old-key"},
+	}))
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "Site TLS",
+		Status:    types.StatusUp,
+		Timestamp: now.Add(time.Minute),
+		CertInfo:  &types.CertInfo{SPKIHash: "new-key"},
+	}))
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "Site TLS",
+		Status:    types.StatusDown,
+		Timestamp: now.Add(2 * time.Minute),
+	}))
+
+	spki, err := storage.GetLastCertSPKI("Site TLS")
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", spki)
+
+	spki, err = storage.GetLastCertSPKI("Unknown")
+	require.NoError(t, err)
+	assert.Empty(t, spki)
+}
+
+func TestMemoryStorage_LastSchemaHash(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "API",
+		Status:    types.StatusUp,
+		Timestamp: now,
+		GraphQL:   &types.GraphQLInfo{SchemaHash: "old-schema"},
+	}))
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "API",
+		Status:    types.StatusUp,
+		Timestamp: now.Add(time.Minute),
+		GraphQL:   &types.GraphQLInfo{SchemaHash: "new-schema"},
+	}))
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "API",
+		Status:    types.StatusDown,
+		Timestamp: now.Add(2 * time.Minute),
+	}))
+
+	hash, err := storage.GetLastSchemaHash("API")
+	require.NoError(t, err)
+	assert.Equal(t, "new-schema", hash)
+
+	hash, err = storage.GetLastSchemaHash("Unknown")
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+}
+
+func TestMemoryStorage_ContentVersions(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	for i, text := range []string{"operational", "degraded", "operational"} {
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:      "Status page",
+			Status:    types.StatusUp,
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+			Content:   &types.ContentInfo{Hash: "hash-" + text, Text: text},
+		}))
+	}
+
+	version, err := storage.GetLastContentVersion("Status page")
+	require.NoError(t, err)
+	require.NotNil(t, version)
+	assert.Equal(t, "hash-operational", version.Hash)
+	assert.Equal(t, "operational", version.Text)
+	assert.Equal(t, now.Add(2*time.Minute), version.LastSeen)
+
+	versions, err := storage.GetContentVersions("Status page")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "hash-operational", versions[0].Hash)
+	assert.Equal(t, "hash-degraded", versions[1].Hash)
+
+	version, err = storage.GetLastContentVersion("Unknown")
+	require.NoError(t, err)
+	assert.Nil(t, version)
+}
+
+func TestMemoryStorage_Families(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	for i, v6Status := range []types.Status{types.StatusUp, types.StatusDown} {
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:         "Dual API",
+			Status:       v6Status,
+			ResponseTime: 40 * time.Millisecond,
+			Timestamp:    now.Add(time.Duration(i) * time.Minute),
+			Families: []types.FamilyResult{
+				{Family: "IPv4", Status: types.StatusUp, ResponseTime: 20 * time.Millisecond},
+				{Family: "IPv6", Status: v6Status, ResponseTime: 40 * time.Millisecond},
+			},
+		}))
+	}
+
+	stats, err := storage.GetServiceStats("Dual API", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stats.Families, 2)
+	assert.Equal(t, 100.0, stats.Families[0].UptimePercent)
+	assert.Equal(t, 20.0, stats.Families[0].AvgResponseTimeMs)
+	assert.Equal(t, 50.0, stats.Families[1].UptimePercent)
+}
+
+func TestMemoryStorage_Maintenance(t *testing.T) {
+	storage, err := NewMemoryStorage("")
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	for i, status := range []types.Status{types.StatusUp, types.StatusDown, types.StatusDown} {
+		maintenance := ""
+		if i > 0 {
+			maintenance = "silence a1"
+		}
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:        "Postgres",
+			Status:      status,
+			Timestamp:   now.Add(time.Duration(i) * time.Minute),
+			Maintenance: maintenance,
+		}))
+	}
+
+	stats, err := storage.GetServiceStats("Postgres", now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalChecks)
+	assert.Equal(t, int64(2), stats.FailedChecks)
+	assert.Equal(t, int64(2), stats.MaintenanceChecks)
+	assert.Equal(t, 100.0, stats.UptimePercent)
+}
+
+func TestMemoryStorage_Silences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "healthcheck.json")
+	storage, err := NewMemoryStorage(path)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, storage.SaveSilence(types.Silence{ID: "a1", Tags: []string{"db"}, StartsAt: now, EndsAt: now.Add(2 * time.Hour)}))
+	require.NoError(t, storage.SaveSilence(types.Silence{ID: "b2", Checks: []string{"API"}, StartsAt: now, EndsAt: now.Add(time.Hour)}))
+	require.NoError(t, storage.Close())
+
+	storage, err = NewMemoryStorage(path)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	silences, err := storage.GetActiveSilences(now.Add(90 * time.Minute))
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	assert.Equal(t, "a1", silences[0].ID)
+
+	require.NoError(t, storage.DeleteSilence("a1"))
+	assert.EqualError(t, storage.DeleteSilence("a1"), "silence a1 not found")
+}
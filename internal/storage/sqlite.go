@@ -2,9 +2,11 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -70,6 +72,10 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	if err := storage.migrateTables(); err != nil {
+		return nil, fmt.Errorf("failed to migrate tables: %w", err)
+	}
+
 	if err := storage.createIndexes(); err != nil {
 		log.Printf("Warning: failed to create indexes: %v", err)
 	}
@@ -139,6 +145,59 @@ func (s *SQLiteStorage) createTables() error {
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
+	CREATE TABLE IF NOT EXISTS check_steps (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		result_id INTEGER NOT NULL REFERENCES check_results(id) ON DELETE CASCADE,
+		step_index INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		status INTEGER NOT NULL,
+		error TEXT,
+		response_time_ms INTEGER NOT NULL,
+		status_code INTEGER
+	);
+
+	CREATE TABLE IF NOT EXISTS check_families (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		result_id INTEGER NOT NULL REFERENCES check_results(id) ON DELETE CASCADE,
+		family TEXT NOT NULL,
+		status INTEGER NOT NULL,
+		error TEXT,
+		response_time_ms INTEGER NOT NULL,
+		status_code INTEGER
+	);
+
+	CREATE TABLE IF NOT EXISTS check_metrics (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		result_id INTEGER NOT NULL REFERENCES check_results(id) ON DELETE CASCADE,
+		label TEXT NOT NULL,
+		value REAL NOT NULL,
+		unit TEXT,
+		warn TEXT,
+		crit TEXT,
+		min TEXT,
+		max TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS content_versions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		content TEXT NOT NULL,
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL,
+		UNIQUE(name, hash)
+	);
+
+	CREATE TABLE IF NOT EXISTS silences (
+		id TEXT PRIMARY KEY,
+		checks TEXT NOT NULL,
+		tags TEXT NOT NULL,
+		reason TEXT,
+		created_at DATETIME NOT NULL,
+		starts_at DATETIME NOT NULL,
+		ends_at DATETIME NOT NULL
+	);
+
 	CREATE TABLE IF NOT EXISTS service_metadata (
 		name TEXT PRIMARY KEY,
 		url TEXT NOT NULL,
@@ -152,6 +211,70 @@ func (s *SQLiteStorage) createTables() error {
 	return err
 }
 
+// migrateTables adds columns introduced after the initial schema to existing databases
+func (s *SQLiteStorage) migrateTables() error {
+	columns := []struct {
+		table      string
+		name       string
+		definition string
+	}{
+		{"check_results", "failed_step", "TEXT"},
+		{"check_results", "dns_ms", "INTEGER"},
+		{"check_results", "connect_ms", "INTEGER"},
+		{"check_results", "tls_ms", "INTEGER"},
+		{"check_results", "ttfb_ms", "INTEGER"},
+		{"check_results", "transfer_ms", "INTEGER"},
+		{"check_results", "cert_spki", "TEXT"},
+		{"check_results", "schema_hash", "TEXT"},
+		{"check_results", "content_hash", "TEXT"},
+		{"check_results", "maintenance", "TEXT"},
+	}
+
+	for _, column := range columns {
+		exists, err := s.columnExists(column.table, column.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", column.table, column.name, column.definition)
+		if _, err := s.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", column.table, column.name, err)
+		}
+	}
+
+	return nil
+}
+
+// columnExists reports whether a table already has the given column
+func (s *SQLiteStorage) columnExists(table, column string) (bool, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
+
 // createIndexes creates database indexes for better performance
 func (s *SQLiteStorage) createIndexes() error {
 	indexes := []string{
@@ -160,6 +283,11 @@ func (s *SQLiteStorage) createIndexes() error {
 		"CREATE INDEX IF NOT EXISTS idx_check_results_name_timestamp ON check_results(name, timestamp)",
 		"CREATE INDEX IF NOT EXISTS idx_check_results_status ON check_results(status)",
 		"CREATE INDEX IF NOT EXISTS idx_check_results_created_at ON check_results(created_at)",
+		"CREATE INDEX IF NOT EXISTS idx_check_steps_result_id ON check_steps(result_id)",
+		"CREATE INDEX IF NOT EXISTS idx_check_families_result_id ON check_families(result_id)",
+		"CREATE INDEX IF NOT EXISTS idx_check_metrics_result_id ON check_metrics(result_id)",
+		"CREATE INDEX IF NOT EXISTS idx_content_versions_name_last_seen ON content_versions(name, last_seen)",
+		"CREATE INDEX IF NOT EXISTS idx_silences_ends_at ON silences(ends_at)",
 	}
 
 	for _, index := range indexes {
@@ -176,15 +304,58 @@ func (s *SQLiteStorage) SaveResult(result types.Result) error {
 	query := `
 	INSERT INTO check_results (
 		name, url, check_type, status, error, response_time_ms, 
-		status_code, body_size, timestamp
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		status_code, body_size, timestamp, failed_step,
+		dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, cert_spki, schema_hash,
+		content_hash, maintenance
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	// Timing columns stay NULL for checks without a breakdown
+	var dnsMs, connectMs, tlsMs, ttfbMs, transferMs sql.NullInt64
+	if t := result.Timings; t != nil {
+		dnsMs = sql.NullInt64{Int64: t.DNS.Milliseconds(), Valid: true}
+		connectMs = sql.NullInt64{Int64: t.Connect.Milliseconds(), Valid: true}
+		tlsMs = sql.NullInt64{Int64: t.TLS.Milliseconds(), Valid: true}
+		ttfbMs = sql.NullInt64{Int64: t.TTFB.Milliseconds(), Valid: true}
+		transferMs = sql.NullInt64{Int64: t.Transfer.Milliseconds(), Valid: true}
+	}
+
+	// Certificate key hash is kept for change detection
+	var certSPKI sql.NullString
+	if result.CertInfo != nil && result.CertInfo.SPKIHash != "" {
+		certSPKI = sql.NullString{String: result.CertInfo.SPKIHash, Valid: true}
+	}
+
+	// GraphQL schema hash is kept for change detection
+	var schemaHash sql.NullString
+	if result.GraphQL != nil && result.GraphQL.SchemaHash != "" {
+		schemaHash = sql.NullString{String: result.GraphQL.SchemaHash, Valid: true}
+	}
+
+	// Content hash links the result to its entry in content_versions
+	var contentHash sql.NullString
+	if result.Content != nil {
+		contentHash = sql.NullString{String: result.Content.Hash, Valid: true}
+	}
+
+	// Results in a maintenance window or silence are left out of the uptime
+	var maintenance sql.NullString
+	if result.Maintenance != "" {
+		maintenance = sql.NullString{String: result.Maintenance, Valid: true}
+	}
 
 	checkType := "http"
 	if result.URL != "" && !sqliteContains(result.URL, "http") {
 		checkType = "tcp"
 	}
 
-	_, err := s.db.Exec(query,
+	// The result and its breakdowns are stored together or not at all
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save result: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(query,
 		result.Name,
 		result.URL,
 		checkType,
@@ -194,20 +365,310 @@ func (s *SQLiteStorage) SaveResult(result types.Result) error {
 		result.StatusCode,
 		result.BodySize,
 		result.Timestamp,
+		result.FailedStep,
+		dnsMs,
+		connectMs,
+		tlsMs,
+		ttfbMs,
+		transferMs,
+		certSPKI,
+		schemaHash,
+		contentHash,
+		maintenance,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to save result: %w", err)
 	}
 
+	// Save scenario step and address family breakdowns, and exec check metrics
+	if len(result.Steps) > 0 || len(result.Families) > 0 || len(result.Metrics) > 0 {
+		resultID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get result id: %w", err)
+		}
+		if err := saveSteps(tx, resultID, result.Steps); err != nil {
+			return err
+		}
+		if err := saveFamilies(tx, resultID, result.Families); err != nil {
+			return err
+		}
+		if err := saveMetrics(tx, resultID, result.Metrics); err != nil {
+			return err
+		}
+	}
+
+	if result.Content != nil {
+		if err := saveContentVersion(tx, result.Name, result.Content, result.Timestamp); err != nil {
+			return err
+		}
+	}
+
 	// Update service metadata
-	s.updateServiceMetadata(result.Name, result.URL, checkType)
+	updateServiceMetadata(tx, result.Name, result.URL, checkType)
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save result: %w", err)
+	}
+	return nil
+}
+
+// saveSteps saves the per-step results of a scenario check
+func saveSteps(tx *sql.Tx, resultID int64, steps []types.StepResult) error {
+	query := `
+	INSERT INTO check_steps (
+		result_id, step_index, name, status, error, response_time_ms, status_code
+	) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	for i, step := range steps {
+		_, err := tx.Exec(query,
+			resultID,
+			i,
+			step.Name,
+			int(step.Status),
+			step.Error,
+			step.ResponseTime.Milliseconds(),
+			step.StatusCode,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save step %s: %w", step.Name, err)
+		}
+	}
 
 	return nil
 }
 
+// saveFamilies saves the per-family results of an ip_version: both check
+func saveFamilies(tx *sql.Tx, resultID int64, families []types.FamilyResult) error {
+	query := `
+	INSERT INTO check_families (
+		result_id, family, status, error, response_time_ms, status_code
+	) VALUES (?, ?, ?, ?, ?, ?)`
+
+	for _, family := range families {
+		_, err := tx.Exec(query,
+			resultID,
+			family.Family,
+			int(family.Status),
+			family.Error,
+			family.ResponseTime.Milliseconds(),
+			family.StatusCode,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save %s result: %w", family.Family, err)
+		}
+	}
+
+	return nil
+}
+
+// saveMetrics saves the performance data reported by an exec check
+func saveMetrics(tx *sql.Tx, resultID int64, metrics []types.Metric) error {
+	query := `
+	INSERT INTO check_metrics (
+		result_id, label, value, unit, warn, crit, min, max
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, metric := range metrics {
+		_, err := tx.Exec(query,
+			resultID,
+			metric.Label,
+			metric.Value,
+			metric.Unit,
+			metric.Warn,
+			metric.Crit,
+			metric.Min,
+			metric.Max,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save metric %s: %w", metric.Label, err)
+		}
+	}
+
+	return nil
+}
+
+// loadMetrics attaches stored exec check metrics to the given results
+func (s *SQLiteStorage) loadMetrics(results []types.CheckResult) error {
+	query := `
+	SELECT result_id, label, value, unit, warn, crit, min, max
+	FROM check_metrics
+	WHERE result_id IN (%s)
+	ORDER BY result_id, id`
+
+	return s.loadChildren(results, "metrics", query, func(rows *sql.Rows) (int64, func(*types.CheckResult), error) {
+		var resultID int64
+		var metric types.Metric
+		var unit, warn, crit, min, max sql.NullString
+
+		if err := rows.Scan(&resultID, &metric.Label, &metric.Value, &unit, &warn, &crit, &min, &max); err != nil {
+			return 0, nil, err
+		}
+
+		metric.Unit = unit.String
+		metric.Warn = warn.String
+		metric.Crit = crit.String
+		metric.Min = min.String
+		metric.Max = max.String
+
+		return resultID, func(result *types.CheckResult) {
+			result.Metrics = append(result.Metrics, metric)
+		}, nil
+	})
+}
+
+// loadFamilies attaches stored address family results to the given results
+func (s *SQLiteStorage) loadFamilies(results []types.CheckResult) error {
+	query := `
+	SELECT result_id, family, status, error, response_time_ms, status_code
+	FROM check_families
+	WHERE result_id IN (%s)
+	ORDER BY result_id, id`
+
+	return s.loadChildren(results, "address family results", query, func(rows *sql.Rows) (int64, func(*types.CheckResult), error) {
+		var resultID, responseTimeMs int64
+		var family types.FamilyResult
+		var status int
+		var errorStr sql.NullString
+		var statusCode sql.NullInt64
+
+		if err := rows.Scan(&resultID, &family.Family, &status, &errorStr, &responseTimeMs, &statusCode); err != nil {
+			return 0, nil, err
+		}
+
+		family.Status = types.Status(status)
+		family.ResponseTime = time.Duration(responseTimeMs) * time.Millisecond
+		family.Error = errorStr.String
+		family.StatusCode = int(statusCode.Int64)
+
+		return resultID, func(result *types.CheckResult) {
+			result.Families = append(result.Families, family)
+		}, nil
+	})
+}
+
+// loadChildren attaches rows stored per result to the given results. query
+// selects result_id first and has a %s for the list of result IDs; scan reads
+// one row and returns its result ID and a function attaching it.
+func (s *SQLiteStorage) loadChildren(results []types.CheckResult, what, query string, scan func(rows *sql.Rows) (int64, func(*types.CheckResult), error)) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]int, len(results))
+	placeholders := make([]string, 0, len(results))
+	args := make([]interface{}, 0, len(results))
+	for i, result := range results {
+		byID[result.ID] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, result.ID)
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(query, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		resultID, attach, err := scan(rows)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", what, err)
+		}
+		if index, ok := byID[resultID]; ok {
+			attach(&results[index])
+		}
+	}
+
+	return rows.Err()
+}
+
+// getFamilyStats aggregates per-family results of a service
+func (s *SQLiteStorage) getFamilyStats(name string, since time.Time) ([]types.FamilyStats, error) {
+	query := `
+	SELECT 
+		f.family,
+		COUNT(*) as total_checks,
+		SUM(CASE WHEN f.status = 0 THEN 1 ELSE 0 END) as successful_checks,
+		AVG(f.response_time_ms) as avg_response_time_ms
+	FROM check_families f
+	JOIN check_results r ON r.id = f.result_id
+	WHERE r.name = ? AND r.timestamp >= ? AND r.maintenance IS NULL
+	GROUP BY f.family
+	ORDER BY f.family`
+
+	rows, err := s.db.Query(query, name, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address family stats: %w", err)
+	}
+	defer rows.Close()
+
+	var families []types.FamilyStats
+	for rows.Next() {
+		var family types.FamilyStats
+		if err := rows.Scan(&family.Family, &family.TotalChecks, &family.SuccessfulChecks, &family.AvgResponseTimeMs); err != nil {
+			return nil, err
+		}
+		if family.TotalChecks > 0 {
+			family.UptimePercent = (float64(family.SuccessfulChecks) / float64(family.TotalChecks)) * 100
+		}
+		families = append(families, family)
+	}
+
+	return families, rows.Err()
+}
+
+// loadSteps attaches stored scenario steps to the given results
+func (s *SQLiteStorage) loadSteps(results []types.CheckResult) error {
+	query := `
+	SELECT result_id, name, status, error, response_time_ms, status_code
+	FROM check_steps
+	WHERE result_id IN (%s)
+	ORDER BY result_id, step_index`
+
+	return s.loadChildren(results, "scenario steps", query, func(rows *sql.Rows) (int64, func(*types.CheckResult), error) {
+		var resultID, responseTimeMs int64
+		var step types.StepResult
+		var status int
+		var errorStr sql.NullString
+		var statusCode sql.NullInt64
+
+		if err := rows.Scan(&resultID, &step.Name, &status, &errorStr, &responseTimeMs, &statusCode); err != nil {
+			return 0, nil, err
+		}
+
+		step.Status = types.Status(status)
+		step.ResponseTime = time.Duration(responseTimeMs) * time.Millisecond
+		step.Error = errorStr.String
+		step.StatusCode = int(statusCode.Int64)
+
+		return resultID, func(result *types.CheckResult) {
+			result.Steps = append(result.Steps, step)
+		}, nil
+	})
+}
+
+// scanTimings rebuilds a timing breakdown from nullable millisecond columns
+func scanTimings(dns, connect, tls, ttfb, transfer sql.NullInt64) *types.Timings {
+	if !dns.Valid && !connect.Valid && !tls.Valid && !ttfb.Valid && !transfer.Valid {
+		return nil
+	}
+
+	ms := func(v sql.NullInt64) time.Duration {
+		return time.Duration(v.Int64) * time.Millisecond
+	}
+
+	return &types.Timings{
+		DNS:      ms(dns),
+		Connect:  ms(connect),
+		TLS:      ms(tls),
+		TTFB:     ms(ttfb),
+		Transfer: ms(transfer),
+	}
+}
+
 // updateServiceMetadata updates or inserts service metadata
-func (s *SQLiteStorage) updateServiceMetadata(name, url, checkType string) error {
+func updateServiceMetadata(tx *sql.Tx, name, url, checkType string) error {
 	query := `
 	INSERT INTO service_metadata (name, url, check_type, updated_at)
 	VALUES (?, ?, ?, CURRENT_TIMESTAMP)
@@ -216,7 +677,7 @@ func (s *SQLiteStorage) updateServiceMetadata(name, url, checkType string) error
 		check_type = excluded.check_type,
 		updated_at = excluded.updated_at`
 
-	_, err := s.db.Exec(query, name, url, checkType)
+	_, err := tx.Exec(query, name, url, checkType)
 	return err
 }
 
@@ -235,13 +696,17 @@ func (s *SQLiteStorage) GetServiceStats(name string, since time.Time) (*types.Se
 		MAX(response_time_ms) as max_response_time_ms,
 		MAX(timestamp) as last_check,
 		MAX(CASE WHEN status = 0 THEN timestamp END) as last_success,
-		MAX(CASE WHEN status != 0 THEN timestamp END) as last_failure
+		MAX(CASE WHEN status != 0 THEN timestamp END) as last_failure,
+		COUNT(maintenance) as maintenance_checks,
+		SUM(CASE WHEN status = 0 AND maintenance IS NULL THEN 1 ELSE 0 END) as counted_successful
 	FROM check_results 
 	WHERE name = ? AND timestamp >= ?
 	GROUP BY name, url, check_type`
 
 	var stats types.ServiceStats
-	var lastSuccess, lastFailure sql.NullTime
+	// Aggregated timestamps lose their column type and are returned as text
+	var lastCheck, lastSuccess, lastFailure sql.NullString
+	var countedSuccessful int64
 
 	err := s.db.QueryRow(query, name, since).Scan(
 		&stats.Name,
@@ -253,9 +718,11 @@ func (s *SQLiteStorage) GetServiceStats(name string, since time.Time) (*types.Se
 		&stats.AvgResponseTimeMs,
 		&stats.MinResponseTimeMs,
 		&stats.MaxResponseTimeMs,
-		&stats.LastCheck,
+		&lastCheck,
 		&lastSuccess,
 		&lastFailure,
+		&stats.MaintenanceChecks,
+		&countedSuccessful,
 	)
 
 	if err != nil {
@@ -265,22 +732,47 @@ func (s *SQLiteStorage) GetServiceStats(name string, since time.Time) (*types.Se
 		return nil, fmt.Errorf("failed to get service stats: %w", err)
 	}
 
-	// Calculate uptime percentage
-	if stats.TotalChecks > 0 {
-		stats.UptimePercent = (float64(stats.SuccessfulChecks) / float64(stats.TotalChecks)) * 100
-	}
+	stats.UptimePercent = uptimePercent(countedSuccessful, stats.TotalChecks-stats.MaintenanceChecks)
 
 	// Handle nullable timestamps
-	if lastSuccess.Valid {
-		stats.LastSuccess = lastSuccess.Time
-	}
-	if lastFailure.Valid {
-		stats.LastFailure = lastFailure.Time
+	stats.LastCheck = parseSQLiteTime(lastCheck)
+	stats.LastSuccess = parseSQLiteTime(lastSuccess)
+	stats.LastFailure = parseSQLiteTime(lastFailure)
+
+	// Split by address family for ip_version: both checks
+	stats.Families, err = s.getFamilyStats(name, since)
+	if err != nil {
+		return nil, err
 	}
 
 	return &stats, nil
 }
 
+// sqliteTimeFormats are the layouts the SQLite driver writes timestamps in
+var sqliteTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// parseSQLiteTime parses a timestamp returned as text, or returns the zero
+// time when it is NULL or not a timestamp
+func parseSQLiteTime(value sql.NullString) time.Time {
+	if !value.Valid {
+		return time.Time{}
+	}
+	text := strings.TrimSuffix(value.String, "Z")
+	for _, layout := range sqliteTimeFormats {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // GetAllServiceStats returns stats for all services
 func (s *SQLiteStorage) GetAllServiceStats(since time.Time) ([]types.ServiceStats, error) {
 	query := `
@@ -315,7 +807,9 @@ func (s *SQLiteStorage) GetAllServiceStats(since time.Time) ([]types.ServiceStat
 func (s *SQLiteStorage) GetRecentResults(limit int) ([]types.CheckResult, error) {
 	query := `
 	SELECT id, name, url, check_type, status, error, response_time_ms, 
-		   status_code, body_size, timestamp, created_at
+		   status_code, body_size, timestamp, created_at, failed_step,
+		   dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, cert_spki, schema_hash,
+		   content_hash, maintenance
 	FROM check_results 
 	ORDER BY timestamp DESC 
 	LIMIT ?`
@@ -329,8 +823,9 @@ func (s *SQLiteStorage) GetRecentResults(limit int) ([]types.CheckResult, error)
 	var results []types.CheckResult
 	for rows.Next() {
 		var result types.CheckResult
-		var errorStr sql.NullString
+		var errorStr, failedStep, certSPKI, schemaHash, contentHash, maintenance sql.NullString
 		var statusCode, bodySize sql.NullInt64
+		var dnsMs, connectMs, tlsMs, ttfbMs, transferMs sql.NullInt64
 
 		err := rows.Scan(
 			&result.ID,
@@ -344,6 +839,16 @@ func (s *SQLiteStorage) GetRecentResults(limit int) ([]types.CheckResult, error)
 			&bodySize,
 			&result.Timestamp,
 			&result.CreatedAt,
+			&failedStep,
+			&dnsMs,
+			&connectMs,
+			&tlsMs,
+			&ttfbMs,
+			&transferMs,
+			&certSPKI,
+			&schemaHash,
+			&contentHash,
+			&maintenance,
 		)
 
 		if err != nil {
@@ -361,18 +866,212 @@ func (s *SQLiteStorage) GetRecentResults(limit int) ([]types.CheckResult, error)
 		if bodySize.Valid {
 			result.BodySize = bodySize.Int64
 		}
+		if failedStep.Valid {
+			result.FailedStep = failedStep.String
+		}
+		if certSPKI.Valid {
+			result.CertSPKI = certSPKI.String
+		}
+		if schemaHash.Valid {
+			result.SchemaHash = schemaHash.String
+		}
+		if contentHash.Valid {
+			result.ContentHash = contentHash.String
+		}
+		if maintenance.Valid {
+			result.Maintenance = maintenance.String
+		}
+		result.Timings = scanTimings(dnsMs, connectMs, tlsMs, ttfbMs, transferMs)
 
 		results = append(results, result)
 	}
 
+	if err := s.loadSteps(results); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := s.loadFamilies(results); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := s.loadMetrics(results); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	return results, nil
 }
 
+// GetLastCertSPKI returns the most recently stored certificate key hash for a service,
+// or an empty string when none has been recorded
+func (s *SQLiteStorage) GetLastCertSPKI(name string) (string, error) {
+	query := `
+	SELECT cert_spki FROM check_results
+	WHERE name = ? AND cert_spki IS NOT NULL
+	ORDER BY timestamp DESC
+	LIMIT 1`
+
+	var spki string
+	err := s.db.QueryRow(query, name).Scan(&spki)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get last certificate key: %w", err)
+	}
+	return spki, nil
+}
+
+// GetLastSchemaHash returns the most recently stored GraphQL schema hash for a service,
+// or an empty string when none has been recorded
+func (s *SQLiteStorage) GetLastSchemaHash(name string) (string, error) {
+	query := `
+	SELECT schema_hash FROM check_results
+	WHERE name = ? AND schema_hash IS NOT NULL
+	ORDER BY timestamp DESC
+	LIMIT 1`
+
+	var hash string
+	err := s.db.QueryRow(query, name).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get last schema hash: %w", err)
+	}
+	return hash, nil
+}
+
+// saveContentVersion records a normalized body the first time it is seen and
+// moves its last-seen time forward on later runs
+func saveContentVersion(tx *sql.Tx, name string, content *types.ContentInfo, seen time.Time) error {
+	query := `
+	INSERT INTO content_versions (name, hash, content, first_seen, last_seen)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(name, hash) DO UPDATE SET last_seen = excluded.last_seen`
+
+	if _, err := tx.Exec(query, name, content.Hash, content.Text, seen, seen); err != nil {
+		return fmt.Errorf("failed to save content version: %w", err)
+	}
+	return nil
+}
+
+// GetLastContentVersion returns the content version seen most recently for a
+// service, or nil when none has been recorded
+func (s *SQLiteStorage) GetLastContentVersion(name string) (*types.ContentVersion, error) {
+	query := `
+	SELECT hash, content, first_seen, last_seen FROM content_versions
+	WHERE name = ?
+	ORDER BY last_seen DESC
+	LIMIT 1`
+
+	var version types.ContentVersion
+	err := s.db.QueryRow(query, name).Scan(&version.Hash, &version.Text, &version.FirstSeen, &version.LastSeen)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last content version: %w", err)
+	}
+	return &version, nil
+}
+
+// GetContentVersions returns every content version recorded for a service, oldest first
+func (s *SQLiteStorage) GetContentVersions(name string) ([]types.ContentVersion, error) {
+	query := `
+	SELECT hash, first_seen, last_seen FROM content_versions
+	WHERE name = ?
+	ORDER BY first_seen ASC`
+
+	rows, err := s.db.Query(query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []types.ContentVersion
+	for rows.Next() {
+		var version types.ContentVersion
+		if err := rows.Scan(&version.Hash, &version.FirstSeen, &version.LastSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan content version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// SaveSilence stores a silence, replacing one with the same ID
+func (s *SQLiteStorage) SaveSilence(silence types.Silence) error {
+	checks, err := json.Marshal(silence.Checks)
+	if err != nil {
+		return fmt.Errorf("failed to encode silence checks: %w", err)
+	}
+	tags, err := json.Marshal(silence.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to encode silence tags: %w", err)
+	}
+
+	query := `
+	INSERT OR REPLACE INTO silences (id, checks, tags, reason, created_at, starts_at, ends_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	if _, err := s.db.Exec(query, silence.ID, string(checks), string(tags), silence.Reason,
+		silence.CreatedAt, silence.StartsAt, silence.EndsAt); err != nil {
+		return fmt.Errorf("failed to save silence: %w", err)
+	}
+	return nil
+}
+
+// GetActiveSilences returns the silences that have not ended by now,
+// including those that start later, ending soonest first
+func (s *SQLiteStorage) GetActiveSilences(now time.Time) ([]types.Silence, error) {
+	query := `
+	SELECT id, checks, tags, reason, created_at, starts_at, ends_at FROM silences
+	WHERE ends_at > ?
+	ORDER BY ends_at ASC`
+
+	rows, err := s.db.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get silences: %w", err)
+	}
+	defer rows.Close()
+
+	var silences []types.Silence
+	for rows.Next() {
+		var silence types.Silence
+		var checks, tags string
+		var reason sql.NullString
+		if err := rows.Scan(&silence.ID, &checks, &tags, &reason, &silence.CreatedAt, &silence.StartsAt, &silence.EndsAt); err != nil {
+			return nil, fmt.Errorf("failed to scan silence: %w", err)
+		}
+		if err := json.Unmarshal([]byte(checks), &silence.Checks); err != nil {
+			return nil, fmt.Errorf("failed to decode checks of silence %s: %w", silence.ID, err)
+		}
+		if err := json.Unmarshal([]byte(tags), &silence.Tags); err != nil {
+			return nil, fmt.Errorf("failed to decode tags of silence %s: %w", silence.ID, err)
+		}
+		silence.Reason = reason.String
+		silences = append(silences, silence)
+	}
+	return silences, rows.Err()
+}
+
+// DeleteSilence removes a silence before it ends
+func (s *SQLiteStorage) DeleteSilence(id string) error {
+	res, err := s.db.Exec("DELETE FROM silences WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete silence: %w", err)
+	}
+	if deleted, _ := res.RowsAffected(); deleted == 0 {
+		return fmt.Errorf("silence %s not found", id)
+	}
+	return nil
+}
+
 // GetServiceHistory returns historical data for a specific service
 func (s *SQLiteStorage) GetServiceHistory(name string, since time.Time, limit int) ([]types.CheckResult, error) {
 	query := `
 	SELECT id, name, url, check_type, status, error, response_time_ms, 
-		   status_code, body_size, timestamp, created_at
+		   status_code, body_size, timestamp, created_at, failed_step,
+		   dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, cert_spki, schema_hash,
+		   content_hash, maintenance
 	FROM check_results 
 	WHERE name = ? AND timestamp >= ?
 	ORDER BY timestamp DESC 
@@ -387,8 +1086,9 @@ func (s *SQLiteStorage) GetServiceHistory(name string, since time.Time, limit in
 	var results []types.CheckResult
 	for rows.Next() {
 		var result types.CheckResult
-		var errorStr sql.NullString
+		var errorStr, failedStep, certSPKI, schemaHash, contentHash, maintenance sql.NullString
 		var statusCode, bodySize sql.NullInt64
+		var dnsMs, connectMs, tlsMs, ttfbMs, transferMs sql.NullInt64
 
 		err := rows.Scan(
 			&result.ID,
@@ -402,6 +1102,16 @@ func (s *SQLiteStorage) GetServiceHistory(name string, since time.Time, limit in
 			&bodySize,
 			&result.Timestamp,
 			&result.CreatedAt,
+			&failedStep,
+			&dnsMs,
+			&connectMs,
+			&tlsMs,
+			&ttfbMs,
+			&transferMs,
+			&certSPKI,
+			&schemaHash,
+			&contentHash,
+			&maintenance,
 		)
 
 		if err != nil {
@@ -417,10 +1127,36 @@ func (s *SQLiteStorage) GetServiceHistory(name string, since time.Time, limit in
 		if bodySize.Valid {
 			result.BodySize = bodySize.Int64
 		}
+		if failedStep.Valid {
+			result.FailedStep = failedStep.String
+		}
+		if certSPKI.Valid {
+			result.CertSPKI = certSPKI.String
+		}
+		if schemaHash.Valid {
+			result.SchemaHash = schemaHash.String
+		}
+		if contentHash.Valid {
+			result.ContentHash = contentHash.String
+		}
+		if maintenance.Valid {
+			result.Maintenance = maintenance.String
+		}
+		result.Timings = scanTimings(dnsMs, connectMs, tlsMs, ttfbMs, transferMs)
 
 		results = append(results, result)
 	}
 
+	if err := s.loadSteps(results); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := s.loadFamilies(results); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := s.loadMetrics(results); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	return results, nil
 }
 
@@ -439,6 +1175,23 @@ func (s *SQLiteStorage) CleanupOldData(olderThan time.Duration) error {
 		log.Printf("Cleaned up %d old check results (older than %v)", rowsAffected, olderThan)
 	}
 
+	// Remove scenario steps whose parent results were deleted
+	if _, err := s.db.Exec("DELETE FROM check_steps WHERE result_id NOT IN (SELECT id FROM check_results)"); err != nil {
+		log.Printf("Warning: failed to cleanup orphaned scenario steps: %v", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM check_families WHERE result_id NOT IN (SELECT id FROM check_results)"); err != nil {
+		log.Printf("Warning: failed to cleanup orphaned address family results: %v", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM check_metrics WHERE result_id NOT IN (SELECT id FROM check_results)"); err != nil {
+		log.Printf("Warning: failed to cleanup orphaned metrics: %v", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM content_versions WHERE last_seen < ?", cutoff); err != nil {
+		log.Printf("Warning: failed to cleanup old content versions: %v", err)
+	}
+	if _, err := s.db.Exec("DELETE FROM silences WHERE ends_at < ?", cutoff); err != nil {
+		log.Printf("Warning: failed to cleanup expired silences: %v", err)
+	}
+
 	// Vacuum to reclaim space
 	if _, err := s.db.Exec("VACUUM"); err != nil {
 		log.Printf("Warning: failed to vacuum database: %v", err)
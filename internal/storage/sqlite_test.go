@@ -0,0 +1,382 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteStorage(t *testing.T) *SQLiteStorage {
+	t.Helper()
+	storage, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "healthcheck.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	return storage
+}
+
+func TestSQLiteStorage_ScenarioSteps(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	result := types.Result{
+		Name:         "Login Flow",
+		URL:          "https://api.example.com",
+		Status:       types.StatusDown,
+		Error:        "Step 'fetch profile' failed: unexpected status 401",
+		ResponseTime: 300 * time.Millisecond,
+		StatusCode:   401,
+		Timestamp:    time.Now(),
+		FailedStep:   "fetch profile",
+		Steps: []types.StepResult{
+			{Name: "login", Status: types.StatusUp, StatusCode: 200, ResponseTime: 120 * time.Millisecond},
+			{Name: "fetch profile", Status: types.StatusDown, StatusCode: 401, ResponseTime: 180 * time.Millisecond, Error: "unexpected status 401"},
+		},
+	}
+	require.NoError(t, storage.SaveResult(result))
+
+	history, err := storage.GetServiceHistory("Login Flow", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "fetch profile", history[0].FailedStep)
+	require.Len(t, history[0].Steps, 2)
+	assert.Equal(t, "login", history[0].Steps[0].Name)
+	assert.Equal(t, types.StatusUp, history[0].Steps[0].Status)
+	assert.Equal(t, 120*time.Millisecond, history[0].Steps[0].ResponseTime)
+	assert.Equal(t, "unexpected status 401", history[0].Steps[1].Error)
+}
+
+func TestSQLiteStorage_SaveResultIsAtomic(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	_, err := storage.db.Exec("DROP TABLE check_steps")
+	require.NoError(t, err)
+
+	err = storage.SaveResult(types.Result{
+		Name:      "Login Flow",
+		Status:    types.StatusUp,
+		Timestamp: time.Now(),
+		Steps:     []types.StepResult{{Name: "login", Status: types.StatusUp}},
+	})
+	require.Error(t, err)
+
+	// The result row is rolled back with its steps
+	history, err := storage.GetServiceHistory("Login Flow", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestSQLiteStorage_LoadReportsScanErrors(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "Dual API",
+		Status:    types.StatusUp,
+		Timestamp: time.Now(),
+		Families:  []types.FamilyResult{{Family: "IPv4", Status: types.StatusUp}},
+	}))
+	_, err := storage.db.Exec("UPDATE check_families SET status = 'broken'")
+	require.NoError(t, err)
+
+	// History is still returned, without the breakdown that cannot be read
+	history, err := storage.GetServiceHistory("Dual API", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Empty(t, history[0].Families)
+
+	err = storage.loadFamilies(history)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read address family results")
+}
+
+func TestSQLiteStorage_MigratesExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	storage, err := NewSQLiteStorage(path)
+	require.NoError(t, err)
+	_, err = storage.db.Exec("ALTER TABLE check_results DROP COLUMN failed_step")
+	require.NoError(t, err)
+	require.NoError(t, storage.Close())
+
+	// Reopening must add the missing column back
+	storage, err = NewSQLiteStorage(path)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	exists, err := storage.columnExists("check_results", "failed_step")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestSQLiteStorage_Timings(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	withTimings := types.Result{
+		Name:         "Timed API",
+		URL:          "https://api.example.com/health",
+		Status:       types.StatusUp,
+		ResponseTime: 250 * time.Millisecond,
+		StatusCode:   200,
+		Timestamp:    time.Now(),
+		Timings: &types.Timings{
+			DNS:      12 * time.Millisecond,
+			Connect:  30 * time.Millisecond,
+			TLS:      45 * time.Millisecond,
+			TTFB:     150 * time.Millisecond,
+			Transfer: 8 * time.Millisecond,
+		},
+	}
+	require.NoError(t, storage.SaveResult(withTimings))
+
+	withoutTimings := types.Result{
+		Name:         "TCP Service",
+		URL:          "db.example.com:5432",
+		Status:       types.StatusUp,
+		ResponseTime: 5 * time.Millisecond,
+		Timestamp:    time.Now(),
+	}
+	require.NoError(t, storage.SaveResult(withoutTimings))
+
+	history, err := storage.GetServiceHistory("Timed API", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.NotNil(t, history[0].Timings)
+	assert.Equal(t, 12*time.Millisecond, history[0].Timings.DNS)
+	assert.Equal(t, 45*time.Millisecond, history[0].Timings.TLS)
+	assert.Equal(t, 150*time.Millisecond, history[0].Timings.TTFB)
+
+	history, err = storage.GetServiceHistory("TCP Service", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Nil(t, history[0].Timings)
+}
+
+func TestSQLiteStorage_LastCertSPKI(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	spki, err := storage.GetLastCertSPKI("Site TLS")
+	require.NoError(t, err)
+	assert.Empty(t, spki)
+
+	now := time.Now()
+	for i, hash := range []string{"old-key", "new-key"} {
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:      "Site TLS",
+			URL:       "https://example.com",
+			Status:    types.StatusUp,
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+			CertInfo:  &types.CertInfo{CommonName: "example.com", SPKIHash: hash},
+		}))
+	}
+	// A failed check without certificate info must not hide the last key
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "Site TLS",
+		URL:       "https://example.com",
+		Status:    types.StatusDown,
+		Timestamp: now.Add(2 * time.Minute),
+	}))
+
+	spki, err = storage.GetLastCertSPKI("Site TLS")
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", spki)
+}
+
+func TestSQLiteStorage_LastSchemaHash(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	hash, err := storage.GetLastSchemaHash("API")
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	now := time.Now()
+	for i, schemaHash := range []string{"old-schema", "new-schema"} {
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:      "API",
+			URL:       "https://api.example.com/graphql",
+			Status:    types.StatusUp,
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+			GraphQL:   &types.GraphQLInfo{SchemaHash: schemaHash, Types: 12},
+		}))
+	}
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:      "API",
+		URL:       "https://api.example.com/graphql",
+		Status:    types.StatusDown,
+		Timestamp: now.Add(2 * time.Minute),
+	}))
+
+	hash, err = storage.GetLastSchemaHash("API")
+	require.NoError(t, err)
+	assert.Equal(t, "new-schema", hash)
+
+	history, err := storage.GetServiceHistory("API", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Empty(t, history[0].SchemaHash)
+	assert.Equal(t, "new-schema", history[1].SchemaHash)
+}
+
+func TestSQLiteStorage_ContentVersions(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	version, err := storage.GetLastContentVersion("Status page")
+	require.NoError(t, err)
+	assert.Nil(t, version)
+
+	now := time.Now().Truncate(time.Second)
+	for i, text := range []string{"operational", "degraded", "operational"} {
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:      "Status page",
+			URL:       "https://status.example.com",
+			Status:    types.StatusUp,
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+			Content:   &types.ContentInfo{Hash: "hash-" + text, Text: text},
+		}))
+	}
+
+	version, err = storage.GetLastContentVersion("Status page")
+	require.NoError(t, err)
+	require.NotNil(t, version)
+	assert.Equal(t, "hash-operational", version.Hash)
+	assert.Equal(t, "operational", version.Text)
+	assert.True(t, version.FirstSeen.Equal(now))
+	assert.True(t, version.LastSeen.Equal(now.Add(2*time.Minute)))
+
+	versions, err := storage.GetContentVersions("Status page")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "hash-operational", versions[0].Hash)
+	assert.Equal(t, "hash-degraded", versions[1].Hash)
+
+	history, err := storage.GetServiceHistory("Status page", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "hash-operational", history[0].ContentHash)
+	assert.Equal(t, "hash-degraded", history[1].ContentHash)
+}
+
+func TestSQLiteStorage_Families(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	now := time.Now()
+	for i, v6Status := range []types.Status{types.StatusUp, types.StatusDown} {
+		require.NoError(t, storage.SaveResult(types.Result{
+			Name:         "Dual API",
+			URL:          "https://api.example.com/health",
+			Status:       v6Status,
+			ResponseTime: 40 * time.Millisecond,
+			Timestamp:    now.Add(time.Duration(i) * time.Minute),
+			Families: []types.FamilyResult{
+				{Family: "IPv4", Status: types.StatusUp, ResponseTime: 20 * time.Millisecond, StatusCode: 200},
+				{Family: "IPv6", Status: v6Status, ResponseTime: 40 * time.Millisecond},
+			},
+		}))
+	}
+
+	history, err := storage.GetServiceHistory("Dual API", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Len(t, history[0].Families, 2)
+	assert.Equal(t, "IPv4", history[0].Families[0].Family)
+	assert.Equal(t, 200, history[0].Families[0].StatusCode)
+
+	families, err := storage.getFamilyStats("Dual API", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, families, 2)
+	assert.Equal(t, "IPv4", families[0].Family)
+	assert.Equal(t, 100.0, families[0].UptimePercent)
+	assert.Equal(t, "IPv6", families[1].Family)
+	assert.Equal(t, int64(2), families[1].TotalChecks)
+	assert.Equal(t, 50.0, families[1].UptimePercent)
+}
+
+func TestSQLiteStorage_Metrics(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	require.NoError(t, storage.SaveResult(types.Result{
+		Name:         "Disk",
+		URL:          "/usr/lib/nagios/plugins/check_disk",
+		Status:       types.StatusWarning,
+		Error:        "DISK WARNING - free space: / 512 MB (9%)",
+		ResponseTime: 30 * time.Millisecond,
+		Timestamp:    time.Now(),
+		Metrics: []types.Metric{
+			{Label: "/", Value: 5120, Unit: "MB", Warn: "4608", Crit: "5120", Min: "0", Max: "5632"},
+			{Label: "inodes", Value: 91.5, Unit: "%"},
+		},
+	}))
+
+	history, err := storage.GetServiceHistory("Disk", time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, []types.Metric{
+		{Label: "/", Value: 5120, Unit: "MB", Warn: "4608", Crit: "5120", Min: "0", Max: "5632"},
+		{Label: "inodes", Value: 91.5, Unit: "%"},
+	}, history[0].Metrics)
+
+	recent, err := storage.GetRecentResults(10)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Len(t, recent[0].Metrics, 2)
+}
+
+func TestSQLiteStorage_Maintenance(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	now := time.Now()
+	for i, result := range []types.Result{
+		{Status: types.StatusUp},
+		{Status: types.StatusDown, Maintenance: "Nightly deploy"},
+		{Status: types.StatusDown},
+		{Status: types.StatusUp},
+	} {
+		result.Name = "API"
+		result.URL = "https://api.example.com"
+		result.Timestamp = now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, storage.SaveResult(result))
+	}
+
+	stats, err := storage.GetServiceStats("API", now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.TotalChecks)
+	assert.Equal(t, int64(1), stats.MaintenanceChecks)
+	assert.InDelta(t, 66.67, stats.UptimePercent, 0.01)
+	assert.True(t, stats.LastCheck.Equal(now.Add(3*time.Minute)), "last check %v", stats.LastCheck)
+
+	history, err := storage.GetServiceHistory("API", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, "Nightly deploy", history[2].Maintenance)
+	assert.Empty(t, history[0].Maintenance)
+}
+
+func TestSQLiteStorage_Silences(t *testing.T) {
+	storage := newTestSQLiteStorage(t)
+
+	now := time.Now().Truncate(time.Second)
+	db := types.Silence{ID: "a1", Tags: []string{"db"}, Reason: "vacuum", CreatedAt: now, StartsAt: now, EndsAt: now.Add(2 * time.Hour)}
+	api := types.Silence{ID: "b2", Checks: []string{"API"}, CreatedAt: now, StartsAt: now, EndsAt: now.Add(time.Hour)}
+	expired := types.Silence{ID: "c3", Checks: []string{"Web"}, CreatedAt: now, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(-time.Minute)}
+	for _, silence := range []types.Silence{db, api, expired} {
+		require.NoError(t, storage.SaveSilence(silence))
+	}
+
+	silences, err := storage.GetActiveSilences(now)
+	require.NoError(t, err)
+	require.Len(t, silences, 2)
+	assert.Equal(t, "b2", silences[0].ID)
+	assert.Equal(t, []string{"API"}, silences[0].Checks)
+	assert.Empty(t, silences[0].Tags)
+	assert.Equal(t, []string{"db"}, silences[1].Tags)
+	assert.Equal(t, "vacuum", silences[1].Reason)
+	assert.True(t, silences[1].EndsAt.Equal(db.EndsAt))
+
+	require.NoError(t, storage.DeleteSilence("b2"))
+	assert.EqualError(t, storage.DeleteSilence("b2"), "silence b2 not found")
+	silences, err = storage.GetActiveSilences(now)
+	require.NoError(t, err)
+	require.Len(t, silences, 1)
+	assert.Equal(t, "a1", silences[0].ID)
+}
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/dependency"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 )
 
@@ -90,6 +91,9 @@ type Model struct {
 	history       map[string][]types.Result
 	memoryConfig  types.MemoryManagementConfig
 	lastCleanup   time.Time
+	selected      int
+	showDetail    bool
+	dependencies  *dependency.Graph
 }
 
 type Stats struct {
@@ -129,6 +133,11 @@ func NewWithConfig(memConfig types.MemoryManagementConfig) Model {
 	}
 }
 
+// SetDependencies shows the depends_on relations of checks as a tree
+func (m *Model) SetDependencies(checks []types.CheckConfig) {
+	m.dependencies = dependency.New(checks)
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.EnterAltScreen,
@@ -163,6 +172,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showHelp = !m.showHelp
 			return m, nil
 
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+
+		case "down", "j":
+			if m.selected < len(m.orderedResults())-1 {
+				m.selected++
+			}
+			return m, nil
+
+		case "enter":
+			m.showDetail = !m.showDetail
+			return m, nil
+
+		case "esc":
+			m.showDetail = false
+			return m, nil
+
 		case "s":
 			// Sort by response time
 			if m.sortBy == "response_time" {
@@ -376,25 +405,33 @@ func (m Model) View() string {
 		return m.renderHelp()
 	}
 
+	if m.showDetail {
+		if result, ok := m.selectedResult(); ok {
+			return lipgloss.JoinVertical(lipgloss.Left,
+				m.renderHeader(),
+				m.renderDetail(result),
+				m.renderFooter(),
+			)
+		}
+	}
+
 	header := m.renderHeader()
-	httpTable := m.renderHTTPTable()
-	tcpTable := m.renderTCPTable()
+	tableSections := []string{m.renderHTTPTable(), m.renderTCPTable()}
+	if tree := m.renderDependencyTree(); tree != "" {
+		tableSections = append(tableSections, tree)
+	}
 	metrics := m.renderMetrics()
 	footer := m.renderFooter()
 
 	// Layout based on terminal size
 	if m.width < 130 {
 		// Narrow layout - stack vertically
-		return lipgloss.JoinVertical(lipgloss.Left,
-			header,
-			httpTable,
-			tcpTable,
-			metrics,
-			footer,
-		)
+		sections := append([]string{header}, tableSections...)
+		sections = append(sections, metrics, footer)
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
 	} else {
 		// Wide layout - side by side with more space
-		tables := lipgloss.JoinVertical(lipgloss.Left, httpTable, tcpTable)
+		tables := lipgloss.JoinVertical(lipgloss.Left, tableSections...)
 
 		// Add some spacing between tables and metrics
 		tablesWithSpacing := lipgloss.NewStyle().MarginRight(2).Render(tables)
@@ -447,15 +484,20 @@ func (m Model) renderHTTPTable() string {
 		fmt.Sprintf("%-22s %-42s %-14s %-16s", "NAME", "URL", "STATUS", "RESPONSE TIME"))
 	rows = append(rows, header)
 
+	selectedName := m.selectedName()
 	for _, result := range httpResults {
-		name := truncate(result.Name, 20)
+		name := selectionMarker(result.Name, selectedName) + truncate(result.Name, 18)
 		url := truncate(result.URL, 40)
 		status := m.formatStatus(result.Status)
+		if result.Maintenance != "" {
+			status += " 🔧"
+		}
 		responseTime := result.ResponseTime.Truncate(time.Millisecond).String()
 
 		row := tableRowStyle.Render(
 			fmt.Sprintf("%-22s %-42s %-14s %-16s", name, url, status, responseTime))
 		rows = append(rows, row)
+		rows = append(rows, m.familyRows(result, "%-22s %-42s %-14s %-16s")...)
 	}
 
 	table := strings.Join(rows, "\n")
@@ -481,15 +523,20 @@ func (m Model) renderTCPTable() string {
 		fmt.Sprintf("%-22s %-28s %-14s %-16s", "NAME", "HOST:PORT", "STATUS", "LATENCY"))
 	rows = append(rows, header)
 
+	selectedName := m.selectedName()
 	for _, result := range tcpResults {
-		name := truncate(result.Name, 20)
+		name := selectionMarker(result.Name, selectedName) + truncate(result.Name, 18)
 		host := truncate(result.URL, 26)
 		status := m.formatStatus(result.Status)
+		if result.Maintenance != "" {
+			status += " 🔧"
+		}
 		latency := result.ResponseTime.Truncate(time.Millisecond).String()
 
 		row := tableRowStyle.Render(
 			fmt.Sprintf("%-22s %-28s %-14s %-16s", name, host, status, latency))
 		rows = append(rows, row)
+		rows = append(rows, m.familyRows(result, "%-22s %-28s %-14s %-16s")...)
 	}
 
 	table := strings.Join(rows, "\n")
@@ -502,6 +549,69 @@ func (m Model) renderTCPTable() string {
 	))
 }
 
+// renderDependencyTree renders the checks others depend on, with their dependents below them
+func (m Model) renderDependencyTree() string {
+	if m.dependencies == nil {
+		return ""
+	}
+	roots := m.dependencies.Roots()
+	if len(roots) == 0 {
+		return ""
+	}
+
+	statuses := make(map[string]types.Status)
+	for _, result := range m.results {
+		statuses[result.Name] = result.Status
+	}
+
+	var rows []string
+	for _, root := range roots {
+		rows = append(rows, dependencyLabel(root, statuses))
+		rows = append(rows, m.dependencyRows(root, "", statuses)...)
+	}
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("🌳 Dependencies"),
+		"",
+		strings.Join(rows, "\n"),
+	))
+}
+
+// dependencyRows renders the dependents of name as branches of the tree
+func (m Model) dependencyRows(name, indent string, statuses map[string]types.Status) []string {
+	var rows []string
+	children := m.dependencies.Children(name)
+	for i, child := range children {
+		branch, next := "├─ ", "│  "
+		if i == len(children)-1 {
+			branch, next = "└─ ", "   "
+		}
+		rows = append(rows, indent+branch+dependencyLabel(child, statuses))
+		rows = append(rows, m.dependencyRows(child, indent+next, statuses)...)
+	}
+	return rows
+}
+
+// dependencyLabel shows a check with the status of its last result
+func dependencyLabel(name string, statuses map[string]types.Status) string {
+	status, ok := statuses[name]
+	if !ok {
+		return "⚪ " + truncate(name, 30)
+	}
+	return status.Emoji() + " " + truncate(name, 30)
+}
+
+// familyRows renders one indented row per address family of an ip_version: both check
+func (m Model) familyRows(result types.Result, format string) []string {
+	var rows []string
+	for _, family := range result.Families {
+		rows = append(rows, tableRowStyle.Render(fmt.Sprintf(format,
+			"  └ "+family.Family, "", m.formatStatus(family.Status),
+			family.ResponseTime.Truncate(time.Millisecond).String())))
+	}
+	return rows
+}
+
 func (m Model) renderMetrics() string {
 	uptime := fmt.Sprintf("%.1f%%", m.stats.UptimePercent)
 	avgResponse := m.stats.AvgResponse.Truncate(time.Millisecond).String()
@@ -528,6 +638,10 @@ func (m Model) renderMetrics() string {
 			icon := "🔴"
 			if result.Status == types.StatusSlow {
 				icon = "🟡"
+			} else if result.Status == types.StatusUnreachable {
+				icon = "🔗"
+			} else if result.Status == types.StatusFlapping {
+				icon = "🔁"
 			}
 			alerts = append(alerts, fmt.Sprintf("%s %s", icon, truncate(result.Name, 16)))
 		}
@@ -549,12 +663,305 @@ func (m Model) renderMetrics() string {
 	))
 }
 
+// renderDetail renders the detail view for a single service
+func (m Model) renderDetail(result types.Result) string {
+	lines := []string{
+		fmt.Sprintf("🏷️  Name:          %s", result.Name),
+		fmt.Sprintf("🔗 URL:           %s", result.URL),
+		fmt.Sprintf("📍 Status:        %s", m.formatStatus(result.Status)),
+		fmt.Sprintf("⚡ Response Time: %v", result.ResponseTime.Truncate(time.Millisecond)),
+		fmt.Sprintf("🕐 Checked At:    %s", result.Timestamp.Format("2006-01-02 15:04:05")),
+	}
+	if result.Maintenance != "" {
+		lines = append(lines, fmt.Sprintf("🔧 Maintenance:   %s (alerts suppressed)", result.Maintenance))
+	}
+	if result.Unconfirmed != "" {
+		lines = append(lines, fmt.Sprintf("⏳ Unconfirmed:   %s", result.Unconfirmed))
+	}
+	if result.StateChanges > 0 {
+		lines = append(lines, fmt.Sprintf("🔁 State Changes: %d in the flapping window", result.StateChanges))
+	}
+	if result.StatusCode > 0 {
+		lines = append(lines, fmt.Sprintf("🌐 HTTP Status:   %d", result.StatusCode))
+	}
+	if result.BodySize > 0 {
+		size := fmt.Sprintf("%d bytes", result.BodySize)
+		if result.BodyTruncated {
+			size += " (truncated)"
+		}
+		lines = append(lines, fmt.Sprintf("📦 Body Size:     %s", size))
+	}
+	if result.Error != "" {
+		lines = append(lines, fmt.Sprintf("❗ Error:         %s", result.Error))
+	}
+	if result.Message != "" {
+		message, _, _ := strings.Cut(result.Message, "\n")
+		lines = append(lines, fmt.Sprintf("💬 Message:       %s", truncate(message, 60)))
+	}
+
+	if t := result.Timings; t != nil {
+		phases := []struct {
+			label string
+			value time.Duration
+		}{
+			{"DNS Lookup ", t.DNS},
+			{"TCP Connect", t.Connect},
+			{"TLS        ", t.TLS},
+			{"TTFB       ", t.TTFB},
+			{"Transfer   ", t.Transfer},
+		}
+
+		var longest time.Duration
+		for _, phase := range phases {
+			if phase.value > longest {
+				longest = phase.value
+			}
+		}
+
+		title := "⏱️  Timings"
+		if t.Reused {
+			title += " (reused connection)"
+		}
+		lines = append(lines, "", title)
+		for _, phase := range phases {
+			lines = append(lines, fmt.Sprintf("   %s %-10v %s",
+				phase.label, phase.value.Truncate(time.Microsecond), timingBar(phase.value, longest, 30)))
+		}
+	}
+
+	if len(result.Families) > 0 {
+		lines = append(lines, "", "🌍 Address Families")
+		for _, family := range result.Families {
+			line := fmt.Sprintf("   %-5s %s %v", family.Family, m.formatStatus(family.Status),
+				family.ResponseTime.Truncate(time.Millisecond))
+			if family.Error != "" {
+				line += "  " + truncate(family.Error, 50)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if broker := result.Broker; broker != nil {
+		lines = append(lines, "", "📨 Broker")
+		if broker.Server != "" {
+			lines = append(lines, fmt.Sprintf("   Server:     %s", broker.Server))
+		}
+		if broker.Queue != "" {
+			lines = append(lines, fmt.Sprintf("   Queue:      %s  %d ready, %d consumers",
+				truncate(broker.Queue, 30), broker.Messages, broker.Consumers))
+		}
+		if broker.RoundTrip > 0 {
+			lines = append(lines, fmt.Sprintf("   Round trip: %v", broker.RoundTrip.Truncate(time.Microsecond)))
+		}
+		if broker.Partitions > 0 {
+			lines = append(lines, fmt.Sprintf("   Brokers:    %d", broker.Brokers))
+			lines = append(lines, fmt.Sprintf("   Partitions: %d in %d topics, %d offline, %d under-replicated",
+				broker.Partitions, broker.Topics, broker.OfflinePartitions, broker.UnderReplicated))
+		}
+	}
+
+	if mail := result.Mail; mail != nil {
+		lines = append(lines, "", "✉️  Mail")
+		lines = append(lines, fmt.Sprintf("   Banner:     %s", truncate(strings.ReplaceAll(mail.Banner, "\n", " / "), 60)))
+		if mail.StartTLS {
+			lines = append(lines, "   STARTTLS:   yes")
+		}
+		if len(mail.Extensions) > 0 {
+			lines = append(lines, fmt.Sprintf("   Extensions: %s", truncate(strings.Join(mail.Extensions, ", "), 60)))
+		}
+		if mail.Mailbox != "" {
+			lines = append(lines, fmt.Sprintf("   Mailbox:    %s, %d messages", truncate(mail.Mailbox, 30), mail.Messages))
+		}
+	}
+
+	if stream := result.Stream; stream != nil {
+		lines = append(lines, "", "📡 Stream")
+		lines = append(lines, fmt.Sprintf("   Handshake:     %v", stream.Handshake.Truncate(time.Microsecond)))
+		if stream.FirstMessage > 0 {
+			lines = append(lines, fmt.Sprintf("   First message: %v, %d received", stream.FirstMessage.Truncate(time.Microsecond), stream.Messages))
+		}
+		if stream.Event != "" {
+			lines = append(lines, fmt.Sprintf("   Event:         %s", truncate(stream.Event, 30)))
+		}
+		if stream.Message != "" {
+			lines = append(lines, fmt.Sprintf("   Message:       %s", truncate(strings.ReplaceAll(stream.Message, "\n", " / "), 60)))
+		}
+	}
+
+	if domain := result.Domain; domain != nil {
+		lines = append(lines, "", "🌐 Domain")
+		if domain.Registrar != "" {
+			lines = append(lines, fmt.Sprintf("   Registrar: %s", truncate(domain.Registrar, 50)))
+		}
+		if !domain.ExpiryDate.IsZero() {
+			lines = append(lines, fmt.Sprintf("   Expires:   %s, %d days", domain.ExpiryDate.Format("2006-01-02"), domain.DaysToExpiry))
+		}
+		lock := "no"
+		if domain.Locked {
+			lock = "yes"
+		}
+		lines = append(lines, fmt.Sprintf("   Locked:    %s (via %s)", lock, strings.ToUpper(domain.Source)))
+	}
+
+	var dependsOn []string
+	if m.dependencies != nil {
+		dependsOn = m.dependencies.Parents(result.Name)
+	}
+	if len(dependsOn) > 0 || len(result.Dependents) > 0 {
+		lines = append(lines, "", "🌳 Dependencies")
+		if len(dependsOn) > 0 {
+			lines = append(lines, fmt.Sprintf("   Depends on: %s", truncate(strings.Join(dependsOn, ", "), 60)))
+		}
+		if len(result.Dependents) > 0 {
+			lines = append(lines, fmt.Sprintf("   Affected:   %s", truncate(strings.Join(result.Dependents, ", "), 60)))
+		}
+	}
+
+	if len(result.Containers) > 0 {
+		lines = append(lines, "", "🐳 Containers")
+		for _, container := range result.Containers {
+			state := container.State
+			if container.Health != "" {
+				state += ", " + container.Health
+			}
+			lines = append(lines, fmt.Sprintf("   %s  %s, %d restarts", truncate(container.Name, 24), state, container.RestartCount))
+		}
+	}
+
+	if graphQL := result.GraphQL; graphQL != nil {
+		lines = append(lines, "", "🧬 GraphQL Schema")
+		lines = append(lines, fmt.Sprintf("   %d types, sha256 %s", graphQL.Types, truncate(graphQL.SchemaHash, 16)))
+		if change := result.SchemaChange; change != nil {
+			lines = append(lines, fmt.Sprintf("   Changed from %s", truncate(change.PreviousHash, 16)))
+		}
+	}
+
+	if content := result.Content; content != nil {
+		lines = append(lines, "", "📝 Content")
+		lines = append(lines, fmt.Sprintf("   sha256 %s", truncate(content.Hash, 16)))
+		if change := result.ContentChange; change != nil {
+			lines = append(lines, fmt.Sprintf("   Changed from %s: +%d -%d lines", truncate(change.PreviousHash, 16), change.Added, change.Removed))
+			diff := strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n")
+			for i, line := range diff {
+				if i == 10 {
+					lines = append(lines, fmt.Sprintf("   ... %d more lines", len(diff)-i))
+					break
+				}
+				lines = append(lines, "   "+truncate(line, 70))
+			}
+		}
+	}
+
+	if len(result.Metrics) > 0 {
+		lines = append(lines, "", "📈 Metrics")
+		for _, metric := range result.Metrics {
+			line := fmt.Sprintf("   %-20s %g%s", truncate(metric.Label, 20), metric.Value, metric.Unit)
+			if metric.Warn != "" || metric.Crit != "" {
+				line += fmt.Sprintf("  warn %s, crit %s", metric.Warn, metric.Crit)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if cert := result.CertInfo; cert != nil {
+		lines = append(lines, "", "🔐 Certificate")
+		if cert.Protocol != "" {
+			lines = append(lines, fmt.Sprintf("   %s, %s", cert.Protocol, cert.CipherSuite))
+		}
+		if cert.SPKIHash != "" {
+			lines = append(lines, fmt.Sprintf("   SPKI sha256/%s", cert.SPKIHash))
+		}
+		for i, chainCert := range cert.Chain {
+			lines = append(lines, fmt.Sprintf("   %d. %-30s %s %d  %dd", i, truncate(chainCert.Subject, 30),
+				chainCert.KeyAlgorithm, chainCert.KeyBits, chainCert.DaysToExpiry))
+		}
+		for _, finding := range cert.Findings {
+			lines = append(lines, fmt.Sprintf("   [%s] %s", strings.ToUpper(finding.Severity), truncate(finding.Message, 60)))
+		}
+		if revocation := cert.Revocation; revocation != nil {
+			line := fmt.Sprintf("   Revocation: %s", revocation.Status)
+			if revocation.Source != "" {
+				line += " via " + revocation.Source
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if len(result.Steps) > 0 {
+		lines = append(lines, "", "🪜 Steps")
+		for i, step := range result.Steps {
+			line := fmt.Sprintf("   %d. %s %-20s %v", i+1, step.Status.Emoji(), truncate(step.Name, 20),
+				step.ResponseTime.Truncate(time.Millisecond))
+			if step.Error != "" {
+				line += "  " + truncate(step.Error, 40)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("🔎 Service Details"),
+		"",
+		strings.Join(lines, "\n"),
+	))
+}
+
+// timingBar renders a proportional bar for a timing phase
+func timingBar(value, longest time.Duration, width int) string {
+	if longest <= 0 || value <= 0 {
+		return ""
+	}
+	length := int(float64(width) * float64(value) / float64(longest))
+	if length < 1 {
+		length = 1
+	}
+	return lipgloss.NewStyle().Foreground(primaryColor).Render(strings.Repeat("█", length))
+}
+
+// orderedResults returns the visible results in on-screen order
+func (m Model) orderedResults() []types.Result {
+	httpResults := m.filterResults("http")
+	m.sortResults(httpResults)
+	tcpResults := m.filterResults("tcp")
+	m.sortResults(tcpResults)
+	return append(httpResults, tcpResults...)
+}
+
+// selectedResult returns the currently selected result, if any
+func (m Model) selectedResult() (types.Result, bool) {
+	results := m.orderedResults()
+	if len(results) == 0 {
+		return types.Result{}, false
+	}
+	index := m.selected
+	if index >= len(results) {
+		index = len(results) - 1
+	}
+	return results[index], true
+}
+
+func (m Model) selectedName() string {
+	if result, ok := m.selectedResult(); ok {
+		return result.Name
+	}
+	return ""
+}
+
+func selectionMarker(name, selectedName string) string {
+	if name == selectedName {
+		return "▸ "
+	}
+	return "  "
+}
+
 func (m Model) renderFooter() string {
 	shortcuts := []string{
 		"[q] quit",
 		"[r] refresh",
 		"[s] sort",
 		"[f] filter",
+		"[↑/↓] select",
+		"[enter] details",
 		"[h] help",
 	}
 
@@ -586,17 +993,24 @@ KEYBOARD SHORTCUTS:
   r            Manual refresh
   s            Toggle sort (name ↔ response time)
   f            Cycle filter (all → up → down → slow → all)
+  ↑/k, ↓/j     Select a service
+  Enter        Toggle details for the selected service
+  Esc          Close the details view
   h            Toggle this help screen
 
 DASHBOARD SECTIONS:
   🌐 HTTP Services    Web endpoints and APIs
   🔌 TCP Services     Port connectivity checks
   📊 Metrics          Statistics and recent events
+  🌳 Dependencies     Checks and the checks depending on them
 
 STATUS INDICATORS:
   🟢 UP      Service is responding correctly
   🟡 SLOW    Service is responding but slowly
   🔴 DOWN    Service is not responding
+  🔗 UNREACHABLE  A check it depends on is down
+  🔁 FLAPPING     Changing state too often, alerted on once
+  🔧         In a maintenance window or silence, alerts suppressed
 
 Press 'h' again to return to the dashboard.
 `
@@ -617,6 +1031,10 @@ func (m Model) formatStatus(status types.Status) string {
 		return statusDownStyle.Render("🔴 DOWN")
 	case types.StatusSlow:
 		return statusSlowStyle.Render("🟡 SLOW")
+	case types.StatusUnreachable:
+		return lipgloss.NewStyle().Foreground(mutedColor).Render("🔗 UNREACHABLE")
+	case types.StatusFlapping:
+		return statusSlowStyle.Render("🔁 FLAPPING")
 	default:
 		return "❓ UNKNOWN"
 	}
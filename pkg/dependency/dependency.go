@@ -0,0 +1,222 @@
+// Package dependency models the depends_on relations between checks: a check
+// whose parent is down is unreachable rather than down itself.
+package dependency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// Graph holds the dependencies of a set of checks, by check name. It is not
+// safe for concurrent use.
+type Graph struct {
+	names    []string            // checks in the order they were added
+	parents  map[string][]string // check -> the checks it depends on
+	children map[string][]string // check -> the checks depending on it
+}
+
+// New returns the graph of checks
+func New(checks []types.CheckConfig) *Graph {
+	g := &Graph{
+		parents:  make(map[string][]string),
+		children: make(map[string][]string),
+	}
+	for _, check := range checks {
+		g.Add(check)
+	}
+	return g
+}
+
+// Add records the dependencies of check, replacing those recorded before
+// under the same name
+func (g *Graph) Add(check types.CheckConfig) {
+	previous, known := g.parents[check.Name]
+	if !known {
+		g.names = append(g.names, check.Name)
+	}
+	for _, parent := range previous {
+		g.children[parent] = remove(g.children[parent], check.Name)
+	}
+
+	parents := append([]string(nil), check.DependsOn...)
+	g.parents[check.Name] = parents
+	for _, parent := range parents {
+		if !contains(g.children[parent], check.Name) {
+			g.children[parent] = append(g.children[parent], check.Name)
+			sort.Strings(g.children[parent])
+		}
+	}
+}
+
+// Validate checks that every depends_on entry names exactly one other check
+// and that no check depends on itself, directly or through others
+func Validate(checks []types.CheckConfig) error {
+	counts := make(map[string]int)
+	for _, check := range checks {
+		counts[check.Name]++
+	}
+
+	for i, check := range checks {
+		for _, name := range check.DependsOn {
+			if counts[name] == 0 {
+				return fmt.Errorf("check[%d]: depends_on names unknown check '%s'", i, name)
+			}
+			if counts[name] > 1 {
+				return fmt.Errorf("check[%d]: depends_on '%s' is ambiguous, %d checks have that name", i, name, counts[name])
+			}
+		}
+	}
+
+	if cycle := New(checks).Cycle(); cycle != nil {
+		return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// Parents returns the checks name depends on
+func (g *Graph) Parents(name string) []string {
+	return g.parents[name]
+}
+
+// Children returns the checks depending directly on name, sorted
+func (g *Graph) Children(name string) []string {
+	return g.children[name]
+}
+
+// Dependents returns every check depending on name directly or through
+// other checks, sorted
+func (g *Graph) Dependents(name string) []string {
+	seen := map[string]bool{name: true}
+	var dependents []string
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range g.children[current] {
+			if !seen[child] {
+				seen[child] = true
+				dependents = append(dependents, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// Roots returns the checks that others depend on but that depend on nothing
+// themselves, in the order they were added
+func (g *Graph) Roots() []string {
+	var roots []string
+	for _, name := range g.names {
+		if len(g.parents[name]) == 0 && len(g.children[name]) > 0 {
+			roots = append(roots, name)
+		}
+	}
+	return roots
+}
+
+// Cycle returns a dependency cycle as the path of check names leading back
+// to its first check, or nil when the checks are acyclic
+func (g *Graph) Cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		path = append(path, name)
+		for _, parent := range g.parents[name] {
+			switch state[parent] {
+			case visiting:
+				for i, step := range path {
+					if step == parent {
+						return append(append([]string(nil), path[i:]...), parent)
+					}
+				}
+			case unvisited:
+				if cycle := visit(parent); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+
+	for _, name := range g.names {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Levels groups the checks so that each check comes after the checks it
+// depends on. Parents that are not in the graph are ignored, and checks
+// caught in a cycle are placed in the last level.
+func (g *Graph) Levels() [][]string {
+	placed := make(map[string]bool)
+	remaining := append([]string(nil), g.names...)
+
+	var levels [][]string
+	for len(remaining) > 0 {
+		var level, next []string
+		for _, name := range remaining {
+			if g.ready(name, placed) {
+				level = append(level, name)
+			} else {
+				next = append(next, name)
+			}
+		}
+		if len(level) == 0 {
+			return append(levels, next)
+		}
+		for _, name := range level {
+			placed[name] = true
+		}
+		levels = append(levels, level)
+		remaining = next
+	}
+	return levels
+}
+
+// ready reports whether every parent of name in the graph has been placed
+func (g *Graph) ready(name string, placed map[string]bool) bool {
+	for _, parent := range g.parents[name] {
+		if _, known := g.parents[parent]; known && !placed[parent] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(names []string, name string) []string {
+	kept := names[:0]
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
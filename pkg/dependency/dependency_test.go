@@ -0,0 +1,101 @@
+package dependency
+
+import (
+	"testing"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func check(name string, dependsOn ...string) types.CheckConfig {
+	return types.CheckConfig{Name: name, DependsOn: dependsOn}
+}
+
+// network is a router with a database behind it, and services behind both
+func network() *Graph {
+	return New([]types.CheckConfig{
+		check("API", "Database", "Router"),
+		check("Router"),
+		check("Database", "Router"),
+		check("Worker", "Database"),
+		check("Website"),
+	})
+}
+
+func TestGraph_Relations(t *testing.T) {
+	g := network()
+
+	assert.Equal(t, []string{"Database", "Router"}, g.Parents("API"))
+	assert.Equal(t, []string{"API", "Database"}, g.Children("Router"))
+	assert.Equal(t, []string{"API", "Database", "Worker"}, g.Dependents("Router"))
+	assert.Equal(t, []string{"API", "Worker"}, g.Dependents("Database"))
+	assert.Empty(t, g.Dependents("Website"))
+	assert.Equal(t, []string{"Router"}, g.Roots())
+}
+
+func TestGraph_AddReplaces(t *testing.T) {
+	g := network()
+	g.Add(check("API", "Router"))
+
+	assert.Equal(t, []string{"Router"}, g.Parents("API"))
+	assert.Equal(t, []string{"Worker"}, g.Children("Database"))
+	assert.Equal(t, []string{"API", "Database"}, g.Children("Router"))
+}
+
+func TestGraph_Levels(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"Router", "Website"},
+		{"Database"},
+		{"API", "Worker"},
+	}, network().Levels())
+
+	// Parents outside the graph don't hold a check back
+	g := New([]types.CheckConfig{check("API", "Gateway")})
+	assert.Equal(t, [][]string{{"API"}}, g.Levels())
+
+	g = New([]types.CheckConfig{check("A", "B"), check("B", "A"), check("C")})
+	assert.Equal(t, [][]string{{"C"}, {"A", "B"}}, g.Levels())
+}
+
+func TestGraph_Cycle(t *testing.T) {
+	assert.Nil(t, network().Cycle())
+
+	tests := []struct {
+		name   string
+		checks []types.CheckConfig
+		want   []string
+	}{
+		{
+			name:   "self",
+			checks: []types.CheckConfig{check("API", "API")},
+			want:   []string{"API", "API"},
+		},
+		{
+			name:   "two checks",
+			checks: []types.CheckConfig{check("A", "B"), check("B", "A")},
+			want:   []string{"A", "B", "A"},
+		},
+		{
+			name:   "behind a tail",
+			checks: []types.CheckConfig{check("Web", "A"), check("A", "B"), check("B", "C"), check("C", "A")},
+			want:   []string{"A", "B", "C", "A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, New(tt.checks).Cycle())
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate([]types.CheckConfig{check("Router"), check("API", "Router")}))
+
+	assert.EqualError(t, Validate([]types.CheckConfig{check("API", "Router")}),
+		"check[0]: depends_on names unknown check 'Router'")
+	assert.EqualError(t, Validate([]types.CheckConfig{check("Router"), check("Router"), check("API", "Router")}),
+		"check[2]: depends_on 'Router' is ambiguous, 2 checks have that name")
+	assert.EqualError(t, Validate([]types.CheckConfig{check("A", "B"), check("B", "A")}),
+		"dependency cycle: A -> B -> A")
+}
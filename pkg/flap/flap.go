@@ -0,0 +1,160 @@
+// Package flap smooths the status of checks over several results: a check
+// only goes DOWN, or back UP, once enough of its latest results agree, and a
+// check that keeps changing state is reported as FLAPPING instead.
+package flap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// DefaultWindow is the flapping window used when a check sets none
+const DefaultWindow = time.Hour
+
+// maxWithin bounds the results kept per check for confirmation
+const maxWithin = 100
+
+// Outcome is what a tracker reports for one result
+type Outcome struct {
+	Status      types.Status // confirmed status, or FLAPPING
+	Unconfirmed string       // the observed status when it is not confirmed yet
+	Changes     int          // confirmed state changes within the flapping window
+}
+
+// Tracker holds the confirmed state of each check, by check name. It is not
+// safe for concurrent use.
+type Tracker struct {
+	checks map[string]*state
+}
+
+// state is the history of one check
+type state struct {
+	confirmed types.Status
+	failing   bool        // whether the confirmed status is a failure
+	results   []bool      // latest results, true for a failure
+	changes   []time.Time // when the confirmed status went from healthy to failing or back
+	flapping  bool
+}
+
+// New returns an empty tracker. Every check starts out confirmed UP.
+func New() *Tracker {
+	return &Tracker{checks: make(map[string]*state)}
+}
+
+// Enabled reports whether check asks for confirmation or flap detection
+func Enabled(check types.CheckConfig) bool {
+	return check.Confirm.Failures > 1 || check.Flapping.Threshold > 0
+}
+
+// Validate checks the confirm and flapping settings of check
+func Validate(check types.CheckConfig) error {
+	confirm, flapping := check.Confirm, check.Flapping
+	switch {
+	case confirm.Failures < 0:
+		return errors.New("confirm.failures cannot be negative")
+	case confirm.Within < 0:
+		return errors.New("confirm.within cannot be negative")
+	case confirm.Within > 0 && confirm.Failures == 0:
+		return errors.New("confirm.within needs confirm.failures")
+	case confirm.Within > 0 && confirm.Within < confirm.Failures:
+		return fmt.Errorf("confirm.within (%d) must be at least confirm.failures (%d)", confirm.Within, confirm.Failures)
+	case confirm.Within > maxWithin || confirm.Failures > maxWithin:
+		return fmt.Errorf("confirm cannot look at more than %d results", maxWithin)
+	case flapping.Threshold < 0:
+		return errors.New("flapping.threshold cannot be negative")
+	case flapping.Threshold == 1:
+		return errors.New("flapping.threshold must be at least 2")
+	case flapping.Window < 0:
+		return errors.New("flapping.window cannot be negative")
+	case flapping.Window > 0 && flapping.Threshold == 0:
+		return errors.New("flapping.window needs flapping.threshold")
+	}
+	return nil
+}
+
+// Observe records the status a check produced at the given time and returns
+// the status to report for it.
+//
+// A change between healthy and failing (DOWN or ERROR) is confirmed once
+// confirm.failures of the latest confirm.within results agree with it; until
+// then the previous confirmed status is kept. A check becomes FLAPPING when
+// its confirmed status changed flapping.threshold times within the window,
+// and stays so until the changes in the window drop to half the threshold.
+func (t *Tracker) Observe(check types.CheckConfig, status types.Status, at time.Time) Outcome {
+	st, ok := t.checks[check.Name]
+	if !ok {
+		st = &state{confirmed: types.StatusUp}
+		t.checks[check.Name] = st
+	}
+
+	needed, within := check.Confirm.Failures, check.Confirm.Within
+	if needed < 1 {
+		needed = 1
+	}
+	if within < needed {
+		within = needed
+	}
+
+	failed := status == types.StatusDown || status == types.StatusError
+	st.results = append(st.results, failed)
+	if len(st.results) > within {
+		st.results = st.results[len(st.results)-within:]
+	}
+
+	var outcome Outcome
+	if failed == st.failing {
+		st.confirmed = status
+	} else {
+		agreeing := 0
+		for _, result := range st.results {
+			if result == failed {
+				agreeing++
+			}
+		}
+		if agreeing >= needed {
+			st.confirmed = status
+			st.failing = failed
+			st.changes = append(st.changes, at)
+		} else {
+			noun := "successes"
+			if failed {
+				noun = "failures"
+			}
+			outcome.Unconfirmed = fmt.Sprintf("%s, %d of %d %s in the last %d checks", status, agreeing, needed, noun, within)
+		}
+	}
+	outcome.Status = st.confirmed
+
+	threshold := check.Flapping.Threshold
+	if threshold <= 0 {
+		st.changes = nil
+		st.flapping = false
+		return outcome
+	}
+
+	window := check.Flapping.Window
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	recent := st.changes[:0]
+	for _, change := range st.changes {
+		if at.Sub(change) < window {
+			recent = append(recent, change)
+		}
+	}
+	st.changes = recent
+
+	outcome.Changes = len(st.changes)
+	if outcome.Changes >= threshold {
+		st.flapping = true
+	} else if outcome.Changes <= threshold/2 {
+		st.flapping = false
+	}
+	if st.flapping {
+		outcome.Status = types.StatusFlapping
+	}
+	return outcome
+}
@@ -0,0 +1,101 @@
+package flap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_Confirm(t *testing.T) {
+	check := types.CheckConfig{Name: "API", Confirm: types.ConfirmConfig{Failures: 3, Within: 5}}
+	tracker := New()
+	start := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	observe := func(i int, status types.Status) Outcome {
+		return tracker.Observe(check, status, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	// A single blip is not confirmed
+	outcome := observe(0, types.StatusDown)
+	assert.Equal(t, types.StatusUp, outcome.Status)
+	assert.Equal(t, "DOWN, 1 of 3 failures in the last 5 checks", outcome.Unconfirmed)
+	assert.Equal(t, types.StatusSlow, observe(1, types.StatusSlow).Status, "healthy statuses pass through")
+
+	// Three failures out of five confirm DOWN
+	assert.Equal(t, types.StatusSlow, observe(2, types.StatusError).Status)
+	outcome = observe(3, types.StatusDown)
+	assert.Equal(t, types.StatusDown, outcome.Status)
+	assert.Empty(t, outcome.Unconfirmed)
+
+	// Recovering needs three successes as well, the earlier SLOW counting
+	outcome = observe(4, types.StatusUp)
+	assert.Equal(t, types.StatusDown, outcome.Status)
+	assert.Equal(t, "UP, 2 of 3 successes in the last 5 checks", outcome.Unconfirmed)
+	assert.Equal(t, types.StatusUp, observe(5, types.StatusUp).Status)
+
+	// Without confirmation every change is reported right away
+	other := types.CheckConfig{Name: "Web"}
+	assert.Equal(t, types.StatusDown, tracker.Observe(other, types.StatusDown, start).Status)
+	assert.Equal(t, types.StatusUp, tracker.Observe(other, types.StatusUp, start).Status)
+}
+
+func TestTracker_Flapping(t *testing.T) {
+	check := types.CheckConfig{Name: "API", Flapping: types.FlappingConfig{Threshold: 4, Window: time.Hour}}
+	tracker := New()
+	start := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	statuses := []types.Status{types.StatusDown, types.StatusUp, types.StatusDown}
+	for i, status := range statuses {
+		outcome := tracker.Observe(check, status, start.Add(time.Duration(i)*time.Minute))
+		assert.Equal(t, status, outcome.Status)
+		assert.Equal(t, i+1, outcome.Changes)
+	}
+
+	// The fourth change within the hour starts flapping
+	outcome := tracker.Observe(check, types.StatusUp, start.Add(3*time.Minute))
+	assert.Equal(t, types.StatusFlapping, outcome.Status)
+	assert.Equal(t, 4, outcome.Changes)
+	assert.Equal(t, types.StatusFlapping, tracker.Observe(check, types.StatusUp, start.Add(30*time.Minute)).Status)
+
+	// Three changes are still too many to stop flapping
+	outcome = tracker.Observe(check, types.StatusUp, start.Add(time.Hour+30*time.Second))
+	assert.Equal(t, types.StatusFlapping, outcome.Status)
+	assert.Equal(t, 3, outcome.Changes)
+
+	// Once no more than half the threshold is left, the real status shows again
+	outcome = tracker.Observe(check, types.StatusUp, start.Add(time.Hour+90*time.Second))
+	assert.Equal(t, types.StatusUp, outcome.Status)
+	assert.Equal(t, 2, outcome.Changes)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		confirm  types.ConfirmConfig
+		flapping types.FlappingConfig
+		err      string
+	}{
+		{"disabled", types.ConfirmConfig{}, types.FlappingConfig{}, ""},
+		{"valid", types.ConfirmConfig{Failures: 3, Within: 5}, types.FlappingConfig{Threshold: 6, Window: time.Hour}, ""},
+		{"within defaults to failures", types.ConfirmConfig{Failures: 3}, types.FlappingConfig{Threshold: 6}, ""},
+		{"negative failures", types.ConfirmConfig{Failures: -1}, types.FlappingConfig{}, "confirm.failures cannot be negative"},
+		{"within without failures", types.ConfirmConfig{Within: 5}, types.FlappingConfig{}, "confirm.within needs confirm.failures"},
+		{"within below failures", types.ConfirmConfig{Failures: 3, Within: 2}, types.FlappingConfig{}, "confirm.within (2) must be at least confirm.failures (3)"},
+		{"within too large", types.ConfirmConfig{Failures: 3, Within: 500}, types.FlappingConfig{}, "confirm cannot look at more than 100 results"},
+		{"threshold of one", types.ConfirmConfig{}, types.FlappingConfig{Threshold: 1}, "flapping.threshold must be at least 2"},
+		{"window without threshold", types.ConfirmConfig{}, types.FlappingConfig{Window: time.Hour}, "flapping.window needs flapping.threshold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(types.CheckConfig{Name: "API", Confirm: tt.confirm, Flapping: tt.flapping})
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package healthcheck
+
+import (
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// Event is a value sent on the stream returned by Monitor.Events. It is
+// either a ResultEvent or a StateChangeEvent; more event types may be added
+// in minor releases, so type switches should ignore events they don't know.
+type Event interface {
+	event()
+}
+
+// ResultEvent carries the result of one check run
+type ResultEvent struct {
+	Result types.Result
+}
+
+// StateChangeEvent follows the ResultEvent of a run whose status differs from
+// the previous run of the same check. The first run of a check sets its state
+// without a change.
+type StateChangeEvent struct {
+	Check    string       // name of the check
+	Previous types.Status // status of the previous run
+	Current  types.Status // status of this run
+	Result   types.Result // the result that changed the state
+}
+
+func (ResultEvent) event()      {}
+func (StateChangeEvent) event() {}
@@ -0,0 +1,397 @@
+// Package healthcheck embeds the healthcheck-cli monitoring engine in Go
+// programs. A Monitor runs checks with the same checkers, retries, rate
+// limiting, circuit breaking and change detection as the CLI, and reports
+// results through hooks and a typed event stream:
+//
+//	monitor, err := healthcheck.New(
+//		healthcheck.WithChecks(types.CheckConfig{
+//			Name:     "API",
+//			URL:      "https://api.example.com/health",
+//			Interval: 30 * time.Second,
+//		}),
+//		healthcheck.OnStateChange(func(change healthcheck.StateChangeEvent) {
+//			log.Printf("%s is now %s", change.Check, change.Current)
+//		}),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer monitor.Close()
+//
+//	go monitor.Run(ctx)
+//	for event := range monitor.Events() {
+//		...
+//	}
+//
+// # Stability
+//
+// This package follows semantic versioning: within a major version its
+// exported identifiers are not removed or changed incompatibly. New options,
+// event types and struct fields may be added in minor releases. The promise
+// extends to the pkg/types structs used in its API, which only gain fields.
+// It does not cover pkg/interfaces: interfaces.Checker and interfaces.Storage,
+// taken by WithChecker and WithStorage, may gain methods in a minor release,
+// although new capabilities are added as optional interfaces where possible.
+// Packages under internal/ carry no guarantee and cannot be imported.
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/renancavalcantercb/healthcheck-cli/internal/checker"
+	"github.com/renancavalcantercb/healthcheck-cli/internal/config"
+	"github.com/renancavalcantercb/healthcheck-cli/internal/services"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/dependency"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/plugin"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// ErrClosed is returned by calls to a Monitor after Close
+var ErrClosed = errors.New("monitor is closed")
+
+// optionsValidator is implemented by checkers that validate plugin options
+type optionsValidator interface {
+	ValidateOptions(options map[string]interface{}) error
+}
+
+// Monitor runs health checks. It is safe for concurrent use.
+type Monitor struct {
+	service     *services.HealthCheckService
+	config      *config.Config
+	checkers    map[types.CheckType]interfaces.Checker
+	checks      []types.CheckConfig
+	notifier    interfaces.NotificationManager
+	plugins     []*plugin.Plugin
+	storage     interfaces.Storage
+	ownsStorage bool
+
+	onResult      []func(types.Result)
+	onStateChange []func(StateChangeEvent)
+	events        chan Event
+
+	mu      sync.RWMutex
+	states  map[string]types.Status
+	running bool
+	closed  bool
+}
+
+// New creates a monitor with the built-in checkers and the given options
+func New(opts ...Option) (*Monitor, error) {
+	s := &settings{
+		checkers:    checker.NewDefaultCheckers(),
+		eventBuffer: defaultEventBuffer,
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			s.release()
+			return nil, err
+		}
+	}
+
+	cfg := s.config
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	if s.rateLimit != nil {
+		cfg.Global.RateLimit = *s.rateLimit
+	}
+	if s.circuitBreaker != nil {
+		cfg.Global.CircuitBreaker = *s.circuitBreaker
+	}
+
+	var notifier interfaces.NotificationManager
+	if len(s.notifiers) > 0 {
+		notifier = notifierSet(s.notifiers)
+	}
+
+	m := &Monitor{
+		service: services.NewHealthCheckServiceWithConfig(
+			s.checkers,
+			s.storage,
+			notifier,
+			cfg.Global.RateLimit,
+			cfg.Global.CircuitBreaker,
+		),
+		config:        cfg,
+		checkers:      s.checkers,
+		notifier:      notifier,
+		plugins:       s.plugins,
+		storage:       s.storage,
+		ownsStorage:   s.ownsStorage,
+		onResult:      s.onResult,
+		onStateChange: s.onStateChange,
+		events:        make(chan Event, s.eventBuffer),
+		states:        make(map[string]types.Status),
+	}
+
+	// Checks from the configuration file are already complete
+	if s.config != nil {
+		for _, check := range s.config.Checks {
+			if err := m.validateChecker(check.CheckConfig); err != nil {
+				m.Close()
+				return nil, err
+			}
+			m.checks = append(m.checks, check.CheckConfig)
+		}
+	}
+	added, err := m.prepare(s.checks)
+	if err != nil {
+		m.Close()
+		return nil, err
+	}
+	m.checks = append(m.checks, added...)
+	m.service.TrackDependencies(m.checks)
+
+	if err := m.service.SetMaintenanceWindows(append(append([]types.MaintenanceWindow(nil), cfg.Maintenance...), s.maintenance...)); err != nil {
+		m.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Checks returns the checks run by Run, with defaults applied
+func (m *Monitor) Checks() []types.CheckConfig {
+	return append([]types.CheckConfig(nil), m.checks...)
+}
+
+// Run checks every configured check at its interval until ctx is done. Each
+// result is saved, sent to the notifiers and hooks, and published as events.
+// Only one Run may be active at a time.
+func (m *Monitor) Run(ctx context.Context) error {
+	m.mu.Lock()
+	switch {
+	case m.closed:
+		m.mu.Unlock()
+		return ErrClosed
+	case m.running:
+		m.mu.Unlock()
+		return errors.New("monitor is already running")
+	}
+	m.running = true
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.running = false
+		m.mu.Unlock()
+	}()
+
+	if len(m.checks) == 0 {
+		return errors.New("no checks configured")
+	}
+
+	// Checks already started are stopped if a later one cannot start
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, check := range m.checks {
+		results, err := m.service.MonitorEndpoint(ctx, check)
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return fmt.Errorf("check %s: %w", check.Name, err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range results {
+				m.publish(result)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// Check runs a single check once. The check is validated and completed with
+// defaults first, and its result is handled like the results of Run.
+func (m *Monitor) Check(ctx context.Context, check types.CheckConfig) (types.Result, error) {
+	m.mu.RLock()
+	closed := m.closed
+	m.mu.RUnlock()
+	if closed {
+		return types.Result{}, ErrClosed
+	}
+
+	prepared, err := m.prepare([]types.CheckConfig{check})
+	if err != nil {
+		return types.Result{}, err
+	}
+
+	result, err := m.service.ExecuteCheck(ctx, prepared[0])
+	if err != nil {
+		return types.Result{}, err
+	}
+	if m.notifier != nil {
+		if err := m.notifier.Notify(result); err != nil {
+			return result, fmt.Errorf("notify: %w", err)
+		}
+	}
+	m.publish(result)
+	return result, nil
+}
+
+// Events returns the event stream. It is closed by Close; events that do not
+// fit in its buffer are dropped, while hooks still see them.
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// Close stops the checker plugins, closes storage opened by WithStoragePath
+// and closes the event stream. Run should have returned before Close is called.
+func (m *Monitor) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.events)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, p := range m.plugins {
+		if err := p.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if m.ownsStorage && m.storage != nil {
+		if err := m.storage.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// prepare validates checks and applies defaults as the configuration loader does
+func (m *Monitor) prepare(checks []types.CheckConfig) ([]types.CheckConfig, error) {
+	if len(checks) == 0 {
+		return nil, nil
+	}
+
+	cfg := *m.config
+	cfg.Checks = make([]config.CheckConfig, len(checks))
+	for i, check := range checks {
+		if check.Interval == 0 {
+			check.Interval = cfg.Global.DefaultInterval
+		}
+		if check.Timeout == 0 {
+			check.Timeout = cfg.Global.DefaultTimeout
+		}
+		// Dependencies may name the monitor's other checks, so they are validated below
+		check.DependsOn = nil
+		cfg.Checks[i] = config.CheckConfig{CheckConfig: check}
+	}
+	// Maintenance windows were validated with the configuration file
+	cfg.Maintenance = nil
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.ApplyDefaults()
+
+	prepared := make([]types.CheckConfig, len(cfg.Checks))
+	for i, check := range cfg.Checks {
+		if err := m.validateChecker(check.CheckConfig); err != nil {
+			return nil, err
+		}
+		prepared[i] = check.CheckConfig
+		prepared[i].DependsOn = checks[i].DependsOn
+	}
+	if err := m.validateDependencies(prepared); err != nil {
+		return nil, err
+	}
+	return prepared, nil
+}
+
+// validateDependencies checks the dependencies of checks together with the
+// monitor's checks; checks named like a monitor check take its place
+func (m *Monitor) validateDependencies(checks []types.CheckConfig) error {
+	names := make(map[string]bool)
+	for _, check := range checks {
+		names[check.Name] = true
+	}
+	all := append([]types.CheckConfig(nil), checks...)
+	for _, check := range m.checks {
+		if !names[check.Name] {
+			all = append(all, check)
+		}
+	}
+	return dependency.Validate(all)
+}
+
+// validateChecker checks that a checker handles the check and accepts its options
+func (m *Monitor) validateChecker(check types.CheckConfig) error {
+	checker, ok := m.checkers[check.Type]
+	if !ok {
+		return fmt.Errorf("check %s: no checker for type '%s'", check.Name, check.Type)
+	}
+	if validator, ok := checker.(optionsValidator); ok {
+		if err := validator.ValidateOptions(check.Plugin); err != nil {
+			return fmt.Errorf("check %s: plugin options: %w", check.Name, err)
+		}
+	}
+	return nil
+}
+
+// publish records the state of the check and hands the result to hooks and the event stream
+func (m *Monitor) publish(result types.Result) {
+	m.mu.Lock()
+	previous, seen := m.states[result.Name]
+	m.states[result.Name] = result.Status
+	m.mu.Unlock()
+
+	var change *StateChangeEvent
+	if seen && previous != result.Status {
+		change = &StateChangeEvent{
+			Check:    result.Name,
+			Previous: previous,
+			Current:  result.Status,
+			Result:   result,
+		}
+	}
+
+	for _, hook := range m.onResult {
+		hook(result)
+	}
+	if change != nil {
+		for _, hook := range m.onStateChange {
+			hook(*change)
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return
+	}
+	m.send(ResultEvent{Result: result})
+	if change != nil {
+		m.send(*change)
+	}
+}
+
+// send publishes an event unless the buffer is full. The caller holds m.mu.
+func (m *Monitor) send(event Event) {
+	select {
+	case m.events <- event:
+	default:
+	}
+}
+
+// release frees what options acquired when New fails
+func (s *settings) release() {
+	for _, p := range s.plugins {
+		p.Close()
+	}
+	if s.ownsStorage && s.storage != nil {
+		s.storage.Close()
+	}
+}
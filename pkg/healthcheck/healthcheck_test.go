@@ -0,0 +1,314 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChecker reports the statuses it is given in turn, then repeats the last one
+type fakeChecker struct {
+	mu       sync.Mutex
+	statuses []types.Status
+}
+
+func (c *fakeChecker) Check(check types.CheckConfig) types.Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := c.statuses[0]
+	if len(c.statuses) > 1 {
+		c.statuses = c.statuses[1:]
+	}
+	return types.Result{Name: check.Name, URL: check.URL, Status: status, Timestamp: time.Now()}
+}
+
+func (c *fakeChecker) Name() string {
+	return "Fake"
+}
+
+func (c *fakeChecker) ValidateOptions(options map[string]interface{}) error {
+	if _, ok := options["zone"]; !ok {
+		return errors.New("zone is required")
+	}
+	return nil
+}
+
+// notifierFunc adapts a function to Notifier
+type notifierFunc func(types.Result) error
+
+func (f notifierFunc) Notify(result types.Result) error {
+	return f(result)
+}
+
+func fakeCheck(name string) types.CheckConfig {
+	return types.CheckConfig{
+		Name:     name,
+		Type:     "fake",
+		Interval: 10 * time.Millisecond,
+		Timeout:  5 * time.Millisecond,
+		Retry:    types.RetryConfig{Attempts: 1},
+		Plugin:   map[string]interface{}{"zone": "eu"},
+	}
+}
+
+// newMonitor creates a monitor with a fake checker and without rate limiting
+func newMonitor(t *testing.T, checker *fakeChecker, opts ...Option) *Monitor {
+	t.Helper()
+
+	opts = append([]Option{
+		WithChecker("fake", checker),
+		WithRateLimit(types.RateLimitConfig{}),
+		WithCircuitBreaker(types.CircuitBreakerConfig{}),
+	}, opts...)
+	monitor, err := New(opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { monitor.Close() })
+	return monitor
+}
+
+func TestMonitor_Check(t *testing.T) {
+	checker := &fakeChecker{statuses: []types.Status{types.StatusUp, types.StatusUp, types.StatusDown, types.StatusUp}}
+
+	var results []types.Result
+	var changes []StateChangeEvent
+	var notified int
+	monitor := newMonitor(t, checker,
+		OnResult(func(result types.Result) { results = append(results, result) }),
+		OnStateChange(func(change StateChangeEvent) { changes = append(changes, change) }),
+		WithNotifier(notifierFunc(func(types.Result) error {
+			notified++
+			return nil
+		})),
+	)
+
+	for range 4 {
+		result, err := monitor.Check(context.Background(), fakeCheck("Zone"))
+		require.NoError(t, err)
+		assert.Equal(t, "fake", result.URL, "the URL defaults to the check type")
+	}
+
+	assert.Len(t, results, 4)
+	assert.Equal(t, 4, notified)
+	require.Len(t, changes, 2)
+	assert.Equal(t, types.StatusUp, changes[0].Previous)
+	assert.Equal(t, types.StatusDown, changes[0].Current)
+	assert.Equal(t, types.StatusDown, changes[1].Previous)
+	assert.Equal(t, types.StatusUp, changes[1].Current)
+
+	var kinds []string
+	for range 6 {
+		switch event := (<-monitor.Events()).(type) {
+		case ResultEvent:
+			kinds = append(kinds, "result:"+event.Result.Status.String())
+		case StateChangeEvent:
+			kinds = append(kinds, "change:"+event.Current.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"result:UP", "result:UP",
+		"result:DOWN", "change:DOWN",
+		"result:UP", "change:UP",
+	}, kinds)
+}
+
+func TestMonitor_Run(t *testing.T) {
+	monitor := newMonitor(t, &fakeChecker{statuses: []types.Status{types.StatusUp}},
+		WithChecks(fakeCheck("First"), fakeCheck("Second")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+
+	seen := map[string]int{}
+	for seen["First"] < 2 || seen["Second"] < 2 {
+		select {
+		case event := <-monitor.Events():
+			if result, ok := event.(ResultEvent); ok {
+				seen[result.Result.Name]++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only received %v", seen)
+		}
+	}
+
+	assert.EqualError(t, monitor.Run(ctx), "monitor is already running")
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+
+	require.NoError(t, monitor.Close())
+	assert.ErrorIs(t, monitor.Run(context.Background()), ErrClosed)
+	_, err := monitor.Check(context.Background(), fakeCheck("Zone"))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestNew_InvalidChecks(t *testing.T) {
+	checker := &fakeChecker{statuses: []types.Status{types.StatusUp}}
+
+	tests := []struct {
+		name  string
+		check types.CheckConfig
+		err   string
+	}{
+		{
+			name:  "unknown type",
+			check: types.CheckConfig{Name: "Directory", Type: "ldap"},
+			err:   "check Directory: no checker for type 'ldap'",
+		},
+		{
+			name:  "options rejected by the checker",
+			check: types.CheckConfig{Name: "Zone", Type: "fake"},
+			err:   "check Zone: plugin options: zone is required",
+		},
+		{
+			name:  "missing URL",
+			check: types.CheckConfig{Name: "API", Type: types.CheckTypeHTTP},
+			err:   "URL is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithChecker("fake", checker), WithChecks(tt.check))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestNew_ConfigFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "healthcheck.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+checks:
+  - name: Local API
+    url: `+server.URL+`
+    interval: 45s
+    timeout: 5s
+`), 0o600))
+
+	var notified []string
+	monitor := newMonitor(t, &fakeChecker{statuses: []types.Status{types.StatusUp}},
+		WithConfigFile(path),
+		WithChecks(types.CheckConfig{Name: "Zone", Type: "fake", Plugin: map[string]interface{}{"zone": "eu"}}),
+		WithNotifier(notifierFunc(func(result types.Result) error {
+			notified = append(notified, result.Name)
+			return nil
+		})),
+	)
+
+	checks := monitor.Checks()
+	require.Len(t, checks, 2)
+	assert.Equal(t, "Local API", checks[0].Name)
+	assert.Equal(t, types.CheckTypeHTTP, checks[0].Type)
+	assert.Equal(t, 45*time.Second, checks[0].Interval)
+	assert.Equal(t, "Zone", checks[1].Name)
+	assert.Equal(t, 30*time.Second, checks[1].Interval, "the global defaults apply")
+	assert.Equal(t, 10*time.Second, checks[1].Timeout)
+
+	result, err := monitor.Check(context.Background(), checks[0])
+	require.NoError(t, err)
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, []string{"Local API"}, notified)
+
+	_, err = New(WithConfigFile(path), WithConfigFile(path))
+	assert.EqualError(t, err, "only one configuration file can be used")
+}
+
+func TestMonitor_Dependencies(t *testing.T) {
+	router := fakeCheck("Router")
+	api := fakeCheck("API")
+	api.DependsOn = []string{"Router"}
+
+	monitor := newMonitor(t, &fakeChecker{statuses: []types.Status{types.StatusDown}}, WithChecks(router, api))
+
+	// The router has not run yet, so it is checked again before API is reported
+	result, err := monitor.Check(context.Background(), api)
+	require.NoError(t, err)
+	assert.Equal(t, types.StatusUnreachable, result.Status)
+	assert.Equal(t, "Router", result.UnreachableVia)
+	assert.Empty(t, result.Dependents)
+
+	result, err = monitor.Check(context.Background(), router)
+	require.NoError(t, err)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, []string{"API"}, result.Dependents)
+
+	orphan := fakeCheck("Worker")
+	orphan.DependsOn = []string{"Queue"}
+	_, err = monitor.Check(context.Background(), orphan)
+	assert.EqualError(t, err, "check[0]: depends_on names unknown check 'Queue'")
+}
+
+func TestMonitor_Maintenance(t *testing.T) {
+	api := fakeCheck("API")
+	api.Tags = []string{"edge"}
+
+	var notified int
+	monitor := newMonitor(t, &fakeChecker{statuses: []types.Status{types.StatusDown}},
+		WithMaintenanceWindows(types.MaintenanceWindow{
+			Name:  "Edge deploy",
+			Start: time.Now().Add(-time.Minute),
+			End:   time.Now().Add(time.Hour),
+			Tags:  []string{"edge"},
+		}),
+		WithNotifier(notifierFunc(func(types.Result) error {
+			notified++
+			return nil
+		})),
+	)
+
+	result, err := monitor.Check(context.Background(), api)
+	require.NoError(t, err)
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "Edge deploy", result.Maintenance)
+
+	result, err = monitor.Check(context.Background(), fakeCheck("Worker"))
+	require.NoError(t, err)
+	assert.Empty(t, result.Maintenance)
+	assert.Equal(t, 1, notified, "only the check outside the window is notified")
+
+	_, err = New(WithMaintenanceWindows(types.MaintenanceWindow{Name: "Deploy"}))
+	assert.EqualError(t, err, "maintenance[0]: a schedule and duration, or a start and end, is required")
+}
+
+func TestMonitor_Confirm(t *testing.T) {
+	api := fakeCheck("API")
+	api.Confirm = types.ConfirmConfig{Failures: 2, Within: 3}
+
+	monitor := newMonitor(t, &fakeChecker{statuses: []types.Status{
+		types.StatusDown, types.StatusUp, types.StatusDown, types.StatusDown,
+	}})
+
+	var statuses []types.Status
+	for range 4 {
+		result, err := monitor.Check(context.Background(), api)
+		require.NoError(t, err)
+		statuses = append(statuses, result.Status)
+	}
+	assert.Equal(t, []types.Status{types.StatusUp, types.StatusUp, types.StatusDown, types.StatusDown}, statuses,
+		"the second failure within three results confirms DOWN")
+
+	api.Confirm.Within = 1
+	_, err := monitor.Check(context.Background(), api)
+	assert.EqualError(t, err, "check[0]: confirm.within (1) must be at least confirm.failures (2)")
+}
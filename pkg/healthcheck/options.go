@@ -0,0 +1,227 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/internal/checker"
+	"github.com/renancavalcantercb/healthcheck-cli/internal/config"
+	"github.com/renancavalcantercb/healthcheck-cli/internal/notifications"
+	"github.com/renancavalcantercb/healthcheck-cli/internal/storage"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/interfaces"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/plugin"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// defaultEventBuffer is the capacity of the event stream unless WithEventBuffer is used
+const defaultEventBuffer = 64
+
+// Option configures a Monitor
+type Option func(*settings) error
+
+// Notifier delivers results, for example to a chat or paging system. It is
+// called for every result outside maintenance; further filtering is up to the
+// notifier.
+type Notifier interface {
+	Notify(result types.Result) error
+}
+
+// settings collects the options passed to New
+type settings struct {
+	config         *config.Config
+	checks         []types.CheckConfig
+	checkers       map[types.CheckType]interfaces.Checker
+	plugins        []*plugin.Plugin
+	storage        interfaces.Storage
+	ownsStorage    bool
+	notifiers      []Notifier
+	rateLimit      *types.RateLimitConfig
+	circuitBreaker *types.CircuitBreakerConfig
+	onResult       []func(types.Result)
+	onStateChange  []func(StateChangeEvent)
+	eventBuffer    int
+	maintenance    []types.MaintenanceWindow
+}
+
+// WithConfigFile loads a healthcheck-cli configuration file. Its checks are
+// added to the monitor, its global settings apply to every check, its email
+// and Discord notifications are enabled, and the checker plugins in its
+// plugins_dir are started.
+func WithConfigFile(path string) Option {
+	return func(s *settings) error {
+		if s.config != nil {
+			return errors.New("only one configuration file can be used")
+		}
+
+		cfg, err := config.LoadConfig(path)
+		if err != nil {
+			return err
+		}
+
+		// Unless replaced, exec checks may only run the configured commands
+		if _, ok := s.checkers[types.CheckTypeExec].(*checker.ExecChecker); ok {
+			s.checkers[types.CheckTypeExec] = checker.NewExecChecker(10*time.Second, cfg.Global.AllowedCommands)
+		}
+
+		if cfg.Global.PluginsDir != "" {
+			plugins, err := plugin.Discover(cfg.Global.PluginsDir)
+			s.plugins = append(s.plugins, plugins...)
+			if err != nil {
+				return fmt.Errorf("load plugins: %w", err)
+			}
+			for _, p := range plugins {
+				s.checkers[p.Type()] = p
+			}
+		}
+
+		s.config = cfg
+		s.notifiers = append(s.notifiers, notifications.NewManager(cfg))
+		return nil
+	}
+}
+
+// WithChecks adds checks to the monitor. They are validated and completed
+// with defaults the same way checks in a configuration file are, except that
+// a zero interval or timeout is replaced by the global default.
+func WithChecks(checks ...types.CheckConfig) Option {
+	return func(s *settings) error {
+		s.checks = append(s.checks, checks...)
+		return nil
+	}
+}
+
+// WithChecker runs checks of checkType with checker, replacing the built-in
+// checker for that type if there is one. New types must be lowercase names
+// such as "ldap". When checker has a ValidateOptions(map[string]interface{}) error
+// method, it is called with the plugin options of each check of its type.
+func WithChecker(checkType types.CheckType, checker interfaces.Checker) Option {
+	return func(s *settings) error {
+		if checker == nil {
+			return fmt.Errorf("checker for '%s' is nil", checkType)
+		}
+		s.checkers[checkType] = checker
+		return nil
+	}
+}
+
+// WithStorage saves every result to store. The caller keeps ownership of
+// store and closes it after the monitor.
+func WithStorage(store interfaces.Storage) Option {
+	return func(s *settings) error {
+		if s.storage != nil {
+			return errors.New("storage is already configured")
+		}
+		s.storage = store
+		return nil
+	}
+}
+
+// WithStoragePath saves every result to the SQLite database at path, falling
+// back to a JSON file next to it when SQLite is not available. The storage is
+// closed with the monitor.
+func WithStoragePath(path string) Option {
+	return func(s *settings) error {
+		if s.storage != nil {
+			return errors.New("storage is already configured")
+		}
+		store, err := storage.NewStorageWithDefaults(path)
+		if err != nil {
+			return fmt.Errorf("open storage: %w", err)
+		}
+		s.storage = store
+		s.ownsStorage = true
+		return nil
+	}
+}
+
+// WithNotifier sends every result of Run and Check to notifier
+func WithNotifier(notifier Notifier) Option {
+	return func(s *settings) error {
+		if notifier == nil {
+			return errors.New("notifier is nil")
+		}
+		s.notifiers = append(s.notifiers, notifier)
+		return nil
+	}
+}
+
+// WithRateLimit limits how often each target is checked, overriding the
+// configuration file
+func WithRateLimit(rateLimit types.RateLimitConfig) Option {
+	return func(s *settings) error {
+		s.rateLimit = &rateLimit
+		return nil
+	}
+}
+
+// WithCircuitBreaker stops checking targets that keep failing, overriding
+// the configuration file
+func WithCircuitBreaker(circuitBreaker types.CircuitBreakerConfig) Option {
+	return func(s *settings) error {
+		s.circuitBreaker = &circuitBreaker
+		return nil
+	}
+}
+
+// WithMaintenanceWindows adds maintenance windows to those of the
+// configuration file. Results of checks in an open window, or in a silence
+// kept in storage, are flagged with Result.Maintenance and not sent to the
+// notifiers.
+func WithMaintenanceWindows(windows ...types.MaintenanceWindow) Option {
+	return func(s *settings) error {
+		s.maintenance = append(s.maintenance, windows...)
+		return nil
+	}
+}
+
+// OnResult calls hook with every result. Hooks run on the goroutine of the
+// check that produced the result and should return quickly.
+func OnResult(hook func(types.Result)) Option {
+	return func(s *settings) error {
+		s.onResult = append(s.onResult, hook)
+		return nil
+	}
+}
+
+// OnStateChange calls hook whenever a check changes status. Hooks run on the
+// goroutine of the check that produced the result and should return quickly.
+func OnStateChange(hook func(StateChangeEvent)) Option {
+	return func(s *settings) error {
+		s.onStateChange = append(s.onStateChange, hook)
+		return nil
+	}
+}
+
+// WithEventBuffer sets the capacity of the event stream. Events that do not
+// fit because the stream is not read fast enough are dropped.
+func WithEventBuffer(size int) Option {
+	return func(s *settings) error {
+		if size < 0 {
+			return errors.New("event buffer size cannot be negative")
+		}
+		s.eventBuffer = size
+		return nil
+	}
+}
+
+// notifierSet adapts notifiers to interfaces.NotificationManager
+type notifierSet []Notifier
+
+func (n notifierSet) Notify(result types.Result) error {
+	if result.Maintenance != "" {
+		return nil
+	}
+
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n notifierSet) UpdateConfig(interface{}) interfaces.NotificationManager {
+	return n
+}
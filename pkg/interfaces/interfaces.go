@@ -13,11 +13,23 @@ type Storage interface {
 	GetServiceStats(serviceName string, since time.Time) (*types.ServiceStats, error)
 	GetAllServiceStats(since time.Time) ([]types.ServiceStats, error)
 	GetServiceHistory(serviceName string, since time.Time, limit int) ([]types.CheckResult, error)
+	GetLastCertSPKI(serviceName string) (string, error)
+	GetLastSchemaHash(serviceName string) (string, error)
+	GetLastContentVersion(serviceName string) (*types.ContentVersion, error)
+	GetContentVersions(serviceName string) ([]types.ContentVersion, error)
 	GetDatabaseInfo() (map[string]interface{}, error)
 	CleanupOldData(maxAge time.Duration) error
 	Close() error
 }
 
+// SilenceStore is implemented by storage that keeps ad-hoc alert silences.
+// It is optional: with storage that does not implement it, no silences apply.
+type SilenceStore interface {
+	SaveSilence(silence types.Silence) error
+	GetActiveSilences(now time.Time) ([]types.Silence, error)
+	DeleteSilence(id string) error
+}
+
 // Checker defines the interface for health check implementations
 type Checker interface {
 	Check(check types.CheckConfig) types.Result
@@ -36,6 +48,7 @@ type HealthCheckService interface {
 	ExecuteChecks(ctx context.Context, checks []types.CheckConfig) ([]types.Result, error)
 	MonitorEndpoint(ctx context.Context, check types.CheckConfig) (<-chan types.Result, error)
 	StartMonitoring(ctx context.Context, checks []types.CheckConfig) error
+	AddChecker(checkType types.CheckType, checker Checker)
 }
 
 // ConfigService defines the interface for configuration management
@@ -53,10 +66,18 @@ type StatsService interface {
 	GetServiceStats(serviceName string, since time.Time) (*types.ServiceStats, error)
 	GetAllStats(since time.Time) ([]types.ServiceStats, error)
 	GetHistory(serviceName string, since time.Time, limit int) ([]types.CheckResult, error)
+	GetContentVersions(serviceName string) ([]types.ContentVersion, error)
 	GetDatabaseInfo() (map[string]interface{}, error)
 	CleanupOldData(maxAge time.Duration) error
 }
 
+// SilenceService defines the interface for ad-hoc alert silences
+type SilenceService interface {
+	Add(checks, tags []string, reason string, duration time.Duration) (types.Silence, error)
+	List() ([]types.Silence, error)
+	Remove(id string) error
+}
+
 // Application defines the main application interface
 type Application interface {
 	// Core operations
@@ -0,0 +1,166 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Lookup returns the value found at a dot-separated path inside a decoded JSON document.
+// Paths look like "data.items[0].id"; a leading "$" or "$." is optional.
+func Lookup(document interface{}, path string) (interface{}, error) {
+	segments, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := document
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if segment.isIndex {
+				return nil, fmt.Errorf("path %q: cannot index object with [%d]", path, segment.index)
+			}
+			value, ok := node[segment.key]
+			if !ok {
+				return nil, fmt.Errorf("path %q: key '%s' not found", path, segment.key)
+			}
+			current = value
+		case []interface{}:
+			if !segment.isIndex {
+				return nil, fmt.Errorf("path %q: cannot read key '%s' from array", path, segment.key)
+			}
+			index := segment.index
+			if index < 0 {
+				index += len(node)
+			}
+			if index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("path %q: index %d out of range (length %d)", path, segment.index, len(node))
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %q: cannot descend into %s", path, describeType(current))
+		}
+	}
+
+	return current, nil
+}
+
+// LookupBytes decodes a JSON document and returns the value at path
+func LookupBytes(data []byte, path string) (interface{}, error) {
+	document, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return Lookup(document, path)
+}
+
+// Decode decodes a JSON document keeping numbers in their textual form
+func Decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+	return document, nil
+}
+
+// Validate checks that a path expression is well formed
+func Validate(path string) error {
+	_, err := parse(path)
+	return err
+}
+
+// String formats a looked-up value for use in templates and comparisons.
+// Strings are returned as-is, everything else is rendered as compact JSON.
+func String(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parse splits a path expression into key and index segments
+func parse(path string) ([]segment, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil, nil
+	}
+
+	var segments []segment
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return nil, fmt.Errorf("path %q: empty segment", path)
+		}
+
+		key := part
+		var indexes []int
+		if open := strings.Index(part, "["); open >= 0 {
+			key = part[:open]
+			rest := part[open:]
+			for rest != "" {
+				if rest[0] != '[' {
+					return nil, fmt.Errorf("path %q: unexpected '%s'", path, rest)
+				}
+				end := strings.Index(rest, "]")
+				if end < 0 {
+					return nil, fmt.Errorf("path %q: missing ']'", path)
+				}
+				index, err := strconv.Atoi(rest[1:end])
+				if err != nil {
+					return nil, fmt.Errorf("path %q: invalid index '%s'", path, rest[1:end])
+				}
+				indexes = append(indexes, index)
+				rest = rest[end+1:]
+			}
+		}
+
+		if key != "" {
+			segments = append(segments, segment{key: key})
+		}
+		for _, index := range indexes {
+			segments = append(segments, segment{index: index, isIndex: true})
+		}
+	}
+
+	return segments, nil
+}
+
+func describeType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
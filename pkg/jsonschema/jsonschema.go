@@ -0,0 +1,598 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema document.
+// It supports the commonly used subset of draft-07 keywords: type, enum, const,
+// properties, required, additionalProperties, items, min/max constraints,
+// pattern, allOf, anyOf, oneOf, not and local "#/..." references. Keywords
+// it cannot enforce, such as patternProperties or if/then/else, are rejected.
+type Schema struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+	refs     map[string]interface{} // resolved target of every $ref
+	mu       sync.Mutex
+}
+
+// Compile parses a JSON Schema document
+func Compile(data []byte) (*Schema, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	switch root.(type) {
+	case map[string]interface{}, bool:
+	default:
+		return nil, fmt.Errorf("JSON schema must be an object or boolean")
+	}
+
+	schema := &Schema{
+		root:     root,
+		patterns: make(map[string]*regexp.Regexp),
+		refs:     make(map[string]interface{}),
+	}
+
+	// Pre-compile all patterns and references so that invalid schemas are reported up front
+	if err := schema.compile(root); err != nil {
+		return nil, err
+	}
+	if err := schema.checkCircular(); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// LoadFile reads and parses a JSON Schema document from disk
+func LoadFile(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON schema: %w", err)
+	}
+	return Compile(data)
+}
+
+// Validate decodes a JSON document and validates it against the schema
+func (s *Schema) Validate(document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+
+	return s.ValidateValue(value)
+}
+
+// ValidateValue validates an already decoded JSON value against the schema
+func (s *Schema) ValidateValue(value interface{}) error {
+	return s.validate(s.root, normalize(value), "$")
+}
+
+// Keywords whose value is a schema, a list of schemas, or an object whose
+// values are schemas
+var (
+	schemaKeywords     = []string{"items", "additionalItems", "additionalProperties", "not"}
+	schemaListKeywords = []string{"items", "allOf", "anyOf", "oneOf"}
+	schemaMapKeywords  = []string{"properties", "definitions", "$defs"}
+)
+
+// unsupportedKeywords are draft-07 keywords validation does not enforce. A
+// schema using them is rejected rather than passing every document.
+var unsupportedKeywords = []string{"patternProperties", "propertyNames", "contains", "if", "then", "else", "dependencies"}
+
+// compile walks the schema, compiles the "pattern" keyword and resolves the
+// "$ref" keyword of every subschema. Only keywords holding schemas are followed,
+// so property names, enum and const values, defaults and examples are never
+// read as keywords.
+func (s *Schema) compile(node interface{}) error {
+	n, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, key := range unsupportedKeywords {
+		if _, ok := n[key]; ok {
+			return fmt.Errorf("schema keyword '%s' is not supported", key)
+		}
+	}
+
+	if child, ok := n["$ref"]; ok {
+		ref, ok := child.(string)
+		if !ok {
+			return fmt.Errorf("schema keyword '$ref' must be a string")
+		}
+		target, err := s.lookupRef(ref)
+		if err != nil {
+			return err
+		}
+		s.refs[ref] = target
+	}
+
+	if child, ok := n["pattern"]; ok {
+		pattern, ok := child.(string)
+		if !ok {
+			return fmt.Errorf("schema keyword 'pattern' must be a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q in schema: %w", pattern, err)
+		}
+		s.patterns[pattern] = re
+	}
+
+	for _, key := range schemaKeywords {
+		if err := s.compile(n[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range schemaListKeywords {
+		list, _ := n[key].([]interface{})
+		for _, child := range list {
+			if err := s.compile(child); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range schemaMapKeywords {
+		children, _ := n[key].(map[string]interface{})
+		for _, child := range children {
+			if err := s.compile(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCircular rejects references that lead back to themselves without
+// descending into the document, such as a definition listing itself in allOf.
+// Validating them would recurse forever. Every such cycle passes through a
+// $ref, so following each reference is enough to find them all.
+func (s *Schema) checkCircular() error {
+	refs := make([]string, 0, len(s.refs))
+	for ref := range s.refs {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	done := make(map[string]bool)
+	for _, ref := range refs {
+		if err := s.followInPlace(map[string]interface{}{"$ref": ref}, make(map[string]bool), done); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followInPlace follows the subschemas that apply to the same value as node:
+// its reference, allOf, anyOf, oneOf and not
+func (s *Schema) followInPlace(node interface{}, active, done map[string]bool) error {
+	n, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	// References replace the rest of the node
+	if ref, ok := n["$ref"].(string); ok {
+		if active[ref] {
+			return fmt.Errorf("schema reference %q is circular", ref)
+		}
+		if done[ref] {
+			return nil
+		}
+		active[ref] = true
+		err := s.followInPlace(s.refs[ref], active, done)
+		delete(active, ref)
+		done[ref] = true
+		return err
+	}
+
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		list, _ := n[key].([]interface{})
+		for _, child := range list {
+			if err := s.followInPlace(child, active, done); err != nil {
+				return err
+			}
+		}
+	}
+	return s.followInPlace(n["not"], active, done)
+}
+
+// validate checks a value against a schema node
+func (s *Schema) validate(node interface{}, value interface{}, path string) error {
+	switch n := node.(type) {
+	case bool:
+		if !n {
+			return fmt.Errorf("%s: value is not allowed", path)
+		}
+		return nil
+	case map[string]interface{}:
+		return s.validateObjectSchema(n, value, path)
+	default:
+		return fmt.Errorf("%s: invalid schema node", path)
+	}
+}
+
+func (s *Schema) validateObjectSchema(node map[string]interface{}, value interface{}, path string) error {
+	// References replace the rest of the node as in draft-07
+	if ref, ok := node["$ref"].(string); ok {
+		return s.validate(s.refs[ref], value, path)
+	}
+
+	if typ, ok := node["type"]; ok {
+		if err := validateType(typ, value, path); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := node["enum"].([]interface{}); ok {
+		matched := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(normalize(candidate), value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: value %s is not one of the allowed values", path, describe(value))
+		}
+	}
+
+	if constant, ok := node["const"]; ok {
+		if !reflect.DeepEqual(normalize(constant), value) {
+			return fmt.Errorf("%s: value %s does not match const %s", path, describe(value), describe(normalize(constant)))
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if err := validateNumber(node, v, path); err != nil {
+			return err
+		}
+	case string:
+		if err := s.validateString(node, v, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := s.validateArray(node, v, path); err != nil {
+			return err
+		}
+	case map[string]interface{}:
+		if err := s.validateObject(node, v, path); err != nil {
+			return err
+		}
+	}
+
+	return s.validateCombinators(node, value, path)
+}
+
+func (s *Schema) validateCombinators(node map[string]interface{}, value interface{}, path string) error {
+	if allOf, ok := node["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			if err := s.validate(sub, value, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if anyOf, ok := node["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if s.validate(sub, value, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: value does not match any schema in anyOf", path)
+		}
+	}
+
+	if oneOf, ok := node["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range oneOf {
+			if s.validate(sub, value, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: value matches %d schemas in oneOf, expected exactly 1", path, matches)
+		}
+	}
+
+	if not, ok := node["not"]; ok {
+		if s.validate(not, value, path) == nil {
+			return fmt.Errorf("%s: value must not match schema in 'not'", path)
+		}
+	}
+
+	return nil
+}
+
+func validateType(typ interface{}, value interface{}, path string) error {
+	var allowed []string
+	switch t := typ.(type) {
+	case string:
+		allowed = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				allowed = append(allowed, name)
+			}
+		}
+	}
+
+	for _, name := range allowed {
+		if matchesType(name, value) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: expected type %s, got %s", path, strings.Join(allowed, " or "), typeOf(value))
+}
+
+func matchesType(name string, value interface{}) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return false
+}
+
+func validateNumber(node map[string]interface{}, value float64, path string) error {
+	if min, ok := number(node["minimum"]); ok && value < min {
+		return fmt.Errorf("%s: %v is less than minimum %v", path, value, min)
+	}
+	if max, ok := number(node["maximum"]); ok && value > max {
+		return fmt.Errorf("%s: %v is greater than maximum %v", path, value, max)
+	}
+	if min, ok := number(node["exclusiveMinimum"]); ok && value <= min {
+		return fmt.Errorf("%s: %v must be greater than %v", path, value, min)
+	}
+	if max, ok := number(node["exclusiveMaximum"]); ok && value >= max {
+		return fmt.Errorf("%s: %v must be less than %v", path, value, max)
+	}
+	if multiple, ok := number(node["multipleOf"]); ok && multiple > 0 {
+		if quotient := value / multiple; quotient != math.Trunc(quotient) {
+			return fmt.Errorf("%s: %v is not a multiple of %v", path, value, multiple)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateString(node map[string]interface{}, value string, path string) error {
+	length := float64(utf8.RuneCountInString(value))
+	if min, ok := number(node["minLength"]); ok && length < min {
+		return fmt.Errorf("%s: string length %v is less than minLength %v", path, length, min)
+	}
+	if max, ok := number(node["maxLength"]); ok && length > max {
+		return fmt.Errorf("%s: string length %v is greater than maxLength %v", path, length, max)
+	}
+	if pattern, ok := node["pattern"].(string); ok {
+		s.mu.Lock()
+		re := s.patterns[pattern]
+		s.mu.Unlock()
+		if re != nil && !re.MatchString(value) {
+			return fmt.Errorf("%s: string does not match pattern %q", path, pattern)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(node map[string]interface{}, value []interface{}, path string) error {
+	count := float64(len(value))
+	if min, ok := number(node["minItems"]); ok && count < min {
+		return fmt.Errorf("%s: array has %v items, fewer than minItems %v", path, count, min)
+	}
+	if max, ok := number(node["maxItems"]); ok && count > max {
+		return fmt.Errorf("%s: array has %v items, more than maxItems %v", path, count, max)
+	}
+
+	if unique, ok := node["uniqueItems"].(bool); ok && unique {
+		for i := 0; i < len(value); i++ {
+			for j := i + 1; j < len(value); j++ {
+				if reflect.DeepEqual(value[i], value[j]) {
+					return fmt.Errorf("%s: array items %d and %d are not unique", path, i, j)
+				}
+			}
+		}
+	}
+
+	switch items := node["items"].(type) {
+	case map[string]interface{}, bool:
+		for i, item := range value {
+			if err := s.validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		// Tuple validation
+		for i, item := range value {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i < len(items) {
+				if err := s.validate(items[i], item, itemPath); err != nil {
+					return err
+				}
+			} else if additional, ok := node["additionalItems"]; ok {
+				if err := s.validate(additional, item, itemPath); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateObject(node map[string]interface{}, value map[string]interface{}, path string) error {
+	count := float64(len(value))
+	if min, ok := number(node["minProperties"]); ok && count < min {
+		return fmt.Errorf("%s: object has %v properties, fewer than minProperties %v", path, count, min)
+	}
+	if max, ok := number(node["maxProperties"]); ok && count > max {
+		return fmt.Errorf("%s: object has %v properties, more than maxProperties %v", path, count, max)
+	}
+
+	if required, ok := node["required"].([]interface{}); ok {
+		for _, item := range required {
+			name, _ := item.(string)
+			if _, exists := value[name]; !exists {
+				return fmt.Errorf("%s: missing required property '%s'", path, name)
+			}
+		}
+	}
+
+	properties, _ := node["properties"].(map[string]interface{})
+	for name, propValue := range value {
+		propPath := path + "." + name
+		if propSchema, ok := properties[name]; ok {
+			if err := s.validate(propSchema, propValue, propPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if additional, ok := node["additionalProperties"]; ok {
+			if err := s.validate(additional, propValue, propPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupRef returns the node a local JSON pointer reference such as
+// "#/definitions/item" points at
+func (s *Schema) lookupRef(ref string) (interface{}, error) {
+	if ref == "#" {
+		return s.root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported schema reference %q (only local references are supported)", ref)
+	}
+
+	current := s.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("schema reference %q not found", ref)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("schema reference %q not found", ref)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("schema reference %q not found", ref)
+		}
+	}
+
+	return current, nil
+}
+
+// normalize converts json.Number values into float64 so that comparisons are uniform
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func number(value interface{}) (float64, bool) {
+	f, ok := value.(float64)
+	return f, ok
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func describe(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(data) > 64 {
+		return string(data[:61]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,204 @@
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["status", "services"],
+	"properties": {
+		"status": {"type": "string", "enum": ["ok", "degraded"]},
+		"version": {"type": "string", "pattern": "^v[0-9]+\\.[0-9]+$"},
+		"uptime": {"type": "number", "minimum": 0},
+		"services": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"$ref": "#/definitions/service"}
+		}
+	},
+	"additionalProperties": false,
+	"definitions": {
+		"service": {
+			"type": "object",
+			"required": ["name", "healthy"],
+			"properties": {
+				"name": {"type": "string", "minLength": 1},
+				"healthy": {"type": "boolean"}
+			}
+		}
+	}
+}`
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+		errMsg  string
+	}{
+		{"ValidObjectSchema", testSchema, false, ""},
+		{"BooleanSchema", `true`, false, ""},
+		{"InvalidJSON", `{"type":`, true, "invalid JSON schema"},
+		{"NonObjectSchema", `"string"`, true, "must be an object or boolean"},
+		{"InvalidPattern", `{"pattern": "("}`, true, "invalid pattern"},
+		{"InvalidNestedPattern", `{"definitions": {"id": {"anyOf": [{"pattern": "("}]}}}`, true, "invalid pattern"},
+		{"PropertyNamedPattern", `{"properties": {"pattern": {"type": "string"}}}`, false, ""},
+		{"EnumValueWithPattern", `{"enum": [{"pattern": 1}], "default": {"pattern": "("}}`, false, ""},
+		{"MissingReference", `{"properties": {"id": {"$ref": "#/definitions/id"}}}`, true, `schema reference "#/definitions/id" not found`},
+		{"RemoteReference", `{"$ref": "https://example.com/schema.json"}`, true, "only local references are supported"},
+		{"UnsupportedKeyword", `{"properties": {"id": {"if": {"type": "string"}, "then": {"minLength": 1}}}}`, true, "schema keyword 'if' is not supported"},
+		{"PatternProperties", `{"patternProperties": {"^x-": {"type": "string"}}}`, true, "schema keyword 'patternProperties' is not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.schema))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "ValidDocument",
+			document: `{"status":"ok","version":"v1.2","uptime":12.5,"services":[{"name":"db","healthy":true}]}`,
+			wantErr:  false,
+		},
+		{
+			name:     "MissingRequired",
+			document: `{"status":"ok"}`,
+			wantErr:  true,
+			errMsg:   "missing required property 'services'",
+		},
+		{
+			name:     "EnumMismatch",
+			document: `{"status":"broken","services":[{"name":"db","healthy":true}]}`,
+			wantErr:  true,
+			errMsg:   "not one of the allowed values",
+		},
+		{
+			name:     "WrongType",
+			document: `{"status":"ok","uptime":"long","services":[{"name":"db","healthy":true}]}`,
+			wantErr:  true,
+			errMsg:   "$.uptime: expected type number, got string",
+		},
+		{
+			name:     "PatternMismatch",
+			document: `{"status":"ok","version":"1.2","services":[{"name":"db","healthy":true}]}`,
+			wantErr:  true,
+			errMsg:   "does not match pattern",
+		},
+		{
+			name:     "MinimumViolation",
+			document: `{"status":"ok","uptime":-1,"services":[{"name":"db","healthy":true}]}`,
+			wantErr:  true,
+			errMsg:   "less than minimum",
+		},
+		{
+			name:     "EmptyArray",
+			document: `{"status":"ok","services":[]}`,
+			wantErr:  true,
+			errMsg:   "fewer than minItems",
+		},
+		{
+			name:     "RefViolation",
+			document: `{"status":"ok","services":[{"name":"db"}]}`,
+			wantErr:  true,
+			errMsg:   "$.services[0]: missing required property 'healthy'",
+		},
+		{
+			name:     "AdditionalPropertyNotAllowed",
+			document: `{"status":"ok","extra":1,"services":[{"name":"db","healthy":true}]}`,
+			wantErr:  true,
+			errMsg:   "$.extra: value is not allowed",
+		},
+		{
+			name:     "NotJSON",
+			document: `<html></html>`,
+			wantErr:  true,
+			errMsg:   "not valid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.document))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSchema_Combinators(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"oneOf": [
+			{"type": "integer"},
+			{"type": "string", "maxLength": 3}
+		],
+		"not": {"const": 13}
+	}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate([]byte(`42`)))
+	assert.NoError(t, schema.Validate([]byte(`"abc"`)))
+	assert.Error(t, schema.Validate([]byte(`"abcd"`)))
+	assert.Error(t, schema.Validate([]byte(`1.5`)))
+	assert.Error(t, schema.Validate([]byte(`13`)))
+}
+
+func TestSchema_References(t *testing.T) {
+	tree, err := Compile([]byte(`{
+		"type": "object",
+		"properties": {"children": {"type": "array", "items": {"$ref": "#"}}}
+	}`))
+	require.NoError(t, err)
+	assert.NoError(t, tree.Validate([]byte(`{"children": [{"children": []}]}`)))
+	assert.Error(t, tree.Validate([]byte(`{"children": [{"children": 1}]}`)))
+
+	for _, circular := range []string{
+		`{"$ref": "#"}`,
+		`{"definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"$ref": "#/definitions/a"}}, "$ref": "#/definitions/a"}`,
+		`{"$ref": "#/definitions/a", "definitions": {"a": {"allOf": [{"$ref": "#/definitions/a"}]}}}`,
+		`{"properties": {"id": {"not": {"anyOf": [{"type": "null"}, {"$ref": "#/properties/id"}]}}}}`,
+	} {
+		_, err := Compile([]byte(circular))
+		require.Error(t, err, circular)
+		assert.Contains(t, err.Error(), "is circular")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(testSchema), 0600))
+
+	schema, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.NotNil(t, schema)
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+// Package maintenance decides whether a check runs during a planned
+// maintenance window or an ad-hoc silence. Such results are still stored but
+// not alerted on, and uptime can leave them out.
+package maintenance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// MaxDuration bounds how long a scheduled window stays open; longer
+// maintenance is better described with a start and end
+const MaxDuration = 7 * 24 * time.Hour
+
+// Window is a maintenance window ready to be compared with the clock
+type Window struct {
+	types.MaintenanceWindow
+	schedule *Schedule
+	location *time.Location
+}
+
+// NewWindow validates a configured window and parses its schedule
+func NewWindow(config types.MaintenanceWindow) (*Window, error) {
+	if config.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	w := &Window{MaintenanceWindow: config, location: time.Local}
+	absolute := !config.Start.IsZero() || !config.End.IsZero()
+
+	switch {
+	case config.Schedule != "" && absolute:
+		return nil, errors.New("use either schedule and duration or start and end, not both")
+	case config.Schedule != "":
+		schedule, err := ParseSchedule(config.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		if config.Duration <= 0 {
+			return nil, errors.New("duration must be greater than 0 for a scheduled window")
+		}
+		if config.Duration > MaxDuration {
+			return nil, fmt.Errorf("duration cannot be longer than %s", MaxDuration)
+		}
+		w.schedule = schedule
+	case absolute:
+		if config.Start.IsZero() || config.End.IsZero() {
+			return nil, errors.New("start and end are both required")
+		}
+		if !config.End.After(config.Start) {
+			return nil, errors.New("end must be after start")
+		}
+		if config.Duration != 0 {
+			return nil, errors.New("duration only applies to a scheduled window")
+		}
+	default:
+		return nil, errors.New("a schedule and duration, or a start and end, is required")
+	}
+
+	if config.Timezone != "" {
+		if config.Schedule == "" {
+			return nil, errors.New("timezone only applies to a scheduled window")
+		}
+		location, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone '%s'", config.Timezone)
+		}
+		w.location = location
+	}
+
+	return w, nil
+}
+
+// NewWindows validates every configured window
+func NewWindows(configs []types.MaintenanceWindow) ([]*Window, error) {
+	windows := make([]*Window, 0, len(configs))
+	for i, config := range configs {
+		w, err := NewWindow(config)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance[%d]: %w", i, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Active reports whether the window is open at now. A scheduled window is
+// open from each minute its schedule fires until its duration has passed.
+func (w *Window) Active(now time.Time) bool {
+	if w.schedule == nil {
+		return !now.Before(w.Start) && now.Before(w.End)
+	}
+
+	now = now.In(w.location)
+	start, ok := w.schedule.Previous(now, now.Add(-w.Duration))
+	return ok && now.Sub(start) < w.Duration
+}
+
+// Covers reports whether check is in the window's scope
+func (w *Window) Covers(check types.CheckConfig) bool {
+	return inScope(w.Checks, w.Tags, check)
+}
+
+// NewSilence creates a silence for the checks and tags given, starting now
+// and lasting for duration
+func NewSilence(checks, tags []string, reason string, now time.Time, duration time.Duration) (types.Silence, error) {
+	if len(checks) == 0 && len(tags) == 0 {
+		return types.Silence{}, errors.New("a silence needs at least one check or tag")
+	}
+	if duration <= 0 {
+		return types.Silence{}, errors.New("silence duration must be greater than 0")
+	}
+
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return types.Silence{}, fmt.Errorf("generate silence id: %w", err)
+	}
+
+	return types.Silence{
+		ID:        hex.EncodeToString(id),
+		Checks:    checks,
+		Tags:      tags,
+		Reason:    reason,
+		CreatedAt: now,
+		StartsAt:  now,
+		EndsAt:    now.Add(duration),
+	}, nil
+}
+
+// SilenceActive reports whether silence is in effect at now
+func SilenceActive(silence types.Silence, now time.Time) bool {
+	return !now.Before(silence.StartsAt) && now.Before(silence.EndsAt)
+}
+
+// Find returns what puts check in maintenance at now: the name of the first
+// open window covering it, or "silence <id>" for a silence, or "" when neither
+// applies
+func Find(windows []*Window, silences []types.Silence, check types.CheckConfig, now time.Time) string {
+	for _, w := range windows {
+		if w.Covers(check) && w.Active(now) {
+			return w.Name
+		}
+	}
+	for _, silence := range silences {
+		if inScope(silence.Checks, silence.Tags, check) && SilenceActive(silence, now) {
+			return "silence " + silence.ID
+		}
+	}
+	return ""
+}
+
+// inScope reports whether check is named in checks or carries one of tags.
+// An empty scope covers every check.
+func inScope(checks, tags []string, check types.CheckConfig) bool {
+	if len(checks) == 0 && len(tags) == 0 {
+		return true
+	}
+	for _, name := range checks {
+		if name == check.Name {
+			return true
+		}
+	}
+	for _, tag := range tags {
+		for _, checkTag := range check.Tags {
+			if tag == checkTag {
+				return true
+			}
+		}
+	}
+	return false
+}
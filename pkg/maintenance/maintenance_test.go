@@ -0,0 +1,100 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindow_Active(t *testing.T) {
+	nightly, err := NewWindow(types.MaintenanceWindow{
+		Name:     "Nightly deploy",
+		Schedule: "0 2 * * *",
+		Duration: 90 * time.Minute,
+		Timezone: "UTC",
+	})
+	require.NoError(t, err)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.March, 2, hour, minute, 30, 0, time.UTC)
+	}
+	assert.False(t, nightly.Active(at(1, 59)))
+	assert.True(t, nightly.Active(at(2, 0)))
+	assert.True(t, nightly.Active(at(3, 29)))
+	assert.False(t, nightly.Active(at(3, 30)))
+
+	weekend, err := NewWindow(types.MaintenanceWindow{
+		Name:     "Weekend freeze",
+		Schedule: "0 18 * * fri",
+		Duration: 60 * time.Hour,
+		Timezone: "UTC",
+	})
+	require.NoError(t, err)
+	assert.True(t, weekend.Active(time.Date(2026, time.March, 1, 23, 0, 0, 0, time.UTC)))
+	assert.False(t, weekend.Active(time.Date(2026, time.March, 2, 6, 0, 0, 0, time.UTC)))
+
+	start := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	migration, err := NewWindow(types.MaintenanceWindow{Name: "Migration", Start: start, End: start.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.False(t, migration.Active(start.Add(-time.Second)))
+	assert.True(t, migration.Active(start))
+	assert.False(t, migration.Active(start.Add(time.Hour)))
+}
+
+func TestNewWindow_Invalid(t *testing.T) {
+	start := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		window types.MaintenanceWindow
+		err    string
+	}{
+		{"no name", types.MaintenanceWindow{Schedule: "0 2 * * *", Duration: time.Hour}, "name is required"},
+		{"no period", types.MaintenanceWindow{Name: "Deploy"}, "a schedule and duration, or a start and end, is required"},
+		{"no duration", types.MaintenanceWindow{Name: "Deploy", Schedule: "0 2 * * *"}, "duration must be greater than 0 for a scheduled window"},
+		{"both kinds", types.MaintenanceWindow{Name: "Deploy", Schedule: "0 2 * * *", Duration: time.Hour, Start: start, End: start.Add(time.Hour)}, "use either schedule and duration or start and end, not both"},
+		{"no end", types.MaintenanceWindow{Name: "Deploy", Start: start}, "start and end are both required"},
+		{"end before start", types.MaintenanceWindow{Name: "Deploy", Start: start, End: start}, "end must be after start"},
+		{"too long", types.MaintenanceWindow{Name: "Deploy", Schedule: "0 2 * * *", Duration: 8 * 24 * time.Hour}, "duration cannot be longer than 168h0m0s"},
+		{"unknown timezone", types.MaintenanceWindow{Name: "Deploy", Schedule: "0 2 * * *", Duration: time.Hour, Timezone: "Mars/Olympus"}, "unknown timezone 'Mars/Olympus'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWindow(tt.window)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	_, err := NewWindows([]types.MaintenanceWindow{{Name: "Deploy"}})
+	assert.EqualError(t, err, "maintenance[0]: a schedule and duration, or a start and end, is required")
+}
+
+func TestFind(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	db := types.CheckConfig{Name: "Postgres", Tags: []string{"db"}}
+	api := types.CheckConfig{Name: "API", Tags: []string{"api"}}
+
+	window, err := NewWindow(types.MaintenanceWindow{Name: "API deploy", Start: now.Add(-time.Minute), End: now.Add(time.Hour), Checks: []string{"API"}})
+	require.NoError(t, err)
+	silence, err := NewSilence(nil, []string{"db"}, "vacuum", now, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, silence.ID, 8)
+
+	windows := []*Window{window}
+	silences := []types.Silence{silence}
+	assert.Equal(t, "API deploy", Find(windows, silences, api, now))
+	assert.Equal(t, "silence "+silence.ID, Find(windows, silences, db, now))
+	assert.Empty(t, Find(windows, silences, types.CheckConfig{Name: "Web"}, now))
+	assert.Empty(t, Find(windows, silences, db, now.Add(2*time.Hour)), "the silence has expired")
+
+	everything, err := NewWindow(types.MaintenanceWindow{Name: "Outage", Start: now, End: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, "Outage", Find([]*Window{everything}, nil, types.CheckConfig{Name: "Web"}, now))
+
+	_, err = NewSilence(nil, nil, "", now, time.Hour)
+	assert.EqualError(t, err, "a silence needs at least one check or tag")
+}
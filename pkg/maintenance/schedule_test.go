@@ -0,0 +1,123 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Matches(t *testing.T) {
+	// 2026-03-02 was a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		match []time.Time
+		miss  []time.Time
+	}{
+		{
+			name:  "every minute",
+			expr:  "* * * * *",
+			match: []time.Time{at(2, 0, 0), at(7, 23, 59)},
+		},
+		{
+			name:  "weekday names and a range",
+			expr:  "0 2 * * mon-fri",
+			match: []time.Time{at(2, 2, 0), at(6, 2, 0)},
+			miss:  []time.Time{at(2, 2, 1), at(7, 2, 0), at(8, 2, 0)},
+		},
+		{
+			name:  "steps and lists",
+			expr:  "*/15 8-18/2,22 * * *",
+			match: []time.Time{at(2, 8, 45), at(2, 10, 0), at(2, 22, 30)},
+			miss:  []time.Time{at(2, 9, 0), at(2, 8, 10)},
+		},
+		{
+			name:  "sunday as 7",
+			expr:  "30 3 * * 7",
+			match: []time.Time{at(8, 3, 30)},
+			miss:  []time.Time{at(7, 3, 30)},
+		},
+		{
+			name:  "day of month or day of week",
+			expr:  "0 0 1 * sat",
+			match: []time.Time{at(1, 0, 0), at(7, 0, 0)},
+			miss:  []time.Time{at(2, 0, 0)},
+		},
+		{
+			name: "month name",
+			expr: "0 0 * jan *",
+			miss: []time.Time{at(2, 0, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			require.NoError(t, err)
+			for _, tm := range tt.match {
+				assert.True(t, schedule.Matches(tm), "%s should match %v", tt.expr, tm)
+			}
+			for _, tm := range tt.miss {
+				assert.False(t, schedule.Matches(tm), "%s should not match %v", tt.expr, tm)
+			}
+		})
+	}
+}
+
+func TestSchedule_Previous(t *testing.T) {
+	// 2026-03-02 was a Monday
+	now := time.Date(2026, time.March, 2, 10, 17, 45, 0, time.UTC)
+	week := now.Add(-7 * 24 * time.Hour)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 2, 10, 17, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 2, 10, 15, 0, 0, time.UTC)},
+		{"30 22 * * *", time.Date(2026, time.March, 1, 22, 30, 0, 0, time.UTC)},
+		{"0 2 * * fri", time.Date(2026, time.February, 27, 2, 0, 0, 0, time.UTC)},
+		{"59 23 28 feb *", time.Date(2026, time.February, 28, 23, 59, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			require.NoError(t, err)
+			got, ok := schedule.Previous(now, week)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	yearly, err := ParseSchedule("0 0 1 jan *")
+	require.NoError(t, err)
+	_, ok := yearly.Previous(now, week)
+	assert.False(t, ok, "no firing within the last week")
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"0 2 * *", "schedule '0 2 * *' must have 5 fields (minute hour day-of-month month day-of-week), got 4"},
+		{"60 * * * *", "minute value 60 is out of range 0-59"},
+		{"0 18-8 * * *", "invalid range '18-8' in hour field"},
+		{"*/0 * * * *", "invalid step '0' in minute field"},
+		{"0 0 * * someday", "invalid value 'someday' in day of week field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseSchedule(tt.expr)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
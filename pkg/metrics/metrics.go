@@ -324,7 +324,7 @@ func (hcm *HealthCheckMetrics) RecordRequest(endpoint string, duration time.Dura
 	if !result.IsHealthy() {
 		errorLabels := map[string]string{
 			"endpoint": endpoint,
-			"type":     string(result.Status),
+			"type":     result.Status.String(),
 		}
 		hcm.ErrorsTotal.WithLabels(errorLabels).Inc()
 		
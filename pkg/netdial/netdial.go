@@ -0,0 +1,360 @@
+// Package netdial builds dialers that honor per-check proxy, DNS resolver,
+// static resolve and address family settings
+package netdial
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// ProxyNone disables a globally configured proxy for a single check
+const ProxyNone = "none"
+
+// Options controls how a check reaches its target
+type Options struct {
+	Proxy    string            // http://, https://, socks5:// or socks5h:// URL
+	Resolver string            // DNS server as host[:port]
+	Resolve  map[string]string // host or host:port to IP
+	Family   string            // types.IPVersion4 or types.IPVersion6 to force an address family
+}
+
+// FromCheck extracts the network options of a check
+func FromCheck(check types.CheckConfig) Options {
+	opts := Options{
+		Proxy:    check.Proxy,
+		Resolver: check.Resolver,
+		Resolve:  check.Resolve,
+	}
+	if check.IPVersion == types.IPVersion4 || check.IPVersion == types.IPVersion6 {
+		opts.Family = check.IPVersion
+	}
+	if opts.Proxy == ProxyNone {
+		opts.Proxy = ""
+	}
+	return opts
+}
+
+// IsZero reports whether the options leave the system defaults in place
+func (o Options) IsZero() bool {
+	return o.Proxy == "" && o.Resolver == "" && len(o.Resolve) == 0 && o.Family == ""
+}
+
+// Key returns a string that identifies distinct options, so callers can
+// share dialers and transports between checks
+func Key(o Options) string {
+	entries := make([]string, 0, len(o.Resolve))
+	for host, ip := range o.Resolve {
+		entries = append(entries, host+"="+ip)
+	}
+	sort.Strings(entries)
+	return fmt.Sprintf("%s|%s|%s|%s", o.Proxy, o.Resolver, strings.Join(entries, ","), o.Family)
+}
+
+// Validate checks the options without opening any connection
+func Validate(o Options) error {
+	if o.Proxy != "" && o.Proxy != ProxyNone {
+		if _, err := parseProxy(o.Proxy); err != nil {
+			return err
+		}
+	}
+
+	if o.Resolver != "" {
+		if _, err := resolverAddress(o.Resolver); err != nil {
+			return err
+		}
+	}
+
+	if o.Family != "" && o.Family != types.IPVersion4 && o.Family != types.IPVersion6 {
+		return fmt.Errorf("unsupported address family '%s' (use 4 or 6)", o.Family)
+	}
+
+	for host, ip := range o.Resolve {
+		if host == "" {
+			return fmt.Errorf("resolve entries need a host name")
+		}
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("resolve entry '%s' must map to an IP address, got '%s'", host, ip)
+		}
+	}
+
+	return nil
+}
+
+// Dialer connects to targets using the configured proxy, resolver and
+// static overrides
+type Dialer struct {
+	proxy    *url.URL
+	resolve  map[string]string
+	resolver *net.Resolver
+	dialer   *net.Dialer
+	family   string
+}
+
+// New creates a Dialer. Zero options produce a dialer equivalent to net.Dialer.
+func New(o Options) (*Dialer, error) {
+	if err := Validate(o); err != nil {
+		return nil, err
+	}
+
+	d := &Dialer{
+		resolve:  o.Resolve,
+		resolver: net.DefaultResolver,
+		family:   o.Family,
+	}
+
+	if o.Proxy != "" && o.Proxy != ProxyNone {
+		d.proxy, _ = parseProxy(o.Proxy)
+	}
+
+	if o.Resolver != "" {
+		address, _ := resolverAddress(o.Resolver)
+		d.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, address)
+			},
+		}
+	}
+
+	d.dialer = &net.Dialer{
+		KeepAlive: 30 * time.Second,
+		Resolver:  d.resolver,
+	}
+
+	return d, nil
+}
+
+// DialContext connects to addr, tunnelling through the proxy when one is set
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.proxy == nil {
+		return d.DialDirect(ctx, network, addr)
+	}
+
+	switch d.proxy.Scheme {
+	case "socks5", "socks5h":
+		return d.dialSOCKS5(ctx, addr)
+	default:
+		return d.dialHTTPConnect(ctx, addr)
+	}
+}
+
+// DialDirect connects to addr without the proxy, applying the static
+// overrides and the custom resolver
+func (d *Dialer) DialDirect(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip, ok := d.static(host, port); ok {
+		if !d.allowed(net.ParseIP(ip)) {
+			return nil, fmt.Errorf("resolve entry for %s is not an %s address", host, FamilyName(d.family))
+		}
+		addr = net.JoinHostPort(ip, port)
+	}
+	if d.family != "" && (network == "tcp" || network == "udp") {
+		network += d.family
+	}
+	return d.dialer.DialContext(ctx, network, addr)
+}
+
+// ConfigureTransport makes an HTTP transport use this dialer. Requests go
+// through the proxy when one is set; the proxy host itself is resolved with
+// the custom resolver.
+//
+// SOCKS proxies are dialed by this dialer rather than by net/http, which
+// would send the host name even for socks5 and skip the static overrides.
+// Behind an HTTP proxy, requests to a host with a static override are
+// tunnelled with CONNECT to the overriding address.
+func (d *Dialer) ConfigureTransport(transport *http.Transport) {
+	transport.DialContext = d.DialDirect
+	if d.proxy == nil {
+		return
+	}
+
+	switch d.proxy.Scheme {
+	case "socks5", "socks5h":
+		transport.DialContext = d.DialContext
+	default:
+		proxy := http.ProxyURL(d.proxy)
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if _, ok := d.static(req.URL.Hostname(), requestPort(req.URL)); ok {
+				return nil, nil
+			}
+			return proxy(req)
+		}
+		proxyAddr := proxyAddress(d.proxy)
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := d.static(host, port); ok && addr != proxyAddr {
+				return d.dialHTTPConnect(ctx, addr)
+			}
+			return d.DialDirect(ctx, network, addr)
+		}
+	}
+}
+
+// LookupIP resolves host the way DialContext would, so callers can refuse
+// private targets. With an HTTP or socks5h proxy the proxy resolves the
+// target; the host is still resolved locally when possible, and no addresses
+// are returned when the local resolver does not know it.
+func (d *Dialer) LookupIP(host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return d.filter([]net.IP{ip}), nil
+	}
+	if ip, ok := d.static(host, ""); ok {
+		return d.filter([]net.IP{net.ParseIP(ip)}), nil
+	}
+
+	addrs, err := d.resolver.LookupIPAddr(context.Background(), host)
+	if err != nil {
+		if d.RemoteResolution() {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	ips = d.filter(ips)
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no %s addresses found for %s", FamilyName(d.family), host)
+	}
+	return ips, nil
+}
+
+// RemoteResolution reports whether the proxy resolves target host names, as
+// HTTP and socks5h proxies do
+func (d *Dialer) RemoteResolution() bool {
+	return d.proxy != nil && d.proxy.Scheme != "socks5"
+}
+
+// Family returns the address family of ip as types.IPVersion4 or types.IPVersion6
+func Family(ip net.IP) string {
+	if ip.To4() != nil {
+		return types.IPVersion4
+	}
+	return types.IPVersion6
+}
+
+// FamilyName returns IPv4 or IPv6 for a family, or "IP" when none is forced
+func FamilyName(family string) string {
+	switch family {
+	case types.IPVersion4:
+		return "IPv4"
+	case types.IPVersion6:
+		return "IPv6"
+	}
+	return "IP"
+}
+
+// allowed reports whether ip belongs to the forced family
+func (d *Dialer) allowed(ip net.IP) bool {
+	return d.family == "" || Family(ip) == d.family
+}
+
+// filter keeps the addresses of the forced family
+func (d *Dialer) filter(ips []net.IP) []net.IP {
+	if d.family == "" {
+		return ips
+	}
+	kept := ips[:0:0]
+	for _, ip := range ips {
+		if d.allowed(ip) {
+			kept = append(kept, ip)
+		}
+	}
+	return kept
+}
+
+// Proxy returns the proxy URL with credentials redacted, or "" without a proxy
+func (d *Dialer) Proxy() string {
+	if d.proxy == nil {
+		return ""
+	}
+	return d.proxy.Redacted()
+}
+
+// static looks up a host:port override first, then a host override
+func (d *Dialer) static(host, port string) (string, bool) {
+	if port != "" {
+		if ip, ok := d.resolve[net.JoinHostPort(host, port)]; ok {
+			return ip, true
+		}
+	}
+	ip, ok := d.resolve[host]
+	return ip, ok
+}
+
+// requestPort returns the port of a request URL, defaulting by scheme
+func requestPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// parseProxy validates a proxy URL
+func parseProxy(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%s' (use http, https, socks5 or socks5h)", parsed.Scheme)
+	}
+
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("proxy URL must have a host")
+	}
+	return parsed, nil
+}
+
+// proxyAddress returns the proxy host:port, filling in the scheme's default port
+func proxyAddress(proxy *url.URL) string {
+	if proxy.Port() != "" {
+		return proxy.Host
+	}
+	port := "1080"
+	switch proxy.Scheme {
+	case "http":
+		port = "80"
+	case "https":
+		port = "443"
+	}
+	return net.JoinHostPort(proxy.Hostname(), port)
+}
+
+// resolverAddress validates a resolver and adds the default DNS port
+func resolverAddress(resolver string) (string, error) {
+	host, port, err := net.SplitHostPort(resolver)
+	if err != nil {
+		host, port = strings.Trim(resolver, "[]"), "53"
+	}
+	if host == "" {
+		return "", fmt.Errorf("resolver '%s' must have a host", resolver)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("resolver '%s' has an invalid port", resolver)
+	}
+	return net.JoinHostPort(host, port), nil
+}
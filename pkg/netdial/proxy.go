@@ -0,0 +1,277 @@
+package netdial
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SOCKS5 protocol constants (RFC 1928, RFC 1929)
+const (
+	socksVersion          = 5
+	socksAuthNone         = 0x00
+	socksAuthPassword     = 0x02
+	socksAuthNoAcceptable = 0xff
+	socksCommandConnect   = 0x01
+	socksAddressIPv4      = 0x01
+	socksAddressDomain    = 0x03
+	socksAddressIPv6      = 0x04
+)
+
+var socksReplies = map[byte]string{
+	1: "general SOCKS server failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// dialHTTPConnect opens a tunnel to addr with an HTTP CONNECT request. A
+// static override replaces the host, so the proxy connects to that address.
+func (d *Dialer) dialHTTPConnect(ctx context.Context, addr string) (net.Conn, error) {
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		if ip, ok := d.static(host, port); ok {
+			addr = net.JoinHostPort(ip, port)
+		}
+	}
+
+	conn, err := d.dialProxy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: d.proxy.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy %s: TLS handshake failed: %w", d.Proxy(), err)
+		}
+		conn = tlsConn
+	}
+
+	clearDeadline := applyDeadline(ctx, conn)
+	defer clearDeadline()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := d.proxy.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", d.Proxy(), err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", d.Proxy(), err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused CONNECT to %s: %s", d.Proxy(), addr, resp.Status)
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// dialSOCKS5 opens a connection to addr through a SOCKS5 proxy. With the
+// socks5 scheme the target is resolved locally, with socks5h by the proxy.
+func (d *Dialer) dialSOCKS5(ctx context.Context, addr string) (net.Conn, error) {
+	host, portText, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port in '%s'", addr)
+	}
+
+	if ip, ok := d.static(host, portText); ok {
+		host = ip
+	} else if d.proxy.Scheme == "socks5" && net.ParseIP(host) == nil {
+		addrs, err := d.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var ips []net.IP
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+		ips = d.filter(ips)
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("no %s addresses found for %s", FamilyName(d.family), host)
+		}
+		host = ips[0].String()
+	}
+
+	conn, err := d.dialProxy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clearDeadline := applyDeadline(ctx, conn)
+	defer clearDeadline()
+
+	if err := socksConnect(conn, d.proxy.User, host, port); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", d.Proxy(), err)
+	}
+	return conn, nil
+}
+
+// dialProxy connects to the proxy server itself
+func (d *Dialer) dialProxy(ctx context.Context) (net.Conn, error) {
+	conn, err := d.DialDirect(ctx, "tcp", proxyAddress(d.proxy))
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s unreachable: %w", d.Proxy(), err)
+	}
+	return conn, nil
+}
+
+// socksConnect performs the SOCKS5 handshake and CONNECT request
+func socksConnect(conn net.Conn, user *url.Userinfo, host string, port int) error {
+	methods := []byte{socksAuthNone}
+	if user != nil {
+		methods = append(methods, socksAuthPassword)
+	}
+	if _, err := conn.Write(append([]byte{socksVersion, byte(len(methods))}, methods...)); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("reading SOCKS greeting: %w", err)
+	}
+	if reply[0] != socksVersion {
+		return fmt.Errorf("not a SOCKS5 proxy (version %d)", reply[0])
+	}
+
+	switch reply[1] {
+	case socksAuthNone:
+	case socksAuthPassword:
+		if user == nil {
+			return fmt.Errorf("proxy requires a username and password")
+		}
+		password, _ := user.Password()
+		if len(user.Username()) > 255 || len(password) > 255 {
+			return fmt.Errorf("SOCKS credentials are limited to 255 bytes")
+		}
+		request := []byte{1, byte(len(user.Username()))}
+		request = append(request, user.Username()...)
+		request = append(request, byte(len(password)))
+		request = append(request, password...)
+		if _, err := conn.Write(request); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return fmt.Errorf("reading SOCKS authentication reply: %w", err)
+		}
+		if reply[1] != 0 {
+			return fmt.Errorf("SOCKS authentication failed")
+		}
+	case socksAuthNoAcceptable:
+		return fmt.Errorf("proxy accepted none of the offered authentication methods")
+	default:
+		return fmt.Errorf("proxy selected unsupported authentication method %d", reply[1])
+	}
+
+	request := []byte{socksVersion, socksCommandConnect, 0}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			request = append(append(request, socksAddressIPv4), ip4...)
+		} else {
+			request = append(append(request, socksAddressIPv6), ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("host name too long for SOCKS: %s", host)
+		}
+		request = append(request, socksAddressDomain, byte(len(host)))
+		request = append(request, host...)
+	}
+	request = binary.BigEndian.AppendUint16(request, uint16(port))
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("reading SOCKS reply: %w", err)
+	}
+	if header[1] != 0 {
+		message, ok := socksReplies[header[1]]
+		if !ok {
+			message = fmt.Sprintf("reply code %d", header[1])
+		}
+		return fmt.Errorf("SOCKS CONNECT to %s failed: %s", net.JoinHostPort(host, strconv.Itoa(port)), message)
+	}
+
+	// Discard the bound address and port
+	var skip int
+	switch header[3] {
+	case socksAddressIPv4:
+		skip = net.IPv4len + 2
+	case socksAddressIPv6:
+		skip = net.IPv6len + 2
+	case socksAddressDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return err
+		}
+		skip = int(length[0]) + 2
+	default:
+		return fmt.Errorf("SOCKS reply has unknown address type %d", header[3])
+	}
+	if _, err := io.ReadFull(conn, make([]byte, skip)); err != nil {
+		return fmt.Errorf("reading SOCKS reply: %w", err)
+	}
+	return nil
+}
+
+// applyDeadline bounds the proxy handshake by the context deadline
+func applyDeadline(ctx context.Context, conn net.Conn) func() {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		return func() { conn.SetDeadline(time.Time{}) }
+	}
+	return func() {}
+}
+
+// bufferedConn returns bytes the proxy sent after its CONNECT response
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,204 @@
+package ocsp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func newCert(t *testing.T, name string, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestCreateAndParseResponse(t *testing.T) {
+	for _, keyType := range []string{"ECDSA", "RSA"} {
+		t.Run(keyType, func(t *testing.T) {
+			var issuerKey crypto.Signer
+			var err error
+			if keyType == "RSA" {
+				issuerKey, err = rsa.GenerateKey(rand.Reader, 2048)
+			} else {
+				issuerKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			}
+			require.NoError(t, err)
+
+			leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+
+			issuer := newCert(t, "Issuer", issuerKey, nil, nil)
+			leaf := newCert(t, "leaf.test", leafKey, issuer, issuerKey)
+
+			now := time.Now().Truncate(time.Second)
+			der, err := CreateResponse(issuer, issuerKey, ResponseTemplate{
+				Status:           Revoked,
+				SerialNumber:     leaf.SerialNumber,
+				ThisUpdate:       now,
+				NextUpdate:       now.Add(time.Hour),
+				RevokedAt:        now.Add(-time.Hour),
+				RevocationReason: 1,
+			})
+			require.NoError(t, err)
+
+			response, err := ParseResponse(der, leaf, issuer, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, Revoked, response.Status)
+			assert.Equal(t, 1, response.RevocationReason)
+			assert.True(t, response.RevokedAt.Equal(now.Add(-time.Hour)))
+			assert.True(t, response.NextUpdate.Equal(now.Add(time.Hour)))
+		})
+	}
+}
+
+func TestParseResponse_Rejects(t *testing.T) {
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	issuer := newCert(t, "Issuer", issuerKey, nil, nil)
+	leaf := newCert(t, "leaf.test", otherKey, issuer, issuerKey)
+	other := newCert(t, "other.test", otherKey, issuer, issuerKey)
+
+	now := time.Now()
+	good := ResponseTemplate{Status: Good, SerialNumber: leaf.SerialNumber, ThisUpdate: now, NextUpdate: now.Add(time.Hour)}
+
+	t.Run("WrongSigner", func(t *testing.T) {
+		der, err := CreateResponse(issuer, otherKey, good)
+		require.NoError(t, err)
+		_, err = ParseResponse(der, leaf, issuer, now)
+		assert.ErrorContains(t, err, "bad OCSP signature")
+	})
+
+	t.Run("OtherCertificate", func(t *testing.T) {
+		der, err := CreateResponse(issuer, issuerKey, good)
+		require.NoError(t, err)
+		_, err = ParseResponse(der, other, issuer, now)
+		assert.ErrorContains(t, err, "no response matching the supplied certificate")
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		_, err = ParseResponse(ocsp.TryLaterErrorResponse, leaf, issuer, now)
+		assert.EqualError(t, err, "OCSP responder returned error status 3")
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		der, err := CreateResponse(issuer, issuerKey, good)
+		require.NoError(t, err)
+		_, err = ParseResponse(der, leaf, issuer, now.Add(2*time.Hour))
+		assert.ErrorContains(t, err, "OCSP response is stale")
+	})
+
+	t.Run("NotYetValid", func(t *testing.T) {
+		der, err := CreateResponse(issuer, issuerKey, good)
+		require.NoError(t, err)
+		_, err = ParseResponse(der, leaf, issuer, now.Add(-time.Hour))
+		assert.ErrorContains(t, err, "OCSP response is not valid until")
+	})
+}
+
+func TestParseResponse_DelegatedResponder(t *testing.T) {
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	responderKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	issuer := newCert(t, "Issuer", issuerKey, nil, nil)
+	leaf := newCert(t, "leaf.test", responderKey, issuer, issuerKey)
+
+	responder := func(notAfter time.Time, usages ...x509.ExtKeyUsage) *x509.Certificate {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: "OCSP Responder"},
+			NotBefore:    time.Now().Add(-2 * time.Hour),
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  usages,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, issuer, responderKey.Public(), issuerKey)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return cert
+	}
+
+	now := time.Now()
+	respond := func(cert *x509.Certificate) ([]byte, error) {
+		return CreateResponse(issuer, responderKey, ResponseTemplate{
+			Status:       Good,
+			SerialNumber: leaf.SerialNumber,
+			ThisUpdate:   now,
+			NextUpdate:   now.Add(time.Hour),
+			Responder:    cert,
+		})
+	}
+
+	der, err := respond(responder(now.Add(time.Hour), x509.ExtKeyUsageOCSPSigning))
+	require.NoError(t, err)
+	response, err := ParseResponse(der, leaf, issuer, now)
+	require.NoError(t, err)
+	assert.Equal(t, Good, response.Status)
+
+	der, err = respond(responder(now.Add(-time.Hour), x509.ExtKeyUsageOCSPSigning))
+	require.NoError(t, err)
+	_, err = ParseResponse(der, leaf, issuer, now)
+	assert.ErrorContains(t, err, "OCSP responder certificate is not valid")
+
+	der, err = respond(responder(now.Add(time.Hour), x509.ExtKeyUsageServerAuth))
+	require.NoError(t, err)
+	_, err = ParseResponse(der, leaf, issuer, now)
+	assert.EqualError(t, err, "OCSP responder certificate lacks the OCSP signing usage")
+}
+
+func TestCheckFreshness(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, CheckFreshness(now.Add(-time.Hour), now.Add(time.Hour), now))
+	assert.NoError(t, CheckFreshness(now.Add(time.Minute), time.Time{}, now), "within the allowed clock skew")
+	assert.EqualError(t, CheckFreshness(now.Add(-2*time.Hour), now.Add(-time.Hour), now), "is stale: nextUpdate was 2026-03-02T09:00:00Z")
+	assert.EqualError(t, CheckFreshness(now.Add(time.Hour), now.Add(2*time.Hour), now), "is not valid until 2026-03-02T11:00:00Z")
+}
+
+func TestCreateRequest(t *testing.T) {
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := newCert(t, "Issuer", issuerKey, nil, nil)
+	leaf := newCert(t, "leaf.test", issuerKey, issuer, issuerKey)
+
+	der, err := CreateRequest(leaf, issuer)
+	require.NoError(t, err)
+
+	parsed, err := ocsp.ParseRequest(der)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.SHA1, parsed.HashAlgorithm)
+	assert.Equal(t, 0, parsed.SerialNumber.Cmp(leaf.SerialNumber))
+}
@@ -0,0 +1,32 @@
+package plugin
+
+import "errors"
+
+// Discover reads the manifests of the plugins in dir, as ReadManifests does,
+// and starts the plugins. Plugins that fail to start are reported in the
+// joined error while the others are still returned.
+func Discover(dir string) ([]*Plugin, error) {
+	manifests, err := ReadManifests(dir)
+	errs := []error{err}
+
+	var plugins []*Plugin
+	for _, manifest := range manifests {
+		plugin, err := manifest.Start()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, errors.Join(errs...)
+}
+
+// Manifests returns the manifests of plugins
+func Manifests(plugins []*Plugin) []*Manifest {
+	manifests := make([]*Manifest, len(plugins))
+	for i, plugin := range plugins {
+		manifests[i] = &plugin.Manifest
+	}
+	return manifests
+}
@@ -0,0 +1,369 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+const (
+	// startTimeout bounds how long a plugin may take to start and describe itself
+	startTimeout = 10 * time.Second
+	// shutdownTimeout bounds how long a plugin may take to exit after shutdown
+	shutdownTimeout = 2 * time.Second
+	// responseGrace is added to the check timeout before a plugin is considered hung
+	responseGrace = 2 * time.Second
+	// defaultCheckTimeout is used for checks without a timeout
+	defaultCheckTimeout = 30 * time.Second
+	// maxMessageSize bounds a single protocol message
+	maxMessageSize = 4 << 20
+)
+
+// typePattern restricts plugin check types to names that read well in YAML
+var typePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// ValidType reports whether a plugin may provide checkType: it must not be
+// built in, and must be a lowercase name such as "ldap" or "s3-bucket"
+func ValidType(checkType types.CheckType) bool {
+	return !checkType.IsBuiltin() && typePattern.MatchString(string(checkType))
+}
+
+// errClosed is returned by calls to a plugin after Close
+var errClosed = errors.New("plugin is closed")
+
+// Plugin is a checker running as a separate process. It implements
+// interfaces.Checker for the check type in its manifest. Checks are sent one
+// at a time; a plugin that exits or stops answering is restarted on the next check.
+type Plugin struct {
+	Manifest
+
+	mu      sync.Mutex
+	process *process
+	nextID  int64
+	closed  bool
+}
+
+// process is a running plugin executable
+type process struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan []byte // one line per response; closed when stdout is closed
+}
+
+// Start reads the manifest of the plugin executable at path, runs it and
+// checks that it describes itself as its manifest does
+func Start(path string) (*Plugin, error) {
+	manifest, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Start()
+}
+
+// Start runs the plugin and checks that it describes itself as the manifest does
+func (m *Manifest) Start() (*Plugin, error) {
+	p := &Plugin{Manifest: *m}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Check sends the check to the plugin and converts its answer to a result
+func (p *Plugin) Check(check types.CheckConfig) types.Result {
+	start := time.Now()
+
+	result := types.Result{
+		Name:      check.Name,
+		URL:       check.URL,
+		Timestamp: start,
+	}
+
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	request := CheckRequest{
+		Name:      check.Name,
+		Target:    check.URL,
+		TimeoutMs: timeout.Milliseconds(),
+		Options:   check.Plugin,
+	}
+
+	var response CheckResponse
+	err := p.call(MethodCheck, request, &response, timeout+responseGrace)
+	result.ResponseTime = time.Since(start)
+	if err != nil {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Plugin %s failed: %v", p.Name(), err)
+		return result
+	}
+
+	status, ok := parseStatus(response.Status)
+	if !ok {
+		result.Status = types.StatusError
+		result.Error = fmt.Sprintf("Plugin %s returned unknown status '%s'", p.Name(), response.Status)
+		return result
+	}
+
+	// The plugin knows best how long the checked operation took
+	if response.ResponseTimeMs > 0 {
+		result.ResponseTime = time.Duration(response.ResponseTimeMs * float64(time.Millisecond))
+	}
+	result.Status = status
+	result.Error = response.Error
+	result.Message = response.Message
+	result.Metrics = response.Metrics
+	return result
+}
+
+// Close asks the plugin to shut down and kills it when it does not exit in time
+func (p *Plugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	if p.process == nil {
+		return nil
+	}
+
+	proc := p.process
+	p.process = nil
+	if err := p.roundTrip(proc, MethodShutdown, nil, nil, shutdownTimeout); err != nil {
+		proc.kill()
+		return nil
+	}
+	proc.stdin.Close()
+
+	exited := make(chan struct{})
+	go func() {
+		proc.wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(shutdownTimeout):
+		proc.cmd.Process.Kill()
+		<-exited
+	}
+	return nil
+}
+
+// call sends a request, starting the plugin again when it is not running
+func (p *Plugin) call(method string, params, result interface{}, timeout time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return errClosed
+	}
+	if p.process == nil {
+		if err := p.start(); err != nil {
+			return err
+		}
+	}
+
+	proc := p.process
+	if err := p.roundTrip(proc, method, params, result, timeout); err != nil {
+		// The process may be stuck or out of step with the protocol, so it is replaced
+		var remote remoteError
+		if !errors.As(err, &remote) {
+			proc.kill()
+			p.process = nil
+		}
+		return err
+	}
+	return nil
+}
+
+// start runs the executable and checks its description. The caller holds p.mu.
+func (p *Plugin) start() error {
+	cmd := exec.Command(p.path)
+	cmd.Stderr = &stderrLogger{name: filepath.Base(p.path)}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", p.path, err)
+	}
+
+	proc := &process{cmd: cmd, stdin: stdin, responses: make(chan []byte)}
+	go proc.read(stdout)
+
+	var description Description
+	if err := p.roundTrip(proc, MethodDescribe, nil, &description, startTimeout); err != nil {
+		proc.kill()
+		return fmt.Errorf("describe %s: %w", p.path, err)
+	}
+	if err := p.accept(description); err != nil {
+		p.roundTrip(proc, MethodShutdown, nil, nil, shutdownTimeout)
+		proc.kill()
+		return fmt.Errorf("plugin %s: %w", p.path, err)
+	}
+
+	p.process = proc
+	return nil
+}
+
+// accept checks a description against the manifest. The options schema of
+// the manifest is used, so checks are validated the same way with or without
+// the plugin running.
+func (p *Plugin) accept(description Description) error {
+	if err := validateDescription(description); err != nil {
+		return err
+	}
+	if description.Type != p.description.Type {
+		return fmt.Errorf("handles '%s' but its manifest declares '%s'", description.Type, p.description.Type)
+	}
+	return nil
+}
+
+// roundTrip writes one request to proc and waits up to timeout for its
+// response. A plugin that stops reading its stdin runs into the timeout as
+// well; the caller kills it, which ends the pending write.
+func (p *Plugin) roundTrip(proc *process, method string, params, result interface{}, timeout time.Duration) error {
+	p.nextID++
+	request := Request{ID: p.nextID, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		request.Params = data
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := proc.stdin.Write(append(data, '\n'))
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		if err != nil {
+			return fmt.Errorf("plugin is not running: %w", err)
+		}
+	case <-deadline.C:
+		return fmt.Errorf("plugin did not read %s within %v", method, timeout)
+	}
+
+	var line []byte
+	select {
+	case received, ok := <-proc.responses:
+		if !ok {
+			return fmt.Errorf("plugin exited")
+		}
+		line = received
+	case <-deadline.C:
+		return fmt.Errorf("no response to %s within %v", method, timeout)
+	}
+
+	var response Response
+	if err := json.Unmarshal(line, &response); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if response.ID != request.ID {
+		return fmt.Errorf("response to request %d received for request %d", response.ID, request.ID)
+	}
+	if response.Error != "" {
+		return remoteError(response.Error)
+	}
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// remoteError is an error reported by the plugin itself; the process is kept
+type remoteError string
+
+func (e remoteError) Error() string {
+	return string(e)
+}
+
+// read passes each line of stdout to responses until the plugin closes it
+func (proc *process) read(stdout io.Reader) {
+	defer close(proc.responses)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64<<10), maxMessageSize)
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		proc.responses <- line
+	}
+}
+
+// kill stops the process and releases its resources in the background
+func (proc *process) kill() {
+	proc.stdin.Close()
+	proc.cmd.Process.Kill()
+	go proc.wait()
+}
+
+// wait discards unread output until the plugin closes stdout, then reaps it
+func (proc *process) wait() {
+	for range proc.responses {
+	}
+	proc.cmd.Wait()
+}
+
+// stderrLogger logs what a plugin writes to stderr, one line at a time. A line
+// longer than maxMessageSize is logged in pieces rather than kept in memory.
+type stderrLogger struct {
+	name string
+	buf  []byte
+}
+
+func (l *stderrLogger) Write(data []byte) (int, error) {
+	l.buf = append(l.buf, data...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		l.log(l.buf[:i])
+		l.buf = l.buf[i+1:]
+	}
+	for len(l.buf) >= maxMessageSize {
+		l.log(l.buf[:maxMessageSize])
+		l.buf = l.buf[maxMessageSize:]
+	}
+	return len(data), nil
+}
+
+func (l *stderrLogger) log(line []byte) {
+	if len(line) > 0 {
+		log.Printf("plugin %s: %s", l.name, line)
+	}
+}
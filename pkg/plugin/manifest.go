@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonschema"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// ManifestSuffix is appended to the name of a plugin executable to find its
+// manifest, e.g. ldap.json for ldap
+const ManifestSuffix = ".json"
+
+// Manifest is what a plugin declares about itself in the file next to its
+// executable: the description it answers describe with. Manifests let a
+// configuration be validated without running any plugin.
+type Manifest struct {
+	path        string
+	description Description
+	schema      *jsonschema.Schema
+}
+
+// ReadManifest reads the manifest of the plugin executable at path
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path + ManifestSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("%s: read manifest: %w", filepath.Base(path), err)
+	}
+
+	var description Description
+	if err := json.Unmarshal(data, &description); err != nil {
+		return nil, fmt.Errorf("%s: invalid manifest: %w", filepath.Base(path), err)
+	}
+	if err := validateDescription(description); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	m := &Manifest{path: path, description: description}
+	if len(description.Schema) > 0 {
+		schema, err := jsonschema.Compile(description.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: options schema: %w", filepath.Base(path), err)
+		}
+		m.schema = schema
+	}
+	return m, nil
+}
+
+// ReadManifests reads the manifests of the plugins in dir without running
+// them. Hidden files, other files that are not executable, and manifests
+// themselves are skipped. Executables writable by other users are refused,
+// since their code would run with the privileges of healthcheck-cli.
+//
+// Plugins without a valid manifest, or that handle a type already taken by an
+// earlier plugin in name order, are reported in the joined error while the
+// others are still returned.
+func ReadManifests(dir string) ([]*Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read plugins directory: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var manifests []*Manifest
+	var errs []error
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || strings.HasSuffix(entry.Name(), ManifestSuffix) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		// Symlinks are followed, so plugins may be linked from elsewhere
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		if info.Mode().Perm()&0o022 != 0 {
+			errs = append(errs, fmt.Errorf("%s: refusing to run a plugin writable by group or others", entry.Name()))
+			continue
+		}
+
+		manifest, err := ReadManifest(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if other := find(manifests, manifest.Type()); other != nil {
+			errs = append(errs, fmt.Errorf("%s: check type '%s' is already handled by %s",
+				entry.Name(), manifest.Type(), filepath.Base(other.Path())))
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	return manifests, errors.Join(errs...)
+}
+
+// Type returns the check type handled by the plugin
+func (m *Manifest) Type() types.CheckType {
+	return m.description.Type
+}
+
+// Name returns the checker name
+func (m *Manifest) Name() string {
+	if m.description.Name != "" {
+		return m.description.Name
+	}
+	return string(m.description.Type)
+}
+
+// Path returns the plugin executable
+func (m *Manifest) Path() string {
+	return m.path
+}
+
+// ValidateOptions checks the plugin options of a check against the schema
+// in the manifest. Plugins without a schema accept any options.
+func (m *Manifest) ValidateOptions(options map[string]interface{}) error {
+	if m.schema == nil {
+		return nil
+	}
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+	// Options are validated as the plugin receives them, after JSON encoding
+	data, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("options cannot be encoded: %w", err)
+	}
+	return m.schema.Validate(data)
+}
+
+// validateDescription checks the protocol version and check type of a description
+func validateDescription(description Description) error {
+	if description.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("speaks protocol version %d, expected %d", description.ProtocolVersion, ProtocolVersion)
+	}
+	if description.Type.IsBuiltin() {
+		return fmt.Errorf("check type '%s' is built in and cannot be replaced", description.Type)
+	}
+	if !ValidType(description.Type) {
+		return fmt.Errorf("check type '%s' is invalid, it must match %s", description.Type, typePattern)
+	}
+	return nil
+}
+
+// find returns the manifest handling checkType
+func find(manifests []*Manifest, checkType types.CheckType) *Manifest {
+	for _, other := range manifests {
+		if other.Type() == checkType {
+			return other
+		}
+	}
+	return nil
+}
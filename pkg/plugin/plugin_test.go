@@ -0,0 +1,292 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPluginEnv makes the test binary act as the plugin named by its value
+const testPluginEnv = "HEALTHCHECK_TEST_PLUGIN"
+
+const ldapSchema = `{
+	"type": "object",
+	"required": ["base_dn"],
+	"properties": {
+		"base_dn": {"type": "string"},
+		"mode": {"type": "string", "enum": ["down", "crash", "hang"]}
+	},
+	"additionalProperties": false
+}`
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(testPluginEnv); name != "" {
+		os.Exit(runTestPlugin(name))
+	}
+	os.Exit(m.Run())
+}
+
+// testDescription describes the named fake plugin
+func testDescription(name string) Description {
+	description := Description{Type: "ldap", Name: "LDAP", Schema: []byte(ldapSchema)}
+	switch name {
+	case "future":
+		description.ProtocolVersion = 99
+	case "dns":
+		description = Description{Type: "dns", Name: "DNS"}
+	}
+	return description
+}
+
+// runTestPlugin serves a fake plugin whose behaviour is chosen by the check options
+func runTestPlugin(name string) int {
+	description := testDescription(name)
+	if name == "deaf" {
+		// Answers describe, then never reads stdin again
+		line, _ := bufio.NewReader(os.Stdin).ReadBytes('\n')
+		serve(bytes.NewReader(line), os.Stdout, description, nil)
+		time.Sleep(time.Minute)
+		return 0
+	}
+
+	err := Serve(description, func(ctx context.Context, request CheckRequest) CheckResponse {
+		switch request.Options["mode"] {
+		case "down":
+			return CheckResponse{Status: StatusDown, Error: "bind failed: invalid credentials"}
+		case "crash":
+			fmt.Fprintln(os.Stderr, "fatal: connection pool corrupted")
+			os.Exit(2)
+		case "hang":
+			time.Sleep(time.Minute)
+		}
+		return CheckResponse{
+			Status:         StatusUp,
+			Message:        fmt.Sprintf("bound to %s under %s", request.Target, request.Options["base_dn"]),
+			ResponseTimeMs: 12.5,
+			Metrics:        []types.Metric{{Label: "entries", Value: 42}},
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// writePlugin installs a script in dir that runs the test binary as the named
+// plugin, along with its manifest
+func writePlugin(t *testing.T, dir, file, name string) string {
+	t.Helper()
+
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, file)
+	script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q\n", testPluginEnv, name, executable)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	writeTestManifest(t, path, testDescription(name))
+	return path
+}
+
+// writeTestManifest writes the manifest of the plugin at path
+func writeTestManifest(t *testing.T, path string, description Description) {
+	t.Helper()
+
+	manifest, err := os.Create(path + ManifestSuffix)
+	require.NoError(t, err)
+	defer manifest.Close()
+	require.NoError(t, writeManifest(manifest, description))
+}
+
+func ldapCheck(options map[string]interface{}) types.CheckConfig {
+	return types.CheckConfig{
+		Name:    "Directory",
+		Type:    "ldap",
+		URL:     "ldap.internal:389",
+		Timeout: 5 * time.Second,
+		Plugin:  options,
+	}
+}
+
+func TestPlugin_Check(t *testing.T) {
+	p, err := Start(writePlugin(t, t.TempDir(), "ldap", "ldap"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	assert.Equal(t, types.CheckType("ldap"), p.Type())
+	assert.Equal(t, "LDAP", p.Name())
+
+	result := p.Check(ldapCheck(map[string]interface{}{"base_dn": "dc=example,dc=com"}))
+	require.Equal(t, types.StatusUp, result.Status, result.Error)
+	assert.Equal(t, "Directory", result.Name)
+	assert.Equal(t, "bound to ldap.internal:389 under dc=example,dc=com", result.Message)
+	assert.Equal(t, 12500*time.Microsecond, result.ResponseTime)
+	assert.Equal(t, []types.Metric{{Label: "entries", Value: 42}}, result.Metrics)
+
+	result = p.Check(ldapCheck(map[string]interface{}{"base_dn": "dc=example,dc=com", "mode": "down"}))
+	assert.Equal(t, types.StatusDown, result.Status)
+	assert.Equal(t, "bind failed: invalid credentials", result.Error)
+}
+
+func TestPlugin_Restart(t *testing.T) {
+	p, err := Start(writePlugin(t, t.TempDir(), "ldap", "ldap"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	result := p.Check(ldapCheck(map[string]interface{}{"base_dn": "dc=example", "mode": "crash"}))
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Equal(t, "Plugin LDAP failed: plugin exited", result.Error)
+
+	// The next check starts the plugin again
+	result = p.Check(ldapCheck(map[string]interface{}{"base_dn": "dc=example"}))
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+}
+
+func TestPlugin_Timeout(t *testing.T) {
+	p, err := Start(writePlugin(t, t.TempDir(), "ldap", "ldap"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	check := ldapCheck(map[string]interface{}{"base_dn": "dc=example", "mode": "hang"})
+	check.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	result := p.Check(check)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "no response to check within 2.1s")
+
+	result = p.Check(ldapCheck(map[string]interface{}{"base_dn": "dc=example"}))
+	assert.Equal(t, types.StatusUp, result.Status, result.Error)
+}
+
+func TestPlugin_StdinTimeout(t *testing.T) {
+	p, err := Start(writePlugin(t, t.TempDir(), "ldap", "deaf"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	// The request is larger than a pipe buffer, so writing it blocks
+	check := ldapCheck(map[string]interface{}{"base_dn": strings.Repeat("x", 1<<20)})
+	check.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	result := p.Check(check)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Contains(t, result.Error, "plugin did not read check within")
+}
+
+func TestPlugin_Manifest(t *testing.T) {
+	dir := t.TempDir()
+
+	missing := writePlugin(t, dir, "ldap", "ldap")
+	require.NoError(t, os.Remove(missing+ManifestSuffix))
+	_, err := Start(missing)
+	assert.ErrorContains(t, err, "ldap: read manifest")
+
+	// The plugin must describe itself as its manifest does
+	mismatch := writePlugin(t, dir, "dns", "dns")
+	writeTestManifest(t, mismatch, testDescription("ldap"))
+	_, err = Start(mismatch)
+	assert.ErrorContains(t, err, "handles 'dns' but its manifest declares 'ldap'")
+
+	// Manifests are read without running the plugin
+	broken := filepath.Join(dir, "broken")
+	require.NoError(t, os.WriteFile(broken, []byte("#!/bin/sh\nexit 1\n"), 0o755))
+	writeTestManifest(t, broken, testDescription("ldap"))
+	manifest, err := ReadManifest(broken)
+	require.NoError(t, err)
+	assert.Equal(t, types.CheckType("ldap"), manifest.Type())
+	assert.ErrorContains(t, manifest.ValidateOptions(nil), "base_dn")
+}
+
+func TestPlugin_ValidateOptions(t *testing.T) {
+	p, err := Start(writePlugin(t, t.TempDir(), "ldap", "ldap"))
+	require.NoError(t, err)
+	defer p.Close()
+
+	assert.NoError(t, p.ValidateOptions(map[string]interface{}{"base_dn": "dc=example"}))
+	assert.ErrorContains(t, p.ValidateOptions(nil), "base_dn")
+	assert.ErrorContains(t, p.ValidateOptions(map[string]interface{}{"base_dn": 5}), "base_dn")
+	assert.ErrorContains(t, p.ValidateOptions(map[string]interface{}{"base_dn": "dc=example", "port": 389}), "port")
+}
+
+func TestPlugin_Close(t *testing.T) {
+	p, err := Start(writePlugin(t, t.TempDir(), "ldap", "ldap"))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Close())
+	result := p.Check(ldapCheck(map[string]interface{}{"base_dn": "dc=example"}))
+	assert.Equal(t, types.StatusError, result.Status)
+	assert.Equal(t, "Plugin LDAP failed: plugin is closed", result.Error)
+}
+
+func TestStderrLogger(t *testing.T) {
+	var output strings.Builder
+	log.SetOutput(&output)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	logger := &stderrLogger{name: "ldap"}
+	logger.Write([]byte("first\nsec"))
+	logger.Write([]byte("ond\n\nthird"))
+	assert.Equal(t, "plugin ldap: first\nplugin ldap: second\n", output.String())
+	assert.Equal(t, "third", string(logger.buf))
+
+	// A line that never ends is logged in pieces of at most maxMessageSize
+	output.Reset()
+	logger.Write([]byte(strings.Repeat("x", maxMessageSize)))
+	assert.Equal(t, 1, strings.Count(output.String(), "plugin ldap: "))
+	assert.Equal(t, "xxxxx", string(logger.buf))
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "10-ldap", "ldap")
+	writePlugin(t, dir, "20-dns", "dns")
+	writePlugin(t, dir, "30-ldap-again", "ldap")
+	writePlugin(t, dir, "40-future", "future")
+	writePlugin(t, dir, ".hidden", "dns")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0o644))
+	writable := writePlugin(t, dir, "50-writable", "dns")
+	require.NoError(t, os.Chmod(writable, 0o777))
+
+	plugins, err := Discover(dir)
+	defer func() {
+		for _, p := range plugins {
+			p.Close()
+		}
+	}()
+
+	var found []string
+	for _, p := range plugins {
+		found = append(found, string(p.Type()))
+	}
+	assert.Equal(t, []string{"ldap", "dns"}, found)
+
+	require.Error(t, err)
+	messages := strings.Split(err.Error(), "\n")
+	require.Len(t, messages, 3)
+	assert.Equal(t, "30-ldap-again: check type 'ldap' is already handled by 10-ldap", messages[0])
+	assert.Contains(t, messages[1], "speaks protocol version 99, expected 1")
+	assert.Equal(t, "50-writable: refusing to run a plugin writable by group or others", messages[2])
+}
+
+func TestDiscover_MissingDirectory(t *testing.T) {
+	_, err := Discover(filepath.Join(t.TempDir(), "absent"))
+	assert.ErrorContains(t, err, "read plugins directory")
+}
@@ -0,0 +1,101 @@
+// Package plugin runs checkers as separate executables speaking a JSON protocol
+// over stdin and stdout, so new check types can be added without rebuilding
+// healthcheck-cli.
+//
+// The host writes one request per line to the plugin's stdin and reads one
+// response per line from its stdout. Requests are sent one at a time.
+// Anything the plugin writes to stderr is logged by the host.
+//
+//	→ {"id":1,"method":"describe"}
+//	← {"id":1,"result":{"protocol_version":1,"type":"ldap","name":"LDAP","schema":{...}}}
+//	→ {"id":2,"method":"check","params":{"name":"Directory","target":"ldap.internal:389","timeout_ms":5000,"options":{...}}}
+//	← {"id":2,"result":{"status":"up","message":"bind succeeded","response_time_ms":12}}
+//	→ {"id":3,"method":"shutdown"}
+//	← {"id":3}
+//
+// A plugin exits after answering shutdown, or when stdin is closed.
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// ProtocolVersion is the version of the protocol spoken by this host.
+// Plugins describing another version are not loaded.
+const ProtocolVersion = 1
+
+// Protocol methods
+const (
+	MethodDescribe = "describe"
+	MethodCheck    = "check"
+	MethodShutdown = "shutdown"
+)
+
+// Check statuses reported in CheckResponse
+const (
+	StatusUp      = "up"
+	StatusDown    = "down"
+	StatusWarning = "warning"
+	StatusSlow    = "slow"
+	StatusError   = "error"
+)
+
+// Request is a message from the host to a plugin
+type Request struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is the answer of a plugin to the request with the same ID.
+// Error is set when the request could not be handled at all; a failed
+// check is reported in the result instead.
+type Response struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Description is the answer to describe
+type Description struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	Type            types.CheckType `json:"type"`             // check type handled by the plugin
+	Name            string          `json:"name"`             // checker name shown to users
+	Schema          json.RawMessage `json:"schema,omitempty"` // JSON Schema of the check's plugin options
+}
+
+// CheckRequest is the parameter of check
+type CheckRequest struct {
+	Name      string                 `json:"name"`
+	Target    string                 `json:"target"` // the check URL
+	TimeoutMs int64                  `json:"timeout_ms"`
+	Options   map[string]interface{} `json:"options,omitempty"` // the check's plugin options
+}
+
+// CheckResponse is the answer to check
+type CheckResponse struct {
+	Status         string         `json:"status"` // up, down, warning, slow or error
+	Error          string         `json:"error,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	ResponseTimeMs float64        `json:"response_time_ms,omitempty"`
+	Metrics        []types.Metric `json:"metrics,omitempty"`
+}
+
+// parseStatus maps a protocol status to a result status
+func parseStatus(status string) (types.Status, bool) {
+	switch status {
+	case StatusUp:
+		return types.StatusUp, true
+	case StatusDown:
+		return types.StatusDown, true
+	case StatusWarning:
+		return types.StatusWarning, true
+	case StatusSlow:
+		return types.StatusSlow, true
+	case StatusError:
+		return types.StatusError, true
+	}
+	return types.StatusError, false
+}
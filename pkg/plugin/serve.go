@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// CheckFunc runs one check for a plugin. The context expires after the check timeout.
+type CheckFunc func(ctx context.Context, request CheckRequest) CheckResponse
+
+// Serve implements the plugin side of the protocol on stdin and stdout. Go
+// plugins call it from main and return when it does:
+//
+//	func main() {
+//		err := plugin.Serve(plugin.Description{Type: "ldap", Name: "LDAP"}, checkLDAP)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Nothing else may be written to stdout; logs belong on stderr.
+//
+// Run with the single argument "manifest", Serve prints the plugin's manifest
+// instead, to be installed next to the executable:
+//
+//	ldap manifest > ldap.json
+func Serve(description Description, check CheckFunc) error {
+	if len(os.Args) == 2 && os.Args[1] == "manifest" {
+		return writeManifest(os.Stdout, description)
+	}
+	return serve(os.Stdin, os.Stdout, description, check)
+}
+
+// writeManifest writes description as a manifest
+func writeManifest(w io.Writer, description Description) error {
+	if description.ProtocolVersion == 0 {
+		description.ProtocolVersion = ProtocolVersion
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(description)
+}
+
+// serve answers requests from r on w until shutdown or the end of r
+func serve(r io.Reader, w io.Writer, description Description, check CheckFunc) error {
+	if description.ProtocolVersion == 0 {
+		description.ProtocolVersion = ProtocolVersion
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxMessageSize)
+	encoder := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		var request Request
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			return fmt.Errorf("invalid request: %w", err)
+		}
+
+		response := Response{ID: request.ID}
+		var result interface{}
+		switch request.Method {
+		case MethodDescribe:
+			result = description
+		case MethodCheck:
+			var params CheckRequest
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				response.Error = fmt.Sprintf("invalid check parameters: %v", err)
+				break
+			}
+			result = runCheck(check, params)
+		case MethodShutdown:
+			return encoder.Encode(response)
+		default:
+			response.Error = fmt.Sprintf("unknown method '%s'", request.Method)
+		}
+
+		if result != nil {
+			data, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			response.Result = data
+		}
+		if err := encoder.Encode(response); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// runCheck calls check with a context bounded by the check timeout
+func runCheck(check CheckFunc, request CheckRequest) CheckResponse {
+	ctx := context.Background()
+	if request.TimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(request.TimeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	start := time.Now()
+	response := check(ctx, request)
+	if response.ResponseTimeMs == 0 {
+		response.ResponseTimeMs = float64(time.Since(start).Microseconds()) / 1000
+	}
+	return response
+}
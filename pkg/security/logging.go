@@ -1,6 +1,7 @@
 package security
 
 import (
+	neturl "net/url"
 	"strings"
 )
 
@@ -63,6 +64,16 @@ func MaskURL(url string) string {
 	return baseURL + "***"
 }
 
+// RedactURLCredentials replaces the password in a URL's user info
+// Example: postgres://app:secret@db:5432/app -> postgres://app:xxxxx@db:5432/app
+func RedactURLCredentials(rawURL string) string {
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil || parsed.User == nil {
+		return rawURL
+	}
+	return parsed.Redacted()
+}
+
 // SanitizeForLogs removes or masks sensitive information from log messages
 func SanitizeForLogs(message string) string {
 	// Remove common sensitive patterns
@@ -302,4 +302,23 @@ func TestMaskURL_EdgeCases(t *testing.T) {
 			assert.Equal(t, tt.want, result)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRedactURLCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "password", url: "postgres://app:secret@db:5432/app", want: "postgres://app:xxxxx@db:5432/app"},
+		{name: "password only", url: "redis://:secret@cache:6379/0", want: "redis://:xxxxx@cache:6379/0"},
+		{name: "user only", url: "mysql://app@db/app", want: "mysql://app@db/app"},
+		{name: "no credentials", url: "https://example.com/health", want: "https://example.com/health"},
+		{name: "host port", url: "db.example.com:5432", want: "db.example.com:5432"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactURLCredentials(tt.url))
+		})
+	}
+}
@@ -4,11 +4,22 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"path/filepath"
 	"strings"
 )
 
+// LookupFunc resolves a hostname to IP addresses
+type LookupFunc func(hostname string) ([]net.IP, error)
+
 // ValidateURL validates a URL and prevents SSRF attacks
 func ValidateURL(rawURL string) error {
+	return ValidateURLWithLookup(rawURL, net.LookupIP)
+}
+
+// ValidateURLWithLookup validates a URL, resolving its hostname with lookup so
+// checks using a custom resolver or proxy are validated against the addresses
+// they will actually connect to
+func ValidateURLWithLookup(rawURL string, lookup LookupFunc) error {
 	if rawURL == "" {
 		return fmt.Errorf("URL cannot be empty")
 	}
@@ -29,7 +40,7 @@ func ValidateURL(rawURL string) error {
 	}
 	
 	// Check if hostname resolves to a private IP
-	if err := validateHostnameNotPrivate(parsed.Hostname()); err != nil {
+	if err := validateHostnameNotPrivate(parsed.Hostname(), lookup); err != nil {
 		return fmt.Errorf("hostname validation failed: %w", err)
 	}
 	
@@ -38,6 +49,11 @@ func ValidateURL(rawURL string) error {
 
 // ValidateSSLTarget validates a target for SSL checks (URL or host:port format)
 func ValidateSSLTarget(target string) error {
+	return ValidateSSLTargetWithLookup(target, net.LookupIP)
+}
+
+// ValidateSSLTargetWithLookup validates an SSL target, resolving its hostname with lookup
+func ValidateSSLTargetWithLookup(target string, lookup LookupFunc) error {
 	if target == "" {
 		return fmt.Errorf("target cannot be empty")
 	}
@@ -59,7 +75,7 @@ func ValidateSSLTarget(target string) error {
 			return fmt.Errorf("URL must have a hostname")
 		}
 		
-		return validateHostnameNotPrivate(parsed.Hostname())
+		return validateHostnameNotPrivate(parsed.Hostname(), lookup)
 	}
 	
 	// Try to parse as host:port format
@@ -73,18 +89,18 @@ func ValidateSSLTarget(target string) error {
 		return fmt.Errorf("hostname cannot be empty")
 	}
 	
-	return validateHostnameNotPrivate(host)
+	return validateHostnameNotPrivate(host, lookup)
 }
 
 // validateHostnameNotPrivate checks if a hostname resolves to a private IP address
-func validateHostnameNotPrivate(hostname string) error {
+func validateHostnameNotPrivate(hostname string, lookup LookupFunc) error {
 	// Skip validation for localhost in development
 	if hostname == "localhost" || hostname == "127.0.0.1" || hostname == "::1" {
 		return nil
 	}
 	
 	// Resolve hostname to IP addresses
-	ips, err := net.LookupIP(hostname)
+	ips, err := lookup(hostname)
 	if err != nil {
 		return fmt.Errorf("failed to resolve hostname: %w", err)
 	}
@@ -220,4 +236,37 @@ func ValidateFilePath(path string) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// ValidateCommand checks that a command run by an exec check is a clean absolute
+// path matching one of the allowed patterns. Patterns use filepath.Match syntax,
+// e.g. /usr/lib/nagios/plugins/*. With no patterns every command is rejected.
+func ValidateCommand(command string, allowed []string) error {
+	if command == "" {
+		return fmt.Errorf("command cannot be empty")
+	}
+	
+	// Relative commands would depend on PATH and the working directory
+	if !filepath.IsAbs(command) {
+		return fmt.Errorf("command must be an absolute path: %s", command)
+	}
+	if filepath.Clean(command) != command {
+		return fmt.Errorf("command path is not clean: %s", command)
+	}
+	
+	if len(allowed) == 0 {
+		return fmt.Errorf("command %s is not allowed: no allowed_commands configured", command)
+	}
+	
+	for _, pattern := range allowed {
+		matched, err := filepath.Match(pattern, command)
+		if err != nil {
+			return fmt.Errorf("invalid allowed_commands pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return nil
+		}
+	}
+	
+	return fmt.Errorf("command %s is not in allowed_commands", command)
+}
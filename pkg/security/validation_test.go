@@ -314,6 +314,93 @@ func TestValidateFilePath(t *testing.T) {
 	}
 }
 
+func TestValidateCommand(t *testing.T) {
+	allowed := []string{"/usr/lib/nagios/plugins/*", "/opt/scripts/check_backup.sh"}
+
+	tests := []struct {
+		name    string
+		command string
+		allowed []string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "MatchesGlob",
+			command: "/usr/lib/nagios/plugins/check_disk",
+			allowed: allowed,
+			wantErr: false,
+		},
+		{
+			name:    "MatchesExactPath",
+			command: "/opt/scripts/check_backup.sh",
+			allowed: allowed,
+			wantErr: false,
+		},
+		{
+			name:    "GlobDoesNotCrossDirectories",
+			command: "/usr/lib/nagios/plugins/contrib/check_mem",
+			allowed: allowed,
+			wantErr: true,
+			errMsg:  "not in allowed_commands",
+		},
+		{
+			name:    "NotAllowed",
+			command: "/bin/sh",
+			allowed: allowed,
+			wantErr: true,
+			errMsg:  "not in allowed_commands",
+		},
+		{
+			name:    "NoAllowList",
+			command: "/usr/lib/nagios/plugins/check_disk",
+			wantErr: true,
+			errMsg:  "no allowed_commands configured",
+		},
+		{
+			name:    "EmptyCommand",
+			allowed: allowed,
+			wantErr: true,
+			errMsg:  "cannot be empty",
+		},
+		{
+			name:    "RelativeCommand",
+			command: "check_disk",
+			allowed: []string{"*"},
+			wantErr: true,
+			errMsg:  "absolute path",
+		},
+		{
+			name:    "TraversalOutOfAllowedDir",
+			command: "/usr/lib/nagios/plugins/../../../bin/sh",
+			allowed: allowed,
+			wantErr: true,
+			errMsg:  "not clean",
+		},
+		{
+			name:    "InvalidPattern",
+			command: "/usr/lib/nagios/plugins/check_disk",
+			allowed: []string{"/usr/lib/[nagios"},
+			wantErr: true,
+			errMsg:  "invalid allowed_commands pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommand(tt.command, tt.allowed)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateHostHeader(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,240 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxEdits bounds the work spent on very different texts; beyond it the
+// whole differing region is reported as removed and re-added
+const maxEdits = 1000
+
+// Op is the kind of a line edit
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is one line of a diff
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the line edits that turn a into b, using Myers' algorithm
+// on the region left after removing the common prefix and suffix
+func Lines(a, b string) []Edit {
+	x, y := splitLines(a), splitLines(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var edits []Edit
+	for _, line := range x[:prefix] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	edits = append(edits, myers(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, line := range x[len(x)-suffix:] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	return edits
+}
+
+// Count returns the number of inserted and deleted lines
+func Count(edits []Edit) (added, removed int) {
+	for _, edit := range edits {
+		switch edit.Op {
+		case Insert:
+			added++
+		case Delete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// Unified formats edits as a unified diff with the given lines of context
+// around each change. It returns an empty string when nothing changed.
+func Unified(edits []Edit, fromName, toName string, context int) string {
+	var out strings.Builder
+
+	for start := 0; start < len(edits); {
+		// Find the next change and extend the hunk while changes are close together
+		first := start
+		for first < len(edits) && edits[first].Op == Equal {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for i := first; i < len(edits); i++ {
+			if edits[i].Op != Equal {
+				last = i
+			} else if i-last > 2*context {
+				break
+			}
+		}
+
+		from := max(first-context, start)
+		to := min(last+context+1, len(edits))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		oldStart, newStart := lineNumbers(edits, from)
+		oldCount, newCount := 0, 0
+		for _, edit := range edits[from:to] {
+			if edit.Op != Insert {
+				oldCount++
+			}
+			if edit.Op != Delete {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, edit := range edits[from:to] {
+			out.WriteString([]string{" ", "-", "+"}[edit.Op])
+			out.WriteString(edit.Text)
+			out.WriteByte('\n')
+		}
+
+		start = to
+	}
+
+	return out.String()
+}
+
+// lineNumbers returns the 1-based old and new line numbers of edits[index]
+func lineNumbers(edits []Edit, index int) (oldLine, newLine int) {
+	oldLine, newLine = 1, 1
+	for _, edit := range edits[:index] {
+		if edit.Op != Insert {
+			oldLine++
+		}
+		if edit.Op != Delete {
+			newLine++
+		}
+	}
+	return oldLine, newLine
+}
+
+// hunkRange formats a hunk header range; an empty range names the line before it
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// myers computes a shortest edit script. Each round keeps only the diagonals
+// it can reach, so memory grows with the square of the edit distance.
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replaceAll(a, b)
+	}
+
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		if d > maxEdits {
+			return replaceAll(a, b)
+		}
+		// Keep the diagonals -d-1..d+1 as they were before this round
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return replaceAll(a, b)
+}
+
+// backtrack walks the recorded rounds from the end to recover the edits
+func backtrack(a, b []string, trace [][]int) []Edit {
+	var reversed []Edit
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Edit{Equal, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Edit{Insert, b[y-1]})
+			} else {
+				reversed = append(reversed, Edit{Delete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]Edit, len(reversed))
+	for i, edit := range reversed {
+		edits[len(reversed)-1-i] = edit
+	}
+	return edits
+}
+
+func replaceAll(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a {
+		edits = append(edits, Edit{Delete, line})
+	}
+	for _, line := range b {
+		edits = append(edits, Edit{Insert, line})
+	}
+	return edits
+}
@@ -0,0 +1,90 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// apply rebuilds both texts from edits
+func apply(edits []Edit) (a, b []string) {
+	for _, edit := range edits {
+		if edit.Op != Insert {
+			a = append(a, edit.Text)
+		}
+		if edit.Op != Delete {
+			b = append(b, edit.Text)
+		}
+	}
+	return a, b
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b         string
+		added, moved int
+	}{
+		{"identical", "a\nb\nc", "a\nb\nc", 0, 0},
+		{"empty to text", "", "a\nb", 2, 0},
+		{"text to empty", "a\nb", "", 0, 2},
+		{"one line changed", "a\nb\nc", "a\nB\nc", 1, 1},
+		{"insert and delete", "a\nb\nc\nd\ne", "a\nc\nd\nx\ne\nf", 2, 1},
+		{"reordered", "a\nb\nc\nd", "d\nc\nb\na", 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := Lines(tt.a, tt.b)
+
+			a, b := apply(edits)
+			assert.Equal(t, splitLines(tt.a), a)
+			assert.Equal(t, splitLines(tt.b), b)
+
+			added, removed := Count(edits)
+			assert.Equal(t, tt.added, added)
+			assert.Equal(t, tt.moved, removed)
+		})
+	}
+}
+
+func TestLines_LargeInputGivesUp(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 3*maxEdits; i++ {
+		a = append(a, fmt.Sprintf("old %d", i))
+		b = append(b, fmt.Sprintf("new %d", i))
+	}
+
+	edits := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+	added, removed := Count(edits)
+	assert.Equal(t, 3*maxEdits, added)
+	assert.Equal(t, 3*maxEdits, removed)
+}
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+
+	diff := Unified(Lines(a, b), "previous", "current", 2)
+
+	assert.Equal(t, `--- previous
++++ current
+@@ -1,5 +1,5 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+@@ -11,2 +11,3 @@
+ 11
+ 12
++13
+`, diff)
+
+	assert.Empty(t, Unified(Lines(a, a), "previous", "current", 2))
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n", Unified(Lines("", "x"), "a", "b", 3))
+}
@@ -0,0 +1,140 @@
+package tlsconfig
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+)
+
+// DefaultMinVersion is used when a check does not set min_version
+const DefaultMinVersion = tls.VersionTLS12
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseVersion converts a version string such as "1.2" into a crypto/tls constant.
+// An empty string returns 0.
+func ParseVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	if v, ok := versions[version]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version '%s' (use 1.0, 1.1, 1.2 or 1.3)", version)
+}
+
+// Validate checks the options without touching the filesystem
+func Validate(opts types.TLSConfig) error {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+
+	minVersion, err := ParseVersion(opts.MinVersion)
+	if err != nil {
+		return fmt.Errorf("min_version: %w", err)
+	}
+	maxVersion, err := ParseVersion(opts.MaxVersion)
+	if err != nil {
+		return fmt.Errorf("max_version: %w", err)
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return fmt.Errorf("min_version %s is greater than max_version %s", opts.MinVersion, opts.MaxVersion)
+	}
+
+	return nil
+}
+
+// Build creates a tls.Config from per-check options, loading the client
+// certificate and CA bundle from disk
+func Build(opts types.TLSConfig) (*tls.Config, error) {
+	if err := Validate(opts); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:         DefaultMinVersion,
+		ServerName:         opts.ServerName,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.MinVersion != "" {
+		config.MinVersion, _ = ParseVersion(opts.MinVersion)
+	}
+	if opts.MaxVersion != "" {
+		config.MaxVersion, _ = ParseVersion(opts.MaxVersion)
+		if config.MaxVersion < config.MinVersion {
+			return nil, fmt.Errorf("max_version %s is below the default minimum TLS 1.2; set min_version too", opts.MaxVersion)
+		}
+	}
+
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file '%s'", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// Key returns a string that identifies a distinct TLS configuration, so
+// callers can share one transport between checks with identical options
+func Key(opts types.TLSConfig) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%t",
+		opts.CertFile, opts.KeyFile, opts.CAFile, opts.ServerName,
+		opts.MinVersion, opts.MaxVersion, opts.InsecureSkipVerify)
+}
+
+// NormalizeFingerprint converts a certificate fingerprint written as hex, with
+// optional colons and sha256: prefix, to lowercase hex
+func NormalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(fingerprint)), "sha256:")
+	return strings.ReplaceAll(fingerprint, ":", "")
+}
+
+// NormalizeSPKIPin strips the optional sha256/ prefix from an SPKI pin
+func NormalizeSPKIPin(pin string) string {
+	return strings.TrimPrefix(strings.TrimSpace(pin), "sha256/")
+}
+
+// ValidateFingerprint checks that a fingerprint is a SHA-256 hash in hex
+func ValidateFingerprint(fingerprint string) error {
+	normalized := NormalizeFingerprint(fingerprint)
+	if decoded, err := hex.DecodeString(normalized); err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("certificate fingerprint '%s' must be a hex SHA-256 hash", fingerprint)
+	}
+	return nil
+}
+
+// ValidateSPKIPin checks that a pin is a base64 SHA-256 hash
+func ValidateSPKIPin(pin string) error {
+	if decoded, err := base64.StdEncoding.DecodeString(NormalizeSPKIPin(pin)); err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("SPKI pin '%s' must be a base64 SHA-256 hash", pin)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    types.TLSConfig
+		wantErr string
+	}{
+		{name: "Empty", opts: types.TLSConfig{}},
+		{name: "VersionsInOrder", opts: types.TLSConfig{MinVersion: "1.2", MaxVersion: "1.3"}},
+		{name: "CertWithoutKey", opts: types.TLSConfig{CertFile: "client.pem"}, wantErr: "must be set together"},
+		{name: "UnknownVersion", opts: types.TLSConfig{MinVersion: "2.0"}, wantErr: "unsupported TLS version"},
+		{name: "MinAboveMax", opts: types.TLSConfig{MinVersion: "1.3", MaxVersion: "1.2"}, wantErr: "greater than max_version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.opts)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	config, err := Build(types.TLSConfig{ServerName: "internal.example", MaxVersion: "1.3", InsecureSkipVerify: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion)
+	assert.Equal(t, "internal.example", config.ServerName)
+	assert.True(t, config.InsecureSkipVerify)
+
+	_, err = Build(types.TLSConfig{MaxVersion: "1.1"})
+	assert.Error(t, err, "max version below the default minimum should fail")
+
+	badCA := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(badCA, []byte("not a certificate"), 0600))
+	_, err = Build(types.TLSConfig{CAFile: badCA})
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+func TestKey(t *testing.T) {
+	a := types.TLSConfig{CAFile: "ca.pem"}
+	b := types.TLSConfig{CAFile: "ca.pem"}
+	c := types.TLSConfig{CAFile: "ca.pem", InsecureSkipVerify: true}
+
+	assert.Equal(t, Key(a), Key(b))
+	assert.NotEqual(t, Key(a), Key(c))
+}
+
+func TestValidatePins(t *testing.T) {
+	hexHash := "3f2a" + strings.Repeat("00", 30)
+	assert.NoError(t, ValidateFingerprint(hexHash))
+	assert.NoError(t, ValidateFingerprint("sha256:3F:2A"+strings.Repeat(":00", 30)))
+	assert.Error(t, ValidateFingerprint("3f2a"))
+	assert.Error(t, ValidateFingerprint(strings.Repeat("zz", 32)))
+
+	assert.NoError(t, ValidateSPKIPin("sha256/YLh1dUR9y6Kja30RrAn7JKnbQG/uEtLMkBgFF2Fuihg="))
+	assert.NoError(t, ValidateSPKIPin("YLh1dUR9y6Kja30RrAn7JKnbQG/uEtLMkBgFF2Fuihg="))
+	assert.Error(t, ValidateSPKIPin("sha256/not-base64"))
+	assert.Error(t, ValidateSPKIPin("c2hvcnQ="))
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strings"
 	"time"
 )
 
@@ -18,6 +19,12 @@ type GlobalConfig struct {
 	RateLimit         RateLimitConfig         `yaml:"rate_limit"`
 	CircuitBreaker    CircuitBreakerConfig    `yaml:"circuit_breaker"`
 	MemoryManagement  MemoryManagementConfig  `yaml:"memory_management"`
+	MaxBodySize       int64                   `yaml:"max_body_size"`
+	Proxy             string                  `yaml:"proxy"`
+	Resolver          string                  `yaml:"resolver"`
+	Resolve           map[string]string       `yaml:"resolve"`
+	AllowedCommands   []string                `yaml:"allowed_commands"`
+	PluginsDir        string                  `yaml:"plugins_dir"`
 }
 
 // RateLimitConfig contains rate limiting configuration
@@ -60,21 +67,32 @@ type ServiceStats struct {
 	LastCheck         time.Time     `json:"last_check"`
 	LastSuccess       time.Time     `json:"last_success"`
 	LastFailure       time.Time     `json:"last_failure"`
+	Families          []FamilyStats `json:"families,omitempty"`
+	MaintenanceChecks int64         `json:"maintenance_checks,omitempty"` // checks run during maintenance, left out of the uptime
 }
 
 // CheckResult represents a stored check result
 type CheckResult struct {
-	ID             int64     `json:"id"`
-	Name           string    `json:"name"`
-	URL            string    `json:"url"`
-	CheckType      string    `json:"check_type"`
-	Status         int       `json:"status"`
-	Error          string    `json:"error"`
-	ResponseTimeMs int64     `json:"response_time_ms"`
-	StatusCode     int       `json:"status_code"`
-	BodySize       int64     `json:"body_size"`
-	Timestamp      time.Time `json:"timestamp"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             int64          `json:"id"`
+	Name           string         `json:"name"`
+	URL            string         `json:"url"`
+	CheckType      string         `json:"check_type"`
+	Status         int            `json:"status"`
+	Error          string         `json:"error"`
+	ResponseTimeMs int64          `json:"response_time_ms"`
+	StatusCode     int            `json:"status_code"`
+	BodySize       int64          `json:"body_size"`
+	Timestamp      time.Time      `json:"timestamp"`
+	CreatedAt      time.Time      `json:"created_at"`
+	FailedStep     string         `json:"failed_step,omitempty"`
+	Steps          []StepResult   `json:"steps,omitempty"`
+	Timings        *Timings       `json:"timings,omitempty"`
+	CertSPKI       string         `json:"cert_spki,omitempty"`
+	Families       []FamilyResult `json:"families,omitempty"`
+	Metrics        []Metric       `json:"metrics,omitempty"`
+	SchemaHash     string         `json:"schema_hash,omitempty"`
+	ContentHash    string         `json:"content_hash,omitempty"`
+	Maintenance    string         `json:"maintenance,omitempty"`
 }
 
 // Status represents the health status of an endpoint
@@ -86,6 +104,8 @@ const (
 	StatusSlow
 	StatusError
 	StatusWarning
+	StatusUnreachable // failed while a check it depends on is down
+	StatusFlapping    // changed state too often to be reported as UP or DOWN
 )
 
 func (s Status) String() string {
@@ -100,6 +120,10 @@ func (s Status) String() string {
 		return "ERROR"
 	case StatusWarning:
 		return "WARNING"
+	case StatusUnreachable:
+		return "UNREACHABLE"
+	case StatusFlapping:
+		return "FLAPPING"
 	default:
 		return "UNKNOWN"
 	}
@@ -118,6 +142,10 @@ func (s Status) Emoji() string {
 		return "❌"
 	case StatusWarning:
 		return "⚠️"
+	case StatusUnreachable:
+		return "🔗"
+	case StatusFlapping:
+		return "🔁"
 	default:
 		return "❓"
 	}
@@ -136,6 +164,10 @@ func (s Status) Color() string {
 		return "\033[91m" // Bright Red
 	case StatusWarning:
 		return "\033[93m" // Bright Yellow
+	case StatusUnreachable:
+		return "\033[90m" // Gray
+	case StatusFlapping:
+		return "\033[35m" // Magenta
 	default:
 		return "\033[37m" // White
 	}
@@ -143,27 +175,282 @@ func (s Status) Color() string {
 
 // Result represents the result of a health check
 type Result struct {
-	Name         string            `json:"name"`
-	URL          string            `json:"url"`
-	Status       Status            `json:"status"`
-	Error        string            `json:"error,omitempty"`
-	ResponseTime time.Duration     `json:"response_time"`
-	StatusCode   int               `json:"status_code,omitempty"`
-	Timestamp    time.Time         `json:"timestamp"`
-	Headers      map[string]string `json:"headers,omitempty"`
-	BodySize     int64             `json:"body_size,omitempty"`
-	CertInfo     *CertInfo         `json:"cert_info,omitempty"`
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Status        Status            `json:"status"`
+	Error         string            `json:"error,omitempty"`
+	ResponseTime  time.Duration     `json:"response_time"`
+	StatusCode    int               `json:"status_code,omitempty"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	BodySize      int64             `json:"body_size,omitempty"`
+	BodyTruncated bool              `json:"body_truncated,omitempty"`
+	CertInfo      *CertInfo         `json:"cert_info,omitempty"`
+	Steps         []StepResult      `json:"steps,omitempty"`
+	FailedStep    string            `json:"failed_step,omitempty"`
+	Timings       *Timings          `json:"timings,omitempty"`
+	CertChange    *CertChange       `json:"cert_change,omitempty"`
+	Families      []FamilyResult    `json:"families,omitempty"`
+	Broker        *BrokerInfo       `json:"broker,omitempty"`
+	Mail          *MailInfo         `json:"mail,omitempty"`
+	Stream        *StreamInfo       `json:"stream,omitempty"`
+	GraphQL       *GraphQLInfo      `json:"graphql,omitempty"`
+	SchemaChange  *SchemaChange     `json:"schema_change,omitempty"`
+	Content       *ContentInfo      `json:"content,omitempty"`
+	ContentChange *ContentChange    `json:"content_change,omitempty"`
+	Domain        *DomainInfo       `json:"domain,omitempty"`
+	Containers    []ContainerInfo   `json:"containers,omitempty"`
+	Message       string            `json:"message,omitempty"` // status text of a healthy exec or local resource check
+	Metrics       []Metric          `json:"metrics,omitempty"`
+
+	// Dependencies
+	UnreachableVia string   `json:"unreachable_via,omitempty"` // the failing check that made this one UNREACHABLE
+	Dependents     []string `json:"dependents,omitempty"`      // dependent checks failing along with this one, reported in its alert
+
+	// Maintenance names the window or silence covering the check when it ran;
+	// such results are stored but not alerted on
+	Maintenance string `json:"maintenance,omitempty"`
+
+	// Confirmation and flap detection
+	Unconfirmed  string `json:"unconfirmed,omitempty"`   // observed status still waiting for confirmation, e.g. "DOWN, 1 of 3 failures in the last 5 checks"
+	StateChanges int    `json:"state_changes,omitempty"` // confirmed state changes within the flapping window
+}
+
+// Timings breaks down where time was spent during an HTTP request.
+// Phases that did not happen (e.g. DNS on a reused connection) are zero.
+type Timings struct {
+	DNS      time.Duration `json:"dns"`
+	Connect  time.Duration `json:"connect"`
+	TLS      time.Duration `json:"tls"`
+	TTFB     time.Duration `json:"ttfb"`     // request written until first response byte
+	Transfer time.Duration `json:"transfer"` // first response byte until body fully read
+	Reused   bool          `json:"reused"`   // connection was taken from the idle pool
+}
+
+// BrokerInfo holds the metrics reported by message broker checks
+type BrokerInfo struct {
+	Server            string        `json:"server,omitempty"`             // AMQP product and version, or Kafka cluster ID
+	Queue             string        `json:"queue,omitempty"`              // AMQP queue inspected
+	Messages          int64         `json:"messages"`                     // AMQP messages ready in the queue
+	Consumers         int64         `json:"consumers"`                    // AMQP consumers on the queue
+	RoundTrip         time.Duration `json:"round_trip,omitempty"`         // MQTT publish to delivery
+	Brokers           int           `json:"brokers,omitempty"`            // Kafka brokers in the cluster
+	Topics            int           `json:"topics,omitempty"`             // Kafka topics inspected
+	Partitions        int           `json:"partitions,omitempty"`         // Kafka partitions inspected
+	OfflinePartitions int           `json:"offline_partitions,omitempty"` // Kafka partitions without a leader
+	UnderReplicated   int           `json:"under_replicated,omitempty"`   // Kafka partitions with fewer in-sync replicas than replicas
+}
+
+// MailInfo holds what a mail server check saw during the session
+type MailInfo struct {
+	Banner     string   `json:"banner"`               // server greeting
+	Extensions []string `json:"extensions,omitempty"` // EHLO extensions, IMAP capabilities or POP3 CAPA lines
+	StartTLS   bool     `json:"starttls,omitempty"`   // the session was upgraded with STARTTLS (or STLS)
+	Mailbox    string   `json:"mailbox,omitempty"`    // IMAP mailbox examined, INBOX for POP3
+	Messages   int64    `json:"messages"`             // messages in the mailbox (IMAP and POP3)
+}
+
+// StreamInfo holds the latencies and the awaited message of WebSocket and SSE checks
+type StreamInfo struct {
+	Handshake    time.Duration `json:"handshake"`               // connect until the upgrade or event stream response
+	FirstMessage time.Duration `json:"first_message,omitempty"` // handshake until the matching message arrived
+	Messages     int           `json:"messages"`                // messages or events received, including the matching one
+	Event        string        `json:"event,omitempty"`         // SSE event type of the matching event
+	Message      string        `json:"message,omitempty"`       // start of the matching message
+}
+
+// GraphQLInfo describes the schema seen by a GraphQL check with schema change detection
+type GraphQLInfo struct {
+	SchemaHash string `json:"schema_hash"` // SHA-256 of the canonical introspection result, hex
+	Types      int    `json:"types"`       // number of types in the schema
+}
+
+// SchemaChange records that a GraphQL schema differs from the last stored one
+type SchemaChange struct {
+	PreviousHash string `json:"previous_hash"`
+	CurrentHash  string `json:"current_hash"`
+}
+
+// ContentInfo fingerprints the normalized body of an HTTP check with content change detection
+type ContentInfo struct {
+	Hash string `json:"hash"` // SHA-256 of the normalized body, hex
+	Text string `json:"-"`    // normalized body, stored for diffs against later versions
+}
+
+// DomainInfo holds the registration data found by a domain check
+type DomainInfo struct {
+	Domain       string    `json:"domain"`
+	Registrar    string    `json:"registrar,omitempty"`
+	ExpiryDate   time.Time `json:"expiry_date,omitempty"` // zero when the registry does not publish it
+	DaysToExpiry int       `json:"days_to_expiry"`        // negative once expired
+	Statuses     []string  `json:"statuses,omitempty"`    // EPP status codes, e.g. clientTransferProhibited
+	Locked       bool      `json:"locked"`                // a client or server transfer lock is set
+	Source       string    `json:"source"`                // rdap or whois
+	Server       string    `json:"server"`                // RDAP base URL or WHOIS server that answered
+	Cached       bool      `json:"cached"`                // answered from the lookup cache
+}
+
+// ContainerInfo describes a container inspected by a Docker check
+type ContainerInfo struct {
+	ID           string    `json:"id"` // short ID
+	Name         string    `json:"name"`
+	Image        string    `json:"image"`
+	State        string    `json:"state"`            // running, exited, restarting, paused, ...
+	Health       string    `json:"health,omitempty"` // healthy, unhealthy or starting; empty without a HEALTHCHECK
+	RestartCount int       `json:"restart_count"`
+	StartedAt    time.Time `json:"started_at"`
+}
+
+// ContentChange records that the normalized body differs from the last stored version
+type ContentChange struct {
+	PreviousHash string `json:"previous_hash"`
+	CurrentHash  string `json:"current_hash"`
+	Added        int    `json:"added"`   // lines added
+	Removed      int    `json:"removed"` // lines removed
+	Diff         string `json:"diff"`    // unified diff excerpt
+}
+
+// ContentVersion is one distinct normalized body seen for a check
+type ContentVersion struct {
+	Hash      string    `json:"hash"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Text      string    `json:"-"`
+}
+
+// Metric is one performance data value reported by an exec check.
+// Thresholds and bounds are kept as the plugin printed them, since Nagios ranges like @10:20 are not numbers.
+type Metric struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"` // s, ms, us, %, B, KB, MB, TB or c
+	Warn  string  `json:"warn,omitempty"`
+	Crit  string  `json:"crit,omitempty"`
+	Min   string  `json:"min,omitempty"`
+	Max   string  `json:"max,omitempty"`
+}
+
+// StepResult represents the outcome of a single step in a scenario check
+type StepResult struct {
+	Name         string        `json:"name"`
+	Status       Status        `json:"status"`
+	Error        string        `json:"error,omitempty"`
+	ResponseTime time.Duration `json:"response_time"`
+	StatusCode   int           `json:"status_code,omitempty"`
 }
 
 // CertInfo represents SSL certificate information
 type CertInfo struct {
-	Subject     string    `json:"subject"`
-	Issuer      string    `json:"issuer"`
-	ExpiryDate  time.Time `json:"expiry_date"`
-	DaysToExpiry int      `json:"days_to_expiry"`
-	IsValid     bool      `json:"is_valid"`
-	CommonName  string    `json:"common_name"`
-	DNSNames    []string  `json:"dns_names"`
+	Subject      string        `json:"subject"`
+	Issuer       string        `json:"issuer"`
+	ExpiryDate   time.Time     `json:"expiry_date"`
+	DaysToExpiry int           `json:"days_to_expiry"`
+	IsValid      bool          `json:"is_valid"`
+	CommonName   string        `json:"common_name"`
+	DNSNames     []string      `json:"dns_names"`
+	Protocol     string        `json:"protocol,omitempty"`
+	CipherSuite  string        `json:"cipher_suite,omitempty"`
+	Chain        []ChainCert   `json:"chain,omitempty"`
+	Findings     []CertFinding `json:"findings,omitempty"`
+	Revocation   *Revocation   `json:"revocation,omitempty"`
+	Fingerprint  string        `json:"fingerprint,omitempty"` // SHA-256 of the leaf certificate, hex
+	SPKIHash     string        `json:"spki_hash,omitempty"`   // base64 SHA-256 of the leaf public key
+}
+
+// CertChange records that the leaf public key differs from the last stored one
+type CertChange struct {
+	PreviousSPKI string `json:"previous_spki"`
+	CurrentSPKI  string `json:"current_spki"`
+}
+
+// Revocation holds the outcome of an OCSP or CRL lookup for the leaf certificate
+type Revocation struct {
+	Status     string    `json:"status"`               // good, revoked or unknown
+	Source     string    `json:"source"`               // ocsp-stapled, ocsp or crl
+	RevokedAt  time.Time `json:"revoked_at,omitempty"` // set when revoked
+	Reason     int       `json:"reason,omitempty"`     // RFC 5280 CRLReason code
+	NextUpdate time.Time `json:"next_update,omitempty"`
+	Error      string    `json:"error,omitempty"` // why the status is unknown
+}
+
+// ChainCert describes one certificate presented by the server, leaf first
+type ChainCert struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	ExpiryDate         time.Time `json:"expiry_date"`
+	DaysToExpiry       int       `json:"days_to_expiry"`
+	KeyAlgorithm       string    `json:"key_algorithm"`
+	KeyBits            int       `json:"key_bits"`
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	IsCA               bool      `json:"is_ca"`
+}
+
+// Certificate audit finding types
+const (
+	FindingChainIncomplete    = "chain_incomplete"
+	FindingHostnameMismatch   = "hostname_mismatch"
+	FindingWeakKey            = "weak_key"
+	FindingSHA1Signature      = "sha1_signature"
+	FindingIntermediateExpiry = "intermediate_expiry"
+)
+
+// Severities a certificate audit finding can be mapped to in Expected
+const (
+	FindingSeverityIgnore  = "ignore"
+	FindingSeverityWarning = "warning"
+	FindingSeverityDown    = "down"
+)
+
+// IsValidFindingSeverity reports whether a configured finding severity is known.
+// An empty value means the finding's default severity.
+func IsValidFindingSeverity(severity string) bool {
+	switch strings.ToLower(severity) {
+	case "", FindingSeverityIgnore, FindingSeverityWarning, FindingSeverityDown:
+		return true
+	}
+	return false
+}
+
+// Address families a check can be forced onto with ip_version
+const (
+	IPVersion4    = "4"
+	IPVersion6    = "6"
+	IPVersionBoth = "both"
+)
+
+// IsValidIPVersion reports whether a configured ip_version is known.
+// An empty value lets the system pick the family.
+func IsValidIPVersion(version string) bool {
+	switch version {
+	case "", IPVersion4, IPVersion6, IPVersionBoth:
+		return true
+	}
+	return false
+}
+
+// FamilyResult is the outcome of probing one address family in ip_version: both mode
+type FamilyResult struct {
+	Family       string        `json:"family"` // IPv4 or IPv6
+	Status       Status        `json:"status"`
+	Error        string        `json:"error,omitempty"`
+	ResponseTime time.Duration `json:"response_time"`
+	StatusCode   int           `json:"status_code,omitempty"`
+}
+
+// FamilyStats aggregates results of one address family
+type FamilyStats struct {
+	Family            string  `json:"family"`
+	TotalChecks       int64   `json:"total_checks"`
+	SuccessfulChecks  int64   `json:"successful_checks"`
+	UptimePercent     float64 `json:"uptime_percent"`
+	AvgResponseTimeMs float64 `json:"avg_response_time_ms"`
+}
+
+// CertFinding is a problem found while auditing a certificate chain
+type CertFinding struct {
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
 }
 
 // IsHealthy returns true if the status indicates a healthy endpoint
@@ -180,17 +467,60 @@ func (r *Result) IsCritical() bool {
 type CheckType string
 
 const (
-	CheckTypeHTTP CheckType = "http"
-	CheckTypeTCP  CheckType = "tcp"
-	CheckTypePing CheckType = "ping"
-	CheckTypeSSL  CheckType = "ssl"
+	CheckTypeHTTP      CheckType = "http"
+	CheckTypeTCP       CheckType = "tcp"
+	CheckTypePing      CheckType = "ping"
+	CheckTypeSSL       CheckType = "ssl"
+	CheckTypeScenario  CheckType = "scenario"
+	CheckTypePostgres  CheckType = "postgres"
+	CheckTypeMySQL     CheckType = "mysql"
+	CheckTypeRedis     CheckType = "redis"
+	CheckTypeAMQP      CheckType = "amqp"
+	CheckTypeMQTT      CheckType = "mqtt"
+	CheckTypeKafka     CheckType = "kafka"
+	CheckTypeSMTP      CheckType = "smtp"
+	CheckTypeIMAP      CheckType = "imap"
+	CheckTypePOP3      CheckType = "pop3"
+	CheckTypeExec      CheckType = "exec"
+	CheckTypeWebSocket CheckType = "websocket"
+	CheckTypeSSE       CheckType = "sse"
+	CheckTypeGraphQL   CheckType = "graphql"
+	CheckTypeDomain    CheckType = "domain"
+	CheckTypeDisk      CheckType = "disk"
+	CheckTypeMemory    CheckType = "memory"
+	CheckTypeLoad      CheckType = "load"
+	CheckTypeProcess   CheckType = "process"
+	CheckTypeFile      CheckType = "file"
+	CheckTypeDocker    CheckType = "docker"
 )
 
+// BuiltinCheckTypes lists the check types implemented by healthcheck-cli itself.
+// Any other type has to be provided by a plugin.
+var BuiltinCheckTypes = []CheckType{
+	CheckTypeHTTP, CheckTypeTCP, CheckTypeSSL, CheckTypeScenario,
+	CheckTypePostgres, CheckTypeMySQL, CheckTypeRedis,
+	CheckTypeAMQP, CheckTypeMQTT, CheckTypeKafka,
+	CheckTypeSMTP, CheckTypeIMAP, CheckTypePOP3, CheckTypeExec,
+	CheckTypeWebSocket, CheckTypeSSE, CheckTypeGraphQL, CheckTypeDomain,
+	CheckTypeDisk, CheckTypeMemory, CheckTypeLoad, CheckTypeProcess, CheckTypeFile,
+	CheckTypeDocker,
+}
+
 // String returns the string representation of CheckType
 func (c CheckType) String() string {
 	return string(c)
 }
 
+// IsBuiltin reports whether the check type is implemented by healthcheck-cli itself
+func (c CheckType) IsBuiltin() bool {
+	for _, builtin := range BuiltinCheckTypes {
+		if c == builtin {
+			return true
+		}
+	}
+	return false
+}
+
 // Checker interface for different types of health checks
 type Checker interface {
 	Check(check CheckConfig) Result
@@ -199,17 +529,100 @@ type Checker interface {
 
 // CheckConfig represents the configuration for a health check
 type CheckConfig struct {
+	Name        string            `yaml:"name" json:"name"`
+	Type        CheckType         `yaml:"type" json:"type"`
+	URL         string            `yaml:"url" json:"url"`
+	Interval    time.Duration     `yaml:"interval" json:"interval"`
+	Timeout     time.Duration     `yaml:"timeout" json:"timeout"`
+	Method      string            `yaml:"method" json:"method"`
+	Headers     map[string]string `yaml:"headers" json:"headers"`
+	Body        string            `yaml:"body" json:"body"`
+	Expected    Expected          `yaml:"expected" json:"expected"`
+	Retry       RetryConfig       `yaml:"retry" json:"retry"`
+	Tags        []string          `yaml:"tags" json:"tags"`
+	Steps       []ScenarioStep    `yaml:"steps" json:"steps"`
+	MaxBodySize int64             `yaml:"max_body_size" json:"max_body_size"` // bytes, 0 uses the global limit
+	SkipBody    bool              `yaml:"skip_body" json:"skip_body"`         // don't read the body unless an assertion needs it
+	TLS         TLSConfig         `yaml:"tls" json:"tls"`
+	Revocation  RevocationConfig  `yaml:"revocation" json:"revocation"`
+	Proxy       string            `yaml:"proxy" json:"proxy"`           // http://, https://, socks5:// or socks5h:// URL; "none" bypasses the global proxy
+	Resolver    string            `yaml:"resolver" json:"resolver"`     // DNS server as host[:port]
+	Resolve     map[string]string `yaml:"resolve" json:"resolve"`       // static host or host:port to IP overrides
+	IPVersion   string            `yaml:"ip_version" json:"ip_version"` // 4, 6 or both; empty uses any family
+	Query       string            `yaml:"query" json:"query"`           // SQL query or Redis command for database checks
+	Broker      BrokerConfig      `yaml:"broker" json:"broker"`
+	Exec        ExecConfig        `yaml:"exec" json:"exec"`
+	Stream      StreamConfig      `yaml:"stream" json:"stream"`
+	GraphQL     GraphQLConfig     `yaml:"graphql" json:"graphql"`
+	Domain      DomainConfig      `yaml:"domain" json:"domain"`
+	Process     ProcessConfig     `yaml:"process" json:"process"`
+	Docker      DockerConfig      `yaml:"docker" json:"docker"`
+	DependsOn   []string          `yaml:"depends_on" json:"depends_on,omitempty"` // names of the checks this one needs to be reachable
+	Confirm     ConfirmConfig     `yaml:"confirm" json:"confirm"`
+	Flapping    FlappingConfig    `yaml:"flapping" json:"flapping"`
+
+	// Plugin options are passed as they are to the plugin handling the check type
+	Plugin map[string]interface{} `yaml:"plugin" json:"plugin,omitempty"`
+}
+
+// ConfirmConfig requires several results to agree before a check goes DOWN,
+// or back UP: Failures failing results among the latest Within, and as many
+// healthy ones to recover
+type ConfirmConfig struct {
+	Failures int `yaml:"failures" json:"failures,omitempty"`
+	Within   int `yaml:"within" json:"within,omitempty"` // latest results considered; defaults to failures
+}
+
+// FlappingConfig marks a check FLAPPING once it changes state Threshold times
+// within the sliding Window
+type FlappingConfig struct {
+	Threshold int           `yaml:"threshold" json:"threshold,omitempty"`
+	Window    time.Duration `yaml:"window" json:"window,omitempty"` // 1h by default
+}
+
+// MaintenanceWindow is a planned period during which the checks in its scope
+// run and are stored as usual but do not alert. It recurs on a cron schedule
+// for a duration, or spans the absolute range from start to end.
+type MaintenanceWindow struct {
+	Name     string        `yaml:"name" json:"name"`
+	Schedule string        `yaml:"schedule" json:"schedule,omitempty"` // cron expression: minute hour day-of-month month day-of-week
+	Duration time.Duration `yaml:"duration" json:"duration,omitempty"`
+	Timezone string        `yaml:"timezone" json:"timezone,omitempty"` // IANA name the schedule is read in; empty uses local time
+	Start    time.Time     `yaml:"start,omitempty" json:"start,omitempty"`
+	End      time.Time     `yaml:"end,omitempty" json:"end,omitempty"`
+	Checks   []string      `yaml:"checks" json:"checks,omitempty"` // check names; with tags empty too, every check
+	Tags     []string      `yaml:"tags" json:"tags,omitempty"`
+}
+
+// Silence mutes alerts for the checks in its scope from StartsAt until
+// EndsAt. Silences are created ad hoc and kept in storage.
+type Silence struct {
+	ID        string    `json:"id"`
+	Checks    []string  `json:"checks,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+// ScenarioStep defines one HTTP request in a multi-step scenario check.
+// URL, headers and body may reference extracted variables as {{name}}.
+type ScenarioStep struct {
 	Name     string            `yaml:"name" json:"name"`
-	Type     CheckType         `yaml:"type" json:"type"`
-	URL      string            `yaml:"url" json:"url"`
-	Interval time.Duration     `yaml:"interval" json:"interval"`
-	Timeout  time.Duration     `yaml:"timeout" json:"timeout"`
 	Method   string            `yaml:"method" json:"method"`
+	URL      string            `yaml:"url" json:"url"`
 	Headers  map[string]string `yaml:"headers" json:"headers"`
 	Body     string            `yaml:"body" json:"body"`
 	Expected Expected          `yaml:"expected" json:"expected"`
-	Retry    RetryConfig       `yaml:"retry" json:"retry"`
-	Tags     []string          `yaml:"tags" json:"tags"`
+	Extract  []Extraction      `yaml:"extract" json:"extract"`
+}
+
+// Extraction captures a value from a step response into a scenario variable
+type Extraction struct {
+	Name   string `yaml:"name" json:"name"`
+	JSON   string `yaml:"json" json:"json"`     // path into the JSON body, e.g. data.token
+	Header string `yaml:"header" json:"header"` // response header name
 }
 
 // Expected defines what constitutes a successful check
@@ -223,6 +636,185 @@ type Expected struct {
 	MinBodySize      int64         `yaml:"min_body_size" json:"min_body_size"`
 	CertExpiryDays   int           `yaml:"cert_expiry_days" json:"cert_expiry_days"`
 	CertValidDomains []string      `yaml:"cert_valid_domains" json:"cert_valid_domains"`
+	BodyRegex        string        `yaml:"body_regex" json:"body_regex"`
+	Headers          []HeaderMatch `yaml:"headers" json:"headers"`
+	JSONSchema       string        `yaml:"json_schema" json:"json_schema"`
+	DNSTimeMax       time.Duration `yaml:"dns_time_max" json:"dns_time_max"`
+	ConnectTimeMax   time.Duration `yaml:"connect_time_max" json:"connect_time_max"`
+	TLSTimeMax       time.Duration `yaml:"tls_time_max" json:"tls_time_max"`
+	TTFBMax          time.Duration `yaml:"ttfb_max" json:"ttfb_max"`
+
+	// Severity of certificate audit findings: warning, down or ignore
+	CertChainIncomplete    string `yaml:"cert_chain_incomplete" json:"cert_chain_incomplete"`       // default down
+	CertHostnameMismatch   string `yaml:"cert_hostname_mismatch" json:"cert_hostname_mismatch"`     // default down
+	CertWeakKey            string `yaml:"cert_weak_key" json:"cert_weak_key"`                       // default warning
+	CertSHA1Signature      string `yaml:"cert_sha1_signature" json:"cert_sha1_signature"`           // default warning
+	CertIntermediateExpiry string `yaml:"cert_intermediate_expiry" json:"cert_intermediate_expiry"` // default warning
+
+	// Certificate pinning and change detection
+	CertFingerprints  []string `yaml:"cert_fingerprints" json:"cert_fingerprints"`       // SHA-256 of the leaf certificate, hex
+	SPKIPins          []string `yaml:"spki_pins" json:"spki_pins"`                       // base64 SHA-256 of any chain certificate's public key
+	CertAlertOnChange bool     `yaml:"cert_alert_on_change" json:"cert_alert_on_change"` // WARNING when the leaf key differs from the last run
+
+	// Message broker thresholds
+	QueueDepthMax int64         `yaml:"queue_depth_max" json:"queue_depth_max"` // WARNING when more messages are ready (AMQP)
+	ConsumersMin  int64         `yaml:"consumers_min" json:"consumers_min"`     // WARNING with fewer consumers on the queue (AMQP)
+	RoundTripMax  time.Duration `yaml:"round_trip_max" json:"round_trip_max"`   // SLOW when publish to delivery takes longer (MQTT)
+	BrokersMin    int           `yaml:"brokers_min" json:"brokers_min"`         // WARNING with fewer live brokers (Kafka)
+
+	// Mail server expectations
+	BannerContains string   `yaml:"banner_contains" json:"banner_contains"` // DOWN when the greeting lacks this text
+	Extensions     []string `yaml:"extensions" json:"extensions"`           // DOWN when any of these is not advertised
+	MessagesMax    int64    `yaml:"messages_max" json:"messages_max"`       // WARNING when the mailbox holds more (IMAP, POP3)
+
+	// WebSocket and SSE expectations; messages are matched with body_contains and body_regex
+	MessageWithin time.Duration `yaml:"message_within" json:"message_within"` // DOWN when no matching message arrives this soon after the handshake
+
+	// JSON body assertions (HTTP and GraphQL) and GraphQL schema change detection
+	JSON                []JSONMatch `yaml:"json" json:"json"`
+	SchemaAlertOnChange bool        `yaml:"schema_alert_on_change" json:"schema_alert_on_change"` // WARNING when the introspected schema differs from the last run
+
+	// Content change detection (HTTP)
+	ContentAlertOnChange bool     `yaml:"content_alert_on_change" json:"content_alert_on_change"` // WARNING when the normalized body differs from the last run
+	ContentIgnore        []string `yaml:"content_ignore" json:"content_ignore"`                   // regexes for regions removed before hashing, e.g. timestamps or CSRF tokens
+
+	// Domain registration expectations
+	DomainExpiryDays int  `yaml:"domain_expiry_days" json:"domain_expiry_days"` // WARNING when the registration expires within this many days
+	DomainLocked     bool `yaml:"domain_locked" json:"domain_locked"`           // WARNING when no transfer lock is set
+
+	// Local resource thresholds; file contents are matched with body_contains and body_regex
+	DiskFreeMin        float64       `yaml:"disk_free_min" json:"disk_free_min"`               // WARNING below this percentage of free space
+	InodesFreeMin      float64       `yaml:"inodes_free_min" json:"inodes_free_min"`           // WARNING below this percentage of free inodes
+	MemoryAvailableMin float64       `yaml:"memory_available_min" json:"memory_available_min"` // WARNING below this percentage of available memory
+	SwapUsedMax        float64       `yaml:"swap_used_max" json:"swap_used_max"`               // WARNING above this percentage of swap in use
+	LoadMax            float64       `yaml:"load_max" json:"load_max"`                         // WARNING when the 5-minute load average per CPU is higher
+	ProcessesMin       int           `yaml:"processes_min" json:"processes_min"`               // DOWN with fewer matching processes, 1 when zero
+	ProcessesMax       int           `yaml:"processes_max" json:"processes_max"`               // DOWN with more matching processes
+	FileMaxAge         time.Duration `yaml:"file_max_age" json:"file_max_age"`                 // DOWN when the file was last modified longer ago
+	FileMaxSize        int64         `yaml:"file_max_size" json:"file_max_size"`               // DOWN when the file is larger, in bytes
+
+	// Docker expectations
+	RestartsMax int `yaml:"restarts_max" json:"restarts_max"` // DOWN when a container restarted more often since the last run, or at all when zero
+}
+
+// BrokerConfig defines what message broker checks inspect
+type BrokerConfig struct {
+	Queue    string `yaml:"queue" json:"queue"`         // AMQP queue to inspect; must already exist
+	Topic    string `yaml:"topic" json:"topic"`         // MQTT round-trip topic, or the Kafka topic to inspect (all topics when empty)
+	ClientID string `yaml:"client_id" json:"client_id"` // MQTT client identifier, random when empty
+	QoS      int    `yaml:"qos" json:"qos"`             // MQTT QoS level for the round trip, 0 or 1
+}
+
+// StreamConfig defines what WebSocket and SSE checks exchange after the handshake
+type StreamConfig struct {
+	Send  string `yaml:"send" json:"send"`   // WebSocket text message sent once connected
+	Event string `yaml:"event" json:"event"` // SSE event type to wait for, any type when empty
+}
+
+// GraphQLConfig defines the operation sent by a GraphQL check
+type GraphQLConfig struct {
+	Query         string                 `yaml:"query" json:"query"`
+	Variables     map[string]interface{} `yaml:"variables" json:"variables"`
+	OperationName string                 `yaml:"operation_name" json:"operation_name"` // selects one operation of a multi-operation document
+}
+
+// IsZero reports whether no GraphQL options are set
+func (g GraphQLConfig) IsZero() bool {
+	return g.Query == "" && len(g.Variables) == 0 && g.OperationName == ""
+}
+
+// DomainConfig defines where a domain check looks up registration data.
+// Both servers are found through the IANA registries when empty.
+type DomainConfig struct {
+	RDAPServer  string        `yaml:"rdap_server" json:"rdap_server"`   // RDAP base URL, e.g. https://rdap.verisign.com/com/v1
+	WHOISServer string        `yaml:"whois_server" json:"whois_server"` // WHOIS server as host[:port], used when RDAP fails
+	CacheTTL    time.Duration `yaml:"cache_ttl" json:"cache_ttl"`       // how long a lookup is reused, 6h when zero
+}
+
+// IsZero reports whether no domain options are set
+func (d DomainConfig) IsZero() bool {
+	return d.RDAPServer == "" && d.WHOISServer == "" && d.CacheTTL == 0
+}
+
+// ProcessConfig selects the processes counted by a process check. When both
+// are set a process has to match both.
+type ProcessConfig struct {
+	Name    string `yaml:"name" json:"name"`       // executable name, e.g. nginx
+	Cmdline string `yaml:"cmdline" json:"cmdline"` // regex on the full command line
+}
+
+// IsZero reports whether no process options are set
+func (p ProcessConfig) IsZero() bool {
+	return p.Name == "" && p.Cmdline == ""
+}
+
+// DockerConfig selects the containers inspected by a Docker check, by name
+// or ID, or by labels that all have to match
+type DockerConfig struct {
+	Socket    string            `yaml:"socket" json:"socket"`       // Engine API unix socket, /var/run/docker.sock when empty
+	Container string            `yaml:"container" json:"container"` // container name or ID
+	Labels    map[string]string `yaml:"labels" json:"labels"`       // label selector; an empty value matches any value
+}
+
+// IsZero reports whether no Docker options are set
+func (d DockerConfig) IsZero() bool {
+	return d.Socket == "" && d.Container == "" && len(d.Labels) == 0
+}
+
+// ExecConfig defines the command run by an exec check. The command is run
+// directly, without a shell, and must match the global allowed_commands.
+type ExecConfig struct {
+	Command string            `yaml:"command" json:"command"` // absolute path of the plugin or script
+	Args    []string          `yaml:"args" json:"args"`
+	Env     map[string]string `yaml:"env" json:"env"` // added to the environment of the healthcheck process
+	Dir     string            `yaml:"dir" json:"dir"` // working directory, the current one when empty
+}
+
+// IsZero reports whether no exec options are set
+func (e ExecConfig) IsZero() bool {
+	return e.Command == "" && len(e.Args) == 0 && len(e.Env) == 0 && e.Dir == ""
+}
+
+// HeaderMatch defines an assertion on a response header.
+// With neither Value nor Regex set, the header only has to be present.
+type HeaderMatch struct {
+	Name  string `yaml:"name" json:"name"`
+	Value string `yaml:"value" json:"value"`
+	Regex string `yaml:"regex" json:"regex"`
+}
+
+// JSONMatch defines an assertion on a value inside a JSON response body, found with
+// the same path expressions as scenario extractions (e.g. data.items[0].id).
+// With neither Value nor Regex set, the path only has to exist.
+type JSONMatch struct {
+	Path  string `yaml:"path" json:"path"`
+	Value string `yaml:"value" json:"value"` // compared with the value rendered as text; objects and arrays as compact JSON
+	Regex string `yaml:"regex" json:"regex"`
+}
+
+// TLSConfig defines per-check TLS client settings
+type TLSConfig struct {
+	CertFile           string `yaml:"cert_file" json:"cert_file"`                       // client certificate for mTLS
+	KeyFile            string `yaml:"key_file" json:"key_file"`                         // client private key for mTLS
+	CAFile             string `yaml:"ca_file" json:"ca_file"`                           // PEM bundle used instead of the system roots
+	ServerName         string `yaml:"server_name" json:"server_name"`                   // SNI and verification name override
+	MinVersion         string `yaml:"min_version" json:"min_version"`                   // 1.0, 1.1, 1.2 or 1.3
+	MaxVersion         string `yaml:"max_version" json:"max_version"`                   // 1.0, 1.1, 1.2 or 1.3
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"` // disables certificate verification
+}
+
+// RevocationConfig enables OCSP/CRL revocation checking for SSL checks
+type RevocationConfig struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	OCSPURL  string        `yaml:"ocsp_url" json:"ocsp_url"`   // overrides the responder from the certificate
+	CRLURL   string        `yaml:"crl_url" json:"crl_url"`     // overrides the CRL distribution point
+	HardFail bool          `yaml:"hard_fail" json:"hard_fail"` // mark DOWN when status cannot be determined
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`     // per responder request, defaults to the check timeout
+}
+
+// IsZero reports whether no TLS options are set
+func (t TLSConfig) IsZero() bool {
+	return t == TLSConfig{}
 }
 
 // RetryConfig defines retry behavior
@@ -18,6 +18,8 @@ func TestStatus_String(t *testing.T) {
 		{"StatusSlow", StatusSlow, "SLOW"},
 		{"StatusError", StatusError, "ERROR"},
 		{"StatusWarning", StatusWarning, "WARNING"},
+		{"StatusUnreachable", StatusUnreachable, "UNREACHABLE"},
+		{"StatusFlapping", StatusFlapping, "FLAPPING"},
 		{"InvalidStatus", Status(999), "UNKNOWN"},
 	}
 
@@ -39,6 +41,8 @@ func TestStatus_Emoji(t *testing.T) {
 		{"StatusSlow", StatusSlow, "🟡"},
 		{"StatusError", StatusError, "❌"},
 		{"StatusWarning", StatusWarning, "⚠️"},
+		{"StatusUnreachable", StatusUnreachable, "🔗"},
+		{"StatusFlapping", StatusFlapping, "🔁"},
 		{"InvalidStatus", Status(999), "❓"},
 	}
 
@@ -60,6 +64,8 @@ func TestStatus_Color(t *testing.T) {
 		{"StatusSlow", StatusSlow, "\033[33m"},
 		{"StatusError", StatusError, "\033[91m"},
 		{"StatusWarning", StatusWarning, "\033[93m"},
+		{"StatusUnreachable", StatusUnreachable, "\033[90m"},
+		{"StatusFlapping", StatusFlapping, "\033[35m"},
 		{"InvalidStatus", Status(999), "\033[37m"},
 	}
 
@@ -161,6 +167,20 @@ func TestResult_IsCritical(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "StatusUnreachable_NotCritical",
+			result: Result{
+				Status: StatusUnreachable,
+			},
+			want: false,
+		},
+		{
+			name: "StatusFlapping_NotCritical",
+			result: Result{
+				Status: StatusFlapping,
+			},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
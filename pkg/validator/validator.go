@@ -3,11 +3,20 @@ package validator
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/dependency"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/errors"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/jsonpath"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/maintenance"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/flap"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/netdial"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/plugin"
+	"github.com/renancavalcantercb/healthcheck-cli/pkg/tlsconfig"
 	"github.com/renancavalcantercb/healthcheck-cli/pkg/types"
 )
 
@@ -112,6 +121,33 @@ func (v *ConfigValidator) ValidateGlobalConfig(config types.GlobalConfig) error
 		).WithContext("value", config.RetryDelay))
 	}
 
+	if config.MaxBodySize < 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			"Invalid max_body_size",
+			"max_body_size cannot be negative",
+		).WithContext("value", config.MaxBodySize))
+	}
+
+	if err := netdial.Validate(netdial.Options{
+		Proxy:    config.Proxy,
+		Resolver: config.Resolver,
+		Resolve:  config.Resolve,
+	}); err != nil {
+		v.errorCollector.Add(errors.NewValidationError(
+			"Invalid network settings",
+			err.Error(),
+		))
+	}
+
+	for _, pattern := range config.AllowedCommands {
+		if _, err := filepath.Match(pattern, ""); err != nil || !filepath.IsAbs(pattern) {
+			v.errorCollector.Add(errors.NewValidationError(
+				"Invalid allowed_commands pattern",
+				"allowed_commands entries must be absolute path globs",
+			).WithContext("pattern", pattern))
+		}
+	}
+
 	// Validate rate limit configuration
 	v.validateRateLimitConfig(config.RateLimit)
 
@@ -124,6 +160,50 @@ func (v *ConfigValidator) ValidateGlobalConfig(config types.GlobalConfig) error
 	return v.errorCollector.ToError()
 }
 
+// ValidateDependencies validates the depends_on names of all checks together
+func (v *ConfigValidator) ValidateDependencies(checks []types.CheckConfig) error {
+	v.errorCollector = errors.NewErrorCollector()
+
+	if err := dependency.Validate(checks); err != nil {
+		v.errorCollector.Add(errors.NewValidationError(
+			"Invalid check dependencies",
+			err.Error(),
+		))
+	}
+
+	return v.errorCollector.ToError()
+}
+
+// ValidateMaintenanceWindows validates maintenance windows and the checks they name
+func (v *ConfigValidator) ValidateMaintenanceWindows(windows []types.MaintenanceWindow, checks []types.CheckConfig) error {
+	v.errorCollector = errors.NewErrorCollector()
+
+	names := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		names[check.Name] = true
+	}
+
+	for i, window := range windows {
+		prefix := fmt.Sprintf("maintenance[%d]", i)
+		if _, err := maintenance.NewWindow(window); err != nil {
+			v.errorCollector.Add(errors.NewValidationError(
+				"Invalid maintenance window",
+				fmt.Sprintf("%s: %v", prefix, err),
+			))
+		}
+		for _, name := range window.Checks {
+			if !names[name] {
+				v.errorCollector.Add(errors.NewValidationError(
+					"Unknown maintenance check",
+					fmt.Sprintf("%s: no check is named '%s'", prefix, name),
+				).WithContext("window", window.Name))
+			}
+		}
+	}
+
+	return v.errorCollector.ToError()
+}
+
 // ValidateCheckConfig validates a single check configuration
 func (v *ConfigValidator) ValidateCheckConfig(check types.CheckConfig, index int) error {
 	v.errorCollector = errors.NewErrorCollector()
@@ -143,13 +223,17 @@ func (v *ConfigValidator) ValidateCheckConfig(check types.CheckConfig, index int
 		).WithContext("name", check.Name))
 	}
 
-	// Validate URL
-	if check.URL == "" {
+	// Validate URL; exec, memory, load, process, docker and plugin checks fall back to a label
+	pluginType := plugin.ValidType(check.Type)
+	urlOptional := check.Type == types.CheckTypeExec || check.Type == types.CheckTypeMemory ||
+		check.Type == types.CheckTypeLoad || check.Type == types.CheckTypeProcess || check.Type == types.CheckTypeDocker ||
+		pluginType
+	if check.URL == "" && !urlOptional {
 		v.errorCollector.Add(errors.NewValidationError(
 			fmt.Sprintf("%s: missing URL", prefix),
 			"check URL is required",
 		))
-	} else {
+	} else if check.URL != "" {
 		if err := v.validateURL(check.URL, check.Type); err != nil {
 			v.errorCollector.Add(err.(*errors.HealthCheckError).
 				WithContext("check_index", index).
@@ -157,14 +241,20 @@ func (v *ConfigValidator) ValidateCheckConfig(check types.CheckConfig, index int
 		}
 	}
 
-	// Validate check type
-	validTypes := []types.CheckType{types.CheckTypeHTTP, types.CheckTypeTCP, types.CheckTypeSSL}
-	if !containsCheckType(validTypes, check.Type) {
+	// Validate check type; other types are provided by plugins, whose options
+	// are checked against the plugin manifests by internal/config
+	if !check.Type.IsBuiltin() && !pluginType {
 		v.errorCollector.Add(errors.NewValidationError(
 			fmt.Sprintf("%s: invalid type", prefix),
-			fmt.Sprintf("check type must be one of: %v", validTypes),
+			fmt.Sprintf("check type must be one of %v or a plugin type", types.BuiltinCheckTypes),
 		).WithContext("type", check.Type))
 	}
+	if len(check.Plugin) > 0 && check.Type.IsBuiltin() {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: unexpected plugin options", prefix),
+			"plugin options are only supported by check types provided by plugins",
+		))
+	}
 
 	// Validate timing configuration
 	if check.Interval <= 0 {
@@ -196,6 +286,73 @@ func (v *ConfigValidator) ValidateCheckConfig(check types.CheckConfig, index int
 		}
 	}
 
+	// Validate scenario steps
+	if check.Type == types.CheckTypeScenario {
+		v.validateScenarioSteps(check, prefix)
+	}
+
+	// Validate exec command
+	if check.Type == types.CheckTypeExec {
+		v.validateExecSettings(check.Exec, prefix)
+	} else if !check.Exec.IsZero() {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: unexpected exec settings", prefix),
+			"exec settings are only supported by exec checks",
+		))
+	}
+
+	// Validate GraphQL operation
+	if check.Type == types.CheckTypeGraphQL && strings.TrimSpace(check.GraphQL.Query) == "" {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: missing GraphQL query", prefix),
+			"graphql.query is required for graphql checks",
+		))
+	} else if check.Type != types.CheckTypeGraphQL && !check.GraphQL.IsZero() {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: unexpected graphql settings", prefix),
+			"graphql settings are only supported by graphql checks",
+		))
+	}
+
+	// Validate content change detection
+	v.validateContentChange(check, prefix)
+
+	// Validate domain registration settings
+	v.validateDomainSettings(check, prefix)
+
+	// Validate local resource settings
+	v.validateLocalSettings(check, prefix)
+
+	// Validate Docker settings
+	v.validateDockerSettings(check, prefix)
+
+	// Validate TLS settings
+	v.validateTLSSettings(check.TLS, prefix)
+
+	// Validate revocation settings
+	v.validateRevocationSettings(check.Revocation, prefix)
+
+	// Validate proxy and resolver settings
+	if err := netdial.Validate(netdial.FromCheck(check)); err != nil {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid network settings", prefix),
+			err.Error(),
+		))
+	}
+
+	// Validate address family
+	if !types.IsValidIPVersion(check.IPVersion) {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid ip_version", prefix),
+			"ip_version must be 4, 6 or both",
+		).WithContext("ip_version", check.IPVersion))
+	} else if check.IPVersion == types.IPVersionBoth && check.Type == types.CheckTypeScenario {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid ip_version", prefix),
+			"ip_version both is not supported for scenario checks",
+		).WithContext("ip_version", check.IPVersion))
+	}
+
 	// Validate expected settings
 	if err := v.validateExpectedSettings(check.Expected, prefix); err != nil {
 		v.errorCollector.Add(err)
@@ -206,13 +363,31 @@ func (v *ConfigValidator) ValidateCheckConfig(check types.CheckConfig, index int
 		v.errorCollector.Add(err)
 	}
 
+	// Validate confirmation and flap detection
+	if err := flap.Validate(check); err != nil {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid confirm or flapping settings", prefix),
+			err.Error(),
+		))
+	}
+
+	// Validate dependencies; names are checked against the other checks by ValidateDependencies
+	for _, name := range check.DependsOn {
+		if name == check.Name {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: invalid depends_on", prefix),
+				"a check cannot depend on itself",
+			).WithContext("name", name))
+		}
+	}
+
 	return v.errorCollector.ToError()
 }
 
 // validateURL validates URL format based on check type
 func (v *ConfigValidator) validateURL(rawURL string, checkType types.CheckType) error {
 	switch checkType {
-	case types.CheckTypeHTTP:
+	case types.CheckTypeHTTP, types.CheckTypeScenario, types.CheckTypeSSE, types.CheckTypeGraphQL:
 		if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
 			return errors.NewValidationError(
 				"Invalid HTTP URL",
@@ -235,6 +410,63 @@ func (v *ConfigValidator) validateURL(rawURL string, checkType types.CheckType)
 			).WithContext("url", rawURL)
 		}
 
+	case types.CheckTypePostgres, types.CheckTypeMySQL, types.CheckTypeRedis,
+		types.CheckTypeAMQP, types.CheckTypeMQTT, types.CheckTypeKafka,
+		types.CheckTypeSMTP, types.CheckTypeIMAP, types.CheckTypePOP3,
+		types.CheckTypeWebSocket:
+		schemes := map[types.CheckType][]string{
+			types.CheckTypePostgres:  {"postgres", "postgresql"},
+			types.CheckTypeMySQL:     {"mysql"},
+			types.CheckTypeRedis:     {"redis", "rediss"},
+			types.CheckTypeAMQP:      {"amqp", "amqps"},
+			types.CheckTypeMQTT:      {"mqtt", "mqtts"},
+			types.CheckTypeKafka:     {"kafka"},
+			types.CheckTypeSMTP:      {"smtp", "smtps"},
+			types.CheckTypeIMAP:      {"imap", "imaps"},
+			types.CheckTypePOP3:      {"pop3", "pop3s"},
+			types.CheckTypeWebSocket: {"ws", "wss"},
+		}[checkType]
+		
+		// Database, broker, mail and WebSocket URLs may carry a password, so only the redacted form is reported
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return errors.NewValidationError(
+				"Malformed URL",
+				"connection URL could not be parsed",
+			)
+		}
+		
+		if !contains(schemes, parsed.Scheme) {
+			return errors.NewValidationError(
+				"Invalid connection URL",
+				fmt.Sprintf("%s checks require a %s:// URL", checkType, strings.Join(schemes, ":// or ")),
+			).WithContext("url", parsed.Redacted())
+		}
+		
+		if parsed.Hostname() == "" {
+			return errors.NewValidationError(
+				"Missing hostname",
+				"URL must include a hostname",
+			).WithContext("url", parsed.Redacted())
+		}
+
+	case types.CheckTypeDisk, types.CheckTypeFile:
+		if !filepath.IsAbs(rawURL) || filepath.Clean(rawURL) != rawURL {
+			return errors.NewValidationError(
+				"Invalid path",
+				fmt.Sprintf("%s checks require a clean absolute path", checkType),
+			).WithContext("url", rawURL)
+		}
+
+	case types.CheckTypeDomain:
+		domainRegex := regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+\.?$`)
+		if !domainRegex.MatchString(rawURL) {
+			return errors.NewValidationError(
+				"Invalid domain name",
+				"domain checks take a domain name such as example.com",
+			).WithContext("url", rawURL)
+		}
+
 	case types.CheckTypeTCP, types.CheckTypeSSL:
 		if strings.Contains(rawURL, "://") {
 			return errors.NewValidationError(
@@ -283,9 +515,336 @@ func (v *ConfigValidator) validateHTTPSettings(check types.CheckConfig, prefix s
 		}
 	}
 
+	// Validate response body handling
+	if check.MaxBodySize < 0 {
+		return errors.NewValidationError(
+			fmt.Sprintf("%s: invalid max_body_size", prefix),
+			"max_body_size cannot be negative",
+		).WithContext("max_body_size", check.MaxBodySize)
+	}
+	if check.SkipBody && (check.Expected.BodyContains != "" || check.Expected.BodyNotContains != "" ||
+		check.Expected.BodyRegex != "" || check.Expected.JSONSchema != "" || check.Expected.MinBodySize > 0) {
+		return errors.NewValidationError(
+			fmt.Sprintf("%s: skip_body conflicts with body assertions", prefix),
+			"skip_body cannot be combined with body_contains, body_not_contains, body_regex, json_schema or min_body_size",
+		)
+	}
+
 	return nil
 }
 
+// validateContentChange validates the content change settings of a check
+func (v *ConfigValidator) validateContentChange(check types.CheckConfig, prefix string) {
+	expected := check.Expected
+
+	if (expected.ContentAlertOnChange || len(expected.ContentIgnore) > 0) && check.Type != types.CheckTypeHTTP {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: unexpected content change settings", prefix),
+			"content_alert_on_change and content_ignore are only supported by http checks",
+		))
+		return
+	}
+	if len(expected.ContentIgnore) > 0 && !expected.ContentAlertOnChange {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: content_ignore without content_alert_on_change", prefix),
+			"content_ignore requires content_alert_on_change",
+		))
+	}
+	if expected.ContentAlertOnChange && check.SkipBody {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: skip_body conflicts with content_alert_on_change", prefix),
+			"content_alert_on_change needs the response body",
+		))
+	}
+	for _, pattern := range expected.ContentIgnore {
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: invalid content_ignore regex", prefix),
+				err.Error(),
+			).WithContext("regex", pattern))
+		}
+	}
+}
+
+// validateDomainSettings validates the lookup settings and expectations of domain checks
+func (v *ConfigValidator) validateDomainSettings(check types.CheckConfig, prefix string) {
+	domain, expected := check.Domain, check.Expected
+
+	if check.Type != types.CheckTypeDomain {
+		if !domain.IsZero() || expected.DomainExpiryDays != 0 || expected.DomainLocked {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: unexpected domain settings", prefix),
+				"domain settings, domain_expiry_days and domain_locked are only supported by domain checks",
+			))
+		}
+		return
+	}
+
+	if expected.DomainExpiryDays < 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid domain_expiry_days", prefix),
+			"domain_expiry_days cannot be negative",
+		).WithContext("domain_expiry_days", expected.DomainExpiryDays))
+	}
+	if domain.CacheTTL < 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid domain cache_ttl", prefix),
+			"cache_ttl cannot be negative",
+		).WithContext("cache_ttl", domain.CacheTTL))
+	}
+	if domain.RDAPServer != "" && !strings.HasPrefix(domain.RDAPServer, "http://") && !strings.HasPrefix(domain.RDAPServer, "https://") {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid rdap_server", prefix),
+			"rdap_server must be an http:// or https:// URL",
+		).WithContext("rdap_server", domain.RDAPServer))
+	}
+	if strings.Contains(domain.WHOISServer, "://") {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid whois_server", prefix),
+			"whois_server should use host or host:port format",
+		).WithContext("whois_server", domain.WHOISServer))
+	}
+}
+
+// validateLocalSettings validates process selection and the thresholds of local resource checks
+func (v *ConfigValidator) validateLocalSettings(check types.CheckConfig, prefix string) {
+	expected := check.Expected
+
+	if check.Type == types.CheckTypeProcess && check.Process.IsZero() {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: missing process selection", prefix),
+			"process checks require process.name or process.cmdline",
+		))
+	} else if check.Type != types.CheckTypeProcess && !check.Process.IsZero() {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: unexpected process settings", prefix),
+			"process settings are only supported by process checks",
+		))
+	}
+	if check.Process.Cmdline != "" {
+		if _, err := regexp.Compile(check.Process.Cmdline); err != nil {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: invalid process cmdline regex", prefix),
+				err.Error(),
+			).WithContext("cmdline", check.Process.Cmdline))
+		}
+	}
+
+	percentages := []struct {
+		name  string
+		value float64
+	}{
+		{"disk_free_min", expected.DiskFreeMin},
+		{"inodes_free_min", expected.InodesFreeMin},
+		{"memory_available_min", expected.MemoryAvailableMin},
+		{"swap_used_max", expected.SwapUsedMax},
+	}
+	for _, percentage := range percentages {
+		if percentage.value < 0 || percentage.value > 100 {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: invalid %s", prefix, percentage.name),
+				"thresholds must be percentages between 0 and 100",
+			).WithContext(percentage.name, percentage.value))
+		}
+	}
+	if expected.LoadMax < 0 || expected.ProcessesMin < 0 || expected.ProcessesMax < 0 ||
+		expected.FileMaxAge < 0 || expected.FileMaxSize < 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: negative local threshold", prefix),
+			"load_max, processes_min, processes_max, file_max_age and file_max_size cannot be negative",
+		))
+	}
+	if expected.ProcessesMax > 0 && expected.ProcessesMin > expected.ProcessesMax {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid process count bounds", prefix),
+			"processes_min cannot be greater than processes_max",
+		).WithContext("processes_min", expected.ProcessesMin).WithContext("processes_max", expected.ProcessesMax))
+	}
+}
+
+// validateDockerSettings validates the container selection and restart threshold of docker checks
+func (v *ConfigValidator) validateDockerSettings(check types.CheckConfig, prefix string) {
+	docker := check.Docker
+
+	if check.Type != types.CheckTypeDocker {
+		if !docker.IsZero() || check.Expected.RestartsMax != 0 {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: unexpected docker settings", prefix),
+				"docker settings and restarts_max are only supported by docker checks",
+			))
+		}
+		return
+	}
+
+	if (docker.Container == "") == (len(docker.Labels) == 0) {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid container selection", prefix),
+			"docker checks require exactly one of docker.container or docker.labels",
+		))
+	}
+	if docker.Socket != "" && !filepath.IsAbs(strings.TrimPrefix(docker.Socket, "unix://")) {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid docker socket", prefix),
+			"docker.socket must be an absolute path or a unix:// URL",
+		).WithContext("socket", docker.Socket))
+	}
+	if check.Expected.RestartsMax < 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid restarts_max", prefix),
+			"restarts_max cannot be negative",
+		).WithContext("restarts_max", check.Expected.RestartsMax))
+	}
+}
+
+// validateExecSettings validates the command of an exec check. Whether it is
+// allowed to run depends on the global allowed_commands and is checked on load.
+func (v *ConfigValidator) validateExecSettings(config types.ExecConfig, prefix string) {
+	if config.Command == "" {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: missing exec command", prefix),
+			"exec checks require exec.command",
+		))
+		return
+	}
+
+	if !filepath.IsAbs(config.Command) || filepath.Clean(config.Command) != config.Command {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid exec command", prefix),
+			"exec.command must be a clean absolute path",
+		).WithContext("command", config.Command))
+	}
+
+	if config.Dir != "" && !filepath.IsAbs(config.Dir) {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid exec dir", prefix),
+			"exec.dir must be an absolute path",
+		).WithContext("dir", config.Dir))
+	}
+}
+
+// validateTLSSettings validates per-check TLS options
+func (v *ConfigValidator) validateTLSSettings(config types.TLSConfig, prefix string) {
+	if err := tlsconfig.Validate(config); err != nil {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid tls settings", prefix),
+			err.Error(),
+		))
+	}
+
+	files := []struct{ field, path string }{
+		{"cert_file", config.CertFile},
+		{"key_file", config.KeyFile},
+		{"ca_file", config.CAFile},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: tls %s not readable", prefix, file.field),
+				err.Error(),
+			).WithContext("path", file.path))
+		}
+	}
+}
+
+// validateRevocationSettings validates OCSP/CRL revocation options
+func (v *ConfigValidator) validateRevocationSettings(config types.RevocationConfig, prefix string) {
+	urls := []struct{ field, value string }{
+		{"ocsp_url", config.OCSPURL},
+		{"crl_url", config.CRLURL},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			continue
+		}
+		parsed, err := url.Parse(u.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: invalid revocation %s", prefix, u.field),
+				fmt.Sprintf("%s must be an http:// or https:// URL", u.field),
+			).WithContext(u.field, u.value))
+		}
+	}
+
+	if config.Timeout < 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: invalid revocation timeout", prefix),
+			"revocation timeout cannot be negative",
+		).WithContext("timeout", config.Timeout))
+	}
+}
+
+// validateScenarioSteps validates the steps of a scenario check
+func (v *ConfigValidator) validateScenarioSteps(check types.CheckConfig, prefix string) {
+	if len(check.Steps) == 0 {
+		v.errorCollector.Add(errors.NewValidationError(
+			fmt.Sprintf("%s: missing scenario steps", prefix),
+			"scenario checks require at least one step",
+		))
+		return
+	}
+
+	variables := make(map[string]bool)
+	for i, step := range check.Steps {
+		stepPrefix := fmt.Sprintf("%s.steps[%d]", prefix, i)
+
+		if step.URL == "" {
+			v.errorCollector.Add(errors.NewValidationError(
+				fmt.Sprintf("%s: missing URL", stepPrefix),
+				"scenario steps require a URL (absolute or relative to the check URL)",
+			))
+		}
+
+		// Reuse HTTP method and header validation for each step
+		stepCheck := types.CheckConfig{Method: step.Method, Headers: step.Headers}
+		if err := v.validateHTTPSettings(stepCheck, stepPrefix); err != nil {
+			v.errorCollector.Add(err)
+		}
+
+		if err := v.validateExpectedSettings(step.Expected, stepPrefix); err != nil {
+			v.errorCollector.Add(err)
+		}
+
+		// Variables must be extracted by an earlier step before they are used
+		for _, field := range append([]string{step.URL, step.Body}, mapValues(step.Headers)...) {
+			for _, match := range scenarioVariablePattern.FindAllStringSubmatch(field, -1) {
+				if !variables[match[1]] {
+					v.errorCollector.Add(errors.NewValidationError(
+						fmt.Sprintf("%s: undefined variable", stepPrefix),
+						fmt.Sprintf("variable '%s' is not extracted by an earlier step", match[1]),
+					).WithContext("variable", match[1]))
+				}
+			}
+		}
+
+		for _, extraction := range step.Extract {
+			if !variableNameRegex.MatchString(extraction.Name) {
+				v.errorCollector.Add(errors.NewValidationError(
+					fmt.Sprintf("%s: invalid extraction name", stepPrefix),
+					"extraction names must be identifiers (letters, digits and underscores)",
+				).WithContext("name", extraction.Name))
+			}
+			if (extraction.JSON == "") == (extraction.Header == "") {
+				v.errorCollector.Add(errors.NewValidationError(
+					fmt.Sprintf("%s: invalid extraction source", stepPrefix),
+					"extractions must set exactly one of json or header",
+				).WithContext("name", extraction.Name))
+			}
+			if extraction.JSON != "" {
+				if err := jsonpath.Validate(extraction.JSON); err != nil {
+					v.errorCollector.Add(errors.NewValidationError(
+						fmt.Sprintf("%s: invalid JSON path", stepPrefix),
+						err.Error(),
+					).WithContext("name", extraction.Name))
+				}
+			}
+			variables[extraction.Name] = true
+		}
+	}
+}
+
 // validateExpectedSettings validates expected response criteria
 func (v *ConfigValidator) validateExpectedSettings(expected types.Expected, prefix string) error {
 	// Validate status code
@@ -326,6 +885,57 @@ func (v *ConfigValidator) validateExpectedSettings(expected types.Expected, pref
 		).WithContext("response_time_max", expected.ResponseTimeMax)
 	}
 
+	// Validate per-phase timing maximums
+	timingLimits := map[string]time.Duration{
+		"dns_time_max":     expected.DNSTimeMax,
+		"connect_time_max": expected.ConnectTimeMax,
+		"tls_time_max":     expected.TLSTimeMax,
+		"ttfb_max":         expected.TTFBMax,
+	}
+	for field, value := range timingLimits {
+		if value < 0 {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s: invalid %s", prefix, field),
+				fmt.Sprintf("%s cannot be negative", field),
+			).WithContext(field, value)
+		}
+	}
+
+	// Validate certificate pins
+	for _, fingerprint := range expected.CertFingerprints {
+		if err := tlsconfig.ValidateFingerprint(fingerprint); err != nil {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s: invalid cert_fingerprints entry", prefix),
+				err.Error(),
+			).WithContext("fingerprint", fingerprint)
+		}
+	}
+	for _, pin := range expected.SPKIPins {
+		if err := tlsconfig.ValidateSPKIPin(pin); err != nil {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s: invalid spki_pins entry", prefix),
+				err.Error(),
+			).WithContext("pin", pin)
+		}
+	}
+
+	// Validate certificate audit severities
+	findingSeverities := []struct{ field, value string }{
+		{"cert_chain_incomplete", expected.CertChainIncomplete},
+		{"cert_hostname_mismatch", expected.CertHostnameMismatch},
+		{"cert_weak_key", expected.CertWeakKey},
+		{"cert_sha1_signature", expected.CertSHA1Signature},
+		{"cert_intermediate_expiry", expected.CertIntermediateExpiry},
+	}
+	for _, severity := range findingSeverities {
+		if !types.IsValidFindingSeverity(severity.value) {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s: invalid %s", prefix, severity.field),
+				fmt.Sprintf("%s must be one of: warning, down, ignore", severity.field),
+			).WithContext(severity.field, severity.value)
+		}
+	}
+
 	// Validate minimum body size
 	if expected.MinBodySize < 0 {
 		return errors.NewValidationError(
@@ -334,6 +944,30 @@ func (v *ConfigValidator) validateExpectedSettings(expected types.Expected, pref
 		).WithContext("min_body_size", expected.MinBodySize)
 	}
 
+	// Validate JSON value assertions
+	for i, match := range expected.JSON {
+		if strings.TrimSpace(match.Path) == "" {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s: empty JSON assertion path", prefix),
+				"JSON assertion paths cannot be empty",
+			).WithContext("json_index", i)
+		}
+		if err := jsonpath.Validate(match.Path); err != nil {
+			return errors.NewValidationError(
+				fmt.Sprintf("%s: invalid JSON assertion path", prefix),
+				err.Error(),
+			).WithContext("path", match.Path)
+		}
+		if match.Regex != "" {
+			if _, err := regexp.Compile(match.Regex); err != nil {
+				return errors.NewValidationError(
+					fmt.Sprintf("%s: invalid JSON assertion regex", prefix),
+					err.Error(),
+				).WithContext("path", match.Path).WithContext("regex", match.Regex)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -437,16 +1071,21 @@ func (v *ConfigValidator) validateMemoryManagementConfig(config types.MemoryMana
 }
 
 // Helper functions
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+
+var (
+	variableNameRegex       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	scenarioVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+)
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
 	}
-	return false
+	return values
 }
 
-func containsCheckType(slice []types.CheckType, item types.CheckType) bool {
+func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
 			return true